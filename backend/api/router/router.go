@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"project/backend/api/v1"
+	"project/backend/internal/upload"
 	"project/backend/middleware"
 	"project/backend/services/auth"
 	"project/backend/services/i18n"
@@ -33,6 +34,9 @@ func InitRouter(r *gin.Engine, authService auth.Service, jwtService jwt.Service,
 		})
 	})
 
+	// 本地存储的上传文件，使用对象存储时不会有文件写到这里
+	r.Static(upload.DefaultConfig.UrlPrefix, upload.DefaultConfig.SavePath)
+
 	// 添加JWT认证中间件，但不全局应用
 	authMiddleware := middleware.Auth(jwtService)
 
@@ -24,9 +24,13 @@ func (r *Router) RegisterCartRoutes(router *gin.RouterGroup) {
 		cartGroup.PATCH("/quantity", handler.UpdateQuantity)
 
 		// 从购物车中移除商品
-		cartGroup.DELETE("/:productID", handler.RemoveFromCart)
+		cartGroup.DELETE("/:skuID", handler.RemoveFromCart)
 
 		// 清空购物车
 		cartGroup.DELETE("", handler.ClearCart)
+
+		// 使用和移除优惠券
+		cartGroup.POST("/coupon", handler.ApplyCoupon)
+		cartGroup.DELETE("/coupon", handler.RemoveCoupon)
 	}
 }
@@ -37,6 +37,21 @@ func (r *Router) RegisterDeviceRoutes(router *gin.RouterGroup) {
 				adminDeviceGroup.POST("/mouse", dHandler.CreateMouseDevice)
 				adminDeviceGroup.PUT("/mouse/:id", dHandler.UpdateMouseDevice)
 				adminDeviceGroup.DELETE("/:id", dHandler.DeleteDevice)
+
+				// SVG轮廓批量上传：先上传预览匹配结果，确认后写入
+				adminDeviceGroup.POST("/mice/svg/upload", dHandler.UploadSVGBatch)
+				adminDeviceGroup.GET("/mice/svg/upload/:batchId", dHandler.GetSVGUploadBatch)
+				adminDeviceGroup.POST("/mice/svg/upload/:batchId/confirm", dHandler.ConfirmSVGUpload)
+
+				// 修改历史：查看、对比、回滚
+				adminDeviceGroup.GET("/:id/revisions", dHandler.GetDeviceRevisions)
+				adminDeviceGroup.GET("/:id/revisions/diff", dHandler.DiffDeviceRevisions)
+				adminDeviceGroup.POST("/:id/revisions/:revision/rollback", dHandler.RollbackDevice)
+
+				// 回收站：删除的设备保留一段时间后自动清理
+				adminDeviceGroup.GET("/trash", dHandler.ListDeletedDevices)
+				adminDeviceGroup.POST("/trash/:id/restore", dHandler.RestoreDevice)
+				adminDeviceGroup.DELETE("/trash/:id", dHandler.PurgeDevice)
 			}
 		}
 	}
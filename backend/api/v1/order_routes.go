@@ -2,9 +2,8 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
-	"net/http"
 	"project/backend/handlers/order"
-	"project/backend/internal/errors"
+	"project/backend/middleware"
 )
 
 // RegisterOrderRoutes 注册订单相关路由
@@ -12,8 +11,11 @@ func (r *Router) RegisterOrderRoutes(router *gin.RouterGroup, handler *order.Han
 	orderRoutes := router.Group("/orders")
 	orderRoutes.Use(r.authMiddleware) // 添加认证中间件 - 订单路由需要认证
 	{
-		// 创建订单
-		orderRoutes.POST("", handler.CreateOrder)
+		// 创建订单，支持Idempotency-Key避免重试时重复下单
+		orderRoutes.POST("", middleware.Idempotency(), handler.CreateOrder)
+
+		// 下单前试算税费和运费
+		orderRoutes.POST("/quote", handler.QuoteOrder)
 
 		// 获取订单详情
 		orderRoutes.GET("/:id", handler.GetOrder)
@@ -30,9 +32,46 @@ func (r *Router) RegisterOrderRoutes(router *gin.RouterGroup, handler *order.Han
 		// 处理支付
 		orderRoutes.POST("/:id/payment", handler.ProcessPayment)
 
-		// 获取订单统计
-		orderRoutes.GET("/stats", func(c *gin.Context) {
-			c.JSON(http.StatusNotImplemented, errors.NewAppError(errors.NotImplemented, "Order stats not implemented"))
-		})
+		// 创建支付意图，支付结果以渠道回调为准
+		orderRoutes.POST("/:id/payment-intent", handler.CreatePaymentIntent)
+
+		// 申请退款和查看订单的退款记录
+		orderRoutes.POST("/:id/refunds", handler.RequestRefund)
+		orderRoutes.GET("/:id/refunds", handler.ListOrderRefunds)
+
+		// 下载发票和发货单PDF，可通过lang参数指定语言
+		orderRoutes.GET("/:id/invoice", handler.DownloadInvoice)
+		orderRoutes.GET("/:id/packing-slip", handler.DownloadPackingSlip)
+
+		// 退款审核 - 仅管理员
+		adminRefundGroup := orderRoutes.Group("/refunds")
+		adminRefundGroup.Use(middleware.RequireRoles("admin"))
+		{
+			adminRefundGroup.GET("", handler.ListRefunds)
+			adminRefundGroup.POST("/:refundId/approve", handler.ApproveRefund)
+			adminRefundGroup.POST("/:refundId/reject", handler.RejectRefund)
+		}
+
+		// 订单管理 - 仅管理员
+		adminOrderGroup := orderRoutes.Group("/admin")
+		adminOrderGroup.Use(middleware.RequireRoles("admin"))
+		{
+			adminOrderGroup.GET("", handler.SearchOrders)
+			adminOrderGroup.PATCH("/status", handler.BulkUpdateStatus)
+			adminOrderGroup.GET("/:id", handler.GetOrderAdmin)
+			adminOrderGroup.PUT("/:id/shipment", handler.UpdateShipment)
+			adminOrderGroup.POST("/:id/notes", handler.AddOrderNote)
+			adminOrderGroup.GET("/:id/invoice", handler.DownloadInvoiceAdmin)
+			adminOrderGroup.GET("/:id/packing-slip", handler.DownloadPackingSlipAdmin)
+		}
+
+		// 获取订单统计 - 仅管理员
+		orderRoutes.GET("/stats", middleware.RequireRoles("admin"), handler.OrderStats)
+	}
+
+	// 支付渠道回调，不需要登录，由签名校验保证来源
+	paymentRoutes := router.Group("/payments")
+	{
+		paymentRoutes.POST("/webhook/:provider", handler.PaymentWebhook)
 	}
 }
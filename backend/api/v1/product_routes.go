@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	productHandler "project/backend/handlers/product"
+	"project/backend/middleware"
+)
+
+// RegisterProductRoutes 注册商品目录相关路由，SKU挂在设备下面
+func (r *Router) RegisterProductRoutes(router *gin.RouterGroup) {
+	pHandler := productHandler.NewHandler(r.productService)
+
+	skuGroup := router.Group("/devices/:id/skus")
+	{
+		// 公开路由，只返回在售的SKU
+		skuGroup.GET("", pHandler.ListDeviceSKUs)
+
+		// 管理员维护价格和库存
+		adminSKUGroup := skuGroup.Group("")
+		adminSKUGroup.Use(middleware.Auth(), middleware.RequireRoles("admin"))
+		{
+			adminSKUGroup.GET("/all", pHandler.ListAllDeviceSKUs)
+			adminSKUGroup.POST("", pHandler.CreateSKU)
+			adminSKUGroup.PUT("/:skuId", pHandler.UpdateSKU)
+			adminSKUGroup.DELETE("/:skuId", pHandler.DeleteSKU)
+		}
+	}
+}
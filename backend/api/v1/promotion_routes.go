@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	promotionHandler "project/backend/handlers/promotion"
+	"project/backend/middleware"
+)
+
+// RegisterPromotionRoutes 注册优惠券管理路由，用户在购物车和下单时使用券码
+func (r *Router) RegisterPromotionRoutes(router *gin.RouterGroup) {
+	pHandler := promotionHandler.NewHandler(r.promotionService)
+
+	couponGroup := router.Group("/coupons")
+	couponGroup.Use(middleware.Auth(), middleware.RequireRoles("admin"))
+	{
+		couponGroup.GET("", pHandler.ListCoupons)
+		couponGroup.POST("", pHandler.CreateCoupon)
+		couponGroup.PUT("/:id", pHandler.UpdateCoupon)
+		couponGroup.DELETE("/:id", pHandler.DeleteCoupon)
+	}
+}
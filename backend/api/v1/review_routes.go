@@ -13,9 +13,16 @@ func (r *Router) RegisterReviewRoutes(router *gin.RouterGroup) {
 	// 评测相关路由 - 部分公开
 	reviewsGroup := router.Group("/reviews")
 	{
-		// 公开路由，无需认证
-		reviewsGroup.GET("", reviewHandler.ListReviews)
-		reviewsGroup.GET("/:id", reviewHandler.GetReview)
+		// 公开路由，无需认证；登录用户带令牌时识别用户，浏览计数按用户去重
+		publicReviewsGroup := reviewsGroup.Group("")
+		publicReviewsGroup.Use(middleware.OptionalAuth())
+		{
+			publicReviewsGroup.GET("", reviewHandler.ListReviews)
+			publicReviewsGroup.GET("/featured", reviewHandler.GetFeaturedReviews)
+			publicReviewsGroup.GET("/trending", reviewHandler.GetTrendingReviews)
+			publicReviewsGroup.GET("/:id", reviewHandler.GetReview)
+			publicReviewsGroup.GET("/:id/comments", reviewHandler.ListComments)
+		}
 		
 		// 需要认证的路由
 		authReviewsGroup := reviewsGroup.Group("")
@@ -26,10 +33,34 @@ func (r *Router) RegisterReviewRoutes(router *gin.RouterGroup) {
 			reviewerGroup.Use(middleware.RequireRoles("reviewer", "admin"))
 			{
 				reviewerGroup.POST("", reviewHandler.CreateReview)
+				reviewerGroup.POST("/images", reviewHandler.UploadReviewImage)
 				reviewerGroup.PUT("/:id", reviewHandler.UpdateReview)
 				reviewerGroup.DELETE("/:id", reviewHandler.DeleteReview)
 			}
 			
+			// 回收站，仅限管理员
+			adminReviewsGroup := authReviewsGroup.Group("/trash")
+			adminReviewsGroup.Use(middleware.RequireRoles("admin"))
+			{
+				adminReviewsGroup.GET("", reviewHandler.ListDeletedReviews)
+				adminReviewsGroup.POST("/:id/restore", reviewHandler.RestoreReview)
+				adminReviewsGroup.DELETE("/:id", reviewHandler.PurgeReview)
+			}
+			
+			// 投票和评论，所有登录用户可用
+			authReviewsGroup.POST("/:id/vote", reviewHandler.VoteReview)
+			authReviewsGroup.DELETE("/:id/vote", reviewHandler.RemoveVote)
+			authReviewsGroup.POST("/:id/comments", reviewHandler.CreateComment)
+			authReviewsGroup.DELETE("/comments/:commentId", reviewHandler.DeleteComment)
+
+			// 评论管理，仅限版主和管理员
+			moderatorGroup := authReviewsGroup.Group("/comments")
+			moderatorGroup.Use(middleware.RequireRoles("moderator", "admin"))
+			{
+				moderatorGroup.POST("/:commentId/hide", reviewHandler.HideComment)
+				moderatorGroup.POST("/:commentId/restore", reviewHandler.RestoreComment)
+			}
+			
 			// 获取用户评测统计
 			authReviewsGroup.GET("/stats/:id", reviewHandler.GetUserReviewStats)
 			authReviewsGroup.GET("/stats", reviewHandler.GetUserReviewStats)
@@ -47,5 +78,27 @@ func (r *Router) RegisterReviewRoutes(router *gin.RouterGroup) {
 		reviewerGroup.POST("/reviews/:id/approve", reviewHandler.ApproveReview)
 		reviewerGroup.POST("/reviews/:id/reject", reviewHandler.RejectReview)
 		reviewerGroup.POST("/reviews/:id/featured", reviewHandler.FeaturedReview)
+
+		// 已发布评测的修改审核
+		reviewerGroup.GET("/reviews/revisions/pending", reviewHandler.GetPendingRevisionReviews)
+		reviewerGroup.GET("/reviews/:id/revisions", reviewHandler.ListReviewRevisions)
+		reviewerGroup.GET("/reviews/:id/revisions/diff", reviewHandler.DiffReviewRevisions)
+		reviewerGroup.POST("/reviews/:id/revisions/:revision/approve", reviewHandler.ApproveRevision)
+		reviewerGroup.POST("/reviews/:id/revisions/:revision/reject", reviewHandler.RejectRevision)
+	}
+
+	// 旧版设备评测接口，数据已合并到reviews
+	deviceReviewsGroup := router.Group("/device-reviews")
+	{
+		deviceReviewsGroup.GET("", reviewHandler.ListDeviceReviews)
+		deviceReviewsGroup.GET("/:id", reviewHandler.GetDeviceReview)
+
+		authDeviceReviewsGroup := deviceReviewsGroup.Group("")
+		authDeviceReviewsGroup.Use(middleware.Auth(), middleware.RequireRoles("reviewer", "admin"))
+		{
+			authDeviceReviewsGroup.POST("", reviewHandler.CreateDeviceReview)
+			authDeviceReviewsGroup.PUT("/:id", reviewHandler.UpdateDeviceReview)
+			authDeviceReviewsGroup.DELETE("/:id", reviewHandler.DeleteDeviceReview)
+		}
 	}
 }
\ No newline at end of file
@@ -3,6 +3,7 @@
 package v1
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -10,10 +11,11 @@ import (
 
 	"project/backend/config"
 	authHandler "project/backend/handlers/auth"
-	deviceHandler "project/backend/handlers/device"
 	orderHandler "project/backend/handlers/order"
 	userHandler "project/backend/handlers/user"
 	"project/backend/internal/database"
+	"project/backend/internal/storage"
+	"project/backend/middleware"
 	authService "project/backend/services/auth"
 	cartService "project/backend/services/cart"
 	deviceService "project/backend/services/device"
@@ -21,21 +23,28 @@ import (
 	"project/backend/services/i18n"
 	"project/backend/services/jwt"
 	orderService "project/backend/services/order"
+	paymentService "project/backend/services/payment"
+	productService "project/backend/services/product"
+	promotionService "project/backend/services/promotion"
 	reviewService "project/backend/services/review"
+	"project/backend/services/scheduler"
 	userService "project/backend/services/user"
+	"project/backend/services/viewcount"
 )
 
 type Router struct {
-	authService    authService.Service
-	emailService   *email.Service
-	deviceService  deviceService.Service
-	userService    userService.Service
-	reviewService  reviewService.Service
-	i18nService    i18n.Service
-	jwtService     jwt.Service
-	authMiddleware gin.HandlerFunc
-	cartService    cartService.Service
-	orderService   *orderService.Service
+	authService      authService.Service
+	emailService     *email.Service
+	deviceService    deviceService.Service
+	userService      userService.Service
+	reviewService    reviewService.Service
+	i18nService      i18n.Service
+	jwtService       jwt.Service
+	authMiddleware   gin.HandlerFunc
+	cartService      cartService.Service
+	orderService     *orderService.Service
+	productService   productService.Service
+	promotionService promotionService.Service
 }
 
 func NewRouter(
@@ -49,18 +58,22 @@ func NewRouter(
 	authMiddleware gin.HandlerFunc,
 	cartSvc cartService.Service,
 	orderSvc *orderService.Service,
+	productSvc productService.Service,
+	promotionSvc promotionService.Service,
 ) *Router {
 	return &Router{
-		authService:    authService,
-		emailService:   emailService,
-		deviceService:  deviceService,
-		userService:    userService,
-		reviewService:  reviewSvc,
-		i18nService:    i18nService,
-		jwtService:     jwtService,
-		authMiddleware: authMiddleware,
-		cartService:    cartSvc,
-		orderService:   orderSvc,
+		authService:      authService,
+		emailService:     emailService,
+		deviceService:    deviceService,
+		userService:      userService,
+		reviewService:    reviewSvc,
+		i18nService:      i18nService,
+		jwtService:       jwtService,
+		authMiddleware:   authMiddleware,
+		cartService:      cartSvc,
+		orderService:     orderSvc,
+		productService:   productSvc,
+		promotionService: promotionSvc,
 	}
 }
 
@@ -77,10 +90,12 @@ func RegisterRoutes(router *gin.RouterGroup, authService authService.Service, jw
 	}
 
 	emailService := email.NewService(config.GetConfig().Email)
-	deviceService := deviceService.New(db) // 使用实际的MongoDB连接，即使数据库连接为nil也使用完整实现
-	userService := &userService.DefaultService{}
-	reviewSvc := &reviewService.DefaultService{}
+	deviceSvc := deviceService.New(db) // 使用实际的MongoDB连接，即使数据库连接为nil也使用完整实现
+	var userSvc userService.Service = &userService.DefaultService{}
+	var reviewSvc reviewService.Service = &reviewService.DefaultService{}
 	i18nSvc := i18n.NewService() // 使用工厂方法创建i18n服务
+	productSvc := productService.New(db)
+	promotionSvc := promotionService.New(db)
 
 	// 安全地创建服务
 	var cartSvc cartService.Service
@@ -88,7 +103,39 @@ func RegisterRoutes(router *gin.RouterGroup, authService authService.Service, jw
 
 	if db != nil {
 		cartSvc = cartService.NewService(db)
-		orderSvc = orderService.NewService(db, nil, nil) // 购物车和设备服务暂时为nil
+		orderSvc = orderService.NewService(db, cartSvc, deviceSvc, config.GetConfig().Order)
+		userSvc = userService.NewService(db, emailService)
+		store, err := storage.New(config.GetConfig().Storage)
+		if err != nil {
+			log.Printf("警告: 初始化图片存储失败，评测图片上传不可用: %v", err)
+		}
+		// 浏览计数依赖Redis，未连接Redis时直接写Mongo
+		var views *viewcount.Counter
+		if database.RedisClient != nil {
+			views = viewcount.New(database.RedisClient, db, config.GetConfig().Review.Views)
+		}
+		reviewSvc = reviewService.New(db, emailService, store, views, config.GetConfig().Review)
+		deviceSvc.SetReviewPurger(reviewSvc)
+
+		// 支付渠道
+		// 开启时配置加载已校验过签名密钥
+		if fakeCfg := config.GetConfig().Payment.Fake; fakeCfg.Enabled {
+			orderSvc.RegisterPaymentProvider(paymentService.NewFakeProvider(fakeCfg.WebhookSecret))
+		}
+
+		// 定时任务，多实例部署时通过Mongo租约保证每个任务只在一个实例上执行
+		orderSvc.SetNotifier(emailService)
+		jobs := scheduler.New(scheduler.RealClock{}, scheduler.NewMongoLocker(db))
+		jobs.Register(orderSvc.AutoCancelJob(config.GetConfig().Order.AutoCancelInterval))
+		jobs.Register(orderSvc.RefundRecoveryJob(0))
+		jobs.Register(reviewService.OrphanImageCleanupJob(reviewSvc, 0, 0))
+		// 定期清理回收站中过期的设备
+		trashCfg := config.GetConfig().Trash
+		jobs.Register(deviceService.TrashPurgeJob(deviceSvc, trashCfg.PurgeInterval, trashCfg.Retention))
+		if views != nil {
+			jobs.Register(views.FlushJob())
+		}
+		go jobs.Start(context.Background())
 	} else {
 		// 使用mock实现避免空指针
 		cartSvc = &cartService.MockService{}
@@ -98,14 +145,16 @@ func RegisterRoutes(router *gin.RouterGroup, authService authService.Service, jw
 	r := NewRouter(
 		authService,
 		emailService,
-		deviceService,
-		userService,
+		deviceSvc,
+		userSvc,
 		reviewSvc,
 		i18nSvc,
 		jwtService,
 		authMiddleware,
 		cartSvc,
 		orderSvc,
+		productSvc,
+		promotionSvc,
 	)
 
 	r.RegisterRoutes(router)
@@ -113,7 +162,6 @@ func RegisterRoutes(router *gin.RouterGroup, authService authService.Service, jw
 
 func (r *Router) RegisterRoutes(router *gin.RouterGroup) {
 	emailHandler := authHandler.NewEmailVerificationHandler(r.authService, r.emailService)
-	dHandler := deviceHandler.NewHandler(r.deviceService)
 	uHandler := userHandler.NewHandler(r.userService)
 	oHandler := orderHandler.NewHandler(r.orderService)
 
@@ -126,6 +174,12 @@ func (r *Router) RegisterRoutes(router *gin.RouterGroup) {
 	// 语言
 	r.registerLanguageRoutes(router)
 
+	// 商品目录
+	r.RegisterProductRoutes(router)
+
+	// 优惠券
+	r.RegisterPromotionRoutes(router)
+
 	// 购物车
 	r.RegisterCartRoutes(router)
 
@@ -170,25 +224,18 @@ func (r *Router) RegisterRoutes(router *gin.RouterGroup) {
 		{
 			userGroup.GET("/me", uHandler.GetUserProfile)
 			userGroup.PUT("/me", uHandler.UpdateUserProfile)
-		}
-	}
-
-	// 设备相关路由已移至device_routes.go
 
-	// 设备评测
-	reviewsGroup := router.Group("/device-reviews")
-	{
-		// 公开路由，无需认证
-		reviewsGroup.GET("", dHandler.ListDeviceReviews)
-		reviewsGroup.GET("/:id", dHandler.GetDeviceReview)
-
-		// 创建评测路由
-		authReviewsGroup := reviewsGroup.Group("")
-		authReviewsGroup.Use(r.authMiddleware)
-		{
-			authReviewsGroup.POST("", dHandler.CreateDeviceReview)
-			authReviewsGroup.PUT("/:id", dHandler.UpdateDeviceReview)
-			authReviewsGroup.DELETE("/:id", dHandler.DeleteDeviceReview)
+			// 申请成为评测员，申请状态见个人资料中的reviewerApplication
+			userGroup.POST("/me/reviewer-application", uHandler.ApplyForReviewer)
+
+			// 评测员申请审核 - 仅管理员
+			reviewerAdmin := userGroup.Group("/reviewer-applications")
+			reviewerAdmin.Use(middleware.RequireRoles("admin"))
+			{
+				reviewerAdmin.GET("", uHandler.GetReviewerApplications)
+				reviewerAdmin.POST("/:userId/approve", uHandler.ApproveReviewerApplication)
+				reviewerAdmin.POST("/:userId/reject", uHandler.RejectReviewerApplication)
+			}
 		}
 	}
 }
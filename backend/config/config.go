@@ -14,12 +14,19 @@ type Config struct {
 	JWT     JWTConfig     `yaml:"jwt"`
 	OAuth   OAuthConfig   `yaml:"oauth"`
 	Email   EmailConfig   `yaml:"email"`
+	Trash   TrashConfig   `yaml:"trash"`
+	Order   OrderConfig   `yaml:"order"`
+	Payment PaymentConfig `yaml:"payment"`
+	Review  ReviewConfig  `yaml:"review"`
+	Storage StorageConfig `yaml:"storage"`
 }
 
 type ServerConfig struct {
 	Port         string        `yaml:"port"`
 	ReadTimeout  time.Duration `yaml:"readTimeout"`
 	WriteTimeout time.Duration `yaml:"writeTimeout"`
+	// TrustedProxies 可信的反向代理地址或网段，只有来自这些地址的请求才使用X-Forwarded-For作为客户端IP，为空时不信任任何代理
+	TrustedProxies []string `yaml:"trustedProxies"`
 }
 
 type MongoDBConfig struct {
@@ -49,6 +56,104 @@ type OAuthConfig struct {
 	} `yaml:"google"`
 }
 
+// TrashConfig 回收站配置，为空时使用默认值
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`     // 删除后的保留期
+	PurgeInterval time.Duration `yaml:"purgeInterval"` // 自动清理间隔
+}
+
+// OrderConfig 订单配置，为空时使用默认值
+type OrderConfig struct {
+	ReservationTTL     time.Duration `yaml:"reservationTTL"`     // 未支付订单的库存预留时长，超时后自动取消
+	AutoCancelInterval time.Duration `yaml:"autoCancelInterval"` // 检查超时未支付订单的间隔
+	Pricing            PricingConfig `yaml:"pricing"`
+}
+
+// PricingConfig 税费和运费规则
+// Source为mongo时从tax_rules和shipping_rates集合读取规则，否则使用这里配置的规则
+type PricingConfig struct {
+	Source         string               `yaml:"source"`         // config 或 mongo
+	DefaultMethod  string               `yaml:"defaultMethod"`  // 未指定配送方式时使用
+	DefaultWeights map[string]int       `yaml:"defaultWeights"` // 设备没有重量数据时按类型估算（克）
+	TaxRules       []TaxRuleConfig      `yaml:"taxRules"`
+	ShippingRates  []ShippingRateConfig `yaml:"shippingRates"`
+}
+
+// TaxRuleConfig 税率规则，Rate单位为万分之一
+type TaxRuleConfig struct {
+	Country   string `yaml:"country"`
+	State     string `yaml:"state"`
+	Rate      int    `yaml:"rate"`
+	Inclusive bool   `yaml:"inclusive"`
+	Name      string `yaml:"name"`
+}
+
+// ShippingRateConfig 运费规则，金额单位为分，重量单位为克
+type ShippingRateConfig struct {
+	Method         string `yaml:"method"`
+	Country        string `yaml:"country"`
+	State          string `yaml:"state"`
+	Currency       string `yaml:"currency"`
+	BaseFee        int64  `yaml:"baseFee"`
+	IncludedWeight int    `yaml:"includedWeight"`
+	StepWeight     int    `yaml:"stepWeight"`
+	StepFee        int64  `yaml:"stepFee"`
+	FreeOver       int64  `yaml:"freeOver"`
+}
+
+// PaymentConfig 支付渠道配置
+type PaymentConfig struct {
+	Fake FakePaymentConfig `yaml:"fake"`
+}
+
+// FakePaymentConfig 本地模拟支付渠道，仅用于开发和测试
+type FakePaymentConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	WebhookSecret string `yaml:"-"` // 只从 PAYMENT_FAKE_WEBHOOK_SECRET 环境变量读取，不写入配置文件
+}
+
+// ReviewConfig 评测配置
+type ReviewConfig struct {
+	Moderation ModerationConfig `yaml:"moderation"`
+	Views      ViewConfig       `yaml:"views"`
+}
+
+// ViewConfig 评测浏览计数配置，为空时使用默认值
+type ViewConfig struct {
+	DedupeWindow     time.Duration `yaml:"dedupeWindow"`     // 同一访客在窗口内重复浏览只计一次
+	FlushInterval    time.Duration `yaml:"flushInterval"`    // 把Redis中的计数批量写回Mongo的间隔
+	TrendingHalfLife time.Duration `yaml:"trendingHalfLife"` // 热度的半衰期
+	TrendingWindow   time.Duration `yaml:"trendingWindow"`   // 热门列表只包含该时间内有浏览的评测
+	BotUserAgents    []string      `yaml:"botUserAgents"`    // 额外的爬虫UA关键字，不区分大小写
+}
+
+// ModerationConfig 评测提交时的自动预审配置，为空时使用默认值
+type ModerationConfig struct {
+	Blocklists          map[string][]string `yaml:"blocklists"`          // 按语言配置的屏蔽词，default对所有语言生效
+	MaxLinks            int                 `yaml:"maxLinks"`            // 超过该数量的链接视为疑似广告
+	DuplicateThreshold  float64             `yaml:"duplicateThreshold"`  // 与已有评测的相似度超过该值视为近似重复
+	RateLimit           int                 `yaml:"rateLimit"`           // 时间窗口内每个用户最多提交的评测数
+	RateWindow          time.Duration       `yaml:"rateWindow"`
+	TrustedMinPublished int                 `yaml:"trustedMinPublished"` // 已发布评测达到该数量且没有被拒绝的评测员视为可信
+	AutoApproveMaxRisk  int                 `yaml:"autoApproveMaxRisk"`  // 可信评测员的评测风险分不超过该值时自动通过
+}
+
+// StorageConfig 上传文件的存储，driver为local时保存到上传目录
+type StorageConfig struct {
+	Driver string   `yaml:"driver"` // local 或 s3
+	S3     S3Config `yaml:"s3"`
+}
+
+// S3Config S3兼容的对象存储，本地开发可使用MinIO
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"` // 例如 http://localhost:9000
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+	PublicURL string `yaml:"publicUrl"` // 对外访问地址，为空时使用endpoint/bucket
+}
+
 // EmailConfig defines email service configuration
 type EmailConfig struct {
 	SMTP struct {
@@ -109,5 +214,21 @@ func LoadConfigFromPath(path string) (*Config, error) {
 		config.Email.SMTP.Password = password
 	}
 
+	// Payment webhook secret, only from environment variable
+	config.Payment.Fake.WebhookSecret = os.Getenv("PAYMENT_FAKE_WEBHOOK_SECRET")
+
+	// 安全校验：开启模拟支付渠道时必须配置回调签名密钥，否则任何人都可以伪造支付成功
+	if config.Payment.Fake.Enabled && config.Payment.Fake.WebhookSecret == "" {
+		return nil, fmt.Errorf("fake payment provider is enabled but PAYMENT_FAKE_WEBHOOK_SECRET is not set")
+	}
+
+	// Object storage credentials environment variable override
+	if accessKey := os.Getenv("STORAGE_S3_ACCESS_KEY"); accessKey != "" {
+		config.Storage.S3.AccessKey = accessKey
+	}
+	if secretKey := os.Getenv("STORAGE_S3_SECRET_KEY"); secretKey != "" {
+		config.Storage.S3.SecretKey = secretKey
+	}
+
 	return config, nil
 }
\ No newline at end of file
@@ -4,9 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-
-	"project/backend/models"
+	"project/backend/internal/errors"
 	"project/backend/services/cart"
 	cartTypes "project/backend/types/cart"
 )
@@ -45,30 +43,45 @@ func (h *Handler) GetCart(c *gin.Context) {
 		return
 	}
 
-	// 计算商品总数和总价
-	total := 0.0
+	// 计算商品总数和总价，不可购买的商品不计入总价
+	var total int64
+	currency := ""
 	itemCount := 0
 	items := make([]cartTypes.CartItemResponse, 0, len(cart.Items))
 
 	for _, item := range cart.Items {
-		total += item.Price * float64(item.Quantity)
+		subtotal := item.Price * int64(item.Quantity)
+		if item.Available {
+			total += subtotal
+			currency = item.Currency
+		}
 		itemCount += item.Quantity
 		items = append(items, cartTypes.CartItemResponse{
 			ProductID:   item.ProductID.Hex(),
+			SKUID:       item.SKUID.Hex(),
 			ProductType: item.ProductType,
 			Name:        item.Name,
 			Price:       item.Price,
+			Currency:    item.Currency,
 			Quantity:    item.Quantity,
+			Subtotal:    subtotal,
 			ImageURL:    item.ImageURL,
+			Available:   item.Available,
+			Reason:      item.Reason,
 		})
 	}
 
 	response := cartTypes.CartResponse{
-		ID:        cart.ID.Hex(),
-		Items:     items,
-		Total:     total,
-		ItemCount: itemCount,
-		UpdatedAt: cart.UpdatedAt,
+		ID:          cart.ID.Hex(),
+		Items:       items,
+		Total:       total,
+		Currency:    currency,
+		ItemCount:   itemCount,
+		CouponCode:  cart.CouponCode,
+		CouponError: cart.CouponError,
+		Discount:    cart.Discount,
+		Payable:     total - cart.Discount,
+		UpdatedAt:   cart.UpdatedAt,
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -100,29 +113,11 @@ func (h *Handler) AddToCart(c *gin.Context) {
 		return
 	}
 
-	productID, err := primitive.ObjectIDFromHex(req.ProductID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    http.StatusBadRequest,
-			"message": "无效的商品ID",
-			"data":    nil,
-		})
-		return
-	}
-
-	cartItem := models.CartItem{
-		ProductID:   productID,
-		ProductType: req.ProductType,
-		Name:        req.Name,
-		Price:       req.Price,
-		Quantity:    req.Quantity,
-		ImageURL:    req.ImageURL,
-	}
-
-	err = h.cartService.AddToCart(c.Request.Context(), userID.(string), cartItem)
+	err := h.cartService.AddToCart(c.Request.Context(), userID.(string), req.SKUID, req.Quantity)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		status := errors.HTTPStatusFromError(err)
+		c.JSON(status, gin.H{
+			"code":    status,
 			"message": err.Error(),
 			"data":    nil,
 		})
@@ -158,10 +153,11 @@ func (h *Handler) UpdateQuantity(c *gin.Context) {
 		return
 	}
 
-	err := h.cartService.UpdateQuantity(c.Request.Context(), userID.(string), req.ProductID, req.Quantity)
+	err := h.cartService.UpdateQuantity(c.Request.Context(), userID.(string), req.SKUID, req.Quantity)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+		status := errors.HTTPStatusFromError(err)
+		c.JSON(status, gin.H{
+			"code":    status,
 			"message": err.Error(),
 			"data":    nil,
 		})
@@ -187,8 +183,8 @@ func (h *Handler) RemoveFromCart(c *gin.Context) {
 		return
 	}
 
-	productID := c.Param("productID")
-	if productID == "" {
+	skuID := c.Param("skuID")
+	if skuID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    http.StatusBadRequest,
 			"message": "缺少商品ID",
@@ -197,7 +193,7 @@ func (h *Handler) RemoveFromCart(c *gin.Context) {
 		return
 	}
 
-	err := h.cartService.RemoveFromCart(c.Request.Context(), userID.(string), productID)
+	err := h.cartService.RemoveFromCart(c.Request.Context(), userID.(string), skuID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
@@ -242,3 +238,73 @@ func (h *Handler) ClearCart(c *gin.Context) {
 		"data":    nil,
 	})
 }
+
+// ApplyCoupon 购物车使用优惠券
+func (h *Handler) ApplyCoupon(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "未授权访问",
+			"data":    nil,
+		})
+		return
+	}
+
+	var req cartTypes.ApplyCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "无效的请求数据",
+			"data":    nil,
+		})
+		return
+	}
+
+	err := h.cartService.ApplyCoupon(c.Request.Context(), userID.(string), req.Code)
+	if err != nil {
+		status := errors.HTTPStatusFromError(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+			"data":    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "优惠券已使用",
+		"data":    nil,
+	})
+}
+
+// RemoveCoupon 移除购物车的优惠券
+func (h *Handler) RemoveCoupon(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    http.StatusUnauthorized,
+			"message": "未授权访问",
+			"data":    nil,
+		})
+		return
+	}
+
+	err := h.cartService.RemoveCoupon(c.Request.Context(), userID.(string))
+	if err != nil {
+		status := errors.HTTPStatusFromError(err)
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": err.Error(),
+			"data":    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "优惠券已移除",
+		"data":    nil,
+	})
+}
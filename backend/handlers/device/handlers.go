@@ -7,7 +7,6 @@ import (
 	"log"
 	"net/http"
 	"project/backend/internal/errors"
-	"project/backend/middleware"
 	"project/backend/models"
 	deviceSvc "project/backend/services/device"
 	deviceTypes "project/backend/types/device"
@@ -142,7 +141,7 @@ func (h *Handler) UpdateMouseDevice(c *gin.Context) {
 		return
 	}
 
-	result, err := h.deviceService.UpdateMouseDevice(c.Request.Context(), deviceID, request)
+	result, err := h.deviceService.UpdateMouseDevice(c.Request.Context(), c.GetString("userId"), deviceID, request)
 	if err != nil {
 		status := errors.HTTPStatusFromError(err)
 		var errObj *errors.AppError
@@ -218,10 +217,14 @@ func (h *Handler) ListDevices(c *gin.Context) {
 
 	// 创建DeviceListFilter
 	filter := deviceTypes.DeviceListFilter{
-		Page:     request.Page,
-		PageSize: request.PageSize,
-		Type:     request.Type,
-		Brand:    request.Brand,
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		Type:       request.Type,
+		Brand:      request.Brand,
+		MinRating:  request.MinRating,
+		MinReviews: request.MinReviews,
+		SortBy:     request.SortBy,
+		SortOrder:  request.SortOrder,
 	}
 	result, err := h.deviceService.ListDevices(c.Request.Context(), filter)
 	if err != nil {
@@ -260,268 +263,6 @@ func (h *Handler) ListDevices(c *gin.Context) {
 	})
 }
 
-// CreateDeviceReview 创建设备评测（仅限评测员和管理员）
-func (h *Handler) CreateDeviceReview(c *gin.Context) {
-	var request deviceTypes.CreateDeviceReviewRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
-		errObj := errors.NewBadRequestError("无效的请求: " + err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 从上下文获取用户ID
-	userID, exists := c.Get("userId")
-	if !exists {
-		errObj := errors.NewUnauthorizedError("用户未认证")
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	role, roleExists := c.Get("userRole")
-	if !roleExists || (role.(string) != string(models.RoleReviewer) && role.(string) != string(models.RoleAdmin)) {
-		errObj := errors.NewForbiddenError("只有评测员才能创建评测")
-		c.JSON(http.StatusForbidden, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 对用户输入进行消毒，防止存储型XSS
-	request.Content = middleware.SanitizeHTML(request.Content)
-	request.Usage = middleware.SanitizeText(request.Usage)
-	for i := range request.Pros {
-		request.Pros[i] = middleware.SanitizeText(request.Pros[i])
-	}
-	for i := range request.Cons {
-		request.Cons[i] = middleware.SanitizeText(request.Cons[i])
-	}
-
-	// 转换请求类型为CreateReviewRequest
-	createReviewReq := deviceTypes.CreateReviewRequest(request)
-
-	// 调用服务创建评测
-	result, err := h.deviceService.CreateDeviceReview(c.Request.Context(), userID.(string), createReviewReq)
-	if err != nil {
-		status := errors.HTTPStatusFromError(err)
-		var errObj *errors.AppError
-		var ok bool
-		if errObj, ok = err.(*errors.AppError); !ok {
-			// 如果不是AppError类型，创建一个通用错误
-			errObj = errors.NewInternalServerError("服务器内部错误")
-		}
-		c.JSON(status, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 构建响应
-	response := mapDeviceReviewToResponse(result)
-	c.JSON(http.StatusCreated, gin.H{
-		"code":    0,
-		"message": "成功",
-		"data":    response,
-	})
-}
-
-// GetDeviceReview 获取评测详情
-func (h *Handler) GetDeviceReview(c *gin.Context) {
-	reviewID := c.Param("id")
-
-	result, err := h.deviceService.GetDeviceReview(c.Request.Context(), reviewID)
-	if err != nil {
-		status := errors.HTTPStatusFromError(err)
-		var errObj *errors.AppError
-		var ok bool
-		if errObj, ok = err.(*errors.AppError); !ok {
-			// 如果不是AppError类型，创建一个通用错误
-			errObj = errors.NewInternalServerError("服务器内部错误")
-		}
-		c.JSON(status, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 构建响应
-	response := mapDeviceReviewToResponse(result)
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "成功",
-		"data":    response,
-	})
-}
-
-// UpdateDeviceReview 更新评测
-func (h *Handler) UpdateDeviceReview(c *gin.Context) {
-	reviewID := c.Param("id")
-
-	var request deviceTypes.UpdateDeviceReviewRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
-		errObj := errors.NewBadRequestError("无效的请求: " + err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 从上下文获取用户ID
-	userID, exists := c.Get("userId")
-	if !exists {
-		errObj := errors.NewUnauthorizedError("用户未认证")
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 对用户输入进行消毒，防止存储型XSS
-	if request.Content != nil {
-		sanitized := middleware.SanitizeHTML(*request.Content)
-		request.Content = &sanitized
-	}
-	if request.Usage != nil {
-		sanitized := middleware.SanitizeText(*request.Usage)
-		request.Usage = &sanitized
-	}
-	if request.Pros != nil {
-		pros := *request.Pros
-		for i := range pros {
-			pros[i] = middleware.SanitizeText(pros[i])
-		}
-	}
-	if request.Cons != nil {
-		cons := *request.Cons
-		for i := range cons {
-			cons[i] = middleware.SanitizeText(cons[i])
-		}
-	}
-
-	// 转换请求类型为UpdateReviewRequest
-	updateReviewReq := deviceTypes.UpdateReviewRequest(request)
-
-	// 调用服务更新评测
-	result, err := h.deviceService.UpdateDeviceReview(c.Request.Context(), userID.(string), reviewID, updateReviewReq)
-	if err != nil {
-		status := errors.HTTPStatusFromError(err)
-		var errObj *errors.AppError
-		var ok bool
-		if errObj, ok = err.(*errors.AppError); !ok {
-			// 如果不是AppError类型，创建一个通用错误
-			errObj = errors.NewInternalServerError("服务器内部错误")
-		}
-		c.JSON(status, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	// 构建响应
-	response := mapDeviceReviewToResponse(result)
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "成功",
-		"data":    response,
-	})
-}
-
-// DeleteDeviceReview 删除评测
-func (h *Handler) DeleteDeviceReview(c *gin.Context) {
-	reviewID := c.Param("id")
-
-	// 从上下文获取用户ID
-	userID, exists := c.Get("userId")
-	if !exists {
-		errObj := errors.NewUnauthorizedError("用户未认证")
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	err := h.deviceService.DeleteDeviceReview(c.Request.Context(), userID.(string), reviewID)
-	if err != nil {
-		status := errors.HTTPStatusFromError(err)
-		var errObj *errors.AppError
-		var ok bool
-		if errObj, ok = err.(*errors.AppError); !ok {
-			// 如果不是AppError类型，创建一个通用错误
-			errObj = errors.NewInternalServerError("服务器内部错误")
-		}
-		c.JSON(status, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "成功",
-		"data":    nil,
-	})
-}
-
-// ListDeviceReviews 获取评测列表
-func (h *Handler) ListDeviceReviews(c *gin.Context) {
-	var request deviceTypes.DeviceReviewListRequest
-	if err := c.ShouldBindQuery(&request); err != nil {
-		errObj := errors.NewBadRequestError("无效的请求参数: " + err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	result, err := h.deviceService.ListDeviceReviews(c.Request.Context(), request)
-	if err != nil {
-		status := errors.HTTPStatusFromError(err)
-		var errObj *errors.AppError
-		var ok bool
-		if errObj, ok = err.(*errors.AppError); !ok {
-			// 如果不是AppError类型，创建一个通用错误
-			errObj = errors.NewInternalServerError("服务器内部错误")
-		}
-		c.JSON(status, gin.H{
-			"code":    errObj.Code,
-			"message": errObj.Error(),
-			"data":    nil,
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "成功",
-		"data":    result,
-	})
-}
-
 // CreateUserDevice 创建用户设备配置
 func (h *Handler) CreateUserDevice(c *gin.Context) {
 	var request deviceTypes.CreateUserDeviceRequest
@@ -832,28 +573,12 @@ func mapMouseDeviceToResponse(device *models.MouseDevice) deviceTypes.MouseRespo
 		Shape:       device.Shape,
 		Technical:   device.Technical,
 		Recommended: device.Recommended,
+		Rating:      device.Rating,
 		CreatedAt:   device.CreatedAt,
 		UpdatedAt:   device.UpdatedAt,
 	}
 }
 
-// mapDeviceReviewToResponse 将设备评测模型转换为响应
-func mapDeviceReviewToResponse(review *models.DeviceReview) deviceTypes.DeviceReviewResponse {
-	return deviceTypes.DeviceReviewResponse{
-		ID:        review.ID.Hex(),
-		DeviceID:  review.DeviceID.Hex(),
-		UserID:    review.UserID.Hex(),
-		Content:   review.Content,
-		Pros:      review.Pros,
-		Cons:      review.Cons,
-		Score:     review.Score,
-		Usage:     review.Usage,
-		Status:    review.Status,
-		CreatedAt: review.CreatedAt,
-		UpdatedAt: review.UpdatedAt,
-	}
-}
-
 // mapUserDeviceToResponse 将用户设备配置模型转换为响应
 func (h *Handler) mapUserDeviceToResponse(c *gin.Context, userDevice *models.UserDevice) (deviceTypes.UserDeviceResponse, error) {
 	response := deviceTypes.UserDeviceResponse{
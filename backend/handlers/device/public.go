@@ -2,12 +2,14 @@ package device
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"project/backend/internal/errors"
+	"project/backend/internal/svg"
 	"project/backend/models"
 	device "project/backend/types/device"
 	deviceTypes "project/backend/types/device"
@@ -90,21 +92,9 @@ func (h *Handler) GetMouseSVG(c *gin.Context) {
 
 	// 检查鼠标SVG数据是否存在
 	var svgData []byte
-	if mouse.SVGData != nil {
-		// 根据请求视图返回对应的SVG数据
-		var svgContent string
-		if req.View == "top" && mouse.SVGData.TopView != "" {
-			svgContent = mouse.SVGData.TopView
-			fmt.Printf("使用数据库中的顶视图SVG: %s\n", mouse.Name)
-		} else if req.View == "side" && mouse.SVGData.SideView != "" {
-			svgContent = mouse.SVGData.SideView
-			fmt.Printf("使用数据库中的侧视图SVG: %s\n", mouse.Name)
-		}
-
-		// 如果找到了对应视图的SVG
-		if svgContent != "" {
-			svgData = []byte(svgContent)
-		}
+	if svgContent := mouseSVGContent(mouse, req.View, req.Canonical); svgContent != "" {
+		svgData = []byte(svgContent)
+		fmt.Printf("使用数据库中的%s视图SVG: %s\n", req.View, mouse.Name)
 	}
 
 	// 如果没有找到SVG数据，返回占位图
@@ -135,6 +125,37 @@ func (h *Handler) GetMouseSVG(c *gin.Context) {
 	})
 }
 
+// mouseSVGContent 获取指定视图的SVG，输出前重新清洗
+// 历史数据未经过上传流程校验，清洗失败时按无数据处理；规范版本缺失时回退到原始SVG
+func mouseSVGContent(mouse *models.MouseDevice, view string, canonical bool) string {
+	if mouse.SVGData == nil {
+		return ""
+	}
+
+	var original, canonicalContent string
+	switch view {
+	case "top":
+		original, canonicalContent = mouse.SVGData.TopView, mouse.SVGData.TopViewCanonical
+	case "side":
+		original, canonicalContent = mouse.SVGData.SideView, mouse.SVGData.SideViewCanonical
+	}
+
+	content := original
+	if canonical && canonicalContent != "" {
+		content = canonicalContent
+	}
+	if content == "" {
+		return ""
+	}
+
+	cleaned, err := svg.Sanitize([]byte(content))
+	if err != nil {
+		log.Printf("SVG清洗失败: %s, %v", mouse.Name, err)
+		return ""
+	}
+	return cleaned
+}
+
 // CompareSVGs 比较多个鼠标的SVG
 func (h *Handler) CompareSVGs(c *gin.Context) {
 	var req device.SVGCompareRequest
@@ -172,21 +193,9 @@ func (h *Handler) CompareSVGs(c *gin.Context) {
 
 		// 检查鼠标是否有SVG数据
 		var svgData []byte
-		if mouse.SVGData != nil {
-			// 根据请求视图返回对应的SVG数据
-			var svgContent string
-			if req.View == "top" && mouse.SVGData.TopView != "" {
-				svgContent = mouse.SVGData.TopView
-				fmt.Printf("比较: 使用数据库中的顶视图SVG: %s\n", mouse.Name)
-			} else if req.View == "side" && mouse.SVGData.SideView != "" {
-				svgContent = mouse.SVGData.SideView
-				fmt.Printf("比较: 使用数据库中的侧视图SVG: %s\n", mouse.Name)
-			}
-
-			// 如果找到了对应视图的SVG
-			if svgContent != "" {
-				svgData = []byte(svgContent)
-			}
+		if svgContent := mouseSVGContent(mouse, req.View, req.Canonical); svgContent != "" {
+			svgData = []byte(svgContent)
+			fmt.Printf("比较: 使用数据库中的%s视图SVG: %s\n", req.View, mouse.Name)
 		}
 
 		// 如果没有找到SVG数据，返回占位图
@@ -292,10 +301,19 @@ func (h *Handler) GetDevices(c *gin.Context) {
 
 	// 构建查询条件
 	filter := deviceTypes.DeviceListFilter{
-		Type:     c.Query("type"),
-		Brand:    c.Query("brand"),
-		Page:     page,
-		PageSize: pageSize,
+		Type:      c.Query("type"),
+		Brand:     c.Query("brand"),
+		Page:      page,
+		PageSize:  pageSize,
+		SortBy:    c.Query("sortBy"),
+		SortOrder: c.Query("sortOrder"),
+	}
+	// 评分筛选，无效的值忽略
+	if minRating, err := strconv.ParseFloat(c.Query("minRating"), 64); err == nil && minRating > 0 {
+		filter.MinRating = minRating
+	}
+	if minReviews, err := strconv.Atoi(c.Query("minReviews")); err == nil && minReviews > 0 {
+		filter.MinReviews = minReviews
 	}
 
 	// 打印调试信息
@@ -304,6 +322,14 @@ func (h *Handler) GetDevices(c *gin.Context) {
 	result, err := h.deviceService.ListDevices(c.Request.Context(), filter)
 	if err != nil {
 		fmt.Printf("GetDevices API错误: %v\n", err)
+		if appErr, ok := err.(*errors.AppError); ok && appErr.HTTPStatus() == http.StatusBadRequest {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    appErr.Code,
+				"message": appErr.Error(),
+				"data":    nil,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    errors.InternalError,
 			"message": "获取设备列表失败",
@@ -0,0 +1,74 @@
+package device
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	deviceTypes "project/backend/types/device"
+)
+
+// GetDeviceRevisions 获取设备修改历史
+func (h *Handler) GetDeviceRevisions(c *gin.Context) {
+	var request deviceTypes.DeviceRevisionListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	result, err := h.deviceService.GetDeviceRevisions(c.Request.Context(), c.Param("id"), request.Page, request.PageSize)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// DiffDeviceRevisions 对比设备的两个版本
+func (h *Handler) DiffDeviceRevisions(c *gin.Context) {
+	var request deviceTypes.DeviceRevisionDiffRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	result, err := h.deviceService.DiffDeviceRevisions(c.Request.Context(), c.Param("id"), request.From, request.To)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// RollbackDevice 回滚设备到指定版本
+func (h *Handler) RollbackDevice(c *gin.Context) {
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		errors.HandleError(c, errors.NewBadRequestError("无效的版本号"))
+		return
+	}
+
+	result, err := h.deviceService.RollbackDevice(c.Request.Context(), c.GetString("userId"), c.Param("id"), revision)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "回滚成功",
+		"data":    result,
+	})
+}
@@ -1,6 +1,7 @@
 package device
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"project/backend/internal/errors"
+	"project/backend/models"
 )
 
 // CompareMice 比较多个鼠标
@@ -25,8 +27,8 @@ func (h *Handler) CompareMice(c *gin.Context) {
 		errors.HandleError(c, errors.NewBadRequestError("至少需要两个鼠标ID"))
 		return
 	}
-	if len(mouseIDs) > 3 {
-		errors.HandleError(c, errors.NewBadRequestError("最多只能比较三个鼠标ID"))
+	if len(mouseIDs) > models.MaxComparisonDevices {
+		errors.HandleError(c, errors.NewBadRequestError(fmt.Sprintf("最多只能比较%d个鼠标ID", models.MaxComparisonDevices)))
 		return
 	}
 
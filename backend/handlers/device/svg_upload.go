@@ -0,0 +1,119 @@
+package device
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	"project/backend/internal/upload"
+	deviceTypes "project/backend/types/device"
+)
+
+// UploadSVGBatch 批量上传鼠标SVG轮廓（支持多个svg文件或zip压缩包）
+// 返回校验和匹配结果，需要调用确认接口后才会写入设备
+func (h *Handler) UploadSVGBatch(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		errors.HandleError(c, errors.NewUnauthorizedError("未授权访问"))
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的上传请求: "+err.Error()))
+		return
+	}
+
+	headers := form.File["files"]
+	if len(headers) == 0 {
+		errors.HandleError(c, errors.NewBadRequestError("请选择要上传的SVG文件"))
+		return
+	}
+
+	cfg := upload.SVGConfig
+	var files []deviceTypes.SVGUploadFile
+	for _, header := range headers {
+		if err := upload.CheckFileWithConfig(header, cfg); err != nil {
+			errors.HandleError(c, errors.NewBadRequestError(fmt.Sprintf("%s: %v", header.Filename, err)))
+			return
+		}
+
+		if strings.ToLower(path.Ext(header.Filename)) == ".zip" {
+			entries, err := upload.ReadZip(header, cfg, []string{".svg"})
+			if err != nil {
+				errors.HandleError(c, errors.NewBadRequestError(fmt.Sprintf("%s: %v", header.Filename, err)))
+				return
+			}
+			for _, entry := range entries {
+				files = append(files, deviceTypes.SVGUploadFile{Name: entry.Name, Content: entry.Content})
+			}
+		} else {
+			content, err := upload.ReadFile(header, cfg)
+			if err != nil {
+				errors.HandleError(c, errors.NewBadRequestError(fmt.Sprintf("%s: %v", header.Filename, err)))
+				return
+			}
+			files = append(files, deviceTypes.SVGUploadFile{Name: path.Base(header.Filename), Content: content})
+		}
+
+		if len(files) > cfg.MaxFiles {
+			errors.HandleError(c, errors.NewBadRequestError(fmt.Sprintf("单次最多上传%d个SVG文件", cfg.MaxFiles)))
+			return
+		}
+	}
+
+	result, err := h.deviceService.PreviewSVGUpload(c.Request.Context(), userID, files)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "上传成功，请确认匹配结果",
+		"data":    result,
+	})
+}
+
+// GetSVGUploadBatch 获取SVG上传批次的匹配结果
+func (h *Handler) GetSVGUploadBatch(c *gin.Context) {
+	result, err := h.deviceService.GetSVGUploadBatch(c.Request.Context(), c.Param("batchId"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// ConfirmSVGUpload 确认SVG上传批次并写入鼠标
+func (h *Handler) ConfirmSVGUpload(c *gin.Context) {
+	var request deviceTypes.ConfirmSVGUploadRequest
+	// 请求体可以为空，表示接受全部自动匹配结果
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+			return
+		}
+	}
+
+	result, err := h.deviceService.ConfirmSVGUpload(c.Request.Context(), c.GetString("userId"), c.Param("batchId"), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
@@ -0,0 +1,60 @@
+package device
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	deviceTypes "project/backend/types/device"
+)
+
+// ListDeletedDevices 获取回收站中的设备
+func (h *Handler) ListDeletedDevices(c *gin.Context) {
+	var request deviceTypes.DeviceTrashListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	result, err := h.deviceService.ListDeletedDevices(c.Request.Context(), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// RestoreDevice 从回收站恢复设备
+func (h *Handler) RestoreDevice(c *gin.Context) {
+	if err := h.deviceService.RestoreDevice(c.Request.Context(), c.Param("id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "恢复成功",
+		"data":    nil,
+	})
+}
+
+// PurgeDevice 彻底删除回收站中的设备
+func (h *Handler) PurgeDevice(c *gin.Context) {
+	result, err := h.deviceService.PurgeDevice(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "已彻底删除",
+		"data":    result,
+	})
+}
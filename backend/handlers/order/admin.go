@@ -0,0 +1,209 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	orderTypes "project/backend/types/order"
+)
+
+// SearchOrders 管理员查询订单
+func (h *Handler) SearchOrders(c *gin.Context) {
+	var req orderTypes.AdminOrderListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的查询参数"))
+		return
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 10
+	}
+
+	orders, total, err := h.orderService.SearchOrders(c.Request.Context(), req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	response := orderTypes.AdminOrderListResponse{
+		Orders:      make([]orderTypes.AdminOrderResponse, 0, len(orders)),
+		TotalCount:  total,
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}
+	for i := range orders {
+		response.Orders = append(response.Orders, convertToAdminOrderResponse(&orders[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOrderAdmin 管理员获取任意用户的订单详情
+func (h *Handler) GetOrderAdmin(c *gin.Context) {
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	orderObj, err := h.orderService.GetOrderByID(c.Request.Context(), orderID)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, convertToAdminOrderResponse(orderObj))
+}
+
+// BulkUpdateStatus 管理员批量变更订单状态
+func (h *Handler) BulkUpdateStatus(c *gin.Context) {
+	var req orderTypes.BulkUpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	// 验证状态
+	if !isValidOrderStatus(models.OrderStatusEnum(req.Status)) {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单状态"))
+		return
+	}
+
+	result, err := h.orderService.BulkUpdateStatus(c.Request.Context(), req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateShipment 管理员填写物流信息
+func (h *Handler) UpdateShipment(c *gin.Context) {
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	var req orderTypes.UpdateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	orderObj, err := h.orderService.UpdateShipment(c.Request.Context(), orderID, req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, convertToAdminOrderResponse(orderObj))
+}
+
+// AddOrderNote 管理员添加内部备注
+func (h *Handler) AddOrderNote(c *gin.Context) {
+	// 获取备注人ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	authorID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	var req orderTypes.AddOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	orderObj, err := h.orderService.AddOrderNote(c.Request.Context(), authorID, orderID, req.Content)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, convertToAdminOrderResponse(orderObj))
+}
+
+// OrderStats 管理员查看营收、订单数和客单价统计
+func (h *Handler) OrderStats(c *gin.Context) {
+	var req orderTypes.OrderStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的查询参数"))
+		return
+	}
+
+	stats, err := h.orderService.OrderStats(c.Request.Context(), req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// convertToAdminOrderResponse 转换为包含内部备注的订单响应
+func convertToAdminOrderResponse(order *models.Order) orderTypes.AdminOrderResponse {
+	response := orderTypes.AdminOrderResponse{
+		OrderResponse: convertToOrderResponse(order),
+		AdminNotes:    make([]orderTypes.OrderNoteResponse, 0, len(order.AdminNotes)),
+	}
+	for _, note := range order.AdminNotes {
+		response.AdminNotes = append(response.AdminNotes, orderTypes.OrderNoteResponse{
+			ID:        note.ID.Hex(),
+			AuthorID:  note.AuthorID.Hex(),
+			Content:   note.Content,
+			CreatedAt: note.CreatedAt,
+		})
+	}
+	return response
+}
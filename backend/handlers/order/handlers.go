@@ -321,13 +321,18 @@ func convertToOrderResponse(order *models.Order) orderTypes.OrderResponse {
 	items := make([]orderTypes.OrderItemResponse, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = orderTypes.OrderItemResponse{
-			ProductID:   item.ProductID.Hex(),
-			ProductType: item.ProductType,
-			Name:        item.Name,
-			Price:       item.Price,
-			Quantity:    item.Quantity,
-			Subtotal:    item.Subtotal,
-			ImageURL:    item.ImageURL,
+			ProductID:        item.ProductID.Hex(),
+			SKUID:            item.SKUID.Hex(),
+			SKUCode:          item.SKUCode,
+			Variant:          item.Variant,
+			ProductType:      item.ProductType,
+			Name:             item.Name,
+			Price:            item.Price,
+			Quantity:         item.Quantity,
+			Subtotal:         item.Subtotal,
+			Discount:         item.Discount,
+			RefundedQuantity: item.RefundedQuantity,
+			ImageURL:         item.ImageURL,
 		}
 	}
 
@@ -342,6 +347,8 @@ func convertToOrderResponse(order *models.Order) orderTypes.OrderResponse {
 		ZipCode:        order.ShippingInfo.ZipCode,
 		Country:        order.ShippingInfo.Country,
 		ShippingMethod: order.ShippingInfo.ShippingMethod,
+		Carrier:        order.ShippingInfo.Carrier,
+		TrackingNumber: order.ShippingInfo.TrackingNumber,
 	}
 
 	// 转换支付信息
@@ -352,27 +359,39 @@ func convertToOrderResponse(order *models.Order) orderTypes.OrderResponse {
 		PaymentStatus:   order.PaymentInfo.PaymentStatus,
 		PaymentProvider: order.PaymentInfo.PaymentProvider,
 	}
+	for _, refund := range order.PaymentInfo.Refunds {
+		paymentInfo.Refunds = append(paymentInfo.Refunds, orderTypes.PaymentRefundResponse{
+			RefundID:         refund.RefundID.Hex(),
+			ProviderRefundID: refund.ProviderRefundID,
+			Amount:           refund.Amount,
+			RefundedAt:       refund.RefundedAt,
+		})
+	}
 
 	return orderTypes.OrderResponse{
-		ID:           order.ID.Hex(),
-		UserID:       order.UserID.Hex(),
-		OrderNumber:  order.OrderNumber,
-		Status:       string(order.Status),
-		Items:        items,
-		ShippingInfo: shippingInfo,
-		PaymentInfo:  paymentInfo,
-		Subtotal:     order.Subtotal,
-		ShippingFee:  order.ShippingFee,
-		Tax:          order.Tax,
-		Discount:     order.Discount,
-		Total:        order.Total,
-		Notes:        order.Notes,
-		CreatedAt:    order.CreatedAt,
-		UpdatedAt:    order.UpdatedAt,
-		PaidAt:       order.PaidAt,
-		ShippedAt:    order.ShippedAt,
-		DeliveredAt:  order.DeliveredAt,
-		CancelledAt:  order.CancelledAt,
-		CancelReason: order.CancelReason,
+		ID:             order.ID.Hex(),
+		UserID:         order.UserID.Hex(),
+		OrderNumber:    order.OrderNumber,
+		Status:         string(order.Status),
+		Items:          items,
+		ShippingInfo:   shippingInfo,
+		PaymentInfo:    paymentInfo,
+		Subtotal:       order.Subtotal,
+		ShippingFee:    order.ShippingFee,
+		Tax:            order.Tax,
+		TaxInclusive:   order.TaxInclusive,
+		Discount:       order.Discount,
+		CouponCode:     order.CouponCode,
+		Total:          order.Total,
+		Currency:       order.Currency,
+		RefundedAmount: order.RefundedAmount,
+		Notes:          order.Notes,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+		PaidAt:         order.PaidAt,
+		ShippedAt:      order.ShippedAt,
+		DeliveredAt:    order.DeliveredAt,
+		CancelledAt:    order.CancelledAt,
+		CancelReason:   order.CancelReason,
 	}
 }
@@ -0,0 +1,82 @@
+package order
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/middleware"
+	"project/backend/models"
+	"project/backend/services/invoice"
+)
+
+// DownloadInvoice 下载自己订单的发票
+func (h *Handler) DownloadInvoice(c *gin.Context) {
+	h.downloadDocument(c, invoice.KindInvoice, false)
+}
+
+// DownloadPackingSlip 下载自己订单的发货单
+func (h *Handler) DownloadPackingSlip(c *gin.Context) {
+	h.downloadDocument(c, invoice.KindPackingSlip, false)
+}
+
+// DownloadInvoiceAdmin 管理员下载任意订单的发票
+func (h *Handler) DownloadInvoiceAdmin(c *gin.Context) {
+	h.downloadDocument(c, invoice.KindInvoice, true)
+}
+
+// DownloadPackingSlipAdmin 管理员下载任意订单的发货单
+func (h *Handler) DownloadPackingSlipAdmin(c *gin.Context) {
+	h.downloadDocument(c, invoice.KindPackingSlip, true)
+}
+
+// downloadDocument 渲染订单文档，语言优先使用lang参数，其次为请求的语言
+func (h *Handler) downloadDocument(c *gin.Context, kind string, admin bool) {
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	var orderObj *models.Order
+	if admin {
+		orderObj, err = h.orderService.GetOrderByID(c.Request.Context(), orderID)
+	} else {
+		userIDStr, exists := c.Get("userId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+			return
+		}
+		userID, parseErr := primitive.ObjectIDFromHex(userIDStr.(string))
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+			return
+		}
+		orderObj, err = h.orderService.GetOrder(c.Request.Context(), userID, orderID)
+	}
+
+	var data []byte
+	if err == nil {
+		locale := c.Query("lang")
+		if locale == "" {
+			locale = middleware.GetLanguage(c)
+		}
+		data, err = h.orderService.RenderDocument(orderObj, kind, locale)
+	}
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.FileName(kind, orderObj)))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
@@ -0,0 +1,76 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	orderTypes "project/backend/types/order"
+)
+
+// CreatePaymentIntent 为订单创建支付意图
+func (h *Handler) CreatePaymentIntent(c *gin.Context) {
+	// 获取用户ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	// 解析请求
+	var req orderTypes.PaymentIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	intent, err := h.orderService.CreatePaymentIntent(c.Request.Context(), userID, orderID, req.Provider)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// PaymentWebhook 接收支付渠道回调
+// 需要读取原始请求体做签名校验，不能先按JSON绑定
+func (h *Handler) PaymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	err = h.orderService.HandlePaymentWebhook(c.Request.Context(), c.Param("provider"), payload, c.Request.Header)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
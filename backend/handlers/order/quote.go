@@ -0,0 +1,46 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	orderTypes "project/backend/types/order"
+)
+
+// QuoteOrder 下单前试算订单金额，返回税费和运费明细
+func (h *Handler) QuoteOrder(c *gin.Context) {
+	// 获取用户ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	// 解析请求
+	var req orderTypes.QuoteOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	quote, err := h.orderService.QuoteOrder(c.Request.Context(), userID, req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
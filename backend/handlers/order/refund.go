@@ -0,0 +1,218 @@
+package order
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	orderTypes "project/backend/types/order"
+)
+
+// RequestRefund 申请退款
+func (h *Handler) RequestRefund(c *gin.Context) {
+	// 获取用户ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	// 解析请求
+	var req orderTypes.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+		return
+	}
+
+	refund, err := h.orderService.RequestRefund(c.Request.Context(), userID, orderID, req)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, convertToRefundResponse(refund))
+}
+
+// ListOrderRefunds 获取订单的退款记录
+func (h *Handler) ListOrderRefunds(c *gin.Context) {
+	// 获取用户ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	// 获取订单ID
+	orderID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的订单ID"))
+		return
+	}
+
+	refunds, err := h.orderService.ListOrderRefunds(c.Request.Context(), userID, orderID)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	response := make([]orderTypes.RefundResponse, 0, len(refunds))
+	for i := range refunds {
+		response = append(response, convertToRefundResponse(&refunds[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListRefunds 管理员获取退款列表
+func (h *Handler) ListRefunds(c *gin.Context) {
+	var req orderTypes.RefundListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的查询参数"))
+		return
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	refunds, total, err := h.orderService.ListRefunds(c.Request.Context(), req.Status, req.Page, req.PageSize)
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	response := orderTypes.RefundListResponse{
+		Refunds:     make([]orderTypes.RefundResponse, 0, len(refunds)),
+		TotalCount:  total,
+		CurrentPage: req.Page,
+		PageSize:    req.PageSize,
+	}
+	for i := range refunds {
+		response.Refunds = append(response.Refunds, convertToRefundResponse(&refunds[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveRefund 管理员批准退款
+func (h *Handler) ApproveRefund(c *gin.Context) {
+	h.reviewRefund(c, true)
+}
+
+// RejectRefund 管理员拒绝退款
+func (h *Handler) RejectRefund(c *gin.Context) {
+	h.reviewRefund(c, false)
+}
+
+func (h *Handler) reviewRefund(c *gin.Context, approve bool) {
+	// 获取审核人ID
+	userIDStr, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewAppError(errors.Unauthorized, "未授权访问"))
+		return
+	}
+	reviewerID, err := primitive.ObjectIDFromHex(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的用户ID"))
+		return
+	}
+
+	refundID, err := primitive.ObjectIDFromHex(c.Param("refundId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的退款ID"))
+		return
+	}
+
+	// 请求体可以为空
+	var req orderTypes.ReviewRefundRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "无效的请求数据"))
+			return
+		}
+	}
+
+	var refund *models.Refund
+	if approve {
+		refund, err = h.orderService.ApproveRefund(c.Request.Context(), reviewerID, refundID, req)
+	} else {
+		refund, err = h.orderService.RejectRefund(c.Request.Context(), reviewerID, refundID, req)
+	}
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if ok {
+			c.JSON(appErr.HTTPStatus(), appErr)
+		} else {
+			c.JSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, convertToRefundResponse(refund))
+}
+
+// convertToRefundResponse 将退款模型转换为响应
+func convertToRefundResponse(refund *models.Refund) orderTypes.RefundResponse {
+	items := make([]orderTypes.RefundItemResponse, 0, len(refund.Items))
+	for _, item := range refund.Items {
+		items = append(items, orderTypes.RefundItemResponse{
+			SKUID:    item.SKUID.Hex(),
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Amount:   item.Amount,
+		})
+	}
+
+	return orderTypes.RefundResponse{
+		ID:               refund.ID.Hex(),
+		OrderID:          refund.OrderID.Hex(),
+		UserID:           refund.UserID.Hex(),
+		Items:            items,
+		Amount:           refund.Amount,
+		Currency:         refund.Currency,
+		Reason:           refund.Reason,
+		Status:           string(refund.Status),
+		Restock:          refund.Restock,
+		ProviderRefundID: refund.ProviderRefundID,
+		FailureReason:    refund.FailureReason,
+		ReviewNote:       refund.ReviewNote,
+		CreatedAt:        refund.CreatedAt,
+		ReviewedAt:       refund.ReviewedAt,
+		RefundedAt:       refund.RefundedAt,
+	}
+}
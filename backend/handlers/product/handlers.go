@@ -0,0 +1,109 @@
+package product
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	productService "project/backend/services/product"
+	productTypes "project/backend/types/product"
+)
+
+// Handler 商品目录处理器
+type Handler struct {
+	productService productService.Service
+}
+
+// NewHandler 创建商品目录处理器
+func NewHandler(productService productService.Service) *Handler {
+	return &Handler{
+		productService: productService,
+	}
+}
+
+// ListDeviceSKUs 获取设备的在售SKU
+func (h *Handler) ListDeviceSKUs(c *gin.Context) {
+	result, err := h.productService.ListDeviceSKUs(c.Request.Context(), c.Param("id"), false)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// ListAllDeviceSKUs 获取设备的全部SKU，包括已下架的
+func (h *Handler) ListAllDeviceSKUs(c *gin.Context) {
+	result, err := h.productService.ListDeviceSKUs(c.Request.Context(), c.Param("id"), true)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// CreateSKU 创建SKU
+func (h *Handler) CreateSKU(c *gin.Context) {
+	var request productTypes.CreateSKURequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	sku, err := h.productService.CreateSKU(c.Request.Context(), c.Param("id"), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    sku,
+	})
+}
+
+// UpdateSKU 更新SKU的价格、库存或规格
+func (h *Handler) UpdateSKU(c *gin.Context) {
+	var request productTypes.UpdateSKURequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	sku, err := h.productService.UpdateSKU(c.Request.Context(), c.Param("id"), c.Param("skuId"), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    sku,
+	})
+}
+
+// DeleteSKU 删除SKU
+func (h *Handler) DeleteSKU(c *gin.Context) {
+	if err := h.productService.DeleteSKU(c.Request.Context(), c.Param("id"), c.Param("skuId")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除成功",
+		"data":    nil,
+	})
+}
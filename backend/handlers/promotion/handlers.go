@@ -0,0 +1,94 @@
+package promotion
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	promotionService "project/backend/services/promotion"
+	promotionTypes "project/backend/types/promotion"
+)
+
+// Handler 优惠券管理处理器
+type Handler struct {
+	promotionService promotionService.Service
+}
+
+// NewHandler 创建优惠券管理处理器
+func NewHandler(promotionService promotionService.Service) *Handler {
+	return &Handler{
+		promotionService: promotionService,
+	}
+}
+
+// ListCoupons 获取优惠券列表
+func (h *Handler) ListCoupons(c *gin.Context) {
+	result, err := h.promotionService.ListCoupons(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// CreateCoupon 创建优惠券
+func (h *Handler) CreateCoupon(c *gin.Context) {
+	var request promotionTypes.CreateCouponRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	coupon, err := h.promotionService.CreateCoupon(c.Request.Context(), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    coupon,
+	})
+}
+
+// UpdateCoupon 更新优惠券
+func (h *Handler) UpdateCoupon(c *gin.Context) {
+	var request promotionTypes.UpdateCouponRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		errors.HandleError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	coupon, err := h.promotionService.UpdateCoupon(c.Request.Context(), c.Param("id"), request)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    coupon,
+	})
+}
+
+// DeleteCoupon 删除未使用过的优惠券
+func (h *Handler) DeleteCoupon(c *gin.Context) {
+	if err := h.promotionService.DeleteCoupon(c.Request.Context(), c.Param("id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除成功",
+		"data":    nil,
+	})
+}
@@ -0,0 +1,203 @@
+package review
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	"project/backend/middleware"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// 旧版 /device-reviews 接口，保持原有的请求和响应格式，内部转为统一的评测服务
+
+// CreateDeviceReview 创建设备评测
+func (h *Handler) CreateDeviceReview(c *gin.Context) {
+	var request review.CreateDeviceReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondDeviceError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		respondDeviceError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	createRequest := review.CreateReviewRequest{
+		ExternalItemID: request.DeviceID,
+		ItemType:       models.ReviewItemTypeDevice,
+		Content:        request.Content,
+		Pros:           request.Pros,
+		Cons:           request.Cons,
+		Score:          request.Score,
+		Usage:          request.Usage,
+		Type:           request.Type,
+		ContentType:    request.ContentType,
+	}
+	sanitizeCreateRequest(&createRequest)
+	createRequest.Locale = c.GetString(middleware.I18nKey)
+
+	result, err := h.service.CreateReview(c.Request.Context(), userID.(string), createRequest)
+	if err != nil {
+		respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    mapDeviceReviewToResponse(mapReviewToResponse(result)),
+	})
+}
+
+// GetDeviceReview 获取设备评测详情
+func (h *Handler) GetDeviceReview(c *gin.Context) {
+	reviewID := c.Param("id")
+
+	result, err := h.service.GetReview(c.Request.Context(), reviewID)
+	if err == nil && result.ItemType != models.ReviewItemTypeDevice {
+		err = errors.NewNotFoundError("评测不存在")
+	}
+	if err != nil {
+		respondDeviceError(c, err)
+		return
+	}
+
+	_ = h.service.IncrementViewCount(c.Request.Context(), reviewID, viewVisitor(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    mapDeviceReviewToResponse(mapReviewToResponse(result)),
+	})
+}
+
+// UpdateDeviceReview 更新设备评测
+func (h *Handler) UpdateDeviceReview(c *gin.Context) {
+	var request review.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondDeviceError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		respondDeviceError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	sanitizeUpdateRequest(&request)
+
+	result, err := h.service.UpdateReview(c.Request.Context(), userID.(string), c.Param("id"), request)
+	if err != nil {
+		respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    mapDeviceReviewToResponse(mapReviewToResponse(result)),
+	})
+}
+
+// DeleteDeviceReview 删除设备评测
+func (h *Handler) DeleteDeviceReview(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		respondDeviceError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	if err := h.service.DeleteReview(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    nil,
+	})
+}
+
+// ListDeviceReviews 获取已发布的设备评测列表
+func (h *Handler) ListDeviceReviews(c *gin.Context) {
+	var request review.DeviceReviewListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		respondDeviceError(c, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.ListReviews(c.Request.Context(), review.ReviewListRequest{
+		ExternalItemID: request.DeviceID,
+		ItemType:       models.ReviewItemTypeDevice,
+		UserID:         request.UserID,
+		Page:           request.Page,
+		PageSize:       request.PageSize,
+		SortBy:         request.SortBy,
+		SortOrder:      request.SortOrder,
+	})
+	if err != nil {
+		respondDeviceError(c, err)
+		return
+	}
+
+	response := review.DeviceReviewListResponse{
+		Total:    result.Total,
+		Page:     result.Page,
+		PageSize: result.PageSize,
+		Reviews:  make([]review.DeviceReviewResponse, len(result.Reviews)),
+	}
+	for i, r := range result.Reviews {
+		response.Reviews[i] = mapDeviceReviewToResponse(r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    response,
+	})
+}
+
+// respondDeviceError 以旧接口的格式返回错误
+func respondDeviceError(c *gin.Context, err error) {
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		appErr = errors.NewInternalServerError("服务器内部错误")
+	}
+	c.JSON(appErr.HTTPStatus(), gin.H{
+		"code":    appErr.Code,
+		"message": appErr.Error(),
+		"data":    nil,
+	})
+}
+
+// mapDeviceReviewToResponse 将评测响应转换为旧版设备评测响应
+func mapDeviceReviewToResponse(r review.ReviewResponse) review.DeviceReviewResponse {
+	return review.DeviceReviewResponse{
+		ID:            r.ID,
+		DeviceID:      r.ExternalItemID,
+		UserID:        r.UserID,
+		Content:       r.Content,
+		Pros:          r.Pros,
+		Cons:          r.Cons,
+		Score:         r.Score,
+		Usage:         r.Usage,
+		Status:        r.Status,
+		Type:          r.Type,
+		ContentType:   r.ContentType,
+		ReviewerID:    r.ReviewerID,
+		ReviewerNotes: r.ReviewerNotes,
+		ReviewedAt:    r.ReviewedAt,
+		PublishedAt:   r.PublishedAt,
+		FeaturedRank:  r.FeaturedRank,
+		ViewCount:     r.ViewCount,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
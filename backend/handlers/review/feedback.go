@@ -0,0 +1,169 @@
+package review
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	"project/backend/middleware"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// VoteReview 投票评测是否有帮助
+func (h *Handler) VoteReview(c *gin.Context) {
+	var request review.VoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	result, err := h.service.VoteReview(c.Request.Context(), userID.(string), c.Param("id"), *request.Helpful)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RemoveVote 撤销投票
+func (h *Handler) RemoveVote(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	result, err := h.service.RemoveVote(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateComment 发表评论或回复
+func (h *Handler) CreateComment(c *gin.Context) {
+	var request review.CreateCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	request.Content = middleware.SanitizeText(request.Content)
+
+	result, err := h.service.CreateComment(c.Request.Context(), userID.(string), c.Param("id"), request)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapCommentToResponse(result))
+}
+
+// ListComments 获取评测的评论
+func (h *Handler) ListComments(c *gin.Context) {
+	var request struct {
+		Page     int `form:"page" binding:"omitempty,min=1"`
+		PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.ListComments(c.Request.Context(), c.Param("id"), request.Page, request.PageSize)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteComment 删除自己的评论
+func (h *Handler) DeleteComment(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	if err := h.service.DeleteComment(c.Request.Context(), userID.(string), c.Param("commentId")); err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HideComment 隐藏评论
+func (h *Handler) HideComment(c *gin.Context) {
+	var request review.ModerateCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	moderatorID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	if err := h.service.HideComment(c.Request.Context(), moderatorID.(string), c.Param("commentId"), middleware.SanitizeText(request.Note)); err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreComment 恢复被隐藏的评论
+func (h *Handler) RestoreComment(c *gin.Context) {
+	moderatorID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	if err := h.service.RestoreComment(c.Request.Context(), moderatorID.(string), c.Param("commentId")); err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func mapCommentToResponse(comment *models.ReviewComment) review.CommentResponse {
+	response := review.CommentResponse{
+		ID:        comment.ID.Hex(),
+		ReviewID:  comment.ReviewID.Hex(),
+		UserID:    comment.UserID.Hex(),
+		Depth:     comment.Depth,
+		Content:   comment.Content,
+		Status:    string(comment.Status),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Replies:   []*review.CommentResponse{},
+	}
+	if comment.ParentID != nil {
+		response.ParentID = comment.ParentID.Hex()
+	}
+	return response
+}
@@ -4,8 +4,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"project/backend/internal/errors"
+	"project/backend/middleware"
 	"project/backend/models"
 	reviewService "project/backend/services/review"
+	"project/backend/services/viewcount"
 	"project/backend/types/review"
 )
 
@@ -41,6 +43,9 @@ func (h *Handler) CreateReview(c *gin.Context) {
 		return
 	}
 
+	sanitizeCreateRequest(&request)
+	request.Locale = c.GetString(middleware.I18nKey)
+
 	result, err := h.service.CreateReview(c.Request.Context(), userID.(string), request)
 	if err != nil {
 		c.JSON(errors.HTTPStatusFromError(err), err)
@@ -64,7 +69,7 @@ func (h *Handler) GetReview(c *gin.Context) {
 	}
 
 	// 增加查看次数
-	_ = h.service.IncrementViewCount(c.Request.Context(), reviewID)
+	_ = h.service.IncrementViewCount(c.Request.Context(), reviewID, viewVisitor(c))
 
 	// 构建响应
 	response := mapReviewToResponse(result)
@@ -88,6 +93,8 @@ func (h *Handler) UpdateReview(c *gin.Context) {
 		return
 	}
 
+	sanitizeUpdateRequest(&request)
+
 	result, err := h.service.UpdateReview(c.Request.Context(), userID.(string), reviewID, request)
 	if err != nil {
 		c.JSON(errors.HTTPStatusFromError(err), err)
@@ -136,6 +143,95 @@ func (h *Handler) ListReviews(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetFeaturedReviews 获取推荐评测，可按评测类型筛选
+func (h *Handler) GetFeaturedReviews(c *gin.Context) {
+	var request struct {
+		Type  string `form:"type" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory game software"`
+		Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.GetFeaturedReviews(c.Request.Context(), request.Type, request.Limit)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTrendingReviews 获取本周热门评测，可按评测类型筛选
+func (h *Handler) GetTrendingReviews(c *gin.Context) {
+	var request struct {
+		Type  string `form:"type" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory game software"`
+		Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.GetTrendingReviews(c.Request.Context(), request.Type, request.Limit)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// viewVisitor 从请求中取出浏览计数需要的访客信息
+func viewVisitor(c *gin.Context) viewcount.Visitor {
+	return viewcount.Visitor{
+		UserID:    c.GetString("userId"),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// ListDeletedReviews 获取回收站中的评测
+func (h *Handler) ListDeletedReviews(c *gin.Context) {
+	var request struct {
+		Page     int `form:"page" binding:"omitempty,min=1"`
+		PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.ListDeletedReviews(c.Request.Context(), request.Page, request.PageSize)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestoreReview 从回收站恢复评测
+func (h *Handler) RestoreReview(c *gin.Context) {
+	if err := h.service.RestoreReview(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PurgeReview 彻底删除回收站中的评测
+func (h *Handler) PurgeReview(c *gin.Context) {
+	if err := h.service.PurgeReview(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetUserReviewStats 获取用户评测统计
 func (h *Handler) GetUserReviewStats(c *gin.Context) {
 	userID := c.Param("id")
@@ -160,12 +256,13 @@ func (h *Handler) GetUserReviewStats(c *gin.Context) {
 
 // GetPendingReviews 获取待审核评测列表
 func (h *Handler) GetPendingReviews(c *gin.Context) {
-	// 从查询参数中获取筛选条件
-	reviewType := c.Query("type")
-	page := 1
-	pageSize := 10
+	var request review.PendingReviewListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
 
-	result, err := h.service.GetPendingReviews(c.Request.Context(), reviewType, page, pageSize)
+	result, err := h.service.GetPendingReviews(c.Request.Context(), request)
 	if err != nil {
 		c.JSON(errors.HTTPStatusFromError(err), err)
 		return
@@ -257,24 +354,66 @@ func (h *Handler) FeaturedReview(c *gin.Context) {
 
 // 辅助方法
 
+// sanitizeCreateRequest 对用户输入进行消毒，防止存储型XSS
+// 正文由服务按格式处理：纯文本转义，Markdown渲染时转义并按白名单清洗
+func sanitizeCreateRequest(request *review.CreateReviewRequest) {
+	request.Usage = middleware.SanitizeText(request.Usage)
+	for i := range request.Pros {
+		request.Pros[i] = middleware.SanitizeText(request.Pros[i])
+	}
+	for i := range request.Cons {
+		request.Cons[i] = middleware.SanitizeText(request.Cons[i])
+	}
+}
+
+// sanitizeUpdateRequest 对用户输入进行消毒，防止存储型XSS，正文同sanitizeCreateRequest
+func sanitizeUpdateRequest(request *review.UpdateReviewRequest) {
+	if request.Usage != nil {
+		sanitized := middleware.SanitizeText(*request.Usage)
+		request.Usage = &sanitized
+	}
+	if request.Pros != nil {
+		pros := *request.Pros
+		for i := range pros {
+			pros[i] = middleware.SanitizeText(pros[i])
+		}
+	}
+	if request.Cons != nil {
+		cons := *request.Cons
+		for i := range cons {
+			cons[i] = middleware.SanitizeText(cons[i])
+		}
+	}
+}
+
 // mapReviewToResponse 将Review模型转换为ReviewResponse
 func mapReviewToResponse(r *models.Review) review.ReviewResponse {
 	response := review.ReviewResponse{
-		ID:             r.ID.Hex(),
-		ExternalItemID: r.ExternalItemID.Hex(),
-		ItemType:       r.ItemType,
-		UserID:         r.UserID.Hex(),
-		Content:        r.Content,
-		Pros:           r.Pros,
-		Cons:           r.Cons,
-		Score:          r.Score,
-		Usage:          r.Usage,
-		Status:         string(r.Status),
-		Type:           string(r.Type),
-		ContentType:    string(r.ContentType),
-		ViewCount:      r.ViewCount,
-		CreatedAt:      r.CreatedAt,
-		UpdatedAt:      r.UpdatedAt,
+		ID:              r.ID.Hex(),
+		ExternalItemID:  r.ExternalItemID.Hex(),
+		ItemType:        r.ItemType,
+		UserID:          r.UserID.Hex(),
+		Content:         r.Content,
+		ContentFormat:   string(r.Format()),
+		ContentHTML:     r.ContentHTML,
+		Excerpt:         r.Excerpt,
+		Pros:            r.Pros,
+		Cons:            r.Cons,
+		Score:           r.Score,
+		Usage:           r.Usage,
+		Images:          reviewService.MapImagesToResponse(r),
+		Status:          string(r.Status),
+		Type:            string(r.Type),
+		ContentType:     string(r.ContentType),
+		Comparison:      reviewService.MapComparisonToResponse(r),
+		ViewCount:       r.ViewCount,
+		HelpfulCount:    r.HelpfulCount,
+		UnhelpfulCount:  r.UnhelpfulCount,
+		HelpfulScore:    r.HelpfulScore,
+		CommentCount:    r.CommentCount,
+		PendingRevision: r.PendingRevision,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
 	}
 
 	if r.ReviewerID != nil {
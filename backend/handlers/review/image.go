@@ -0,0 +1,50 @@
+package review
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	"project/backend/internal/upload"
+	"project/backend/types/review"
+)
+
+// UploadReviewImage 上传评测图片，返回的图片ID在创建或更新评测时通过imageIds关联
+func (h *Handler) UploadReviewImage(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("请选择要上传的图片"))
+		return
+	}
+	// 扩展名只做初步过滤，实际类型由服务按内容识别
+	if err := upload.CheckFileWithConfig(header, upload.ImageConfig); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+	content, err := upload.ReadFile(header, upload.ImageConfig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	image, err := h.service.UploadReviewImage(c.Request.Context(), userID.(string), content)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review.ReviewImageResponse{
+		ID:           image.ID.Hex(),
+		URL:          image.URL,
+		ThumbnailURL: image.ThumbnailURL,
+		Width:        image.Width,
+		Height:       image.Height,
+	})
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// GetPendingRevisionReviews 获取有待审核修改的已发布评测
+func (h *Handler) GetPendingRevisionReviews(c *gin.Context) {
+	var request review.ReviewRevisionListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.GetPendingRevisionReviews(c.Request.Context(), request.Page, request.PageSize)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListReviewRevisions 获取评测修改历史
+func (h *Handler) ListReviewRevisions(c *gin.Context) {
+	var request review.ReviewRevisionListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.ListReviewRevisions(c.Request.Context(), c.Param("id"), request.Page, request.PageSize)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DiffReviewRevisions 对比评测的两个版本，默认对比待审核的修改和公开内容
+func (h *Handler) DiffReviewRevisions(c *gin.Context) {
+	var request review.ReviewRevisionDiffRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求参数: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.DiffReviewRevisions(c.Request.Context(), c.Param("id"), request.From, request.To)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ApproveRevision 通过评测的修改
+func (h *Handler) ApproveRevision(c *gin.Context) {
+	h.resolveRevision(c, h.service.ApproveRevision)
+}
+
+// RejectRevision 拒绝评测的修改
+func (h *Handler) RejectRevision(c *gin.Context) {
+	h.resolveRevision(c, h.service.RejectRevision)
+}
+
+func (h *Handler) resolveRevision(c *gin.Context, resolve func(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error)) {
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的版本号"))
+		return
+	}
+
+	var request review.ReviewerActionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	moderatorID, exists := c.Get("userId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	result, err := resolve(c.Request.Context(), moderatorID.(string), c.Param("id"), revision, request.Notes)
+	if err != nil {
+		c.JSON(errors.HTTPStatusFromError(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapReviewToResponse(result))
+}
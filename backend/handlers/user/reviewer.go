@@ -0,0 +1,123 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"project/backend/internal/errors"
+	userTypes "project/backend/types/user"
+)
+
+// ApplyForReviewer 申请成为评测员
+func (h *Handler) ApplyForReviewer(c *gin.Context) {
+	userID, exists := c.Get("userId")
+	if !exists {
+		respondError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	var req userTypes.ReviewerApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, errors.NewBadRequestError("无效的请求: "+err.Error()))
+		return
+	}
+
+	if err := h.service.ApplyForReviewer(c.Request.Context(), userID.(string), req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    0,
+		"message": "申请已提交，请等待审核",
+		"data":    nil,
+	})
+}
+
+// GetReviewerApplications 管理员查看评测员申请
+func (h *Handler) GetReviewerApplications(c *gin.Context) {
+	var req userTypes.ReviewerApplicationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, errors.NewBadRequestError("无效的查询参数"))
+		return
+	}
+
+	result, _, err := h.service.GetReviewerApplications(c.Request.Context(), req.Status, req.Page, req.PageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "成功",
+		"data":    result,
+	})
+}
+
+// ApproveReviewerApplication 管理员批准评测员申请
+func (h *Handler) ApproveReviewerApplication(c *gin.Context) {
+	adminID, exists := c.Get("userId")
+	if !exists {
+		respondError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	if err := h.service.ApproveReviewerApplication(c.Request.Context(), c.Param("userId"), adminID.(string)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "已批准评测员申请",
+		"data":    nil,
+	})
+}
+
+// RejectReviewerApplication 管理员拒绝评测员申请
+func (h *Handler) RejectReviewerApplication(c *gin.Context) {
+	adminID, exists := c.Get("userId")
+	if !exists {
+		respondError(c, errors.NewUnauthorizedError("用户未认证"))
+		return
+	}
+
+	var req userTypes.RejectReviewerApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, errors.NewBadRequestError("请填写拒绝原因"))
+		return
+	}
+
+	if err := h.service.RejectReviewerApplication(c.Request.Context(), c.Param("userId"), adminID.(string), req.Reason); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "已拒绝评测员申请",
+		"data":    nil,
+	})
+}
+
+// respondError 按统一格式返回错误
+func respondError(c *gin.Context, err error) {
+	statusCode := errors.HTTPStatusFromError(err)
+	errorCode := http.StatusInternalServerError
+	errorMsg := err.Error()
+
+	// HTTPStatusFromError不处理409等状态码，AppError直接使用自身的状态码
+	if appErr, ok := err.(*errors.AppError); ok {
+		statusCode = appErr.HTTPStatus()
+		errorCode = appErr.Code
+		errorMsg = appErr.Message
+	}
+
+	c.JSON(statusCode, gin.H{
+		"code":    errorCode,
+		"message": errorMsg,
+		"data":    nil,
+	})
+}
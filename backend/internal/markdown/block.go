@@ -0,0 +1,394 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxDepth 引用和列表的最大嵌套层数，超过后按普通段落处理
+const maxDepth = 8
+
+var (
+	unorderedMarker = regexp.MustCompile(`^([-*+])( +|$)`)
+	orderedMarker   = regexp.MustCompile(`^(\d{1,9})([.)])( +|$)`)
+	delimiterCell   = regexp.MustCompile(`^:?-+:?$`)
+)
+
+// listItemMarker 列表项的标记
+type listItemMarker struct {
+	ordered bool
+	delim   byte // 无序列表为 - * +，有序列表为 . )
+	start   int
+	indent  int // 列表项内容相对行首的缩进，后续行缩进达到该值时属于同一列表项
+}
+
+// sameList 两个标记是否属于同一个列表
+func (m *listItemMarker) sameList(other *listItemMarker) bool {
+	return m.ordered == other.ordered && m.delim == other.delim
+}
+
+// parseBlocks 按行解析块级元素
+func parseBlocks(lines []string, depth int) []*node {
+	var blocks []*node
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		var block *node
+		switch {
+		case trimmed == "":
+			i++
+			continue
+		case fenceMarker(trimmed) != "":
+			block, i = parseFence(lines, i)
+		case headingLevel(trimmed) > 0:
+			block, i = parseHeading(trimmed), i+1
+		case isThematicBreak(trimmed):
+			block, i = element("hr"), i+1
+		case depth < maxDepth && strings.HasPrefix(trimmed, ">"):
+			block, i = parseQuote(lines, i, depth)
+		case isTableStart(lines, i):
+			block, i = parseTable(lines, i)
+		case depth < maxDepth && listMarker(lines[i]) != nil:
+			block, i = parseList(lines, i, depth)
+		default:
+			block, i = parseParagraph(lines, i)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// interrupts 该行是否开始一个新的块，用于结束段落
+// 有序列表只有从1开始时才能打断段落，避免正文中的“2019. ”之类被当成列表
+func interrupts(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	if fenceMarker(trimmed) != "" || headingLevel(trimmed) > 0 || isThematicBreak(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || isTableStart(lines, i) {
+		return true
+	}
+	m := listMarker(lines[i])
+	return m != nil && strings.TrimSpace(lines[i][min(m.indent, len(lines[i])):]) != "" && (!m.ordered || m.start == 1)
+}
+
+// parseParagraph 段落，段落内的换行保留
+func parseParagraph(lines []string, i int) (*node, int) {
+	text := []string{strings.TrimSpace(lines[i])}
+	for i++; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || interrupts(lines, i) {
+			break
+		}
+		text = append(text, strings.TrimSpace(lines[i]))
+	}
+	return element("p", parseInline(strings.Join(text, "\n"), 0)...), i
+}
+
+// headingLevel ATX标题的级别，不是标题时返回0
+func headingLevel(trimmed string) int {
+	n := 0
+	for n < len(trimmed) && trimmed[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(trimmed) && trimmed[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// parseHeading 标题，h1留给页面标题，# 和 ## 都输出为h2，#### 及以下都输出为h4
+func parseHeading(trimmed string) *node {
+	level := headingLevel(trimmed)
+	content := strings.TrimSpace(trimmed[level:])
+	// 去掉结尾可选的 #
+	if stripped := strings.TrimRight(content, "#"); stripped == "" || strings.HasSuffix(stripped, " ") {
+		content = strings.TrimSpace(stripped)
+	}
+
+	tag := "h4"
+	switch {
+	case level <= 2:
+		tag = "h2"
+	case level == 3:
+		tag = "h3"
+	}
+	return element(tag, parseInline(content, 0)...)
+}
+
+// isThematicBreak 三个及以上的 - * _ 组成的分隔线
+func isThematicBreak(trimmed string) bool {
+	if len(trimmed) < 3 {
+		return false
+	}
+	c := trimmed[0]
+	if c != '-' && c != '*' && c != '_' {
+		return false
+	}
+	count := 0
+	for i := 0; i < len(trimmed); i++ {
+		switch trimmed[i] {
+		case c:
+			count++
+		case ' ':
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// fenceMarker 代码块的起止标记，不是代码块时返回空
+func fenceMarker(trimmed string) string {
+	if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+		return ""
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+	// 反引号代码块的语言标记中不能有反引号
+	if trimmed[0] == '`' && strings.Contains(trimmed[n:], "`") {
+		return ""
+	}
+	return trimmed[:n]
+}
+
+// parseFence 代码块，语言标记不输出，内容原样保留，没有结束标记时到文档结尾
+func parseFence(lines []string, i int) (*node, int) {
+	fence := fenceMarker(strings.TrimSpace(lines[i]))
+	indent := leadingSpaces(lines[i])
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		line = line[min(indent, leadingSpaces(line)):]
+		code = append(code, line)
+	}
+
+	content := strings.Join(code, "\n")
+	if len(code) > 0 {
+		content += "\n"
+	}
+	return element("pre", element("code", textNode(content))), i
+}
+
+// parseQuote 引用，内容按块递归解析，段落可以延续到不带 > 的行
+func parseQuote(lines []string, i int, depth int) (*node, int) {
+	var inner []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			if trimmed == "" || strings.TrimSpace(inner[len(inner)-1]) == "" || interrupts(lines, i) {
+				break
+			}
+			inner = append(inner, trimmed)
+			continue
+		}
+		inner = append(inner, strings.TrimPrefix(trimmed[1:], " "))
+	}
+	return element("blockquote", parseBlocks(inner, depth+1)...), i
+}
+
+// listMarker 解析行首的列表标记，不是列表项时返回nil
+func listMarker(line string) *listItemMarker {
+	lead := leadingSpaces(line)
+	if lead > 3 {
+		return nil
+	}
+	rest := line[lead:]
+
+	var m listItemMarker
+	var width, spaces int
+	if sub := unorderedMarker.FindStringSubmatch(rest); sub != nil {
+		m.delim = sub[1][0]
+		width, spaces = len(sub[1]), len(sub[2])
+	} else if sub := orderedMarker.FindStringSubmatch(rest); sub != nil {
+		m.ordered = true
+		m.delim = sub[2][0]
+		m.start, _ = strconv.Atoi(sub[1])
+		width, spaces = len(sub[1])+1, len(sub[3])
+	} else {
+		return nil
+	}
+	// 标记后没有内容或空格过多时，内容缩进按一个空格计算
+	if spaces == 0 || spaces > 4 {
+		spaces = 1
+	}
+	m.indent = lead + width + spaces
+	return &m
+}
+
+// parseList 列表，列表项内容按块递归解析，只有一个段落的列表项直接输出行内内容
+func parseList(lines []string, i int, depth int) (*node, int) {
+	first := listMarker(lines[i])
+	list := element("ul")
+	if first.ordered {
+		list = element("ol")
+		if first.start != 1 {
+			list.setAttr("start", strconv.Itoa(first.start))
+		}
+	}
+
+	for i < len(lines) {
+		m := listMarker(lines[i])
+		if m == nil || !m.sameList(first) {
+			break
+		}
+
+		item := []string{lines[i][min(m.indent, len(lines[i])):]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// 空行后仍有缩进的内容属于同一列表项
+				next := nextNonBlank(lines, i)
+				if next == len(lines) || leadingSpaces(lines[next]) < m.indent {
+					break
+				}
+				for ; i < next; i++ {
+					item = append(item, "")
+				}
+				i--
+				continue
+			}
+			if leadingSpaces(line) >= m.indent {
+				item = append(item, line[m.indent:])
+				continue
+			}
+			// 未缩进的行：同一列表的下一项或其他块结束当前项，否则是段落的延续
+			if next := listMarker(line); (next != nil && next.sameList(first)) || interrupts(lines, i) {
+				break
+			}
+			item = append(item, strings.TrimSpace(line))
+		}
+		list.Children = append(list.Children, listItem(parseBlocks(item, depth+1)))
+
+		// 列表项之间允许有空行
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			next := nextNonBlank(lines, i)
+			if next == len(lines) {
+				i = next
+				break
+			}
+			if m := listMarker(lines[next]); m == nil || !m.sameList(first) {
+				break
+			}
+			i = next
+		}
+	}
+	return list, i
+}
+
+// listItem 列表项
+func listItem(blocks []*node) *node {
+	if len(blocks) == 1 && blocks[0].Tag == "p" {
+		return element("li", blocks[0].Children...)
+	}
+	return element("li", blocks...)
+}
+
+// isTableStart 表头行之后紧跟列数相同的分隔行时为表格
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !strings.Contains(lines[i+1], "|") {
+		return false
+	}
+	aligns, ok := parseDelimiterRow(lines[i+1])
+	return ok && len(splitRow(lines[i])) == len(aligns)
+}
+
+// parseDelimiterRow 解析表格的分隔行，返回各列的对齐方式
+func parseDelimiterRow(line string) ([]string, bool) {
+	cells := splitRow(line)
+	aligns := make([]string, len(cells))
+	for i, cell := range cells {
+		if !delimiterCell.MatchString(cell) {
+			return nil, false
+		}
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns[i] = "center"
+		case right:
+			aligns[i] = "right"
+		case left:
+			aligns[i] = "left"
+		}
+	}
+	return aligns, true
+}
+
+// parseTable 表格，表体到空行、不含 | 的行或其他块为止
+func parseTable(lines []string, i int) (*node, int) {
+	aligns, _ := parseDelimiterRow(lines[i+1])
+	table := element("table", element("thead", tableRow("th", splitRow(lines[i]), aligns)))
+
+	body := element("tbody")
+	for i += 2; i < len(lines); i++ {
+		if !strings.Contains(lines[i], "|") || interrupts(lines, i) {
+			break
+		}
+		body.Children = append(body.Children, tableRow("td", splitRow(lines[i]), aligns))
+	}
+	if len(body.Children) > 0 {
+		table.Children = append(table.Children, body)
+	}
+	return table, i
+}
+
+// tableRow 表格的一行，单元格数按表头补齐或截断
+func tableRow(tag string, cells []string, aligns []string) *node {
+	row := element("tr")
+	for i, align := range aligns {
+		cell := element(tag)
+		if i < len(cells) {
+			cell.Children = parseInline(cells[i], 0)
+		}
+		if align != "" {
+			cell.setAttr("align", align)
+		}
+		row.Children = append(row.Children, cell)
+	}
+	return row
+}
+
+// splitRow 按 | 拆分表格行，\| 不拆分
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func leadingSpaces(line string) int {
+	n := 0
+	for n < len(line) && line[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	return i
+}
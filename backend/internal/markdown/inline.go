@@ -0,0 +1,317 @@
+package markdown
+
+import "strings"
+
+// maxInlineDepth 链接和强调的最大嵌套层数，超过后按文本处理
+const maxInlineDepth = 16
+
+// maxLinkTarget 链接地址和标题的最大长度
+const maxLinkTarget = 2048
+
+// inlineScanner 解析一段行内文本
+// 记录已确认找不到结束标记的位置，避免长文本中反复向后查找
+type inlineScanner struct {
+	s        string
+	brackets map[int]int    // [ 的位置 -> 匹配的 ] 的位置
+	noCloser map[string]int // 结束标记 -> 从该位置之后没有结束标记
+	noCode   map[int]int    // 反引号个数 -> 从该位置之后没有同样长度的反引号
+}
+
+func newInlineScanner(s string) *inlineScanner {
+	sc := &inlineScanner{s: s, noCloser: map[string]int{}, noCode: map[int]int{}}
+	sc.brackets = sc.matchBrackets()
+	return sc
+}
+
+// link 解析出的链接或图片
+type link struct {
+	label string
+	dest  string
+	title string
+	end   int // 链接结束后的位置
+}
+
+// parseInline 解析行内元素：转义、换行、行内代码、图片、链接、加粗和斜体
+func parseInline(s string, depth int) []*node {
+	sc := newInlineScanner(s)
+	var nodes []*node
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, textNode(buf.String()))
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '\n':
+			flush()
+			nodes = append(nodes, element("br"))
+			i++
+			continue
+		case c == '`':
+			content, end, ok := sc.codeSpan(i)
+			if ok {
+				flush()
+				nodes = append(nodes, element("code", textNode(content)))
+			} else {
+				// 没有匹配的反引号时整段按文本输出
+				buf.WriteString(s[i:end])
+			}
+			i = end
+			continue
+		case depth >= maxInlineDepth:
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if l, ok := sc.parseLink(i + 1); ok {
+				flush()
+				img := element("img")
+				img.setAttr("src", l.dest)
+				img.setAttr("alt", inlineText(parseInline(l.label, depth+1)))
+				if l.title != "" {
+					img.setAttr("title", l.title)
+				}
+				nodes = append(nodes, img)
+				i = l.end
+				continue
+			}
+		case c == '[':
+			if l, ok := sc.parseLink(i); ok {
+				flush()
+				a := element("a", parseInline(l.label, depth+1)...)
+				a.setAttr("href", l.dest)
+				if l.title != "" {
+					a.setAttr("title", l.title)
+				}
+				nodes = append(nodes, a)
+				i = l.end
+				continue
+			}
+		case c == '*' || c == '_':
+			if n, end, ok := sc.emphasis(i, depth); ok {
+				flush()
+				nodes = append(nodes, n)
+				i = end
+				continue
+			}
+		}
+		buf.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return nodes
+}
+
+// codeSpan 解析从i开始的行内代码，没有匹配的结束标记时end为整串反引号之后
+func (sc *inlineScanner) codeSpan(i int) (string, int, bool) {
+	s := sc.s
+	n := backtickRun(s, i)
+	if from, ok := sc.noCode[n]; ok && i >= from {
+		return "", i + n, false
+	}
+	for j := i + n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		m := backtickRun(s, j)
+		if m == n {
+			content := strings.ReplaceAll(s[i+n:j], "\n", " ")
+			if len(content) > 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.TrimSpace(content) != "" {
+				content = content[1 : len(content)-1]
+			}
+			return content, j + m, true
+		}
+		j += m
+	}
+	sc.noCode[n] = i
+	return "", i + n, false
+}
+
+func backtickRun(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// parseLink 解析从i开始的 [文本](地址 "标题")
+func (sc *inlineScanner) parseLink(i int) (link, bool) {
+	s := sc.s
+	closing, ok := sc.brackets[i]
+	if !ok || closing+1 >= len(s) || s[closing+1] != '(' {
+		return link{}, false
+	}
+
+	// 地址中允许成对的括号
+	level := 0
+	end := -1
+	for j := closing + 2; j < len(s) && j < closing+2+maxLinkTarget && end < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '(':
+			level++
+		case ')':
+			if level == 0 {
+				end = j
+			}
+			level--
+		}
+	}
+	if end < 0 {
+		return link{}, false
+	}
+
+	dest, title, ok := splitDestination(strings.TrimSpace(s[closing+2 : end]))
+	if !ok {
+		return link{}, false
+	}
+	return link{label: s[i+1 : closing], dest: dest, title: title, end: end + 1}, true
+}
+
+// matchBrackets 一次扫描找出所有成对的 [ ]，跳过转义字符和行内代码
+func (sc *inlineScanner) matchBrackets() map[int]int {
+	s := sc.s
+	matches := map[int]int{}
+	var open []int
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			_, end, _ := sc.codeSpan(j)
+			j = end - 1
+		case '[':
+			open = append(open, j)
+		case ']':
+			if len(open) > 0 {
+				matches[open[len(open)-1]] = j
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	return matches
+}
+
+// splitDestination 拆分链接地址和可选的标题，地址可以用 <> 包起来
+func splitDestination(inside string) (string, string, bool) {
+	var dest, rest string
+	if strings.HasPrefix(inside, "<") {
+		end := strings.IndexByte(inside, '>')
+		if end < 0 {
+			return "", "", false
+		}
+		dest, rest = inside[1:end], strings.TrimSpace(inside[end+1:])
+	} else if space := strings.IndexAny(inside, " \n"); space >= 0 {
+		dest, rest = inside[:space], strings.TrimSpace(inside[space+1:])
+	} else {
+		dest = inside
+	}
+
+	title := ""
+	if rest != "" {
+		if len(rest) < 2 || !isTitleQuote(rest[0], rest[len(rest)-1]) {
+			return "", "", false
+		}
+		title = unescape(rest[1 : len(rest)-1])
+	}
+	return unescape(dest), title, true
+}
+
+func isTitleQuote(open, close byte) bool {
+	return (open == '"' && close == '"') || (open == '\'' && close == '\'') || (open == '(' && close == ')')
+}
+
+// emphasis 解析从i开始的 *斜体*、**加粗** 或 ***加粗斜体***，_ 同理
+func (sc *inlineScanner) emphasis(i int, depth int) (*node, int, bool) {
+	s := sc.s
+	c := s[i]
+	// 单词中间的 _ 不作为强调，如 snake_case
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return nil, 0, false
+	}
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	start := i + n
+	if n > 3 || start >= len(s) || s[start] == ' ' || s[start] == '\n' {
+		return nil, 0, false
+	}
+
+	end := sc.closingDelimiter(start, s[i:start])
+	if end < 0 {
+		return nil, 0, false
+	}
+	children := parseInline(s[start:end], depth+1)
+	var el *node
+	switch n {
+	case 1:
+		el = element("em", children...)
+	case 2:
+		el = element("strong", children...)
+	default:
+		el = element("strong", element("em", children...))
+	}
+	return el, end + n, true
+}
+
+// closingDelimiter 找到长度正好为len(delim)的结束标记，结束标记前不能是空白
+func (sc *inlineScanner) closingDelimiter(from int, delim string) int {
+	if failed, ok := sc.noCloser[delim]; ok && from >= failed {
+		return -1
+	}
+	s := sc.s
+	c := delim[0]
+	for j := from + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '`':
+			if _, end, ok := sc.codeSpan(j); ok {
+				j = end - 1
+			}
+		case strings.HasPrefix(s[j:], delim):
+			after := j + len(delim)
+			if s[j-1] == c || s[j-1] == ' ' || s[j-1] == '\n' || (after < len(s) && s[after] == c) {
+				continue
+			}
+			if c == '_' && after < len(s) && isWordByte(s[after]) {
+				continue
+			}
+			return j
+		}
+	}
+	sc.noCloser[delim] = from
+	return -1
+}
+
+// isWordByte 字母、数字和非ASCII字符
+func isWordByte(b byte) bool {
+	return b >= 0x80 || b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isPunct(b byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", b) >= 0
+}
+
+// unescape 去掉标点前的转义反斜杠
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
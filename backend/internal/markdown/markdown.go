@@ -0,0 +1,83 @@
+// Package markdown 评测使用的受限Markdown方言
+// 支持标题、段落、列表、引用、代码块、分隔线、表格以及加粗、斜体、行内代码、链接和图片
+// 原始HTML一律按文本输出，渲染结果只包含白名单中的元素和属性
+package markdown
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Options 渲染选项
+type Options struct {
+	// AllowImage 判断图片地址是否允许显示，不允许的图片只保留替代文本；为nil时不显示任何图片
+	AllowImage func(src string) bool
+}
+
+// Document 解析并清洗后的文档
+type Document struct {
+	blocks []*node
+}
+
+// Parse 解析Markdown并按白名单清洗
+func Parse(src string, opts Options) *Document {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	src = strings.ReplaceAll(src, "\x00", "�")
+
+	blocks := parseBlocks(strings.Split(src, "\n"), 0)
+	return &Document{blocks: sanitizeNodes(blocks, opts, false)}
+}
+
+// HTML 渲染为HTML
+func (d *Document) HTML() string {
+	var b strings.Builder
+	render(&b, d.blocks)
+	return strings.TrimSpace(b.String())
+}
+
+// Text 文档的纯文本内容，块之间以换行分隔，图片取替代文本
+func (d *Document) Text() string {
+	var b strings.Builder
+	plainText(&b, d.blocks)
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Links 文档中所有链接的地址，不包括图片
+func (d *Document) Links() []string {
+	var links []string
+	var walk func(nodes []*node)
+	walk = func(nodes []*node) {
+		for _, n := range nodes {
+			if n.Tag == "a" {
+				links = append(links, n.attr("href"))
+			}
+			walk(n.Children)
+		}
+	}
+	walk(d.blocks)
+	return links
+}
+
+// Excerpt 文档的纯文本摘要
+func (d *Document) Excerpt(maxRunes int) string {
+	return Excerpt(d.Text(), maxRunes)
+}
+
+// Excerpt 把纯文本的空白合并为单个空格，超过maxRunes个字符时截断并加省略号
+func Excerpt(text string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if maxRunes <= 0 || utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
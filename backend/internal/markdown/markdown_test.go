@@ -0,0 +1,87 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allowUploads = Options{AllowImage: func(src string) bool {
+	return strings.HasPrefix(src, "/uploads/")
+}}
+
+func TestRender(t *testing.T) {
+	t.Run("标题、段落和行内格式", func(t *testing.T) {
+		doc := Parse("# 外观\n\n### 手感 ###\n\n**做工**很好，*侧键*偏软，型号`G-PRO`\n第二行", Options{})
+		assert.Equal(t, "<h2>外观</h2>\n<h3>手感</h3>\n<p><strong>做工</strong>很好，<em>侧键</em>偏软，型号<code>G-PRO</code><br>第二行</p>", doc.HTML())
+	})
+
+	t.Run("列表", func(t *testing.T) {
+		doc := Parse("- 轻\n- 稳\n  - 微动清脆\n\n3. 第三\n4. 第四", Options{})
+		assert.Equal(t, "<ul>\n<li>轻</li>\n<li><p>稳</p>\n<ul>\n<li>微动清脆</li>\n</ul>\n</li>\n</ul>\n<ol start=\"3\">\n<li>第三</li>\n<li>第四</li>\n</ol>", doc.HTML())
+	})
+
+	t.Run("规格对比表格", func(t *testing.T) {
+		doc := Parse("| 型号 | 重量 | 回报率 |\n|:---|---:|:---:|\n| A | 60g | 4K |\n| B \\| Pro | 55g |", Options{})
+		html := doc.HTML()
+		assert.Contains(t, html, `<thead>`+"\n"+`<tr><th align="left">型号</th><th align="right">重量</th><th align="center">回报率</th></tr>`)
+		assert.Contains(t, html, `<tr><td align="left">B | Pro</td><td align="right">55g</td><td align="center"></td></tr>`)
+	})
+
+	t.Run("引用和代码块", func(t *testing.T) {
+		doc := Parse("> 官方宣称\n续行\n\n```ini\n<dpi>=800\n```", Options{})
+		assert.Equal(t, "<blockquote>\n<p>官方宣称<br>续行</p>\n</blockquote>\n<pre><code>&lt;dpi&gt;=800\n</code></pre>", doc.HTML())
+	})
+
+	t.Run("原始HTML按文本输出", func(t *testing.T) {
+		doc := Parse(`<script>alert(1)</script><img src=x onerror=alert(1)>`, Options{})
+		assert.Equal(t, "<p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;img src=x onerror=alert(1)&gt;</p>", doc.HTML())
+	})
+}
+
+func TestSanitizeLinksAndImages(t *testing.T) {
+	t.Run("只允许安全的链接", func(t *testing.T) {
+		doc := Parse(`[官网](https://example.com/a?b=1&c="2") [站内](/devices/1) [脚本](javascript:alert(1)) [协议相对](//evil.test) [数据](data:text/html,x)`, Options{})
+		html := doc.HTML()
+		assert.Contains(t, html, `<a href="https://example.com/a?b=1&amp;c=&#34;2&#34;" rel="nofollow noopener ugc">官网</a>`)
+		assert.Contains(t, html, `<a href="/devices/1" rel="nofollow noopener ugc">站内</a>`)
+		assert.NotContains(t, html, "javascript")
+		assert.NotContains(t, html, "evil.test")
+		assert.NotContains(t, html, "data:")
+		assert.Contains(t, html, "脚本")
+		assert.Equal(t, []string{`https://example.com/a?b=1&c="2"`, "/devices/1"}, doc.Links())
+	})
+
+	t.Run("只允许上传的图片", func(t *testing.T) {
+		doc := Parse(`![侧面](/uploads/reviews/a.jpg "侧面图") ![外链](https://evil.test/x.png) ![](javascript:alert(1))`, allowUploads)
+		assert.Equal(t, `<p><img src="/uploads/reviews/a.jpg" alt="侧面" title="侧面图" loading="lazy"> 外链 </p>`, doc.HTML())
+
+		assert.Equal(t, "<p>侧面</p>", Parse("![侧面](/uploads/a.jpg)", Options{}).HTML(), "未配置时不显示图片")
+	})
+
+	t.Run("链接不能嵌套", func(t *testing.T) {
+		doc := Parse("[外层 [内层](/b)](/a)", Options{})
+		assert.Equal(t, 1, strings.Count(doc.HTML(), "<a "))
+	})
+
+	t.Run("输出只包含白名单元素和属性", func(t *testing.T) {
+		input := "## *a* [b](/c \"t\")\n\n| x |\n|---|\n| ![y](/uploads/y.png) |\n\n1. `z`\n\n---\n> **w**"
+		tags := regexp.MustCompile(`<(/?)([a-z0-9]+)((?: [a-z]+="[^"]*")*)>`)
+		for _, m := range tags.FindAllStringSubmatch(Parse(input, allowUploads).HTML(), -1) {
+			allowed, ok := allowedElements[m[2]]
+			assert.True(t, ok, m[0])
+			for _, a := range regexp.MustCompile(`([a-z]+)=`).FindAllStringSubmatch(m[3], -1) {
+				assert.True(t, allowed[a[1]], m[0])
+			}
+		}
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	doc := Parse("## 总结\n\n这款鼠标**很轻**，适合  FPS。\n\n- 优点：稳定\n\n| 重量 |\n|---|\n| 60g |\n\n![图](/uploads/a.jpg)", allowUploads)
+	assert.Equal(t, "总结\n这款鼠标很轻，适合  FPS。\n优点：稳定\n重量\n60g\n图", doc.Text())
+	assert.Equal(t, "总结 这款鼠标很轻，适合 FPS。 优点：稳定 重量 60g 图", doc.Excerpt(100))
+	assert.Equal(t, "总结 这款鼠标…", doc.Excerpt(7))
+}
@@ -0,0 +1,122 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// node 渲染前的HTML节点，Tag为空时为文本节点
+type node struct {
+	Tag      string
+	Attrs    []attr
+	Children []*node
+	Text     string
+}
+
+type attr struct {
+	Name  string
+	Value string
+}
+
+func element(tag string, children ...*node) *node {
+	return &node{Tag: tag, Children: children}
+}
+
+func textNode(text string) *node {
+	return &node{Text: text}
+}
+
+func (n *node) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) setAttr(name, value string) {
+	for i := range n.Attrs {
+		if n.Attrs[i].Name == name {
+			n.Attrs[i].Value = value
+			return
+		}
+	}
+	n.Attrs = append(n.Attrs, attr{Name: name, Value: value})
+}
+
+// voidElements 没有结束标签的元素
+var voidElements = map[string]bool{
+	"br":  true,
+	"hr":  true,
+	"img": true,
+}
+
+// blockElements 块级元素，渲染后换行以便阅读，提取纯文本时和前后内容分开
+var blockElements = map[string]bool{
+	"p": true, "h2": true, "h3": true, "h4": true, "ul": true, "ol": true, "li": true,
+	"blockquote": true, "pre": true, "hr": true, "table": true, "thead": true, "tbody": true, "tr": true,
+}
+
+// containerElements 包含其他块的元素，开始标签后也换行
+var containerElements = map[string]bool{
+	"ul": true, "ol": true, "blockquote": true, "table": true, "thead": true, "tbody": true,
+}
+
+// render 输出HTML，文本和属性值都会转义
+func render(b *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		if n.Tag == "" {
+			b.WriteString(html.EscapeString(n.Text))
+			continue
+		}
+		b.WriteString("<" + n.Tag)
+		for _, a := range n.Attrs {
+			b.WriteString(" " + a.Name + `="` + html.EscapeString(a.Value) + `"`)
+		}
+		b.WriteString(">")
+		if containerElements[n.Tag] {
+			b.WriteString("\n")
+		}
+		if voidElements[n.Tag] {
+			if blockElements[n.Tag] {
+				b.WriteString("\n")
+			}
+			continue
+		}
+		render(b, n.Children)
+		b.WriteString("</" + n.Tag + ">")
+		if blockElements[n.Tag] {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// plainText 提取纯文本，块之间和单元格之间用空白分开，图片取替代文本
+func plainText(b *strings.Builder, nodes []*node) {
+	for _, n := range nodes {
+		switch {
+		case n.Tag == "":
+			b.WriteString(n.Text)
+		case n.Tag == "img":
+			b.WriteString(n.attr("alt"))
+		case n.Tag == "br":
+			b.WriteString("\n")
+		case n.Tag == "th" || n.Tag == "td":
+			plainText(b, n.Children)
+			b.WriteString(" ")
+		default:
+			plainText(b, n.Children)
+		}
+		if blockElements[n.Tag] {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// inlineText 行内元素的纯文本，用于图片的替代文本
+func inlineText(nodes []*node) string {
+	var b strings.Builder
+	plainText(&b, nodes)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
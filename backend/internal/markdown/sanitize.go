@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// allowedElements 允许输出的元素及各自允许的属性，其他元素只保留内容，其他属性移除
+var allowedElements = map[string]map[string]bool{
+	"p":          {},
+	"br":         {},
+	"hr":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"strong":     {},
+	"em":         {},
+	"code":       {},
+	"pre":        {},
+	"blockquote": {},
+	"ul":         {},
+	"ol":         {"start": true},
+	"li":         {},
+	"table":      {},
+	"thead":      {},
+	"tbody":      {},
+	"tr":         {},
+	"th":         {"align": true},
+	"td":         {"align": true},
+	"a":          {"href": true, "title": true, "rel": true},
+	"img":        {"src": true, "alt": true, "title": true, "loading": true},
+}
+
+// linkRel 用户内容中的链接不传递权重，也不能访问打开它的页面
+const linkRel = "nofollow noopener ugc"
+
+// sanitizeNodes 按白名单清洗节点
+// 地址不安全的链接只保留文字，不允许的图片只保留替代文本，链接中不能再嵌套链接
+func sanitizeNodes(nodes []*node, opts Options, inLink bool) []*node {
+	out := make([]*node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Tag == "" {
+			out = append(out, n)
+			continue
+		}
+		allowed, ok := allowedElements[n.Tag]
+		if !ok || (n.Tag == "a" && inLink) {
+			out = append(out, sanitizeNodes(n.Children, opts, inLink)...)
+			continue
+		}
+
+		attrs := n.Attrs[:0]
+		for _, a := range n.Attrs {
+			if allowed[a.Name] && isSafeAttr(a) {
+				attrs = append(attrs, a)
+			}
+		}
+		n.Attrs = attrs
+
+		switch n.Tag {
+		case "a":
+			if n.attr("href") == "" {
+				out = append(out, sanitizeNodes(n.Children, opts, inLink)...)
+				continue
+			}
+			n.setAttr("rel", linkRel)
+		case "img":
+			if src := n.attr("src"); src == "" || opts.AllowImage == nil || !opts.AllowImage(src) {
+				if alt := n.attr("alt"); alt != "" {
+					out = append(out, textNode(alt))
+				}
+				continue
+			}
+			n.setAttr("loading", "lazy")
+		}
+		n.Children = sanitizeNodes(n.Children, opts, inLink || n.Tag == "a")
+		out = append(out, n)
+	}
+	return out
+}
+
+func isSafeAttr(a attr) bool {
+	switch a.Name {
+	case "href":
+		return isSafeURL(a.Value, true)
+	case "src":
+		return isSafeURL(a.Value, false)
+	case "align":
+		return a.Value == "left" || a.Value == "center" || a.Value == "right"
+	case "start":
+		n, err := strconv.Atoi(a.Value)
+		return err == nil && n >= 0
+	case "loading":
+		return a.Value == "lazy"
+	case "rel":
+		return a.Value == linkRel
+	}
+	return true
+}
+
+// isSafeURL 只允许http(s)地址和以 / 开头的站内地址，链接还允许mailto和页内锚点
+// javascript:、data: 等协议以及 // 开头的协议相对地址都不允许
+func isSafeURL(raw string, isLink bool) bool {
+	if raw == "" || strings.ContainsAny(raw, " \\") || strings.HasPrefix(raw, "//") {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		return strings.HasPrefix(raw, "/") || (isLink && strings.HasPrefix(raw, "#"))
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return isLink && u.Opaque != ""
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+// Package pdf 生成只包含文字和线条的简单PDF文档
+//
+// 西文使用内置的Helvetica字体，中文使用PDF阅读器自带的STSong-Light字体，
+// 两者都不需要嵌入字体文件。坐标以左上角为原点，单位为点（1/72英寸）。
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4纸尺寸
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// 字体资源名
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontCJK     = "F3"
+)
+
+// cjkWidth 中文字体的字宽，单位为千分之一字号
+const cjkWidth = 1000
+
+// Document PDF文档
+type Document struct {
+	Width  float64
+	Height float64
+	Title  string
+	pages  []*bytes.Buffer
+	cur    int
+}
+
+// New 创建A4纸大小的文档
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height}
+}
+
+// AddPage 新增一页，之后的绘制都在该页上
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.cur = len(d.pages) - 1
+}
+
+// SetPage 切换到第i页（从0开始），用于补充页脚等内容
+func (d *Document) SetPage(i int) {
+	if i >= 0 && i < len(d.pages) {
+		d.cur = i
+	}
+}
+
+// PageCount 当前页数
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[d.cur]
+}
+
+// Text 在(x, y)处绘制文字，y为文字基线
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	buf := d.page()
+	for _, run := range splitRuns(s) {
+		font := fontRegular
+		if bold {
+			font = fontBold
+		}
+		encoded := "(" + escapeLiteral(run.text) + ")"
+		if run.cjk {
+			font = fontCJK
+			encoded = "<" + encodeUCS2(run.text) + ">"
+		}
+		fmt.Fprintf(buf, "BT /%s %s Tf %s %s Td %s Tj ET\n", font, num(size), num(x), num(d.Height-y), encoded)
+		x += runWidth(run, size, bold)
+	}
+}
+
+// TextRight 绘制右对齐的文字，right为文字右边缘
+func (d *Document) TextRight(right, y, size float64, bold bool, s string) {
+	d.Text(right-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// Line 绘制线段
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n", num(width), num(x1), num(d.Height-y1), num(x2), num(d.Height-y2))
+}
+
+// FillRect 填充灰色矩形，gray取值0（黑）到1（白）
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.page(), "q %s g %s %s %s %s re f Q\n", num(gray), num(x), num(d.Height-y-h), num(w), num(h))
+}
+
+// TextWidth 文字宽度
+func TextWidth(s string, size float64, bold bool) float64 {
+	width := 0.0
+	for _, run := range splitRuns(s) {
+		width += runWidth(run, size, bold)
+	}
+	return width
+}
+
+// Truncate 截断文字使宽度不超过maxWidth，截断时以...结尾
+func Truncate(s string, maxWidth, size float64, bold bool) string {
+	if TextWidth(s, size, bold) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "..."
+		if TextWidth(candidate, size, bold) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Bytes 输出PDF文件内容
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 对象编号：1 Catalog，2 Pages，3-6 字体，7 Info，之后每页两个对象
+	const firstPage = 8
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	w.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	w.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	w.object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	w.object(5, "<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [6 0 R] >>")
+	w.object(6, "<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light"+
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >>"+
+		" /FontDescriptor << /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880]"+
+		" /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>"+
+		fmt.Sprintf(" /DW %d >>", cjkWidth))
+	w.object(7, fmt.Sprintf("<< /Producer (FullStackOfYear) /Title <FEFF%s> >>", encodeUTF16(d.Title)))
+
+	resources := fmt.Sprintf("<< /Font << /%s 3 0 R /%s 4 0 R /%s 5 0 R >> >>", fontRegular, fontBold, fontCJK)
+	for i, content := range d.pages {
+		pageID := firstPage + 2*i
+		w.object(pageID, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			num(d.Width), num(d.Height), resources, pageID+1))
+		w.object(pageID+1, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	return w.finish(1, 7)
+}
+
+// writer 记录每个对象的偏移量以生成交叉引用表
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+	maxID   int
+}
+
+func (w *writer) object(id int, body string) {
+	if w.offsets == nil {
+		w.offsets = map[int]int{}
+	}
+	w.offsets[id] = w.buf.Len()
+	if id > w.maxID {
+		w.maxID = id
+	}
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (w *writer) finish(root, info int) []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", w.maxID+1)
+	for id := 1; id <= w.maxID; id++ {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[id])
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", w.maxID+1, root, info, xref)
+	return w.buf.Bytes()
+}
+
+// run 使用同一字体的一段文字
+type run struct {
+	text string
+	cjk  bool
+}
+
+// splitRuns 按字体拆分文字：可打印ASCII使用Helvetica，其他字符使用中文字体
+func splitRuns(s string) []run {
+	var runs []run
+	var current strings.Builder
+	cjk := false
+	for _, r := range s {
+		isCJK := r < 0x20 || r > 0x7e
+		if r > 0xffff || r < 0x20 {
+			// 控制字符和基本平面以外的字符无法显示
+			r, isCJK = '?', false
+		}
+		if current.Len() > 0 && isCJK != cjk {
+			runs = append(runs, run{text: current.String(), cjk: cjk})
+			current.Reset()
+		}
+		cjk = isCJK
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		runs = append(runs, run{text: current.String(), cjk: cjk})
+	}
+	return runs
+}
+
+func runWidth(r run, size float64, bold bool) float64 {
+	if r.cjk {
+		return float64(len([]rune(r.text))*cjkWidth) * size / 1000
+	}
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for i := 0; i < len(r.text); i++ {
+		total += widths[r.text[i]-0x20]
+	}
+	return float64(total) * size / 1000
+}
+
+// escapeLiteral 转义PDF字符串中的特殊字符
+func escapeLiteral(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// encodeUCS2 按UniGB-UCS2-H编码输出十六进制字符串
+func encodeUCS2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// encodeUTF16 文档信息使用UTF-16BE编码
+func encodeUTF16(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
+// num 输出最多两位小数的数字
+func num(f float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// Helvetica字体中0x20-0x7E字符的宽度，单位为千分之一字号
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesProducesValidStructure(t *testing.T) {
+	doc := New()
+	doc.Title = "发票 001"
+	doc.Text(50, 70, 12, true, "Order (A) 订单")
+	doc.AddPage()
+	doc.Line(50, 100, 200, 100, 1)
+
+	out := doc.Bytes()
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	// 括号需要转义，中文按UCS-2十六进制输出
+	assert.Contains(t, string(out), `(Order \(A\) )`)
+	assert.Contains(t, string(out), "<8BA25355>")
+
+	// 交叉引用表中的偏移量指向对应的对象
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 11)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))), "object %d", i+1)
+	}
+}
+
+func TestTextWidthAndTruncate(t *testing.T) {
+	assert.InDelta(t, 16.12, TextWidth("abc", 10, false), 0.001)
+	assert.Equal(t, 20.0, TextWidth("中文", 10, false))
+
+	assert.Equal(t, "short", Truncate("short", 100, 10, false))
+	truncated := Truncate("a very long product name", 60, 10, false)
+	assert.LessOrEqual(t, TextWidth(truncated, 10, false), 60.0)
+	assert.Contains(t, truncated, "...")
+}
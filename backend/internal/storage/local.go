@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local 本地磁盘存储，文件通过静态文件服务访问
+type Local struct {
+	root      string
+	urlPrefix string
+}
+
+// NewLocal 创建本地磁盘存储，root为保存目录，urlPrefix为静态文件服务的地址前缀
+func NewLocal(root, urlPrefix string) *Local {
+	return &Local{root: root, urlPrefix: strings.TrimSuffix(urlPrefix, "/")}
+}
+
+// Root 保存目录
+func (s *Local) Root() string {
+	return s.root
+}
+
+// Save 先写入临时文件再重命名，避免读到写了一半的文件
+func (s *Local) Save(ctx context.Context, key string, content []byte, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// Delete 删除文件
+func (s *Local) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.root, filepath.FromSlash(key)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// URL 文件的访问地址
+func (s *Local) URL(key string) string {
+	return s.urlPrefix + "/" + key
+}
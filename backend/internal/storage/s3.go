@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"project/backend/config"
+)
+
+// S3 S3兼容的对象存储，使用path-style地址和AWS签名V4
+// 本地开发可使用MinIO作为替身，只用到PutObject和DeleteObject两个接口，不依赖AWS SDK
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3 创建S3存储
+func NewS3(cfg config.S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("s3 storage requires endpoint, bucket, accessKey and secretKey")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", cfg.Endpoint)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint.String() + "/" + cfg.Bucket
+	}
+	return &S3{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+// Save 上传对象
+func (s *S3) Save(ctx context.Context, key string, content []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("put", key, resp)
+	}
+	return nil
+}
+
+// Delete 删除对象，S3删除不存在的对象也返回成功
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", key, resp)
+	}
+	return nil
+}
+
+// URL 对象的公开访问地址
+func (s *S3) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *S3) do(ctx context.Context, method, key string, body []byte, contentType string) (*http.Response, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	target := *s.endpoint
+	target.Path = s.endpoint.Path + "/" + s.bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, body)
+	return s.client.Do(req)
+}
+
+// sign 按AWS签名V4签名请求，签名的头为host、x-amz-content-sha256和x-amz-date
+func (s *S3) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func s3Error(op, key string, resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s failed: %s %s", op, key, resp.Status, strings.TrimSpace(string(detail)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
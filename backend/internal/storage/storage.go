@@ -0,0 +1,49 @@
+// Package storage 上传文件的存储，目前支持本地磁盘和S3兼容的对象存储
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"project/backend/config"
+	"project/backend/internal/upload"
+)
+
+// 存储驱动
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Storage 文件存储，key为以/分隔的相对路径
+type Storage interface {
+	// Save 保存文件，key已存在时覆盖
+	Save(ctx context.Context, key string, content []byte, contentType string) error
+	// Delete 删除文件，文件不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// URL 文件的访问地址
+	URL(key string) string
+}
+
+// New 按配置创建存储，未配置驱动时使用本地磁盘
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(upload.DefaultConfig.SavePath, upload.DefaultConfig.UrlPrefix), nil
+	case DriverS3:
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// cleanKey 校验key，不允许绝对路径和跳出存储目录
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if key == "" || cleaned == "." || strings.HasPrefix(cleaned, "/") || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return cleaned, nil
+}
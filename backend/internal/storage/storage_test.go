@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/config"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocal(root, "/static/uploads/")
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "reviews/a/b.jpg", []byte("data"), "image/jpeg"))
+	content, err := os.ReadFile(filepath.Join(root, "reviews", "a", "b.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+	assert.Equal(t, "/static/uploads/reviews/a/b.jpg", store.URL("reviews/a/b.jpg"))
+
+	require.NoError(t, store.Delete(ctx, "reviews/a/b.jpg"))
+	require.NoError(t, store.Delete(ctx, "reviews/a/b.jpg"))
+	_, err = os.Stat(filepath.Join(root, "reviews", "a", "b.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", "../escape.jpg", "/etc/passwd", "a/../../escape.jpg"} {
+		assert.Error(t, store.Save(ctx, key, []byte("x"), ""), key)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	type request struct {
+		method, path, auth, contentType, body string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("Content-Type"), string(body)})
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewS3(config.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "media",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+	store.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "reviews/x.png", []byte("png"), "image/png"))
+	require.NoError(t, store.Delete(ctx, "reviews/x.png"))
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, http.MethodPut, requests[0].method)
+	assert.Equal(t, "/media/reviews/x.png", requests[0].path)
+	assert.Equal(t, "image/png", requests[0].contentType)
+	assert.Equal(t, "png", requests[0].body)
+	assert.True(t, strings.HasPrefix(requests[0].auth, "AWS4-HMAC-SHA256 Credential=AKID/20240501/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+	assert.Equal(t, http.MethodDelete, requests[1].method)
+	assert.Equal(t, server.URL+"/media/reviews/x.png", store.URL("reviews/x.png"))
+
+	_, err = NewS3(config.S3Config{Endpoint: server.URL})
+	assert.Error(t, err)
+}
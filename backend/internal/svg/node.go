@@ -0,0 +1,226 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	svgNamespace   = "http://www.w3.org/2000/svg"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+
+	// 防止恶意构造的深层嵌套或超大文档
+	maxDepth    = 64
+	maxElements = 10000
+)
+
+var (
+	// ErrInvalidSVG 文件不是合法的XML/SVG
+	ErrInvalidSVG = errors.New("invalid svg document")
+	// ErrNotSVG 根元素不是svg
+	ErrNotSVG = errors.New("root element is not svg")
+)
+
+// node SVG文档树中的一个元素
+type node struct {
+	Name     string // 带前缀的名称，例如 path、xlink:href
+	Attrs    []attr
+	Children []*node
+	Text     string
+}
+
+// attr 元素属性
+type attr struct {
+	Name  string
+	Value string
+}
+
+// get 获取属性值
+func (n *node) get(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// set 设置属性值，不存在时追加
+func (n *node) set(name, value string) {
+	for i, a := range n.Attrs {
+		if a.Name == name {
+			n.Attrs[i].Value = value
+			return
+		}
+	}
+	n.Attrs = append(n.Attrs, attr{Name: name, Value: value})
+}
+
+// remove 删除属性
+func (n *node) remove(name string) {
+	attrs := n.Attrs[:0]
+	for _, a := range n.Attrs {
+		if a.Name != name {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attrs = attrs
+}
+
+// parse 将SVG解析为文档树
+// 命名空间只保留svg、xlink和xml，其它命名空间（如编辑器元数据）的元素和属性会被丢弃
+func parse(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var root *node
+	var stack []*node
+	skipDepth := 0
+	elements := 0
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			name, ok := qualifiedName(t.Name)
+			if !ok {
+				skipDepth = 1
+				continue
+			}
+
+			elements++
+			if elements > maxElements || len(stack) >= maxDepth {
+				return nil, fmt.Errorf("%w: document too complex", ErrInvalidSVG)
+			}
+
+			n := &node{Name: name}
+			for _, a := range t.Attr {
+				// 命名空间声明在输出时统一生成
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue
+				}
+				attrName, ok := qualifiedName(a.Name)
+				if !ok {
+					continue
+				}
+				n.Attrs = append(n.Attrs, attr{Name: attrName, Value: a.Value})
+			}
+
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("%w: multiple root elements", ErrInvalidSVG)
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, n)
+			}
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if skipDepth > 0 || len(stack) == 0 {
+				continue
+			}
+			current := stack[len(stack)-1]
+			current.Text += string(t)
+		}
+		// 注释、处理指令和DOCTYPE一律丢弃
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: empty document", ErrInvalidSVG)
+	}
+	if root.Name != "svg" {
+		return nil, ErrNotSVG
+	}
+	return root, nil
+}
+
+// qualifiedName 将解析后的命名空间映射回固定前缀
+func qualifiedName(name xml.Name) (string, bool) {
+	switch name.Space {
+	case "", svgNamespace:
+		return name.Local, true
+	case xlinkNamespace, "xlink":
+		return "xlink:" + name.Local, true
+	case xmlNamespace, "xml":
+		return "xml:" + name.Local, true
+	default:
+		return "", false
+	}
+}
+
+// render 将文档树序列化为SVG字符串
+func render(root *node) string {
+	var buf strings.Builder
+	renderNode(&buf, root, true)
+	return buf.String()
+}
+
+func renderNode(buf *strings.Builder, n *node, isRoot bool) {
+	buf.WriteString("<" + n.Name)
+	if isRoot {
+		buf.WriteString(` xmlns="` + svgNamespace + `"`)
+		if usesXlink(n) {
+			buf.WriteString(` xmlns:xlink="` + xlinkNamespace + `"`)
+		}
+	}
+	for _, a := range n.Attrs {
+		buf.WriteString(" " + a.Name + `="`)
+		xml.EscapeText(buf, []byte(a.Value))
+		buf.WriteString(`"`)
+	}
+
+	text := strings.TrimSpace(n.Text)
+	if len(n.Children) == 0 && text == "" {
+		buf.WriteString("/>")
+		return
+	}
+
+	buf.WriteString(">")
+	if text != "" {
+		xml.EscapeText(buf, []byte(text))
+	}
+	for _, child := range n.Children {
+		renderNode(buf, child, false)
+	}
+	buf.WriteString("</" + n.Name + ">")
+}
+
+// usesXlink 检查文档是否使用了xlink属性
+func usesXlink(n *node) bool {
+	for _, a := range n.Attrs {
+		if strings.HasPrefix(a.Name, "xlink:") {
+			return true
+		}
+	}
+	for _, child := range n.Children {
+		if usesXlink(child) {
+			return true
+		}
+	}
+	return false
+}
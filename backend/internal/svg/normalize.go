@@ -0,0 +1,285 @@
+package svg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrNoOutline 没有找到轮廓路径
+	ErrNoOutline = errors.New("svg contains no outline path")
+	// ErrMultipleOutlines 轮廓必须是唯一的一条路径
+	ErrMultipleOutlines = errors.New("svg must contain exactly one outline path")
+	// ErrMultipleSubpaths 轮廓路径中包含多个子路径
+	ErrMultipleSubpaths = errors.New("outline path must consist of a single subpath")
+	// ErrOpenOutline 轮廓路径没有闭合
+	ErrOpenOutline = errors.New("outline path is not closed")
+	// ErrDegenerateOutline 轮廓没有面积
+	ErrDegenerateOutline = errors.New("outline path has no area")
+	// ErrMissingLength 缺少鼠标长度，无法换算为毫米
+	ErrMissingLength = errors.New("mouse length is required to normalize outline")
+)
+
+// nonRenderedElements 其中的图形不会直接渲染，不参与轮廓提取
+var nonRenderedElements = map[string]bool{
+	"defs":     true,
+	"clipPath": true,
+	"mask":     true,
+	"symbol":   true,
+	"title":    true,
+	"desc":     true,
+}
+
+// Outline 从SVG中提取的鼠标轮廓，transform已经展平到坐标中
+type Outline struct {
+	segs     []segment
+	min, max point
+}
+
+// Normalized 规范化结果
+type Normalized struct {
+	Original  string // 清洗后的原始SVG
+	Canonical string // 毫米坐标系下的规范SVG
+}
+
+// Normalize 清洗SVG并生成毫米坐标系下的规范版本
+func Normalize(data []byte, lengthMM float64) (*Normalized, error) {
+	original, err := Sanitize(data)
+	if err != nil {
+		return nil, err
+	}
+	outline, err := ParseOutline(data)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := outline.Canonical(lengthMM)
+	if err != nil {
+		return nil, err
+	}
+	return &Normalized{Original: original, Canonical: canonical}, nil
+}
+
+// ParseOutline 清洗SVG并提取唯一的闭合轮廓
+// path、polygon、polyline 视为轮廓；circle、rect 等通常是传感器位置之类的标记，忽略
+func ParseOutline(data []byte) (*Outline, error) {
+	root, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	sanitizeNode(root)
+
+	var outlines [][]segment
+	if err := collectOutlines(root, identity, &outlines); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(outlines) == 0:
+		return nil, ErrNoOutline
+	case len(outlines) > 1:
+		return nil, ErrMultipleOutlines
+	}
+
+	segs, err := outerSubpath(outlines[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := validateClosedPath(segs); err != nil {
+		return nil, err
+	}
+
+	min, max := bounds(segs)
+	if max.X-min.X <= 0 || max.Y-min.Y <= 0 {
+		return nil, ErrDegenerateOutline
+	}
+	return &Outline{segs: segs, min: min, max: max}, nil
+}
+
+// Size 轮廓在原始坐标系下的宽高
+func (o *Outline) Size() (float64, float64) {
+	return o.max.X - o.min.X, o.max.Y - o.min.Y
+}
+
+// Canonical 按鼠标长度等比缩放到毫米坐标系，原点移到包围盒左上角
+// 轮廓的长边即鼠标长度：俯视图为长×宽，侧视图为长×高
+func (o *Outline) Canonical(lengthMM float64) (string, error) {
+	if lengthMM <= 0 {
+		return "", ErrMissingLength
+	}
+
+	w, h := o.Size()
+	scale := lengthMM / math.Max(w, h)
+	m := matrix{A: scale, D: scale}.multiply(matrix{A: 1, D: 1, E: -o.min.X, F: -o.min.Y})
+	segs := m.applySegments(o.segs)
+
+	width, height := formatNumber(w*scale), formatNumber(h*scale)
+	return fmt.Sprintf(`<svg xmlns="%s" viewBox="0 0 %s %s" width="%smm" height="%smm"><path d="%s"/></svg>`,
+		svgNamespace, width, height, width, height, formatPathData(segs)), nil
+}
+
+// collectOutlines 遍历文档树，收集所有轮廓并展平变换
+func collectOutlines(n *node, parent matrix, outlines *[][]segment) error {
+	m := parent
+	if t := strings.TrimSpace(n.get("transform")); t != "" {
+		local, err := parseTransform(t)
+		if err != nil {
+			return err
+		}
+		m = parent.multiply(local)
+	}
+
+	var segs []segment
+	var err error
+	switch n.Name {
+	case "path":
+		segs, err = parsePathData(n.get("d"))
+	case "polygon":
+		segs, err = parsePoints(n.get("points"), true)
+	case "polyline":
+		segs, err = parsePoints(n.get("points"), false)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+	}
+	if segs != nil {
+		*outlines = append(*outlines, m.applySegments(segs))
+	}
+
+	for _, child := range n.Children {
+		if nonRenderedElements[child.Name] {
+			continue
+		}
+		if err := collectOutlines(child, m, outlines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePoints 解析polygon/polyline的points属性
+func parsePoints(s string, closed bool) ([]segment, error) {
+	sc := &pathScanner{s: s}
+	var segs []segment
+	for {
+		sc.skipSeparators()
+		if sc.done() {
+			break
+		}
+		p, err := sc.readPoint()
+		if err != nil {
+			return nil, err
+		}
+		cmd := byte('L')
+		if len(segs) == 0 {
+			cmd = 'M'
+		}
+		segs = append(segs, segment{Cmd: cmd, Pts: []point{p}})
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("empty points")
+	}
+	if closed {
+		segs = append(segs, segment{Cmd: 'Z'})
+	}
+	return segs, nil
+}
+
+// outerSubpath 返回包围盒最大的子路径作为轮廓
+// 完全落在轮廓包围盒内的其他子路径（传感器标记、镂空等）直接丢弃，否则视为多个轮廓
+func outerSubpath(segs []segment) ([]segment, error) {
+	subpaths := splitSubpaths(segs)
+	if len(subpaths) == 1 {
+		return subpaths[0], nil
+	}
+
+	type box struct{ min, max point }
+	boxes := make([]box, len(subpaths))
+	outer := 0
+	for i, sub := range subpaths {
+		min, max := bounds(sub)
+		boxes[i] = box{min, max}
+		area := (max.X - min.X) * (max.Y - min.Y)
+		best := boxes[outer]
+		if area > (best.max.X-best.min.X)*(best.max.Y-best.min.Y) {
+			outer = i
+		}
+	}
+
+	o := boxes[outer]
+	for i, b := range boxes {
+		if i == outer {
+			continue
+		}
+		if b.min.X < o.min.X || b.min.Y < o.min.Y || b.max.X > o.max.X || b.max.Y > o.max.Y {
+			return nil, ErrMultipleSubpaths
+		}
+	}
+	return subpaths[outer], nil
+}
+
+// splitSubpaths 按M命令拆分子路径
+func splitSubpaths(segs []segment) [][]segment {
+	var subpaths [][]segment
+	start := 0
+	for i, seg := range segs {
+		if seg.Cmd == 'M' && i > start {
+			subpaths = append(subpaths, segs[start:i])
+			start = i
+		}
+	}
+	return append(subpaths, segs[start:])
+}
+
+// validateClosedPath 校验子路径闭合
+func validateClosedPath(segs []segment) error {
+	if segs[len(segs)-1].Cmd == 'Z' {
+		return nil
+	}
+
+	// 没有Z但终点与起点重合也视为闭合
+	start := segs[0].Pts[0]
+	last := segs[len(segs)-1].Pts
+	end := last[len(last)-1]
+	min, max := bounds(segs)
+	tolerance := math.Hypot(max.X-min.X, max.Y-min.Y) * 1e-3
+	if math.Hypot(end.X-start.X, end.Y-start.Y) > tolerance {
+		return ErrOpenOutline
+	}
+	return nil
+}
+
+// bounds 计算包围盒，曲线通过采样近似
+func bounds(segs []segment) (point, point) {
+	min := point{math.Inf(1), math.Inf(1)}
+	max := point{math.Inf(-1), math.Inf(-1)}
+	extend := func(p point) {
+		min.X, min.Y = math.Min(min.X, p.X), math.Min(min.Y, p.Y)
+		max.X, max.Y = math.Max(max.X, p.X), math.Max(max.Y, p.Y)
+	}
+
+	var cur point
+	for _, seg := range segs {
+		switch seg.Cmd {
+		case 'M', 'L':
+			cur = seg.Pts[0]
+			extend(cur)
+		case 'C':
+			const samples = 16
+			for i := 1; i <= samples; i++ {
+				extend(cubicAt(cur, seg.Pts[0], seg.Pts[1], seg.Pts[2], float64(i)/samples))
+			}
+			cur = seg.Pts[2]
+		}
+	}
+	return min, max
+}
+
+func cubicAt(p0, c1, c2, p1 point, t float64) point {
+	mt := 1 - t
+	a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+	return point{
+		X: a*p0.X + b*c1.X + c*c2.X + d*p1.X,
+		Y: a*p0.Y + b*c1.Y + c*c2.Y + d*p1.Y,
+	}
+}
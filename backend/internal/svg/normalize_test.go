@@ -0,0 +1,141 @@
+package svg
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathData(t *testing.T) {
+	t.Run("紧凑数字写法", func(t *testing.T) {
+		segs, err := parsePathData("M322.67803 1249.724187c-16.79243.3452-30.80108.3694-31.13035.053-.32926-.3157-5.39548-.9364-11.25828-1.3797z")
+		require.NoError(t, err)
+		require.Len(t, segs, 4)
+		assert.Equal(t, byte('C'), segs[1].Cmd)
+		assert.InDelta(t, 322.67803-16.79243, segs[1].Pts[0].X, 1e-9)
+		assert.InDelta(t, 1249.724187+0.3452, segs[1].Pts[0].Y, 1e-9)
+		// 第二组隐式c命令相对于上一段终点
+		assert.InDelta(t, 322.67803-31.13035-0.32926, segs[2].Pts[0].X, 1e-9)
+	})
+
+	t.Run("隐式命令与H/V", func(t *testing.T) {
+		segs, err := parsePathData("m10 10 20 0v20h-20Z")
+		require.NoError(t, err)
+		require.Len(t, segs, 5)
+		assert.Equal(t, point{30, 10}, segs[1].Pts[0])
+		assert.Equal(t, point{30, 30}, segs[2].Pts[0])
+		assert.Equal(t, point{10, 30}, segs[3].Pts[0])
+		assert.Equal(t, byte('Z'), segs[4].Cmd)
+	})
+
+	t.Run("弧线标志位没有分隔符", func(t *testing.T) {
+		segs, err := parsePathData("M0 0a10 10 0 1110 0z")
+		require.NoError(t, err)
+		last := segs[len(segs)-2]
+		assert.Equal(t, byte('C'), last.Cmd)
+		assert.InDelta(t, 10, last.Pts[2].X, 1e-9)
+		assert.InDelta(t, 0, last.Pts[2].Y, 1e-9)
+	})
+
+	t.Run("非法数据", func(t *testing.T) {
+		_, err := parsePathData("10 10")
+		assert.Error(t, err)
+		_, err = parsePathData("M10")
+		assert.Error(t, err)
+		_, err = parsePathData("M0 0 X 1 1")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseTransform(t *testing.T) {
+	m, err := parseTransform("translate(10, 20) scale(2)")
+	require.NoError(t, err)
+	assert.Equal(t, point{12, 22}, m.apply(point{1, 1}))
+
+	m, err = parseTransform("rotate(90 5 5)")
+	require.NoError(t, err)
+	p := m.apply(point{10, 5})
+	assert.InDelta(t, 5, p.X, 1e-9)
+	assert.InDelta(t, 10, p.Y, 1e-9)
+
+	_, err = parseTransform("perspective(1)")
+	assert.Error(t, err)
+}
+
+func TestParseOutline(t *testing.T) {
+	t.Run("展平嵌套变换", func(t *testing.T) {
+		input := `<svg xmlns="http://www.w3.org/2000/svg"><g transform="translate(100 0)"><path transform="scale(2)" d="M0 0H10V20H0Z"/></g><circle cx="5" cy="5" r="1"/></svg>`
+		outline, err := ParseOutline([]byte(input))
+		require.NoError(t, err)
+		w, h := outline.Size()
+		assert.InDelta(t, 20, w, 1e-9)
+		assert.InDelta(t, 40, h, 1e-9)
+		assert.InDelta(t, 100, outline.min.X, 1e-9)
+	})
+
+	t.Run("校验轮廓", func(t *testing.T) {
+		_, err := ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><circle r="5"/></svg>`))
+		assert.ErrorIs(t, err, ErrNoOutline)
+
+		_, err = ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0H1V1Z"/><path d="M2 2H3V3Z"/></svg>`))
+		assert.ErrorIs(t, err, ErrMultipleOutlines)
+
+		_, err = ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0H1V1ZM2 2H3V3Z"/></svg>`))
+		assert.ErrorIs(t, err, ErrMultipleSubpaths)
+
+		_, err = ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0H10V10"/></svg>`))
+		assert.ErrorIs(t, err, ErrOpenOutline)
+
+		// 终点回到起点也视为闭合
+		_, err = ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><polyline points="0,0 10,0 10,10 0,0"/></svg>`))
+		assert.NoError(t, err)
+
+		// defs中的路径不参与
+		_, err = ParseOutline([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><defs><path d="M0 0H1V1Z"/></defs><polygon points="0,0 10,0 10,10"/></svg>`))
+		assert.NoError(t, err)
+	})
+}
+
+func TestCanonical(t *testing.T) {
+	input := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 400 200"><path transform="translate(50 50)" d="M0 0H200V100H0Z"/></svg>`
+	outline, err := ParseOutline([]byte(input))
+	require.NoError(t, err)
+
+	canonical, err := outline.Canonical(120)
+	require.NoError(t, err)
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 120 60" width="120mm" height="60mm"><path d="M 0 0 L 120 0 L 120 60 L 0 60 Z"/></svg>`, canonical)
+
+	_, err = outline.Canonical(0)
+	assert.ErrorIs(t, err, ErrMissingLength)
+
+	// 规范结果可以再次解析
+	again, err := ParseOutline([]byte(canonical))
+	require.NoError(t, err)
+	w, h := again.Size()
+	assert.InDelta(t, 120, w, 1e-9)
+	assert.InDelta(t, 60, h, 1e-9)
+}
+
+func TestNormalizeSampleOutlines(t *testing.T) {
+	files, _ := filepath.Glob("../../../SVG/*.svg")
+	if len(files) == 0 {
+		t.Skip("sample SVG directory not available")
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		result, err := Normalize(data, 120)
+		require.NoError(t, err, file)
+
+		outline, err := ParseOutline([]byte(result.Canonical))
+		require.NoError(t, err, file)
+		w, h := outline.Size()
+		assert.InDelta(t, 120, math.Max(w, h), 0.01, file)
+	}
+}
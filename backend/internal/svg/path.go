@@ -0,0 +1,437 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// point 二维坐标
+type point struct {
+	X, Y float64
+}
+
+// segment 绝对坐标下的路径片段
+// 解析后只保留 M、L、C、Z 四种命令：H/V转为L，S/Q/T/A统一转为三次贝塞尔曲线，方便做仿射变换
+type segment struct {
+	Cmd byte
+	Pts []point
+}
+
+// parsePathData 解析path的d属性
+func parsePathData(d string) ([]segment, error) {
+	sc := &pathScanner{s: d}
+	var segs []segment
+
+	var cur, start point
+	var lastCtrl point // 上一条曲线的第二控制点，用于S/T的反射
+	var lastCmd byte
+	var cmd byte
+
+	for {
+		sc.skipSeparators()
+		if sc.done() {
+			break
+		}
+
+		if c := sc.peek(); isCommand(c) {
+			cmd = c
+			sc.pos++
+		} else if cmd == 0 {
+			return nil, fmt.Errorf("path data must start with a command")
+		} else if cmd == 'Z' || cmd == 'z' {
+			return nil, fmt.Errorf("unexpected number after close path at %d", sc.pos)
+		}
+		// 没有新命令时重复上一条命令（M之后的隐式命令是L）
+
+		rel := cmd >= 'a' && cmd <= 'z'
+		offset := func(p point) point {
+			if rel {
+				return point{p.X + cur.X, p.Y + cur.Y}
+			}
+			return p
+		}
+
+		switch upper(cmd) {
+		case 'M':
+			p, err := sc.readPoint()
+			if err != nil {
+				return nil, err
+			}
+			cur = offset(p)
+			start = cur
+			segs = append(segs, segment{Cmd: 'M', Pts: []point{cur}})
+			// 后续的坐标对按L处理
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+			lastCmd = 'M'
+			continue
+
+		case 'L':
+			p, err := sc.readPoint()
+			if err != nil {
+				return nil, err
+			}
+			cur = offset(p)
+			segs = append(segs, segment{Cmd: 'L', Pts: []point{cur}})
+
+		case 'H':
+			x, err := sc.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				x += cur.X
+			}
+			cur = point{x, cur.Y}
+			segs = append(segs, segment{Cmd: 'L', Pts: []point{cur}})
+
+		case 'V':
+			y, err := sc.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			if rel {
+				y += cur.Y
+			}
+			cur = point{cur.X, y}
+			segs = append(segs, segment{Cmd: 'L', Pts: []point{cur}})
+
+		case 'C':
+			pts, err := sc.readPoints(3)
+			if err != nil {
+				return nil, err
+			}
+			c1, c2, end := offset(pts[0]), offset(pts[1]), offset(pts[2])
+			segs = append(segs, segment{Cmd: 'C', Pts: []point{c1, c2, end}})
+			lastCtrl, cur = c2, end
+
+		case 'S':
+			pts, err := sc.readPoints(2)
+			if err != nil {
+				return nil, err
+			}
+			c1 := cur
+			if u := upper(lastCmd); u == 'C' || u == 'S' {
+				c1 = reflect(lastCtrl, cur)
+			}
+			c2, end := offset(pts[0]), offset(pts[1])
+			segs = append(segs, segment{Cmd: 'C', Pts: []point{c1, c2, end}})
+			lastCtrl, cur = c2, end
+
+		case 'Q':
+			pts, err := sc.readPoints(2)
+			if err != nil {
+				return nil, err
+			}
+			q, end := offset(pts[0]), offset(pts[1])
+			segs = append(segs, quadToCubic(cur, q, end))
+			lastCtrl, cur = q, end
+
+		case 'T':
+			p, err := sc.readPoint()
+			if err != nil {
+				return nil, err
+			}
+			q := cur
+			if u := upper(lastCmd); u == 'Q' || u == 'T' {
+				q = reflect(lastCtrl, cur)
+			}
+			end := offset(p)
+			segs = append(segs, quadToCubic(cur, q, end))
+			lastCtrl, cur = q, end
+
+		case 'A':
+			rx, err := sc.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			ry, err := sc.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			rotation, err := sc.readNumber()
+			if err != nil {
+				return nil, err
+			}
+			largeArc, err := sc.readFlag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := sc.readFlag()
+			if err != nil {
+				return nil, err
+			}
+			p, err := sc.readPoint()
+			if err != nil {
+				return nil, err
+			}
+			end := offset(p)
+			segs = append(segs, arcToCubics(cur, end, rx, ry, rotation, largeArc, sweep)...)
+			cur = end
+
+		case 'Z':
+			segs = append(segs, segment{Cmd: 'Z'})
+			cur = start
+		}
+		lastCmd = cmd
+	}
+
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("empty path data")
+	}
+	return segs, nil
+}
+
+func isCommand(c byte) bool {
+	return strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// reflect 求控制点关于当前点的对称点
+func reflect(ctrl, cur point) point {
+	return point{2*cur.X - ctrl.X, 2*cur.Y - ctrl.Y}
+}
+
+// quadToCubic 二次贝塞尔曲线精确转换为三次贝塞尔曲线
+func quadToCubic(p0, q, p point) segment {
+	c1 := point{p0.X + 2.0/3.0*(q.X-p0.X), p0.Y + 2.0/3.0*(q.Y-p0.Y)}
+	c2 := point{p.X + 2.0/3.0*(q.X-p.X), p.Y + 2.0/3.0*(q.Y-p.Y)}
+	return segment{Cmd: 'C', Pts: []point{c1, c2, p}}
+}
+
+// arcToCubics 椭圆弧转换为若干段三次贝塞尔曲线（每段不超过90度）
+// 参考 SVG 1.1 附录 F.6 的端点参数到中心参数的换算
+func arcToCubics(p0, p point, rx, ry, rotationDeg float64, largeArc, sweep bool) []segment {
+	if p0 == p {
+		return nil
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []segment{{Cmd: 'L', Pts: []point{p}}}
+	}
+
+	phi := rotationDeg * math.Pi / 180
+	cosPhi, sinPhi := math.Cos(phi), math.Sin(phi)
+
+	dx, dy := (p0.X-p.X)/2, (p0.Y-p.Y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// 半径过小时按比例放大
+	lambda := (x1*x1)/(rx*rx) + (y1*y1)/(ry*ry)
+	if lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := 0.0
+	if den != 0 && num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+
+	cx := cosPhi*cx1 - sinPhi*cy1 + (p0.X+p.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (p0.Y+p.Y)/2
+
+	theta1 := vectorAngle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := vectorAngle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	if n < 1 {
+		n = 1
+	}
+	step := delta / float64(n)
+	k := 4.0 / 3.0 * math.Tan(step/4)
+
+	ellipse := func(t float64) (point, point) {
+		cosT, sinT := math.Cos(t), math.Sin(t)
+		pt := point{
+			cx + rx*cosT*cosPhi - ry*sinT*sinPhi,
+			cy + rx*cosT*sinPhi + ry*sinT*cosPhi,
+		}
+		deriv := point{
+			-rx*sinT*cosPhi - ry*cosT*sinPhi,
+			-rx*sinT*sinPhi + ry*cosT*cosPhi,
+		}
+		return pt, deriv
+	}
+
+	segs := make([]segment, 0, n)
+	t := theta1
+	from, d1 := ellipse(t)
+	for i := 0; i < n; i++ {
+		to, d2 := ellipse(t + step)
+		if i == n-1 {
+			to = p // 避免浮点误差
+		}
+		segs = append(segs, segment{Cmd: 'C', Pts: []point{
+			{from.X + k*d1.X, from.Y + k*d1.Y},
+			{to.X - k*d2.X, to.Y - k*d2.Y},
+			to,
+		}})
+		t += step
+		from, d1 = to, d2
+	}
+	return segs
+}
+
+func vectorAngle(ux, uy, vx, vy float64) float64 {
+	return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+}
+
+// pathScanner path数据的词法扫描器
+type pathScanner struct {
+	s   string
+	pos int
+}
+
+func (sc *pathScanner) done() bool {
+	return sc.pos >= len(sc.s)
+}
+
+func (sc *pathScanner) peek() byte {
+	return sc.s[sc.pos]
+}
+
+func (sc *pathScanner) skipSeparators() {
+	for !sc.done() {
+		switch sc.peek() {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			sc.pos++
+		default:
+			return
+		}
+	}
+}
+
+// readNumber 读取一个数字，支持 "-.5-.3"、".5.5"、"1e-3" 这类紧凑写法
+func (sc *pathScanner) readNumber() (float64, error) {
+	sc.skipSeparators()
+	begin := sc.pos
+	if !sc.done() && (sc.peek() == '+' || sc.peek() == '-') {
+		sc.pos++
+	}
+	digits, seenDot := 0, false
+	for !sc.done() {
+		c := sc.peek()
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !seenDot {
+			seenDot = true
+		} else {
+			break
+		}
+		sc.pos++
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("expected number at %d", begin)
+	}
+	// 指数部分
+	if !sc.done() && (sc.peek() == 'e' || sc.peek() == 'E') {
+		save := sc.pos
+		sc.pos++
+		if !sc.done() && (sc.peek() == '+' || sc.peek() == '-') {
+			sc.pos++
+		}
+		expDigits := 0
+		for !sc.done() && sc.peek() >= '0' && sc.peek() <= '9' {
+			sc.pos++
+			expDigits++
+		}
+		if expDigits == 0 {
+			sc.pos = save
+		}
+	}
+	return strconv.ParseFloat(sc.s[begin:sc.pos], 64)
+}
+
+// readFlag 读取弧线命令的标志位，标志位之间可以没有分隔符
+func (sc *pathScanner) readFlag() (bool, error) {
+	sc.skipSeparators()
+	if sc.done() {
+		return false, fmt.Errorf("expected flag at %d", sc.pos)
+	}
+	switch sc.peek() {
+	case '0':
+		sc.pos++
+		return false, nil
+	case '1':
+		sc.pos++
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid flag at %d", sc.pos)
+}
+
+func (sc *pathScanner) readPoint() (point, error) {
+	x, err := sc.readNumber()
+	if err != nil {
+		return point{}, err
+	}
+	y, err := sc.readNumber()
+	if err != nil {
+		return point{}, err
+	}
+	return point{x, y}, nil
+}
+
+func (sc *pathScanner) readPoints(n int) ([]point, error) {
+	pts := make([]point, n)
+	for i := range pts {
+		p, err := sc.readPoint()
+		if err != nil {
+			return nil, err
+		}
+		pts[i] = p
+	}
+	return pts, nil
+}
+
+// formatPathData 将片段序列化为d属性
+func formatPathData(segs []segment) string {
+	var b strings.Builder
+	for i, seg := range segs {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte(seg.Cmd)
+		for _, p := range seg.Pts {
+			b.WriteByte(' ')
+			b.WriteString(formatNumber(p.X))
+			b.WriteByte(' ')
+			b.WriteString(formatNumber(p.Y))
+		}
+	}
+	return b.String()
+}
+
+// formatNumber 保留三位小数（毫米坐标下即微米精度）
+func formatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
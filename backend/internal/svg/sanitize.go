@@ -0,0 +1,111 @@
+package svg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// allowedElements 允许保留的SVG元素，其余元素连同子树一起移除
+// script、foreignObject、style、image、a、animate/set 等都不在列表中
+var allowedElements = map[string]bool{
+	"svg":            true,
+	"g":              true,
+	"defs":           true,
+	"title":          true,
+	"desc":           true,
+	"path":           true,
+	"rect":           true,
+	"circle":         true,
+	"ellipse":        true,
+	"line":           true,
+	"polyline":       true,
+	"polygon":        true,
+	"use":            true,
+	"symbol":         true,
+	"clipPath":       true,
+	"mask":           true,
+	"linearGradient": true,
+	"radialGradient": true,
+	"stop":           true,
+	"text":           true,
+	"tspan":          true,
+}
+
+// textElements 允许保留文本内容的元素
+var textElements = map[string]bool{
+	"title": true,
+	"desc":  true,
+	"text":  true,
+	"tspan": true,
+}
+
+// urlPattern 匹配属性值和样式中的url(...)引用
+var urlPattern = regexp.MustCompile(`(?i)url\s*\(\s*['"]?\s*([^'")\s]*)`)
+
+// Sanitize 校验并清洗SVG
+// 移除脚本、事件处理器、foreignObject等不安全元素以及所有外部引用，只允许文档内部的 #id 引用
+func Sanitize(data []byte) (string, error) {
+	root, err := parse(data)
+	if err != nil {
+		return "", err
+	}
+	sanitizeNode(root)
+	return render(root), nil
+}
+
+func sanitizeNode(n *node) {
+	attrs := n.Attrs[:0]
+	for _, a := range n.Attrs {
+		if isSafeAttr(a) {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attrs = attrs
+
+	if !textElements[n.Name] {
+		n.Text = ""
+	}
+
+	children := n.Children[:0]
+	for _, child := range n.Children {
+		if !allowedElements[child.Name] {
+			continue
+		}
+		sanitizeNode(child)
+		children = append(children, child)
+	}
+	n.Children = children
+}
+
+// isSafeAttr 判断属性是否可以保留
+func isSafeAttr(a attr) bool {
+	name := strings.ToLower(a.Name)
+
+	// 事件处理器 onload、onclick 等
+	if strings.HasPrefix(name, "on") {
+		return false
+	}
+
+	value := strings.ToLower(strings.Join(strings.Fields(a.Value), ""))
+	if strings.Contains(value, "javascript:") || strings.Contains(value, "vbscript:") || strings.Contains(value, "data:") {
+		return false
+	}
+
+	// 链接只允许指向文档内部
+	if name == "href" || name == "xlink:href" {
+		return strings.HasPrefix(strings.TrimSpace(a.Value), "#")
+	}
+
+	if name == "style" && (strings.Contains(value, "expression(") || strings.Contains(value, "@import")) {
+		return false
+	}
+
+	// url(...) 同样只允许内部引用
+	for _, match := range urlPattern.FindAllStringSubmatch(a.Value, -1) {
+		if !strings.HasPrefix(match[1], "#") {
+			return false
+		}
+	}
+
+	return true
+}
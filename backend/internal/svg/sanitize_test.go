@@ -0,0 +1,56 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Run("保留轮廓数据", func(t *testing.T) {
+		input := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 50" id="SVG 91" xmlns:xlink="http://www.w3.org/1999/xlink"><path d="M0 0L100 0L100 50Z"></path><circle cx="51%" cy="calc(100% - 4px)" r="5" fill="#ff00ee"></circle></svg>`
+		result, err := Sanitize([]byte(input))
+		assert.NoError(t, err)
+		assert.Contains(t, result, `viewBox="0 0 100 50"`)
+		assert.Contains(t, result, `<path d="M0 0L100 0L100 50Z"/>`)
+		assert.Contains(t, result, `<circle`)
+		assert.NotContains(t, result, "xmlns:xlink", "未使用xlink时不输出声明")
+	})
+
+	t.Run("移除脚本和事件处理器", func(t *testing.T) {
+		input := `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><script>alert(1)</script><g onclick="x()"><path d="M0 0Z" onmouseover="y()"/></g><foreignObject><div xmlns="http://www.w3.org/1999/xhtml">hi</div></foreignObject></svg>`
+		result, err := Sanitize([]byte(input))
+		assert.NoError(t, err)
+		assert.NotContains(t, result, "script")
+		assert.NotContains(t, result, "alert")
+		assert.NotContains(t, result, "onclick")
+		assert.NotContains(t, result, "onmouseover")
+		assert.NotContains(t, result, "foreignObject")
+		assert.NotContains(t, result, "hi")
+		assert.Contains(t, result, `<path d="M0 0Z"/>`)
+	})
+
+	t.Run("移除外部引用", func(t *testing.T) {
+		input := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><image href="http://evil.test/a.png"/><use xlink:href="http://evil.test/a.svg#x"/><use xlink:href="#outline"/><path id="outline" d="M0 0Z" fill="url(http://evil.test/p)" style="fill:url(#grad)"/><a href="javascript:alert(1)"><path d="M1 1Z"/></a></svg>`
+		result, err := Sanitize([]byte(input))
+		assert.NoError(t, err)
+		assert.NotContains(t, result, "evil.test")
+		assert.NotContains(t, result, "javascript")
+		assert.NotContains(t, result, "<image")
+		assert.NotContains(t, result, "<a")
+		assert.Contains(t, result, `xlink:href="#outline"`)
+		assert.Contains(t, result, `style="fill:url(#grad)"`)
+		assert.Contains(t, result, `xmlns:xlink=`)
+	})
+
+	t.Run("非法文档", func(t *testing.T) {
+		_, err := Sanitize([]byte(`<svg><path></svg>`))
+		assert.ErrorIs(t, err, ErrInvalidSVG)
+
+		_, err = Sanitize([]byte(`<html><body/></html>`))
+		assert.ErrorIs(t, err, ErrNotSVG)
+
+		_, err = Sanitize([]byte(``))
+		assert.ErrorIs(t, err, ErrInvalidSVG)
+	})
+}
@@ -0,0 +1,149 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// matrix 仿射变换矩阵 [a c e; b d f; 0 0 1]
+type matrix struct {
+	A, B, C, D, E, F float64
+}
+
+var identity = matrix{A: 1, D: 1}
+
+// multiply 返回 m × n（先应用n，再应用m）
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+func (m matrix) apply(p point) point {
+	return point{
+		X: m.A*p.X + m.C*p.Y + m.E,
+		Y: m.B*p.X + m.D*p.Y + m.F,
+	}
+}
+
+// applySegments 对所有片段应用变换，M/L/C在仿射变换下保持不变
+func (m matrix) applySegments(segs []segment) []segment {
+	if m == identity {
+		return segs
+	}
+	out := make([]segment, len(segs))
+	for i, seg := range segs {
+		pts := make([]point, len(seg.Pts))
+		for j, p := range seg.Pts {
+			pts[j] = m.apply(p)
+		}
+		out[i] = segment{Cmd: seg.Cmd, Pts: pts}
+	}
+	return out
+}
+
+// parseTransform 解析transform属性，例如 "translate(10 20) rotate(45) scale(2)"
+func parseTransform(s string) (matrix, error) {
+	result := identity
+	rest := strings.TrimSpace(s)
+
+	for rest != "" {
+		open := strings.IndexByte(rest, '(')
+		closeIdx := strings.IndexByte(rest, ')')
+		if open <= 0 || closeIdx < open {
+			return identity, fmt.Errorf("invalid transform: %q", s)
+		}
+
+		name := strings.TrimSpace(rest[:open])
+		sc := &pathScanner{s: rest[open+1 : closeIdx]}
+		var args []float64
+		for {
+			sc.skipSeparators()
+			if sc.done() {
+				break
+			}
+			v, err := sc.readNumber()
+			if err != nil {
+				return identity, fmt.Errorf("invalid transform arguments: %q", s)
+			}
+			args = append(args, v)
+		}
+
+		m, err := transformMatrix(name, args)
+		if err != nil {
+			return identity, err
+		}
+		result = result.multiply(m)
+
+		rest = strings.TrimLeft(rest[closeIdx+1:], " \t\n\r,")
+	}
+	return result, nil
+}
+
+func transformMatrix(name string, args []float64) (matrix, error) {
+	argCount := func(counts ...int) bool {
+		for _, c := range counts {
+			if len(args) == c {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch name {
+	case "matrix":
+		if !argCount(6) {
+			break
+		}
+		return matrix{args[0], args[1], args[2], args[3], args[4], args[5]}, nil
+	case "translate":
+		if !argCount(1, 2) {
+			break
+		}
+		m := matrix{A: 1, D: 1, E: args[0]}
+		if len(args) == 2 {
+			m.F = args[1]
+		}
+		return m, nil
+	case "scale":
+		if !argCount(1, 2) {
+			break
+		}
+		sy := args[0]
+		if len(args) == 2 {
+			sy = args[1]
+		}
+		return matrix{A: args[0], D: sy}, nil
+	case "rotate":
+		if !argCount(1, 3) {
+			break
+		}
+		rad := args[0] * math.Pi / 180
+		cos, sin := math.Cos(rad), math.Sin(rad)
+		m := matrix{A: cos, B: sin, C: -sin, D: cos}
+		if len(args) == 3 {
+			cx, cy := args[1], args[2]
+			m = matrix{A: 1, D: 1, E: cx, F: cy}.multiply(m).multiply(matrix{A: 1, D: 1, E: -cx, F: -cy})
+		}
+		return m, nil
+	case "skewX":
+		if !argCount(1) {
+			break
+		}
+		return matrix{A: 1, C: math.Tan(args[0] * math.Pi / 180), D: 1}, nil
+	case "skewY":
+		if !argCount(1) {
+			break
+		}
+		return matrix{A: 1, B: math.Tan(args[0] * math.Pi / 180), D: 1}, nil
+	default:
+		return identity, fmt.Errorf("unsupported transform: %s", name)
+	}
+	return identity, fmt.Errorf("invalid number of arguments for %s", name)
+}
@@ -5,6 +5,7 @@ type UploadConfig struct {
 	AllowTypes []string `yaml:"allowTypes"`
 	MaxSize    int64    `yaml:"maxSize"` // MB
 	UrlPrefix  string   `yaml:"urlPrefix"`
+	MaxFiles   int      `yaml:"maxFiles"` // 批量上传时的文件数量上限
 }
 
 var DefaultConfig = UploadConfig{
@@ -13,3 +14,10 @@ var DefaultConfig = UploadConfig{
 	MaxSize:    5, // 5MB
 	UrlPrefix:  "/static/uploads",
 }
+
+// SVGConfig SVG轮廓批量上传配置，单个文件（包括zip中的条目）沿用默认大小限制
+var SVGConfig = UploadConfig{
+	AllowTypes: []string{".svg", ".zip"},
+	MaxSize:    DefaultConfig.MaxSize,
+	MaxFiles:   200,
+}
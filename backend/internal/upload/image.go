@@ -0,0 +1,273 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxImagePixels 解码前按图片头检查像素数，防止解压炸弹
+	MaxImagePixels = 40_000_000
+	// ThumbnailSize 缩略图的最长边
+	ThumbnailSize = 320
+
+	imageQuality     = 90
+	thumbnailQuality = 80
+)
+
+// ImageConfig 评测图片上传配置，每篇评测最多MaxFiles张
+var ImageConfig = UploadConfig{
+	SavePath:   DefaultConfig.SavePath,
+	AllowTypes: DefaultConfig.AllowTypes,
+	MaxSize:    DefaultConfig.MaxSize,
+	UrlPrefix:  DefaultConfig.UrlPrefix,
+	MaxFiles:   9,
+}
+
+// imageExts 允许的图片类型（按内容识别）及保存时使用的扩展名
+var imageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// Image 处理后的图片，重新编码后不再包含EXIF等元数据
+type Image struct {
+	ContentType     string
+	Ext             string
+	Data            []byte
+	Width           int
+	Height          int
+	Thumbnail       []byte
+	ThumbnailWidth  int
+	ThumbnailHeight int
+}
+
+// SniffImage 按文件内容识别图片类型，不信任扩展名
+func SniffImage(content []byte) (string, error) {
+	contentType := http.DetectContentType(content)
+	if _, ok := imageExts[contentType]; !ok {
+		return "", fmt.Errorf("unsupported image type：%s", contentType)
+	}
+	return contentType, nil
+}
+
+// ProcessImage 校验并重新编码图片：按EXIF方向旋转后丢弃全部元数据，并生成缩略图
+func ProcessImage(content []byte) (*Image, error) {
+	contentType, err := SniffImage(content)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are not allowed", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	img := toNRGBA(src)
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(content))
+	}
+
+	data, err := encodeImage(img, contentType, imageQuality)
+	if err != nil {
+		return nil, err
+	}
+	thumb := resize(img, ThumbnailSize)
+	thumbData, err := encodeImage(thumb, contentType, thumbnailQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		ContentType:     contentType,
+		Ext:             imageExts[contentType],
+		Data:            data,
+		Width:           img.Bounds().Dx(),
+		Height:          img.Bounds().Dy(),
+		Thumbnail:       thumbData,
+		ThumbnailWidth:  thumb.Bounds().Dx(),
+		ThumbnailHeight: thumb.Bounds().Dy(),
+	}, nil
+}
+
+func encodeImage(img image.Image, contentType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Rect.Min == (image.Point{}) {
+		return img
+	}
+	b := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Src)
+	return img
+}
+
+// resize 按比例缩小到最长边不超过size，使用区域平均，小图原样返回
+func resize(src *image.NRGBA, size int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := y*h/th, (y+1)*h/th
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < tw; x++ {
+			x0, x1 := x*w/tw, (x+1)*w/tw
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// jpegOrientation 读取JPEG中EXIF的方向标记(0x0112)，没有时返回1
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(content); {
+		if content[i] != 0xFF {
+			return 1
+		}
+		marker := content[i+1]
+		if marker == 0xDA || marker == 0xD9 { // 图像数据开始，后面不会再有EXIF
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(content[i+2:]))
+		if length < 2 || i+2+length > len(content) {
+			return 1
+		}
+		segment := content[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation 按EXIF方向把图片转为正向
+func applyOrientation(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // 5-8需要交换宽高
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], src.Pix[y*src.Stride+x*4:y*src.Stride+x*4+4])
+		}
+	}
+	return dst
+}
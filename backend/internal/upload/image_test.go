@@ -0,0 +1,81 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withExifOrientation 在JPEG的SOI之后插入只包含方向标记的EXIF段
+func withExifOrientation(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x0112))
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+func TestProcessImageStripsExifAndRotates(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 640, 320))
+	for x := 0; x < 640; x++ {
+		for y := 0; y < 320; y++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, src, nil))
+	content := withExifOrientation(t, buf.Bytes(), 6)
+	require.Equal(t, 6, jpegOrientation(content))
+
+	img, err := ProcessImage(content)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", img.ContentType)
+	assert.Equal(t, ".jpg", img.Ext)
+	assert.Equal(t, 320, img.Width)
+	assert.Equal(t, 640, img.Height)
+	assert.False(t, bytes.Contains(img.Data, []byte("Exif")))
+	assert.Equal(t, 1, jpegOrientation(img.Data))
+
+	assert.Equal(t, 160, img.ThumbnailWidth)
+	assert.Equal(t, ThumbnailSize, img.ThumbnailHeight)
+	thumb, err := jpeg.Decode(bytes.NewReader(img.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 160, ThumbnailSize), thumb.Bounds())
+}
+
+func TestProcessImageSniffsContent(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 10, 10))))
+	img, err := ProcessImage(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, 10, img.ThumbnailWidth)
+
+	_, err = ProcessImage([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.Error(t, err)
+	_, err = ProcessImage([]byte("GIF89a not really"))
+	assert.Error(t, err)
+}
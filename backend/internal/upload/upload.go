@@ -1,23 +1,112 @@
 package upload
 
 import (
+	"archive/zip"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"path"
+	"strings"
 )
 
+// File 从上传请求或压缩包中读取的文件
+type File struct {
+	Name    string
+	Content []byte
+}
+
 func CheckFile(file *multipart.FileHeader) error {
+	return CheckFileWithConfig(file, DefaultConfig)
+}
+
+// CheckFileWithConfig 按指定配置检查上传文件的大小和类型
+func CheckFileWithConfig(file *multipart.FileHeader, cfg UploadConfig) error {
 	// 检查大小
-	if file.Size > DefaultConfig.MaxSize<<20 {
-		return fmt.Errorf("file exceeds the maximum size：%dMB", DefaultConfig.MaxSize)
+	if file.Size > cfg.MaxSize<<20 {
+		return fmt.Errorf("file exceeds the maximum size：%dMB", cfg.MaxSize)
 	}
 
 	// 检查类型
-	ext := path.Ext(file.Filename)
-	for _, allowType := range DefaultConfig.AllowTypes {
+	ext := strings.ToLower(path.Ext(file.Filename))
+	for _, allowType := range cfg.AllowTypes {
 		if ext == allowType {
 			return nil
 		}
 	}
 	return fmt.Errorf("unsupported file type：%s", ext)
 }
+
+// ReadFile 读取上传文件内容
+func ReadFile(file *multipart.FileHeader, cfg UploadConfig) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readLimited(f, cfg.MaxSize<<20)
+}
+
+// ReadZip 读取zip压缩包中扩展名符合allowTypes的文件
+// 每个条目解压后的大小和条目数量都受cfg限制，防止压缩炸弹
+func ReadZip(file *multipart.FileHeader, cfg UploadConfig, allowTypes []string) ([]File, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader, err := zip.NewReader(f, file.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	var files []File
+	for _, entry := range reader.File {
+		name := entry.Name
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		if !hasAllowedExt(name, allowTypes) {
+			continue
+		}
+		if cfg.MaxFiles > 0 && len(files) >= cfg.MaxFiles {
+			return nil, fmt.Errorf("archive contains more than %d files", cfg.MaxFiles)
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", name, err)
+		}
+		content, err := readLimited(rc, cfg.MaxSize<<20)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		files = append(files, File{Name: path.Base(name), Content: content})
+	}
+	return files, nil
+}
+
+// readLimited 读取内容，超过上限时返回错误
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("file exceeds the maximum size：%dMB", limit>>20)
+	}
+	return content, nil
+}
+
+func hasAllowedExt(name string, allowTypes []string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, allowType := range allowTypes {
+		if ext == allowType {
+			return true
+		}
+	}
+	return false
+}
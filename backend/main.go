@@ -111,6 +111,10 @@ func main() {
 
 	// 初始化路由
 	r := gin.Default()
+	// 浏览计数等按客户端IP区分访客，不能信任任意来源的X-Forwarded-For
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
 
 	// 健康检查路由 - 只在根路由添加，不在子路由添加
 	r.GET("/api/health", func(c *gin.Context) {
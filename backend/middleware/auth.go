@@ -14,77 +14,103 @@ import (
 
 // Auth validates the JWT token and adds claims to context
 func Auth(jwtService ...jwt.Service) gin.HandlerFunc {
-	var jwtSvc jwt.Service
-	if len(jwtService) > 0 {
-		jwtSvc = jwtService[0]
-	}
+	jwtSvc := injectedJWTService(jwtService)
 
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(401, gin.H{
-				"code":    errors.Unauthorized,
-				"message": "Authorization header required",
-			})
-			return
-		}
-
-		parts := strings.SplitN(authHeader, " ", 2)
-		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			c.AbortWithStatusJSON(401, gin.H{
-				"code":    errors.Unauthorized,
-				"message": "Invalid authorization format",
-			})
+		claims, failure := authenticate(c, jwtSvc)
+		if failure != nil {
+			c.AbortWithStatusJSON(401, failure)
 			return
 		}
 
-		// Use a context with timeout for token operations
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-		defer cancel()
+		setClaims(c, claims)
+		c.Next()
+	}
+}
 
-		var claims *jwt.Claims
-		var err error
+// OptionalAuth 公开路由使用：令牌有效时写入用户信息，没有令牌或令牌无效时按匿名访问继续
+func OptionalAuth(jwtService ...jwt.Service) gin.HandlerFunc {
+	jwtSvc := injectedJWTService(jwtService)
 
-		if jwtSvc != nil {
-			// 使用注入的JWT服务
-			claims, err = jwtSvc.ParseToken(parts[1])
-		} else {
-			// 使用全局JWT服务（向后兼容）
-			claims, err = jwt.ParseToken(parts[1])
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			if claims, failure := authenticate(c, jwtSvc); failure == nil {
+				setClaims(c, claims)
+			}
 		}
+		c.Next()
+	}
+}
 
-		if err != nil {
-			c.AbortWithStatusJSON(401, err)
-			return
-		}
+func injectedJWTService(jwtService []jwt.Service) jwt.Service {
+	if len(jwtService) > 0 {
+		return jwtService[0]
+	}
+	return nil
+}
 
-		// Verify token exists in Redis and matches the provided token
-		tokenManager := token.NewManager(database.RedisClient)
-		exists, err := tokenManager.CheckTokenExists(ctx, claims.UserID, claims.DeviceID, "access", parts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(401, gin.H{
-				"code":    errors.Unauthorized,
-				"message": "Unable to verify token",
-			})
-			return
+// authenticate 校验Authorization头中的访问令牌，失败时返回给客户端的错误内容
+func authenticate(c *gin.Context, jwtSvc jwt.Service) (*jwt.Claims, interface{}) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil, gin.H{
+			"code":    errors.Unauthorized,
+			"message": "Authorization header required",
 		}
+	}
 
-		if !exists {
-			c.AbortWithStatusJSON(401, gin.H{
-				"code":    errors.Unauthorized,
-				"message": "Token has been revoked",
-			})
-			return
+	parts := strings.SplitN(authHeader, " ", 2)
+	if !(len(parts) == 2 && parts[0] == "Bearer") {
+		return nil, gin.H{
+			"code":    errors.Unauthorized,
+			"message": "Invalid authorization format",
 		}
+	}
 
-		// Set claims to context
-		c.Set("userId", claims.UserID)
-		c.Set("userRole", claims.Role)
-		c.Set("deviceId", claims.DeviceID)
-		c.Set("tokenType", claims.Type)
+	// Use a context with timeout for token operations
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
 
-		c.Next()
+	var claims *jwt.Claims
+	var err error
+
+	if jwtSvc != nil {
+		// 使用注入的JWT服务
+		claims, err = jwtSvc.ParseToken(parts[1])
+	} else {
+		// 使用全局JWT服务（向后兼容）
+		claims, err = jwt.ParseToken(parts[1])
+	}
+
+	if err != nil {
+		return nil, err
 	}
+
+	// Verify token exists in Redis and matches the provided token
+	tokenManager := token.NewManager(database.RedisClient)
+	exists, err := tokenManager.CheckTokenExists(ctx, claims.UserID, claims.DeviceID, "access", parts[1])
+	if err != nil {
+		return nil, gin.H{
+			"code":    errors.Unauthorized,
+			"message": "Unable to verify token",
+		}
+	}
+
+	if !exists {
+		return nil, gin.H{
+			"code":    errors.Unauthorized,
+			"message": "Token has been revoked",
+		}
+	}
+	return claims, nil
+}
+
+// setClaims Set claims to context
+func setClaims(c *gin.Context, claims *jwt.Claims) {
+	c.Set("userId", claims.UserID)
+	c.Set("userRole", claims.Role)
+	c.Set("deviceId", claims.DeviceID)
+	c.Set("tokenType", claims.Type)
 }
 
 // RequireRoles validates user roles
@@ -97,6 +97,11 @@ func (s *AuthMiddlewareSuite) setupTestRoutes() {
 		})
 	}
 
+	// 可选认证的公开路由
+	s.router.GET("/optional", OptionalAuth(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "userId": c.GetString("userId")})
+	})
+
 	// 需要特定角色的路由
 	adminRoutes := s.router.Group("/admin")
 	adminRoutes.Use(Auth(), RequireRoles("admin"))
@@ -171,6 +176,35 @@ func (s *AuthMiddlewareSuite) TestAuthenticatedRouteWithValidToken() {
 	assert.Equal(s.T(), role, response["userRole"])
 }
 
+// TestOptionalAuth 测试可选认证：有效令牌写入用户，没有令牌或令牌无效时按匿名访问
+func (s *AuthMiddlewareSuite) TestOptionalAuth() {
+	token := s.generateValidToken("optional_user_id", "user", "test_device_id")
+
+	tests := []struct {
+		name   string
+		header string
+		userID string
+	}{
+		{"有效令牌", "Bearer " + token, "optional_user_id"},
+		{"没有令牌", "", ""},
+		{"无效令牌", "Bearer invalid.token.string", ""},
+		{"格式错误", "InvalidFormat token123", ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", "/optional", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+
+		assert.Equal(s.T(), http.StatusOK, w.Code, tt.name)
+		var response map[string]interface{}
+		assert.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &response), tt.name)
+		assert.Equal(s.T(), tt.userID, response["userId"], tt.name)
+	}
+}
+
 // TestAuthenticatedRouteWithoutToken 测试没有令牌的认证路由
 func (s *AuthMiddlewareSuite) TestAuthenticatedRouteWithoutToken() {
 	req, _ := http.NewRequest("GET", "/auth/user", nil)
@@ -27,8 +27,8 @@ func CORS() gin.HandlerFunc {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,X-CSRF-Token")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,X-CSRF-Token,Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token,Idempotent-Replayed")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
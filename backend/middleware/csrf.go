@@ -3,11 +3,15 @@ package middleware
 import (
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/csrf"
 )
 
+// paymentWebhookPathPrefix 支付回调路径前缀
+const paymentWebhookPathPrefix = "/api/payments/webhook/"
+
 // CSRFProtection returns a real CSRF protection middleware.
 // It expects the frontend to read the X-XSRF-TOKEN response header/cookie and send it back
 // in the X-XSRF-TOKEN request header for state-changing requests.
@@ -30,6 +34,11 @@ func CSRFProtection() gin.HandlerFunc {
 	)
 
 	return func(c *gin.Context) {
+		// 支付渠道回调不带CSRF令牌，由回调签名校验来源
+		if strings.HasPrefix(c.Request.URL.Path, paymentWebhookPathPrefix) {
+			c.Request = csrf.UnsafeSkipCheck(c.Request)
+		}
+
 		served := false
 		protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			c.Request = r
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+
+	"project/backend/internal/database"
+	"project/backend/internal/errors"
+)
+
+const (
+	// IdempotencyKeyHeader 客户端为同一次操作生成的唯一键，重试时保持不变
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader 标记响应来自缓存
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyPrefix   = "idempotency:"
+	maxIdempotencyKeyLen   = 255
+	idempotencyResponseTTL = 24 * time.Hour
+	// idempotencyLockTTL 请求处理中的占位时长，进程异常退出后占位自动失效
+	idempotencyLockTTL = time.Minute
+)
+
+// idempotencyRecord 保存在Redis中的请求记录
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// idempotencyWriter 记录响应内容以便缓存
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 带Idempotency-Key的请求只执行一次，重试时返回首次的响应
+// 键按用户和接口隔离；同一个键携带不同的请求体返回422，首次请求仍在处理时返回409
+// 服务端错误不缓存，客户端可以用同一个键重试。需放在认证中间件之后
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "Idempotency-Key过长"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.NewAppError(errors.BadRequest, "读取请求失败"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		redisKey := idempotencyRedisKey(c.GetString("userId"), c.Request.Method, c.FullPath(), key)
+		fingerprint := sha256.Sum256(body)
+		record := idempotencyRecord{Fingerprint: hex.EncodeToString(fingerprint[:])}
+
+		pending, _ := json.Marshal(record)
+		acquired, err := database.RedisClient.SetNX(ctx, redisKey, pending, idempotencyLockTTL).Result()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, "幂等校验失败"))
+			return
+		}
+
+		if !acquired {
+			replayIdempotentResponse(c, redisKey, record.Fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// 客户端断开后请求已经处理完成，结果仍需写入，否则占位过期后重试会重复执行
+		saveCtx := context.WithoutCancel(ctx)
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := database.RedisClient.Del(saveCtx, redisKey).Err(); err != nil {
+				log.Printf("删除幂等占位失败: key=%s, %v", redisKey, err)
+			}
+			return
+		}
+
+		record.Done = true
+		record.Status = status
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.Body = writer.body.Bytes()
+		data, err := json.Marshal(record)
+		if err != nil {
+			log.Printf("序列化幂等响应失败: key=%s, %v", redisKey, err)
+			return
+		}
+		if err := database.RedisClient.Set(saveCtx, redisKey, data, idempotencyResponseTTL).Err(); err != nil {
+			log.Printf("保存幂等响应失败: key=%s, %v", redisKey, err)
+		}
+	}
+}
+
+// replayIdempotentResponse 返回已缓存的响应
+func replayIdempotentResponse(c *gin.Context, redisKey, fingerprint string) {
+	data, err := database.RedisClient.Get(c.Request.Context(), redisKey).Bytes()
+	if err == redis.Nil {
+		// 占位刚好过期或首次请求失败后被删除，让客户端重试
+		c.AbortWithStatusJSON(http.StatusConflict, errors.NewAppError(errors.Conflict, "请求正在处理中，请稍后重试"))
+		return
+	}
+	var record idempotencyRecord
+	if err != nil || json.Unmarshal(data, &record) != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.NewAppError(errors.InternalError, "幂等校验失败"))
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errors.NewAppError(errors.Validation, "Idempotency-Key已用于其他请求"))
+		return
+	}
+	if !record.Done {
+		c.AbortWithStatusJSON(http.StatusConflict, errors.NewAppError(errors.Conflict, "请求正在处理中，请稍后重试"))
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// idempotencyRedisKey 按用户、方法和路由隔离，键本身做哈希避免过长或包含特殊字符
+func idempotencyRedisKey(userID, method, route, key string) string {
+	sum := sha256.Sum256([]byte(method + " " + route + " " + key))
+	return idempotencyKeyPrefix + userID + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/internal/database"
+)
+
+func setupIdempotencyRouter(t *testing.T, status int) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	calls := 0
+	router := gin.New()
+	router.POST("/orders", func(c *gin.Context) {
+		c.Set("userId", c.GetHeader("X-User"))
+	}, Idempotency(), func(c *gin.Context) {
+		calls++
+		c.JSON(status, gin.H{"call": calls})
+	})
+	return router, &calls
+}
+
+func postOrder(router *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	router, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	first := postOrder(router, "u1", "key-1", `{"a":1}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replay := postOrder(router, "u1", "key-1", `{"a":1}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, *calls)
+
+	// 同一个键用于不同的请求体
+	assert.Equal(t, http.StatusUnprocessableEntity, postOrder(router, "u1", "key-1", `{"a":2}`).Code)
+
+	// 不同用户的同名键互不影响；不带键的请求不做处理
+	assert.Equal(t, http.StatusCreated, postOrder(router, "u2", "key-1", `{"a":1}`).Code)
+	postOrder(router, "u1", "", `{"a":1}`)
+	postOrder(router, "u1", "", `{"a":1}`)
+	assert.Equal(t, 4, *calls)
+}
+
+func TestIdempotencyDoesNotCacheServerErrors(t *testing.T) {
+	router, calls := setupIdempotencyRouter(t, http.StatusInternalServerError)
+
+	postOrder(router, "u1", "key-1", `{}`)
+	postOrder(router, "u1", "key-1", `{}`)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencySavesResponseAfterClientDisconnects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	router := gin.New()
+	router.POST("/orders", Idempotency(), func(c *gin.Context) {
+		calls++
+		// 订单已提交，客户端在响应前断开
+		cancel()
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"a":1}`)).WithContext(ctx)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// 占位过期后重试仍返回首次的结果
+	mr.FastForward(idempotencyLockTTL + time.Second)
+	retry := postOrder(router, "", "key-1", `{"a":1}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+}
@@ -7,20 +7,29 @@ import (
 )
 
 // CartItem 表示购物车中的单个商品
+// 名称、价格和图片是加购时的快照，读取购物车时会按商品目录重新定价
 type CartItem struct {
-	ProductID   primitive.ObjectID `bson:"product_id" json:"product_id"`
+	ProductID   primitive.ObjectID `bson:"product_id" json:"product_id"` // 设备ID
+	SKUID       primitive.ObjectID `bson:"sku_id" json:"sku_id"`
 	ProductType string             `bson:"product_type" json:"product_type"` // "mouse", "keyboard", 等
 	Name        string             `bson:"name" json:"name"`
-	Price       float64            `bson:"price" json:"price"`
+	Price       int64              `bson:"price" json:"price"` // 单价（分）
+	Currency    string             `bson:"currency" json:"currency"`
 	Quantity    int                `bson:"quantity" json:"quantity"`
 	ImageURL    string             `bson:"image_url" json:"image_url,omitempty"`
+	Available   bool               `bson:"-" json:"available"`        // 当前是否可以购买，不入库
+	Reason      string             `bson:"-" json:"reason,omitempty"` // 不可购买的原因
 }
 
 // Cart 表示用户的购物车
+// 优惠券只保存券码，读取购物车时按当前商品重新计算折扣
 type Cart struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Items     []CartItem         `bson:"items" json:"items"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
-}
\ No newline at end of file
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Items       []CartItem         `bson:"items" json:"items"`
+	CouponCode  string             `bson:"coupon_code,omitempty" json:"coupon_code,omitempty"`
+	Discount    int64              `bson:"-" json:"discount"`               // 优惠券折扣（分），不入库
+	CouponError string             `bson:"-" json:"coupon_error,omitempty"` // 优惠券当前不可用的原因
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CouponTypeEnum 优惠券类型
+type CouponTypeEnum string
+
+const (
+	CouponTypePercentage CouponTypeEnum = "percentage" // 按比例折扣，Value为百分比
+	CouponTypeFixed      CouponTypeEnum = "fixed"      // 固定金额减免，Value为金额（分）
+)
+
+// Coupon 优惠券
+// Brands和DeviceTypes为空时适用全部商品，都设置时商品需要同时满足
+type Coupon struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code         string             `bson:"code" json:"code"` // 券码，统一大写，全局唯一
+	Name         string             `bson:"name" json:"name"`
+	Type         CouponTypeEnum     `bson:"type" json:"type"`
+	Value        int64              `bson:"value" json:"value"`
+	MaxDiscount  int64              `bson:"maxDiscount,omitempty" json:"maxDiscount,omitempty"` // 按比例折扣的封顶金额（分），0表示不封顶
+	Currency     string             `bson:"currency,omitempty" json:"currency,omitempty"`       // 固定金额券和门槛金额的币种
+	MinSpend     int64              `bson:"minSpend" json:"minSpend"`                           // 适用商品的最低消费（分）
+	Brands       []string           `bson:"brands,omitempty" json:"brands,omitempty"`
+	DeviceTypes  []string           `bson:"deviceTypes,omitempty" json:"deviceTypes,omitempty"`
+	UsageLimit   int                `bson:"usageLimit" json:"usageLimit"`     // 总使用次数上限，0表示不限
+	PerUserLimit int                `bson:"perUserLimit" json:"perUserLimit"` // 每个用户的使用次数上限，0表示不限
+	UsedCount    int                `bson:"usedCount" json:"usedCount"`       // 已使用次数，订单取消后归还
+	StartsAt     *time.Time         `bson:"startsAt,omitempty" json:"startsAt,omitempty"`
+	EndsAt       *time.Time         `bson:"endsAt,omitempty" json:"endsAt,omitempty"`
+	Active       bool               `bson:"active" json:"active"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CouponRedemptionStatusEnum 优惠券使用记录状态
+type CouponRedemptionStatusEnum string
+
+const (
+	CouponRedemptionApplied  CouponRedemptionStatusEnum = "applied"  // 已用于订单
+	CouponRedemptionReleased CouponRedemptionStatusEnum = "released" // 订单取消，已归还次数
+)
+
+// CouponRedemption 优惠券使用记录，一个订单最多一条
+type CouponRedemption struct {
+	ID         primitive.ObjectID         `bson:"_id,omitempty" json:"id"`
+	CouponID   primitive.ObjectID         `bson:"couponId" json:"couponId"`
+	Code       string                     `bson:"code" json:"code"`
+	UserID     primitive.ObjectID         `bson:"userId" json:"userId"`
+	OrderID    primitive.ObjectID         `bson:"orderId" json:"orderId"`
+	Discount   int64                      `bson:"discount" json:"discount"`
+	Status     CouponRedemptionStatusEnum `bson:"status" json:"status"`
+	CreatedAt  time.Time                  `bson:"createdAt" json:"createdAt"`
+	ReleasedAt *time.Time                 `bson:"releasedAt,omitempty" json:"releasedAt,omitempty"`
+}
+
+// 集合名常量
+const (
+	CouponsCollection           = "coupons"
+	CouponRedemptionsCollection = "coupon_redemptions"
+)
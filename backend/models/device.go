@@ -24,11 +24,22 @@ type HardwareDevice struct {
 	Type        DeviceTypeEnum     `bson:"type" json:"type"`                                   // 设备类型
 	ImageURL    string             `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`       // 图片URL
 	Description string             `bson:"description,omitempty" json:"description,omitempty"` // 设备描述
+	Rating      *DeviceRating      `bson:"rating,omitempty" json:"rating,omitempty"`           // 评分汇总，没有已发布的评测时为空
 	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 	DeletedAt   *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
 }
 
+// DeviceRating 设备评分汇总，由评测服务在评测发布、修改和删除时重新计算
+type DeviceRating struct {
+	Count         int            `bson:"count" json:"count"`                 // 已发布的评测数
+	Average       float64        `bson:"average" json:"average"`             // 平均分
+	Bayesian      float64        `bson:"bayesian" json:"bayesian"`           // 贝叶斯平均分，评测较少时向先验均值收缩，用于排序
+	Histogram     [5]int         `bson:"histogram" json:"histogram"`         // 1-5分各自的评测数，分数四舍五入
+	ByContentType map[string]int `bson:"byContentType" json:"byContentType"` // 按内容类型统计的评测数
+	UpdatedAt     time.Time      `bson:"updatedAt" json:"updatedAt"`
+}
+
 // MouseDevice 鼠标设备
 type MouseDevice struct {
 	HardwareDevice `bson:",inline"`
@@ -43,6 +54,10 @@ type MouseDevice struct {
 type MouseSVGData struct {
 	TopView  string `bson:"topView,omitempty" json:"topView,omitempty"`   // 俯视图SVG
 	SideView string `bson:"sideView,omitempty" json:"sideView,omitempty"` // 侧视图SVG
+
+	// 规范版本：单一闭合路径，变换已展平，viewBox单位为毫米
+	TopViewCanonical  string `bson:"topViewCanonical,omitempty" json:"topViewCanonical,omitempty"`
+	SideViewCanonical string `bson:"sideViewCanonical,omitempty" json:"sideViewCanonical,omitempty"`
 }
 
 // MouseDimensions 鼠标尺寸信息
@@ -55,22 +70,30 @@ type MouseDimensions struct {
 
 // MouseShape 鼠标形状信息
 type MouseShape struct {
-	Type              string `bson:"type" json:"type"`                           // 形状类型 (ergonomic, ambidextrous)
-	HumpPlacement     string `bson:"humpPlacement" json:"humpPlacement"`         // 坑位位置 (front, center, back)
-	FrontFlare        string `bson:"frontFlare" json:"frontFlare"`               // 前端开叉 (narrow, medium, wide)
-	SideCurvature     string `bson:"sideCurvature" json:"sideCurvature"`         // 侧面曲线 (straight, curved)
-	HandCompatibility string `bson:"handCompatibility" json:"handCompatibility"` // 手型适配 (small, medium, large)
+	Type              string `bson:"type" json:"type"`                             // 形状类型 (ergonomic, ambidextrous)
+	HumpPlacement     string `bson:"humpPlacement" json:"humpPlacement"`           // 坑位位置 (front, center, back)
+	FrontFlare        string `bson:"frontFlare" json:"frontFlare"`                 // 前端开叉 (narrow, medium, wide)
+	SideCurvature     string `bson:"sideCurvature" json:"sideCurvature"`           // 侧面曲线 (straight, curved)
+	HandCompatibility string `bson:"handCompatibility" json:"handCompatibility"`   // 手型适配 (small, medium, large)
+	ThumbRest         bool   `bson:"thumbRest" json:"thumbRest"`                   // 拇指托
+	RingFingerRest    bool   `bson:"ringFingerRest" json:"ringFingerRest"`         // 无名指托
+	Material          string `bson:"material,omitempty" json:"material,omitempty"` // 外壳材质
 }
 
 // MouseTechnical 鼠标技术参数
 type MouseTechnical struct {
-	Connectivity []string `bson:"connectivity" json:"connectivity"`           // 连接方式 (wired, wireless, bluetooth)
-	Sensor       string   `bson:"sensor" json:"sensor"`                       // 传感器型号
-	MaxDPI       int      `bson:"maxDPI" json:"maxDPI"`                       // 最大DPI
-	PollingRate  int      `bson:"pollingRate" json:"pollingRate"`             // 轮询率(Hz)
-	SideButtons  int      `bson:"sideButtons" json:"sideButtons"`             // 侧键数量
-	Weight       float64  `bson:"weight,omitempty" json:"weight,omitempty"`   // 重量(g)
-	Battery      *Battery `bson:"battery,omitempty" json:"battery,omitempty"` // 电池信息
+	Connectivity     []string `bson:"connectivity" json:"connectivity"`                             // 连接方式 (wired, wireless, bluetooth)
+	Sensor           string   `bson:"sensor" json:"sensor"`                                         // 传感器型号
+	MaxDPI           int      `bson:"maxDPI" json:"maxDPI"`                                         // 最大DPI
+	PollingRate      int      `bson:"pollingRate" json:"pollingRate"`                               // 轮询率(Hz)
+	SideButtons      int      `bson:"sideButtons" json:"sideButtons"`                               // 侧键数量
+	MiddleButtons    int      `bson:"middleButtons" json:"middleButtons"`                           // 中键数量
+	SensorTechnology string   `bson:"sensorTechnology,omitempty" json:"sensorTechnology,omitempty"` // 传感器技术 (optical, laser)
+	SensorPosition   string   `bson:"sensorPosition,omitempty" json:"sensorPosition,omitempty"`     // 传感器位置 (front, center, back)
+	TrackingSpeed    int      `bson:"trackingSpeed,omitempty" json:"trackingSpeed,omitempty"`       // 追踪速度(IPS)
+	Acceleration     int      `bson:"acceleration,omitempty" json:"acceleration,omitempty"`         // 加速度(G)
+	Weight           float64  `bson:"weight,omitempty" json:"weight,omitempty"`                     // 重量(g)
+	Battery          *Battery `bson:"battery,omitempty" json:"battery,omitempty"`                   // 电池信息
 }
 
 // Battery 电池信息
@@ -147,30 +170,6 @@ type MousepadDevice struct {
 	Recommended []string `bson:"recommended" json:"recommended"` // 推荐场景
 }
 
-// DeviceReview 设备评测
-type DeviceReview struct {
-	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
-	DeviceID      primitive.ObjectID  `bson:"deviceId" json:"deviceId"`
-	UserID        primitive.ObjectID  `bson:"userId" json:"userId"`
-	Content       string              `bson:"content" json:"content"`
-	Pros          []string            `bson:"pros" json:"pros"`
-	Cons          []string            `bson:"cons" json:"cons"`
-	Score         float64             `bson:"score" json:"score"`                                     // 评分(1-5)
-	Usage         string              `bson:"usage" json:"usage"`                                     // 使用场景
-	Status        string              `bson:"status" json:"status"`                                   // 状态(pending, approved, rejected)
-	Type          ReviewType          `bson:"type" json:"type"`                                       // 评测类型(mouse, keyboard等)
-	ContentType   ReviewContentType   `bson:"contentType" json:"contentType"`                         // 内容类型(single, comparison等)
-	ReviewerID    *primitive.ObjectID `bson:"reviewerId,omitempty" json:"reviewerId,omitempty"`       // 评审员ID
-	ReviewerNotes string              `bson:"reviewerNotes,omitempty" json:"reviewerNotes,omitempty"` // 评审员备注
-	ReviewedAt    *time.Time          `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`       // 评审时间
-	PublishedAt   *time.Time          `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`     // 发布时间
-	FeaturedRank  *int                `bson:"featuredRank,omitempty" json:"featuredRank,omitempty"`   // 推荐排名
-	ViewCount     int                 `bson:"viewCount" json:"viewCount"`                             // 查看次数
-	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time           `bson:"updatedAt" json:"updatedAt"`
-	DeletedAt     *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
-}
-
 // UserDevice 用户设备配置
 type UserDevice struct {
 	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
@@ -192,7 +191,9 @@ type UserDeviceSettings struct {
 
 // 集合名常量
 const (
-	DevicesCollection       = "devices"
+	DevicesCollection     = "devices"
+	UserDevicesCollection = "user_devices"
+
+	// DeviceReviewsCollection 旧版设备评测集合，已合并到reviews，仅供迁移脚本读取
 	DeviceReviewsCollection = "device_reviews"
-	UserDevicesCollection   = "user_devices"
 )
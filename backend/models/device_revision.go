@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeviceRevisionSource 设备修改来源
+type DeviceRevisionSource string
+
+const (
+	DeviceRevisionBaseline  DeviceRevisionSource = "baseline"   // 开始记录历史前的状态
+	DeviceRevisionAdminEdit DeviceRevisionSource = "admin_edit" // 管理员编辑
+	DeviceRevisionCSVImport DeviceRevisionSource = "csv_import" // CSV导入
+	DeviceRevisionSVGImport DeviceRevisionSource = "svg_import" // SVG批量上传
+	DeviceRevisionRollback  DeviceRevisionSource = "rollback"   // 回滚到历史版本
+	DeviceRevisionScript    DeviceRevisionSource = "script"     // 维护脚本批量修正
+)
+
+// DeviceRevision 设备修改记录
+// Snapshot保存修改后的完整文档，用于任意两个版本之间的对比和回滚
+type DeviceRevision struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	DeviceID     primitive.ObjectID   `bson:"deviceId" json:"deviceId"`
+	Revision     int                  `bson:"revision" json:"revision"` // 从1开始递增
+	Source       DeviceRevisionSource `bson:"source" json:"source"`
+	ActorID      *primitive.ObjectID  `bson:"actorId,omitempty" json:"actorId,omitempty"` // 脚本导入时为空
+	Changes      []FieldChange        `bson:"changes" json:"changes"`
+	RolledBackTo int                  `bson:"rolledBackTo,omitempty" json:"rolledBackTo,omitempty"`
+	Snapshot     bson.M               `bson:"snapshot" json:"-"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+}
+
+// FieldChange 单个字段的变更，Path为点分隔的字段路径
+type FieldChange struct {
+	Path     string      `bson:"path" json:"path"`
+	OldValue interface{} `bson:"oldValue,omitempty" json:"oldValue,omitempty"`
+	NewValue interface{} `bson:"newValue,omitempty" json:"newValue,omitempty"`
+}
+
+const (
+	DeviceRevisionsCollection = "device_revisions"
+)
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReservationStatusEnum 库存预留状态
+type ReservationStatusEnum string
+
+const (
+	ReservationStatusReserved  ReservationStatusEnum = "reserved"  // 已预留，等待支付
+	ReservationStatusReleased  ReservationStatusEnum = "released"  // 订单取消或超时，库存已归还
+	ReservationStatusCommitted ReservationStatusEnum = "committed" // 已支付，库存正式扣除
+)
+
+// InventoryReservation 下单时预留的库存
+// 下单即从SKU可售库存中扣除，未支付的订单到期后归还
+type InventoryReservation struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	OrderID    primitive.ObjectID    `bson:"orderId" json:"orderId"`
+	UserID     primitive.ObjectID    `bson:"userId" json:"userId"`
+	Items      []ReservedItem        `bson:"items" json:"items"`
+	Status     ReservationStatusEnum `bson:"status" json:"status"`
+	ExpiresAt  time.Time             `bson:"expiresAt" json:"expiresAt"` // 到期未支付则取消订单并归还库存
+	CreatedAt  time.Time             `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time             `bson:"updatedAt" json:"updatedAt"`
+	ReleasedAt *time.Time            `bson:"releasedAt,omitempty" json:"releasedAt,omitempty"`
+}
+
+// ReservedItem 预留的SKU和数量
+type ReservedItem struct {
+	SKUID    primitive.ObjectID `bson:"skuId" json:"skuId"`
+	Quantity int                `bson:"quantity" json:"quantity"`
+}
+
+// 集合名常量
+const (
+	InventoryReservationsCollection = "inventory_reservations"
+)
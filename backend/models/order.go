@@ -39,11 +39,17 @@ type Order struct {
 	Items         []OrderItem        `bson:"items" json:"items"`             // 订单商品
 	ShippingInfo  ShippingInfo       `bson:"shippingInfo" json:"shippingInfo"` // 配送信息
 	PaymentInfo   PaymentInfo        `bson:"paymentInfo" json:"paymentInfo"`   // 支付信息
-	Subtotal      float64            `bson:"subtotal" json:"subtotal"`         // 商品小计
-	ShippingFee   float64            `bson:"shippingFee" json:"shippingFee"`   // 配送费
-	Tax           float64            `bson:"tax" json:"tax"`                   // 税费
-	Discount      float64            `bson:"discount" json:"discount"`         // 折扣
-	Total         float64            `bson:"total" json:"total"`               // 总价
+	Subtotal      int64              `bson:"subtotal" json:"subtotal"`         // 商品小计
+	ShippingFee   int64              `bson:"shippingFee" json:"shippingFee"`   // 配送费
+	Tax           int64              `bson:"tax" json:"tax"`                   // 税费
+	TaxInclusive  bool               `bson:"taxInclusive,omitempty" json:"taxInclusive,omitempty"` // 税费是否已含在商品价格中，含税时不计入总价
+	ShippingWeight int               `bson:"shippingWeight,omitempty" json:"shippingWeight,omitempty"` // 计费重量（克）
+	Discount      int64              `bson:"discount" json:"discount"`         // 折扣
+	CouponCode    string             `bson:"couponCode,omitempty" json:"couponCode,omitempty"` // 使用的优惠券
+	Total         int64              `bson:"total" json:"total"`               // 总价
+	Currency      string             `bson:"currency" json:"currency"`         // 结算币种，金额单位均为分
+	RefundedAmount int64             `bson:"refundedAmount" json:"refundedAmount"` // 已退款金额
+	RefundVersion int64              `bson:"refundVersion,omitempty" json:"-"` // 退款版本号，申请、批准和完成退款时递增，用于串行化同一订单的退款
 	Notes         string             `bson:"notes,omitempty" json:"notes,omitempty"` // 备注
 	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
@@ -52,16 +58,31 @@ type Order struct {
 	DeliveredAt   *time.Time         `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
 	CancelledAt   *time.Time         `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
 	CancelReason  string             `bson:"cancelReason,omitempty" json:"cancelReason,omitempty"`
+	ReservationExpiresAt *time.Time  `bson:"reservationExpiresAt,omitempty" json:"reservationExpiresAt,omitempty"` // 库存预留到期时间，需在此之前支付
+	AdminNotes    []OrderNote        `bson:"adminNotes,omitempty" json:"adminNotes,omitempty"` // 内部备注，仅管理员可见
 }
 
-// OrderItem 订单商品
+// OrderNote 管理员添加的订单内部备注
+type OrderNote struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	AuthorID  primitive.ObjectID `bson:"authorId" json:"authorId"`
+	Content   string             `bson:"content" json:"content"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// OrderItem 订单商品，保存下单时的商品快照
 type OrderItem struct {
-	ProductID   primitive.ObjectID `bson:"productId" json:"productId"`     // 商品ID
+	ProductID   primitive.ObjectID `bson:"productId" json:"productId"`     // 设备ID
+	SKUID       primitive.ObjectID `bson:"skuId" json:"skuId"`             // SKU ID
+	SKUCode     string             `bson:"skuCode" json:"skuCode"`         // SKU编码
+	Variant     ProductVariant     `bson:"variant" json:"variant"`         // 规格属性
 	ProductType string             `bson:"productType" json:"productType"` // 商品类型
 	Name        string             `bson:"name" json:"name"`               // 商品名称
-	Price       float64            `bson:"price" json:"price"`             // 单价
+	Price       int64              `bson:"price" json:"price"`             // 单价（分）
 	Quantity    int                `bson:"quantity" json:"quantity"`       // 数量
-	Subtotal    float64            `bson:"subtotal" json:"subtotal"`       // 小计
+	Subtotal    int64              `bson:"subtotal" json:"subtotal"`       // 小计（分）
+	Discount    int64              `bson:"discount,omitempty" json:"discount,omitempty"` // 分摊到该行的优惠券折扣（分）
+	RefundedQuantity int           `bson:"refundedQuantity" json:"refundedQuantity"` // 已退款数量
 	ImageURL    string             `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"` // 图片URL
 }
 
@@ -76,6 +97,8 @@ type ShippingInfo struct {
 	ZipCode      string  `bson:"zipCode" json:"zipCode"`             // 邮编
 	Country      string  `bson:"country" json:"country"`             // 国家
 	ShippingMethod string `bson:"shippingMethod" json:"shippingMethod"` // 配送方式
+	Carrier      string  `bson:"carrier,omitempty" json:"carrier,omitempty"`               // 承运商
+	TrackingNumber string `bson:"trackingNumber,omitempty" json:"trackingNumber,omitempty"` // 物流单号
 }
 
 // PaymentInfo 支付信息
@@ -85,9 +108,13 @@ type PaymentInfo struct {
 	LastFourDigits  string            `bson:"lastFourDigits,omitempty" json:"lastFourDigits,omitempty"` // 卡号后四位
 	PaymentStatus   string            `bson:"paymentStatus" json:"paymentStatus"`     // 支付状态
 	PaymentProvider string            `bson:"paymentProvider,omitempty" json:"paymentProvider,omitempty"` // 支付提供商
+	IntentID        string            `bson:"intentId,omitempty" json:"intentId,omitempty"` // 支付渠道的支付意图ID
+	Refunds         []PaymentRefund   `bson:"refunds,omitempty" json:"refunds,omitempty"`   // 已完成的退款
 }
 
 // 集合名常量
 const (
 	OrdersCollection = "orders"
+	// OrderCountersCollection 按天分配订单号序号的计数器
+	OrderCountersCollection = "order_counters"
 )
\ No newline at end of file
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentEvent 已处理的支付回调事件
+// (provider, eventId) 唯一，支付渠道重复推送同一事件时直接忽略
+type PaymentEvent struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Provider      string             `bson:"provider" json:"provider"`
+	EventID       string             `bson:"eventId" json:"eventId"`
+	Type          string             `bson:"type" json:"type"`
+	OrderID       primitive.ObjectID `bson:"orderId" json:"orderId"`
+	IntentID      string             `bson:"intentId,omitempty" json:"intentId,omitempty"`
+	TransactionID string             `bson:"transactionId,omitempty" json:"transactionId,omitempty"`
+	Amount        int64              `bson:"amount" json:"amount"` // 金额（分）
+	Currency      string             `bson:"currency" json:"currency"`
+	Status        PaymentEventStatus `bson:"status" json:"status"`
+	Reason        string             `bson:"reason,omitempty" json:"reason,omitempty"` // 忽略的原因
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// PaymentEventStatus 支付回调事件的处理结果
+type PaymentEventStatus string
+
+const (
+	PaymentEventProcessed PaymentEventStatus = "processed" // 已处理
+	PaymentEventIgnored   PaymentEventStatus = "ignored"   // 订单已取消等无法处理，需要人工退款，不再让支付渠道重试
+)
+
+// 集合名常量
+const (
+	PaymentEventsCollection = "payment_events"
+)
@@ -0,0 +1,35 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// TaxRule 税率规则，按收货国家和省/州匹配
+// Country为空表示默认规则，State为空表示适用整个国家
+type TaxRule struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Country   string             `bson:"country" json:"country"`
+	State     string             `bson:"state,omitempty" json:"state,omitempty"`
+	Rate      int                `bson:"rate" json:"rate"`           // 税率，单位为万分之一，1300表示13%
+	Inclusive bool               `bson:"inclusive" json:"inclusive"` // 商品价格是否已含税
+	Name      string             `bson:"name,omitempty" json:"name,omitempty"`
+}
+
+// ShippingRate 运费规则，按配送方式、目的地和重量计算
+// 首重内收BaseFee，超出部分每StepWeight克加收StepFee
+type ShippingRate struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Method         string             `bson:"method" json:"method"`                       // 为空表示不限配送方式
+	Country        string             `bson:"country,omitempty" json:"country,omitempty"` // 为空表示默认目的地
+	State          string             `bson:"state,omitempty" json:"state,omitempty"`
+	Currency       string             `bson:"currency,omitempty" json:"currency,omitempty"`
+	BaseFee        int64              `bson:"baseFee" json:"baseFee"`                       // 首重运费（分）
+	IncludedWeight int                `bson:"includedWeight" json:"includedWeight"`         // 首重（克）
+	StepWeight     int                `bson:"stepWeight" json:"stepWeight"`                 // 续重单位（克）
+	StepFee        int64              `bson:"stepFee" json:"stepFee"`                       // 每个续重单位的运费（分）
+	FreeOver       int64              `bson:"freeOver,omitempty" json:"freeOver,omitempty"` // 折后商品金额达到该值免运费，0表示不包邮
+}
+
+// 集合名常量
+const (
+	TaxRulesCollection      = "tax_rules"
+	ShippingRatesCollection = "shipping_rates"
+)
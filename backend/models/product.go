@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultCurrency 默认结算货币
+const DefaultCurrency = "CNY"
+
+// ProductSKU 可售卖的商品规格，挂在设备下面
+// 同一款设备的不同颜色、有线/无线版本分别是一个SKU
+// 价格以最小货币单位（分）保存，避免浮点误差
+type ProductSKU struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DeviceID  primitive.ObjectID `bson:"deviceId" json:"deviceId"`
+	Code      string             `bson:"code" json:"code"`                             // SKU编码，全局唯一
+	Variant   ProductVariant     `bson:"variant" json:"variant"`                       // 规格属性
+	Price     int64              `bson:"price" json:"price"`                           // 单价（分）
+	Currency  string             `bson:"currency" json:"currency"`                     // ISO 4217货币代码
+	Stock     int                `bson:"stock" json:"stock"`                           // 可售库存
+	ImageURL  string             `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"` // 规格图片，为空时使用设备图片
+	Active    bool               `bson:"active" json:"active"`                         // 是否上架
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ProductVariant SKU规格属性
+type ProductVariant struct {
+	Color      string `bson:"color,omitempty" json:"color,omitempty"`           // 颜色
+	Connection string `bson:"connection,omitempty" json:"connection,omitempty"` // 连接方式：wired/wireless
+}
+
+// 集合名常量
+const (
+	ProductSKUsCollection = "product_skus"
+)
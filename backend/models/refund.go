@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefundStatusEnum 退款状态
+type RefundStatusEnum string
+
+const (
+	RefundStatusRequested  RefundStatusEnum = "requested"  // 用户已申请，等待审核
+	RefundStatusProcessing RefundStatusEnum = "processing" // 已批准，正在向支付渠道退款
+	RefundStatusSucceeded  RefundStatusEnum = "succeeded"  // 退款成功
+	RefundStatusRejected   RefundStatusEnum = "rejected"   // 审核拒绝
+	RefundStatusFailed     RefundStatusEnum = "failed"     // 支付渠道退款失败
+)
+
+// Refund 退款记录，按订单商品行和数量退款
+type Refund struct {
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	OrderID          primitive.ObjectID  `bson:"orderId" json:"orderId"`
+	UserID           primitive.ObjectID  `bson:"userId" json:"userId"`
+	Items            []RefundItem        `bson:"items" json:"items"`
+	Amount           int64               `bson:"amount" json:"amount"` // 退款金额（分）
+	Currency         string              `bson:"currency" json:"currency"`
+	Reason           string              `bson:"reason" json:"reason"`
+	Status           RefundStatusEnum    `bson:"status" json:"status"`
+	Restock          bool                `bson:"restock" json:"restock"` // 是否归还库存
+	Provider         string              `bson:"provider,omitempty" json:"provider,omitempty"`
+	ProviderRefundID string              `bson:"providerRefundId,omitempty" json:"providerRefundId,omitempty"`
+	FailureReason    string              `bson:"failureReason,omitempty" json:"failureReason,omitempty"`
+	ReviewerID       *primitive.ObjectID `bson:"reviewerId,omitempty" json:"reviewerId,omitempty"`
+	ReviewNote       string              `bson:"reviewNote,omitempty" json:"reviewNote,omitempty"`
+	CreatedAt        time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time           `bson:"updatedAt" json:"updatedAt"`
+	ReviewedAt       *time.Time          `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	RefundedAt       *time.Time          `bson:"refundedAt,omitempty" json:"refundedAt,omitempty"`
+}
+
+// RefundItem 退款的商品行
+type RefundItem struct {
+	SKUID    primitive.ObjectID `bson:"skuId" json:"skuId"`
+	Name     string             `bson:"name" json:"name"`
+	Quantity int                `bson:"quantity" json:"quantity"`
+	Amount   int64              `bson:"amount" json:"amount"` // 该行退款金额（分），含分摊的税费和折扣
+}
+
+// PaymentRefund 支付信息中记录的已完成退款
+type PaymentRefund struct {
+	RefundID         primitive.ObjectID `bson:"refundId" json:"refundId"`
+	ProviderRefundID string             `bson:"providerRefundId" json:"providerRefundId"`
+	Amount           int64              `bson:"amount" json:"amount"`
+	RefundedAt       time.Time          `bson:"refundedAt" json:"refundedAt"`
+}
+
+// 集合名常量
+const (
+	RefundsCollection = "refunds"
+)
@@ -27,6 +27,70 @@ const (
 	ReviewContentBuying     ReviewContentType = "buying"     // 选购建议
 )
 
+// ReviewContentFormat 评测正文的格式，为空时为纯文本
+type ReviewContentFormat string
+
+const (
+	ReviewContentFormatPlain    ReviewContentFormat = "plain"    // 纯文本，转义后保存
+	ReviewContentFormatMarkdown ReviewContentFormat = "markdown" // 受限的Markdown，保存原文和渲染后的HTML
+)
+
+// ReviewItemTypeDevice 外设评测的项目类型，对应devices集合
+const ReviewItemTypeDevice = "device"
+
+// ComparisonCategory 对比评测的比较维度
+type ComparisonCategory string
+
+const (
+	ComparisonCategoryShape  ComparisonCategory = "shape"  // 形状手感
+	ComparisonCategoryWeight ComparisonCategory = "weight" // 重量
+	ComparisonCategorySensor ComparisonCategory = "sensor" // 传感器
+	ComparisonCategoryBuild  ComparisonCategory = "build"  // 做工
+)
+
+// MaxComparisonDevices 一次最多对比的设备数
+const MaxComparisonDevices = 4
+
+// ReviewComparison 对比评测涉及的设备、各设备的评分和各维度的胜出者
+type ReviewComparison struct {
+	Items   []ComparisonItem   `bson:"items" json:"items"`
+	Winners []ComparisonWinner `bson:"winners" json:"winners"`
+}
+
+// ComparisonItem 对比评测中的一个设备
+type ComparisonItem struct {
+	DeviceID primitive.ObjectID `bson:"deviceId" json:"deviceId"`
+	Score    float64            `bson:"score" json:"score"` // 该设备的评分(1-5)
+}
+
+// ComparisonWinner 某个维度胜出的设备，未声明的维度视为不分胜负
+type ComparisonWinner struct {
+	Category ComparisonCategory `bson:"category" json:"category"`
+	DeviceID primitive.ObjectID `bson:"deviceId" json:"deviceId"`
+}
+
+// ReviewModeration 提交或修改评测时自动预审的结果
+type ReviewModeration struct {
+	RiskScore    int              `bson:"riskScore" json:"riskScore"` // 0-100，越高越可疑
+	Flags        []ModerationFlag `bson:"flags" json:"flags"`
+	AutoApproved bool             `bson:"autoApproved" json:"autoApproved"` // 可信评测员的低风险评测自动通过
+	CheckedAt    time.Time        `bson:"checkedAt" json:"checkedAt"`
+}
+
+// ModerationFlag 预审规则命中的一项
+type ModerationFlag struct {
+	Code   string `bson:"code" json:"code"` // blocklist、links、duplicate等
+	Detail string `bson:"detail" json:"detail"`
+	Score  int    `bson:"score" json:"score"` // 该项计入的风险分
+}
+
+// ReviewTrending 按时间衰减的浏览热度，Score为UpdatedAt时的热度
+type ReviewTrending struct {
+	Score      float64   `bson:"score" json:"score"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+	FlushBatch string    `bson:"flushBatch,omitempty" json:"-"` // 最后写入的浏览数批次，重试同一批次时不重复累加
+}
+
 // ReviewStatus 评测状态枚举
 type ReviewStatus string
 
@@ -37,6 +101,9 @@ const (
 	ReviewStatusFeatured ReviewStatus = "featured" // 已推荐
 )
 
+// PublishedReviewStatuses 对外可见的评测状态
+var PublishedReviewStatuses = []ReviewStatus{ReviewStatusApproved, ReviewStatusFeatured}
+
 // Review 评测数据模型
 type Review struct {
 	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
@@ -44,22 +111,71 @@ type Review struct {
 	ItemType       string              `bson:"itemType" json:"itemType"`           // 项目类型(device, game等)
 	UserID         primitive.ObjectID  `bson:"userId" json:"userId"`
 	Content        string              `bson:"content" json:"content"`
+	ContentFormat  ReviewContentFormat `bson:"contentFormat,omitempty" json:"contentFormat,omitempty"`
+	ContentHTML    string              `bson:"contentHtml,omitempty" json:"contentHtml,omitempty"` // Markdown渲染并清洗后的HTML
+	Excerpt        string              `bson:"excerpt" json:"excerpt"`                             // 纯文本摘要，用于列表和搜索
 	Pros           []string            `bson:"pros" json:"pros"`
 	Cons           []string            `bson:"cons" json:"cons"`
 	Score          float64             `bson:"score" json:"score"`                // 评分(1-5)
 	Usage          string              `bson:"usage" json:"usage"`                // 使用场景
+	Images         []ReviewAttachment  `bson:"images,omitempty" json:"images,omitempty"`
 	Status         ReviewStatus        `bson:"status" json:"status"`              // 状态
 	Type           ReviewType          `bson:"type" json:"type"`                  // 评测类型
 	ContentType    ReviewContentType   `bson:"contentType" json:"contentType"`    // 内容类型
+	Comparison     *ReviewComparison   `bson:"comparison,omitempty" json:"comparison,omitempty"` // 对比评测的设备和结论
+	Locale         string              `bson:"locale,omitempty" json:"locale,omitempty"`         // 提交时的语言，用于选择屏蔽词
+	Moderation     *ReviewModeration   `bson:"moderation,omitempty" json:"-"` // 自动预审结果，只通过审核接口返回
 	ReviewerID     *primitive.ObjectID `bson:"reviewerId,omitempty" json:"reviewerId,omitempty"`
 	ReviewerNotes  string              `bson:"reviewerNotes,omitempty" json:"reviewerNotes,omitempty"`
 	ReviewedAt     *time.Time          `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
 	PublishedAt    *time.Time          `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
 	FeaturedRank   *int                `bson:"featuredRank,omitempty" json:"featuredRank,omitempty"`
 	ViewCount      int                 `bson:"viewCount" json:"viewCount"`
+	Trending       *ReviewTrending     `bson:"trending,omitempty" json:"-"`          // 由浏览计数定期更新
+	HelpfulCount   int                 `bson:"helpfulCount" json:"helpfulCount"`     // 认为有帮助的票数
+	UnhelpfulCount int                 `bson:"unhelpfulCount" json:"unhelpfulCount"` // 认为没有帮助的票数
+	HelpfulScore   float64             `bson:"helpfulScore" json:"helpfulScore"`     // Wilson得分下限，用于按有用程度排序
+	CommentCount   int                 `bson:"commentCount" json:"commentCount"`     // 可见的评论数
+	Revision       int                 `bson:"revision,omitempty" json:"revision,omitempty"`               // 最新的修改版本号，开始记录历史前为0
+	PendingRevision int                `bson:"pendingRevision,omitempty" json:"pendingRevision,omitempty"` // 已发布评测待审核的修改
 	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
 	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
 	DeletedAt      *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
 }
 
+// Format 正文的格式，早期的评测没有记录格式，按纯文本处理
+func (r *Review) Format() ReviewContentFormat {
+	if r.ContentFormat == "" {
+		return ReviewContentFormatPlain
+	}
+	return r.ContentFormat
+}
+
+// DeviceIDs 评测涉及的所有设备，对比评测返回全部对比设备
+func (r *Review) DeviceIDs() []primitive.ObjectID {
+	if r.ItemType != ReviewItemTypeDevice {
+		return nil
+	}
+	if r.Comparison == nil || len(r.Comparison.Items) == 0 {
+		return []primitive.ObjectID{r.ExternalItemID}
+	}
+	ids := make([]primitive.ObjectID, len(r.Comparison.Items))
+	for i, item := range r.Comparison.Items {
+		ids[i] = item.DeviceID
+	}
+	return ids
+}
+
+// ScoreFor 评测给某个设备的评分，对比评测取该设备单独的评分
+func (r *Review) ScoreFor(deviceID primitive.ObjectID) float64 {
+	if r.Comparison != nil {
+		for _, item := range r.Comparison.Items {
+			if item.DeviceID == deviceID {
+				return item.Score
+			}
+		}
+	}
+	return r.Score
+}
+
 // 集合名常量已在 constants.go 中定义
\ No newline at end of file
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewVote 用户对评测是否有帮助的投票，每个用户对同一评测只有一票，可以修改
+type ReviewVote struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReviewID  primitive.ObjectID `bson:"reviewId" json:"reviewId"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Helpful   bool               `bson:"helpful" json:"helpful"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CommentStatus 评论状态
+type CommentStatus string
+
+const (
+	CommentStatusVisible CommentStatus = "visible" // 正常显示
+	CommentStatusHidden  CommentStatus = "hidden"  // 被管理员隐藏
+	CommentStatusDeleted CommentStatus = "deleted" // 被作者删除
+)
+
+// MaxCommentDepth 评论最多嵌套的层数，顶层评论为0
+const MaxCommentDepth = 4
+
+// ReviewComment 评测下的评论，通过ParentID形成楼中楼
+type ReviewComment struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ReviewID       primitive.ObjectID  `bson:"reviewId" json:"reviewId"`
+	UserID         primitive.ObjectID  `bson:"userId" json:"userId"`
+	ParentID       *primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	RootID         *primitive.ObjectID `bson:"rootId,omitempty" json:"rootId,omitempty"` // 所属顶层评论，顶层评论为空
+	Depth          int                 `bson:"depth" json:"depth"`
+	Content        string              `bson:"content" json:"content"`
+	Status         CommentStatus       `bson:"status" json:"status"`
+	ModeratorID    *primitive.ObjectID `bson:"moderatorId,omitempty" json:"moderatorId,omitempty"`
+	ModerationNote string              `bson:"moderationNote,omitempty" json:"moderationNote,omitempty"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// 集合名常量
+const (
+	ReviewVotesCollection    = "review_votes"
+	ReviewCommentsCollection = "review_comments"
+)
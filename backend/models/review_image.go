@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewImage 评测图片，先上传再在创建或修改评测时关联，长时间未关联的图片会被定期清理
+type ReviewImage struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID  `bson:"userId" json:"userId"`
+	ReviewID     *primitive.ObjectID `bson:"reviewId,omitempty" json:"reviewId,omitempty"` // 未关联评测时为空
+	Key          string              `bson:"key" json:"key"`                               // 存储中的路径
+	ThumbnailKey string              `bson:"thumbnailKey" json:"thumbnailKey"`
+	URL          string              `bson:"url" json:"url"`
+	ThumbnailURL string              `bson:"thumbnailUrl" json:"thumbnailUrl"`
+	ContentType  string              `bson:"contentType" json:"contentType"` // 按内容识别的类型
+	Size         int                 `bson:"size" json:"size"`
+	Width        int                 `bson:"width" json:"width"`
+	Height       int                 `bson:"height" json:"height"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+	AttachedAt   *time.Time          `bson:"attachedAt,omitempty" json:"attachedAt,omitempty"`
+}
+
+// ReviewAttachment 评测中按顺序引用的图片
+type ReviewAttachment struct {
+	ID           primitive.ObjectID `bson:"id" json:"id"`
+	URL          string             `bson:"url" json:"url"`
+	ThumbnailURL string             `bson:"thumbnailUrl" json:"thumbnailUrl"`
+	Width        int                `bson:"width" json:"width"`
+	Height       int                `bson:"height" json:"height"`
+}
+
+// ReviewImagesCollection 评测图片集合
+const ReviewImagesCollection = "review_images"
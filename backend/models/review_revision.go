@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewRevisionStatus 评测修改记录的审核状态
+type ReviewRevisionStatus string
+
+const (
+	ReviewRevisionPending    ReviewRevisionStatus = "pending"    // 待审核
+	ReviewRevisionApproved   ReviewRevisionStatus = "approved"   // 已通过，内容已公开
+	ReviewRevisionRejected   ReviewRevisionStatus = "rejected"   // 已拒绝
+	ReviewRevisionSuperseded ReviewRevisionStatus = "superseded" // 审核前又被作者修改
+)
+
+// ReviewSnapshot 评测中作者可以修改的内容
+type ReviewSnapshot struct {
+	ExternalItemID primitive.ObjectID  `bson:"externalItemId" json:"externalItemId"`
+	Content        string              `bson:"content" json:"content"`
+	ContentFormat  ReviewContentFormat `bson:"contentFormat,omitempty" json:"contentFormat,omitempty"`
+	ContentHTML    string              `bson:"contentHtml,omitempty" json:"contentHtml,omitempty"`
+	Excerpt        string              `bson:"excerpt" json:"excerpt"`
+	Pros           []string            `bson:"pros" json:"pros"`
+	Cons           []string            `bson:"cons" json:"cons"`
+	Score          float64             `bson:"score" json:"score"`
+	Usage          string              `bson:"usage" json:"usage"`
+	Comparison     *ReviewComparison   `bson:"comparison,omitempty" json:"comparison,omitempty"`
+	Images         []ReviewAttachment  `bson:"images,omitempty" json:"images,omitempty"`
+}
+
+// ReviewRevision 评测的一次修改，保存修改后的完整内容
+// 已发布评测的修改需要重新审核，通过前评测继续展示上一次通过的内容
+type ReviewRevision struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ReviewID      primitive.ObjectID   `bson:"reviewId" json:"reviewId"`
+	UserID        primitive.ObjectID   `bson:"userId" json:"userId"`
+	Revision      int                  `bson:"revision" json:"revision"` // 从1开始递增
+	Status        ReviewRevisionStatus `bson:"status" json:"status"`
+	Snapshot      ReviewSnapshot       `bson:"snapshot" json:"snapshot"`
+	Changes       []FieldChange        `bson:"changes" json:"changes"` // 相对于提交时公开内容（未发布时为上一版本）的变更
+	Moderation    *ReviewModeration    `bson:"moderation,omitempty" json:"moderation,omitempty"`
+	ReviewerID    *primitive.ObjectID  `bson:"reviewerId,omitempty" json:"reviewerId,omitempty"`
+	ReviewerNotes string               `bson:"reviewerNotes,omitempty" json:"reviewerNotes,omitempty"`
+	ReviewedAt    *time.Time           `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	CreatedAt     time.Time            `bson:"createdAt" json:"createdAt"`
+}
+
+// Snapshot 评测当前的内容
+func (r *Review) Snapshot() ReviewSnapshot {
+	return ReviewSnapshot{
+		ExternalItemID: r.ExternalItemID,
+		Content:        r.Content,
+		ContentFormat:  r.ContentFormat,
+		ContentHTML:    r.ContentHTML,
+		Excerpt:        r.Excerpt,
+		Pros:           r.Pros,
+		Cons:           r.Cons,
+		Score:          r.Score,
+		Usage:          r.Usage,
+		Comparison:     r.Comparison,
+		Images:         r.Images,
+	}
+}
+
+// ApplySnapshot 用修改记录的内容替换评测内容
+func (r *Review) ApplySnapshot(snapshot ReviewSnapshot) {
+	r.ExternalItemID = snapshot.ExternalItemID
+	r.Content = snapshot.Content
+	r.ContentFormat = snapshot.ContentFormat
+	r.ContentHTML = snapshot.ContentHTML
+	r.Excerpt = snapshot.Excerpt
+	r.Pros = snapshot.Pros
+	r.Cons = snapshot.Cons
+	r.Score = snapshot.Score
+	r.Usage = snapshot.Usage
+	r.Comparison = snapshot.Comparison
+	r.Images = snapshot.Images
+}
+
+// ReviewRevisionsCollection 评测修改记录集合
+const ReviewRevisionsCollection = "review_revisions"
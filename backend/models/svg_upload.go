@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SVGUploadStatus SVG批量上传批次状态
+type SVGUploadStatus string
+
+const (
+	SVGUploadStatusPending   SVGUploadStatus = "pending"   // 等待管理员确认
+	SVGUploadStatusConfirmed SVGUploadStatus = "confirmed" // 已确认并写入设备
+)
+
+// SVGMatchStatus 单个文件的匹配状态
+type SVGMatchStatus string
+
+const (
+	SVGMatchMatched   SVGMatchStatus = "matched"   // 唯一高置信度匹配
+	SVGMatchAmbiguous SVGMatchStatus = "ambiguous" // 有候选但需要人工选择
+	SVGMatchUnmatched SVGMatchStatus = "unmatched" // 没有找到候选鼠标
+	SVGMatchInvalid   SVGMatchStatus = "invalid"   // 文件校验失败
+)
+
+// SVGUploadResult 确认时单个文件的处理结果
+type SVGUploadResult string
+
+const (
+	SVGUploadResultUpdated SVGUploadResult = "updated" // 已写入鼠标，再次确认时不会重复写入
+	SVGUploadResultSkipped SVGUploadResult = "skipped" // 未写入，再次确认时重新处理
+)
+
+// SVGUploadBatch SVG批量上传批次
+// 上传后先保存清洗过的SVG和匹配结果，管理员确认后才写入设备的MouseSVGData
+// 每个文件单独保存在svg_upload_items中，大批次不会超过单个文档的大小限制
+type SVGUploadBatch struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UploadedBy  primitive.ObjectID `bson:"uploadedBy" json:"uploadedBy"`
+	Status      SVGUploadStatus    `bson:"status" json:"status"`
+	ItemCount   int                `bson:"itemCount" json:"itemCount"`
+	Items       []SVGUploadItem    `bson:"-" json:"items"` // 查询批次时从svg_upload_items加载
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	ExpiresAt   time.Time          `bson:"expiresAt" json:"expiresAt"` // TTL索引，过期未确认的批次自动删除
+	ConfirmedAt *time.Time         `bson:"confirmedAt,omitempty" json:"confirmedAt,omitempty"`
+}
+
+// SVGUploadItem 批次中的单个SVG文件
+type SVGUploadItem struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"-"`
+	BatchID         primitive.ObjectID  `bson:"batchId" json:"-"`
+	Index           int                 `bson:"index" json:"index"` // 文件在批次中的序号
+	FileName        string              `bson:"fileName" json:"fileName"`
+	View            string              `bson:"view" json:"view"`       // top, side
+	SVG             string              `bson:"svg,omitempty" json:"-"` // 清洗后的SVG
+	Status          SVGMatchStatus      `bson:"status" json:"status"`
+	Error           string              `bson:"error,omitempty" json:"error,omitempty"`
+	MatchedDeviceID *primitive.ObjectID `bson:"matchedDeviceId,omitempty" json:"matchedDeviceId,omitempty"`
+	Candidates      []SVGMatchCandidate `bson:"candidates,omitempty" json:"candidates,omitempty"`
+	ExpiresAt       time.Time           `bson:"expiresAt" json:"-"` // 与批次相同，TTL索引自动删除
+
+	// 确认时记录的处理结果，确认中断后再次确认可以从未写入的文件继续
+	Result          SVGUploadResult     `bson:"result,omitempty" json:"result,omitempty"`
+	ResultReason    string              `bson:"resultReason,omitempty" json:"resultReason,omitempty"`
+	AppliedDeviceID *primitive.ObjectID `bson:"appliedDeviceId,omitempty" json:"appliedDeviceId,omitempty"`
+	AppliedView     string              `bson:"appliedView,omitempty" json:"appliedView,omitempty"`
+	ProcessedAt     *time.Time          `bson:"processedAt,omitempty" json:"processedAt,omitempty"`
+}
+
+// SVGMatchCandidate 文件名模糊匹配得到的候选鼠标
+type SVGMatchCandidate struct {
+	DeviceID primitive.ObjectID `bson:"deviceId" json:"deviceId"`
+	Name     string             `bson:"name" json:"name"`
+	Brand    string             `bson:"brand" json:"brand"`
+	Score    float64            `bson:"score" json:"score"` // 0-1
+}
+
+const (
+	SVGUploadBatchesCollection = "svg_upload_batches"
+	SVGUploadItemsCollection   = "svg_upload_items"
+)
@@ -25,6 +25,9 @@ type User struct {
 	LoginHistory []LoginRecord `bson:"loginHistory" json:"loginHistory"`
 	SecurityLogs []SecurityLog `bson:"securityLogs" json:"securityLogs"`
 
+	// 评测员申请，未申请时为空
+	ReviewerApplication *ReviewerApplication `bson:"reviewerApplication,omitempty" json:"reviewerApplication,omitempty"`
+
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
 	IsVerified bool     `bson:"isVerified" json:"isVerified"`
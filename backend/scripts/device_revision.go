@@ -0,0 +1,38 @@
+package scripts
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/models"
+)
+
+// recordImportRevision 为CSV导入新建的设备写入第一条修改记录
+// 新建设备没有旧值，变更列表留空，完整内容保存在快照中
+func recordImportRevision(ctx context.Context, devices *mongo.Collection, deviceID interface{}) error {
+	id, ok := deviceID.(primitive.ObjectID)
+	if !ok {
+		return nil
+	}
+
+	var snapshot bson.M
+	if err := devices.FindOne(ctx, bson.M{"_id": id}).Decode(&snapshot); err != nil {
+		return err
+	}
+	delete(snapshot, "_id")
+
+	revisions := devices.Database().Collection(models.DeviceRevisionsCollection)
+	_, err := revisions.InsertOne(ctx, models.DeviceRevision{
+		DeviceID:  id,
+		Revision:  1,
+		Source:    models.DeviceRevisionCSVImport,
+		Changes:   []models.FieldChange{},
+		Snapshot:  snapshot,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
@@ -57,11 +57,27 @@ func InitCollections() {
 	// 创建集合
 	collections := []string{
 		"devices",
-		"device_reviews",
+		"reviews",
+		"review_votes",
+		"review_comments",
+		"review_images",
+		"review_revisions",
 		"user_devices",
 		"users",
 		"orders",
 		"carts",
+		"svg_upload_batches",
+		"svg_upload_items",
+		"device_revisions",
+		"product_skus",
+		"inventory_reservations",
+		"payment_events",
+		"refunds",
+		"coupons",
+		"coupon_redemptions",
+		"tax_rules",
+		"shipping_rates",
+		"order_counters",
 	}
 
 	for _, collName := range collections {
@@ -84,10 +100,44 @@ func InitCollections() {
 			{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
 			{Keys: bson.D{{Key: "brand", Value: 1}}, Options: options.Index()},
 			{Keys: bson.D{{Key: "type", Value: 1}}, Options: options.Index()},
+			// 设备列表按评分、评测数排序
+			{Keys: bson.D{{Key: "rating.bayesian", Value: -1}}, Options: options.Index()},
+			{Keys: bson.D{{Key: "rating.count", Value: -1}}, Options: options.Index()},
 		},
-		"device_reviews": {
-			{Keys: bson.D{{Key: "deviceId", Value: 1}}, Options: options.Index()},
+		"reviews": {
+			// 按项目查询已发布的评测，审核列表按状态和提交时间查询
+			{Keys: bson.D{{Key: "externalItemId", Value: 1}, {Key: "itemType", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index()},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}, Options: options.Index()},
+			// 审核列表按预审风险分排序
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "moderation.riskScore", Value: -1}, {Key: "createdAt", Value: 1}}, Options: options.Index()},
 			{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index()},
+			// 按设备查询对比评测
+			{Keys: bson.D{{Key: "comparison.items.deviceId", Value: 1}}, Options: options.Index().SetSparse(true)},
+			// 按"有帮助"得分排序
+			{Keys: bson.D{{Key: "helpfulScore", Value: -1}}, Options: options.Index()},
+			// 有待审核修改的评测
+			{Keys: bson.D{{Key: "pendingRevision", Value: 1}}, Options: options.Index().SetSparse(true)},
+			// 热门评测只统计近期有浏览的评测
+			{Keys: bson.D{{Key: "trending.updatedAt", Value: -1}}, Options: options.Index().SetSparse(true)},
+			// 按摘要搜索评测，中英文混排不做分词
+			{Keys: bson.D{{Key: "excerpt", Value: "text"}}, Options: options.Index().SetDefaultLanguage("none")},
+		},
+		"review_votes": {
+			// 每个用户对同一评测只有一票
+			{Keys: bson.D{{Key: "reviewId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"review_comments": {
+			// 按顶层评论分页，再按顶层评论取回复
+			{Keys: bson.D{{Key: "reviewId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: 1}}, Options: options.Index()},
+			{Keys: bson.D{{Key: "rootId", Value: 1}, {Key: "createdAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+		},
+		"review_images": {
+			// 按评测查图片；reviewId为空的是未关联图片，按上传时间清理
+			{Keys: bson.D{{Key: "reviewId", Value: 1}, {Key: "createdAt", Value: 1}}, Options: options.Index()},
+		},
+		"review_revisions": {
+			// 同一评测的版本号唯一，并发修改时依赖该索引检测冲突
+			{Keys: bson.D{{Key: "reviewId", Value: 1}, {Key: "revision", Value: -1}}, Options: options.Index().SetUnique(true)},
 		},
 		"user_devices": {
 			{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index()},
@@ -97,10 +147,67 @@ func InitCollections() {
 		},
 		"orders": {
 			{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index()},
+			// 管理后台按状态、订单号筛选，按支付时间统计
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}, Options: options.Index()},
+			// 订单号唯一，创建前由prepareOrderNumberIndex删除旧的非唯一索引并处理重复的订单号
+			{Keys: bson.D{{Key: "orderNumber", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "paidAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+			// 查找超时未支付的订单
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "reservationExpiresAt", Value: 1}}, Options: options.Index()},
 		},
 		"carts": {
 			{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index()},
 		},
+		"svg_upload_batches": {
+			// 过期未确认的上传批次自动清理
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"svg_upload_items": {
+			{Keys: bson.D{{Key: "batchId", Value: 1}, {Key: "index", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// 与批次同时过期
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"device_revisions": {
+			// 同一设备的版本号唯一，并发修改时依赖该索引检测冲突
+			{Keys: bson.D{{Key: "deviceId", Value: 1}, {Key: "revision", Value: -1}}, Options: options.Index().SetUnique(true)},
+		},
+		"product_skus": {
+			{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "deviceId", Value: 1}, {Key: "active", Value: 1}}, Options: options.Index()},
+		},
+		"inventory_reservations": {
+			{Keys: bson.D{{Key: "orderId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// 查找到期未支付的预留
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expiresAt", Value: 1}}, Options: options.Index()},
+		},
+		"payment_events": {
+			// 回调按事件ID去重
+			{Keys: bson.D{{Key: "provider", Value: 1}, {Key: "eventId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"refunds": {
+			{Keys: bson.D{{Key: "orderId", Value: 1}}, Options: options.Index()},
+			// 管理员按状态查看待审核的退款
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}, Options: options.Index()},
+		},
+		"coupons": {
+			{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"coupon_redemptions": {
+			// 一个订单最多使用一张优惠券
+			{Keys: bson.D{{Key: "orderId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// 统计用户的使用次数
+			{Keys: bson.D{{Key: "couponId", Value: 1}, {Key: "userId", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index()},
+		},
+		"tax_rules": {
+			{Keys: bson.D{{Key: "country", Value: 1}, {Key: "state", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"shipping_rates": {
+			{Keys: bson.D{{Key: "method", Value: 1}, {Key: "country", Value: 1}, {Key: "state", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+	}
+
+	if err := prepareOrderNumberIndex(ctx, db.Collection("orders")); err != nil {
+		log.Printf("Warning: Failed to prepare orderNumber index: %v\n", err)
 	}
 
 	for collName, collIndexes := range indexes {
@@ -67,13 +67,21 @@ type MouseDevice struct {
 		FrontFlare        string `bson:"frontFlare"`
 		SideCurvature     string `bson:"sideCurvature"`
 		HandCompatibility string `bson:"handCompatibility"`
+		ThumbRest         bool   `bson:"thumbRest"`
+		RingFingerRest    bool   `bson:"ringFingerRest"`
+		Material          string `bson:"material,omitempty"`
 	} `bson:"shape"`
 	Technical struct {
-		Connectivity []string `bson:"connectivity"`
-		Sensor       string   `bson:"sensor"`
-		MaxDPI       int      `bson:"maxDPI"`
-		PollingRate  int      `bson:"pollingRate"`
-		SideButtons  int      `bson:"sideButtons"`
+		Connectivity     []string `bson:"connectivity"`
+		Sensor           string   `bson:"sensor"`
+		MaxDPI           int      `bson:"maxDPI"`
+		PollingRate      int      `bson:"pollingRate"`
+		SideButtons      int      `bson:"sideButtons"`
+		MiddleButtons    int      `bson:"middleButtons"`
+		SensorTechnology string   `bson:"sensorTechnology,omitempty"`
+		SensorPosition   string   `bson:"sensorPosition,omitempty"`
+		TrackingSpeed    int      `bson:"trackingSpeed,omitempty"`
+		Acceleration     int      `bson:"acceleration,omitempty"`
 	} `bson:"technical"`
 	Recommended struct {
 		GameTypes    []string `bson:"gameTypes"`
@@ -184,12 +192,15 @@ func InitMongo() {
 			mouseDevice := createMouseDevice(mouseData)
 
 			// 插入到数据库
-			_, err = collection.InsertOne(ctx, mouseDevice)
+			res, err := collection.InsertOne(ctx, mouseDevice)
 			if err != nil {
 				log.Printf("Error inserting mouse device into database: %v", err)
 				errorCount++
 				continue
 			}
+			if err := recordImportRevision(ctx, collection, res.InsertedID); err != nil {
+				log.Printf("Error recording revision for %s: %v", mouseData.Name, err)
+			}
 			successCount++
 		}
 	}
@@ -299,6 +310,21 @@ func parseInt(value string) (int, error) {
 	return strconv.Atoi(value)
 }
 
+// parseYesNo 将CSV中的Yes/No转换为布尔值
+func parseYesNo(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == "yes" || value == "true" || value == "1"
+}
+
+// cleanUnknown CSV中用"-"表示未知值
+func cleanUnknown(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "-" {
+		return ""
+	}
+	return value
+}
+
 func createMouseDevice(data MouseData) MouseDevice {
 	now := time.Now()
 	brandName := extractBrandName(data.Name)
@@ -324,6 +350,9 @@ func createMouseDevice(data MouseData) MouseDevice {
 	mouse.Shape.FrontFlare = data.FrontFlare
 	mouse.Shape.SideCurvature = data.SideCurvature
 	mouse.Shape.HandCompatibility = data.HandCompatibility
+	mouse.Shape.ThumbRest = parseYesNo(data.ThumbRest)
+	mouse.Shape.RingFingerRest = parseYesNo(data.RingFingerRest)
+	mouse.Shape.Material = cleanUnknown(data.Material)
 
 	// 设置技术参数
 	connectivityList := []string{}
@@ -335,6 +364,11 @@ func createMouseDevice(data MouseData) MouseDevice {
 	mouse.Technical.MaxDPI = data.DPI
 	mouse.Technical.PollingRate = data.PollingRate
 	mouse.Technical.SideButtons = data.SideButtons
+	mouse.Technical.MiddleButtons = data.MiddleButtons
+	mouse.Technical.SensorTechnology = cleanUnknown(data.SensorTechnology)
+	mouse.Technical.SensorPosition = cleanUnknown(data.SensorPosition)
+	mouse.Technical.TrackingSpeed = data.TrackingSpeed
+	mouse.Technical.Acceleration = data.Acceleration
 
 	// 设置推荐信息
 	// 根据形状和性能特征推断适合的游戏类型和握持方式
@@ -0,0 +1,40 @@
+package scripts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOrderAmountUpdates(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{
+		"subtotal":    199.99,
+		"shippingFee": 10.0,
+		"tax":         int64(0),
+		"discount":    0.0,
+		"total":       209.99,
+		"items": bson.A{
+			bson.M{"price": 199.99, "quantity": 1, "subtotal": 199.99},
+			bson.M{"price": int64(1000), "subtotal": int64(1000)},
+		},
+	})
+	require.NoError(t, err)
+	// 按从数据库读取的方式解码，嵌套文档为bson.M
+	var doc bson.M
+	require.NoError(t, bson.Unmarshal(raw, &doc))
+
+	assert.Equal(t, bson.M{
+		"subtotal":         int64(19999),
+		"shippingFee":      int64(1000),
+		"discount":         int64(0),
+		"total":            int64(20999),
+		"items.0.price":    int64(19999),
+		"items.0.subtotal": int64(19999),
+		"currency":         "CNY",
+	}, orderAmountUpdates(doc))
+
+	// 已经转换过的订单不再更新
+	assert.Empty(t, orderAmountUpdates(bson.M{"total": int64(20999), "currency": "CNY"}))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -172,13 +173,16 @@ func importMice(config *Config) error {
 			fmt.Printf("Document from line %d: %+v\n", lineCount, doc)
 		} else {
 			// Insert the document
-			_, err = collection.InsertOne(ctx, doc)
+			res, err := collection.InsertOne(ctx, doc)
 			if err != nil {
 				errorCount++
 				log.Printf("Warning: Error inserting document from line %d: %v", lineCount, err)
 				lineCount++
 				continue
 			}
+			if err := recordImportRevision(ctx, collection, res.InsertedID); err != nil {
+				log.Printf("Warning: Error recording revision for line %d: %v", lineCount, err)
+			}
 		}
 
 		importedCount++
@@ -268,7 +272,7 @@ func mapHeaderToField(header string) string {
 		"hand compatibility":   "shape.handCompatibility",
 		"thumb rest":           "shape.thumbRest",
 		"ring finger rest":     "shape.ringFingerRest",
-		"material":             "material",
+		"material":             "shape.material",
 		"connectivity":         "connection_type",
 		"sensor":               "technical.sensor",
 		"sensor technology":    "technical.sensorTechnology",
@@ -326,12 +330,8 @@ func getConverter(field string) func(string) (interface{}, error) {
 	// Determine converter based on field type
 	field = strings.ToLower(field)
 
-	// Numeric fields
-	numericFields := []string{
-		"dimensions.length", "length", "length (mm)",
-		"dimensions.width", "width", "width (mm)",
-		"dimensions.height", "height", "height (mm)",
-		"dimensions.weight", "weight", "weight (g)",
+	// Integer fields (stored as int to match models.MouseTechnical)
+	integerFields := []string{
 		"technical.maxdpi", "dpi",
 		"technical.pollingrate", "polling rate",
 		"technical.trackingspeed", "tracking speed", "tracking speed (ips)",
@@ -340,29 +340,47 @@ func getConverter(field string) func(string) (interface{}, error) {
 		"technical.middlebuttons", "middle buttons",
 	}
 
+	// Numeric fields
+	numericFields := []string{
+		"dimensions.length", "length", "length (mm)",
+		"dimensions.width", "width", "width (mm)",
+		"dimensions.height", "height", "height (mm)",
+		"dimensions.weight", "weight", "weight (g)",
+	}
+
 	// Boolean fields
 	booleanFields := []string{
 		"shape.thumbrest", "thumb rest",
 		"shape.ringfingerrest", "ring finger rest",
 	}
 
+	// Check for integer fields
+	for _, inf := range integerFields {
+		if strings.Contains(field, inf) {
+			return func(val string) (interface{}, error) {
+				val = keepNumeric(val)
+				if val == "" {
+					return 0, nil
+				}
+
+				f, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					return nil, err
+				}
+				return int(math.Round(f)), nil
+			}
+		}
+	}
+
 	// Check for numeric fields
 	for _, nf := range numericFields {
 		if strings.Contains(field, nf) {
 			return func(val string) (interface{}, error) {
-				val = strings.TrimSpace(val)
+				val = keepNumeric(val)
 				if val == "" {
 					return 0.0, nil
 				}
 
-				// Remove any non-numeric characters except decimal point
-				val = strings.Map(func(r rune) rune {
-					if (r >= '0' && r <= '9') || r == '.' {
-						return r
-					}
-					return -1
-				}, val)
-
 				// Parse as float64
 				return strconv.ParseFloat(val, 64)
 			}
@@ -379,12 +397,26 @@ func getConverter(field string) func(string) (interface{}, error) {
 		}
 	}
 
-	// Default string converter
+	// Default string converter, "-" means the value is unknown
 	return func(val string) (interface{}, error) {
-		return strings.TrimSpace(val), nil
+		val = strings.TrimSpace(val)
+		if val == "-" {
+			return "", nil
+		}
+		return val, nil
 	}
 }
 
+// keepNumeric removes any non-numeric characters except decimal point
+func keepNumeric(val string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' {
+			return r
+		}
+		return -1
+	}, strings.TrimSpace(val))
+}
+
 func processRecord(record []string, mappings []ColumnMapping, config *Config) (bson.M, error) {
 	// Create a new document
 	doc := bson.M{}
@@ -0,0 +1,99 @@
+package scripts
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// orderNumberIndexName 订单号索引的默认名称，旧版本以同名创建过非唯一索引
+const orderNumberIndexName = "orderNumber_1"
+
+// prepareOrderNumberIndex 为创建订单号唯一索引做准备：删除旧的非唯一索引，并为重复的旧订单号加后缀
+// 同名同字段的索引选项不同会导致创建失败，重复的订单号会导致唯一索引无法建立
+func prepareOrderNumberIndex(ctx context.Context, orders *mongo.Collection) error {
+	if err := dropNonUniqueOrderNumberIndex(ctx, orders); err != nil {
+		return fmt.Errorf("drop non-unique orderNumber index: %w", err)
+	}
+
+	renamed, err := dedupeOrderNumbers(ctx, orders)
+	if err != nil {
+		return fmt.Errorf("dedupe order numbers: %w", err)
+	}
+	if renamed > 0 {
+		fmt.Printf("Renamed %d orders with duplicate order numbers\n", renamed)
+	}
+	return nil
+}
+
+// dropNonUniqueOrderNumberIndex 删除旧版本创建的非唯一订单号索引，已是唯一索引时保留
+func dropNonUniqueOrderNumberIndex(ctx context.Context, orders *mongo.Collection) error {
+	cursor, err := orders.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if index["name"] != orderNumberIndexName {
+			continue
+		}
+		if unique, _ := index["unique"].(bool); unique {
+			return nil
+		}
+		_, err := orders.Indexes().DropOne(ctx, orderNumberIndexName)
+		return err
+	}
+	return nil
+}
+
+// dedupeOrderNumbers 同一订单号最早创建的订单保留原号，其余订单追加后缀，返回修改的订单数
+func dedupeOrderNumbers(ctx context.Context, orders *mongo.Collection) (int, error) {
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"orderNumber": bson.M{"$type": "string"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$orderNumber",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var duplicates []struct {
+		Number string               `bson:"_id"`
+		IDs    []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return 0, err
+	}
+
+	renamed := 0
+	for _, duplicate := range duplicates {
+		for id, number := range renameDuplicateOrderNumbers(duplicate.Number, duplicate.IDs) {
+			if _, err := orders.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"orderNumber": number}}); err != nil {
+				return renamed, err
+			}
+			renamed++
+		}
+	}
+	return renamed, nil
+}
+
+// renameDuplicateOrderNumbers 按创建顺序为重复的订单号分配新号，第一个订单保留原号
+// 后缀以D开头，不会与当前格式的订单号冲突
+func renameDuplicateOrderNumbers(number string, ids []primitive.ObjectID) map[primitive.ObjectID]string {
+	renamed := make(map[primitive.ObjectID]string, len(ids))
+	for i := 1; i < len(ids); i++ {
+		renamed[ids[i]] = fmt.Sprintf("%s-D%d", number, i+1)
+	}
+	return renamed
+}
@@ -0,0 +1,19 @@
+package scripts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRenameDuplicateOrderNumbers(t *testing.T) {
+	first, second, third := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	assert.Equal(t, map[primitive.ObjectID]string{
+		second: "20240101-0420-D2",
+		third:  "20240101-0420-D3",
+	}, renameDuplicateOrderNumbers("20240101-0420", []primitive.ObjectID{first, second, third}))
+
+	assert.Empty(t, renameDuplicateOrderNumbers("20240101-0420", []primitive.ObjectID{first}))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"project/backend/internal/errors"
 	"project/backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockService 实现购物车服务接口的Mock版本
@@ -17,17 +19,22 @@ func (s *MockService) GetCart(ctx context.Context, userID string) (*models.Cart,
 }
 
 // AddToCart 向购物车添加商品
-func (s *MockService) AddToCart(ctx context.Context, userID string, item models.CartItem) error {
+func (s *MockService) AddToCart(ctx context.Context, userID string, skuID string, quantity int) error {
 	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
 }
 
 // UpdateQuantity 更新购物车商品数量
-func (s *MockService) UpdateQuantity(ctx context.Context, userID string, productID string, quantity int) error {
+func (s *MockService) UpdateQuantity(ctx context.Context, userID string, skuID string, quantity int) error {
 	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
 }
 
 // RemoveFromCart 从购物车移除商品
-func (s *MockService) RemoveFromCart(ctx context.Context, userID string, productID string) error {
+func (s *MockService) RemoveFromCart(ctx context.Context, userID string, skuID string) error {
+	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
+}
+
+// RemoveItems 批量移除购物车商品
+func (s *MockService) RemoveItems(ctx context.Context, userID string, skuIDs []primitive.ObjectID) error {
 	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
 }
 
@@ -35,3 +42,13 @@ func (s *MockService) RemoveFromCart(ctx context.Context, userID string, product
 func (s *MockService) ClearCart(ctx context.Context, userID string) error {
 	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
 }
+
+// ApplyCoupon 为购物车使用优惠券
+func (s *MockService) ApplyCoupon(ctx context.Context, userID string, code string) error {
+	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
+}
+
+// RemoveCoupon 移除购物车的优惠券
+func (s *MockService) RemoveCoupon(ctx context.Context, userID string) error {
+	return errors.NewInternalServerError("数据库连接失败，购物车服务暂不可用")
+}
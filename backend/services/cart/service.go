@@ -11,20 +11,27 @@ import (
 
 	"project/backend/internal/errors"
 	"project/backend/models"
+	"project/backend/services/product"
+	"project/backend/services/promotion"
 )
 
 // Service 购物车服务接口
 type Service interface {
 	GetCart(ctx context.Context, userID string) (*models.Cart, error)
-	AddToCart(ctx context.Context, userID string, item models.CartItem) error
-	UpdateQuantity(ctx context.Context, userID string, productID string, quantity int) error
-	RemoveFromCart(ctx context.Context, userID string, productID string) error
+	AddToCart(ctx context.Context, userID string, skuID string, quantity int) error
+	UpdateQuantity(ctx context.Context, userID string, skuID string, quantity int) error
+	RemoveFromCart(ctx context.Context, userID string, skuID string) error
+	RemoveItems(ctx context.Context, userID string, skuIDs []primitive.ObjectID) error
 	ClearCart(ctx context.Context, userID string) error
+	ApplyCoupon(ctx context.Context, userID string, code string) error
+	RemoveCoupon(ctx context.Context, userID string) error
 }
 
 // MongoService 实现了购物车服务接口
 type MongoService struct {
 	collection *mongo.Collection
+	products   product.Service
+	promotions promotion.Service
 }
 
 // NewService 创建新的购物车服务
@@ -35,14 +42,35 @@ func NewService(db *mongo.Database) Service {
 	}
 	return &MongoService{
 		collection: db.Collection("carts"),
+		products:   product.New(db),
+		promotions: promotion.New(db),
 	}
 }
 
 // GetCart 获取用户的购物车
 func (s *MongoService) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	cart, priced, err := s.loadCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 优惠券不可用时保留券码并返回原因，由用户决定是否移除
+	if cart.CouponCode != "" {
+		quote, err := s.promotions.Quote(ctx, cart.UserID, cart.CouponCode, priced)
+		if err != nil {
+			cart.CouponError = err.Error()
+		} else {
+			cart.Discount = quote.Discount
+		}
+	}
+	return cart, nil
+}
+
+// loadCart 获取购物车并按商品目录重新定价，用户尚无购物车时创建
+func (s *MongoService) loadCart(ctx context.Context, userID string) (*models.Cart, []product.PricedLine, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, errors.NewBadRequestError("无效的用户ID")
+		return nil, nil, errors.NewBadRequestError("无效的用户ID")
 	}
 
 	filter := bson.M{"user_id": objectID}
@@ -60,24 +88,63 @@ func (s *MongoService) GetCart(ctx context.Context, userID string) (*models.Cart
 			}
 			result, err := s.collection.InsertOne(ctx, cart)
 			if err != nil {
-				return nil, errors.NewInternalServerError("创建购物车失败")
+				return nil, nil, errors.NewInternalServerError("创建购物车失败")
 			}
 			cart.ID = result.InsertedID.(primitive.ObjectID)
 		} else {
-			return nil, errors.NewInternalServerError("获取购物车失败")
+			return nil, nil, errors.NewInternalServerError("获取购物车失败")
 		}
 	}
 
-	return cart, nil
+	priced, err := s.refreshPrices(ctx, cart)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cart, priced, nil
 }
 
-// AddToCart 向购物车添加商品
-func (s *MongoService) AddToCart(ctx context.Context, userID string, item models.CartItem) error {
+// refreshPrices 按商品目录的当前价格和库存更新购物车中的商品
+func (s *MongoService) refreshPrices(ctx context.Context, cart *models.Cart) ([]product.PricedLine, error) {
+	lines := make([]product.Line, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lines = append(lines, product.Line{SKUID: item.SKUID, Quantity: item.Quantity})
+	}
+
+	priced, err := s.products.PriceLines(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range priced {
+		item := &cart.Items[i]
+		item.Available = line.Available
+		item.Reason = line.Reason
+		if line.SKU == nil {
+			continue
+		}
+		item.Name = line.Name
+		item.Price = line.UnitPrice
+		item.Currency = line.Currency
+		item.ImageURL = line.ImageURL
+	}
+	return priced, nil
+}
+
+// AddToCart 向购物车添加商品，名称和价格从商品目录获取
+func (s *MongoService) AddToCart(ctx context.Context, userID string, skuID string, quantity int) error {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的用户ID")
 	}
 
+	skuObjID, err := primitive.ObjectIDFromHex(skuID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的商品ID")
+	}
+	if quantity <= 0 {
+		return errors.NewBadRequestError("商品数量必须大于0")
+	}
+
 	// 获取用户购物车
 	cart, err := s.GetCart(ctx, userID)
 	if err != nil {
@@ -87,22 +154,53 @@ func (s *MongoService) AddToCart(ctx context.Context, userID string, item models
 	// 检查商品是否已在购物车中
 	itemIndex := -1
 	for i, cartItem := range cart.Items {
-		if cartItem.ProductID == item.ProductID {
+		if cartItem.SKUID == skuObjID {
 			itemIndex = i
 			break
 		}
 	}
 
+	// 按加购后的总数量校验库存
+	total := quantity
+	if itemIndex >= 0 {
+		total += cart.Items[itemIndex].Quantity
+	}
+	priced, err := s.products.ResolveLines(ctx, []product.Line{{SKUID: skuObjID, Quantity: total}})
+	if err != nil {
+		return err
+	}
+	line := priced[0]
+
+	// 购物车只支持单一币种
+	for _, cartItem := range cart.Items {
+		if cartItem.SKUID != skuObjID && cartItem.Currency != "" && cartItem.Currency != line.Currency {
+			return errors.NewBadRequestError("购物车中已有其他币种的商品")
+		}
+	}
+
+	item := models.CartItem{
+		ProductID:   line.DeviceID,
+		SKUID:       skuObjID,
+		ProductType: line.ProductType,
+		Name:        line.Name,
+		Price:       line.UnitPrice,
+		Currency:    line.Currency,
+		Quantity:    total,
+		ImageURL:    line.ImageURL,
+	}
+
 	if itemIndex >= 0 {
 		// 若商品已存在，更新数量
-		cart.Items[itemIndex].Quantity += item.Quantity
 		update := bson.M{
 			"$set": bson.M{
-				"items":      cart.Items,
-				"updated_at": time.Now(),
+				"items.$[elem]": item,
+				"updated_at":    time.Now(),
 			},
 		}
-		_, err = s.collection.UpdateOne(ctx, bson.M{"user_id": objectID}, update)
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"elem.sku_id": skuObjID}},
+		})
+		_, err = s.collection.UpdateOne(ctx, bson.M{"user_id": objectID}, update, opts)
 	} else {
 		// 若商品不存在，添加到购物车
 		update := bson.M{
@@ -119,31 +217,39 @@ func (s *MongoService) AddToCart(ctx context.Context, userID string, item models
 }
 
 // UpdateQuantity 更新购物车商品数量
-func (s *MongoService) UpdateQuantity(ctx context.Context, userID string, productID string, quantity int) error {
+func (s *MongoService) UpdateQuantity(ctx context.Context, userID string, skuID string, quantity int) error {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的用户ID")
 	}
 
-	productObjID, err := primitive.ObjectIDFromHex(productID)
+	skuObjID, err := primitive.ObjectIDFromHex(skuID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的商品ID")
 	}
 
 	if quantity <= 0 {
 		// 若数量为0，从购物车中移除
-		return s.RemoveFromCart(ctx, userID, productID)
+		return s.RemoveFromCart(ctx, userID, skuID)
+	}
+
+	// 校验库存并刷新价格
+	priced, err := s.products.ResolveLines(ctx, []product.Line{{SKUID: skuObjID, Quantity: quantity}})
+	if err != nil {
+		return err
 	}
 
 	update := bson.M{
 		"$set": bson.M{
 			"items.$[elem].quantity": quantity,
+			"items.$[elem].price":    priced[0].UnitPrice,
+			"items.$[elem].currency": priced[0].Currency,
 			"updated_at":             time.Now(),
 		},
 	}
 
 	opts := options.Update().SetArrayFilters(options.ArrayFilters{
-		Filters: []interface{}{bson.M{"elem.product_id": productObjID}},
+		Filters: []interface{}{bson.M{"elem.sku_id": skuObjID}},
 	})
 
 	_, err = s.collection.UpdateOne(
@@ -159,20 +265,41 @@ func (s *MongoService) UpdateQuantity(ctx context.Context, userID string, produc
 }
 
 // RemoveFromCart 从购物车移除商品
-func (s *MongoService) RemoveFromCart(ctx context.Context, userID string, productID string) error {
+func (s *MongoService) RemoveFromCart(ctx context.Context, userID string, skuID string) error {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的用户ID")
 	}
 
-	productObjID, err := primitive.ObjectIDFromHex(productID)
+	skuObjID, err := primitive.ObjectIDFromHex(skuID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的商品ID")
 	}
 
 	update := bson.M{
 		"$pull": bson.M{
-			"items": bson.M{"product_id": productObjID},
+			"items": bson.M{"sku_id": skuObjID},
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	_, err = s.collection.UpdateOne(ctx, bson.M{"user_id": objectID}, update)
+	if err != nil {
+		return errors.NewInternalServerError("从购物车移除商品失败")
+	}
+	return nil
+}
+
+// RemoveItems 批量移除购物车商品，下单后移除已购买的商品
+func (s *MongoService) RemoveItems(ctx context.Context, userID string, skuIDs []primitive.ObjectID) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的用户ID")
+	}
+
+	update := bson.M{
+		"$pull": bson.M{
+			"items": bson.M{"sku_id": bson.M{"$in": skuIDs}},
 		},
 		"$set": bson.M{"updated_at": time.Now()},
 	}
@@ -204,3 +331,51 @@ func (s *MongoService) ClearCart(ctx context.Context, userID string) error {
 	}
 	return nil
 }
+
+// ApplyCoupon 为购物车使用优惠券，按当前商品校验通过后才保存
+func (s *MongoService) ApplyCoupon(ctx context.Context, userID string, code string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的用户ID")
+	}
+
+	_, priced, err := s.loadCart(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	quote, err := s.promotions.Quote(ctx, objectID, code, priced)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"coupon_code": quote.Coupon.Code,
+			"updated_at":  time.Now(),
+		},
+	}
+	_, err = s.collection.UpdateOne(ctx, bson.M{"user_id": objectID}, update)
+	if err != nil {
+		return errors.NewInternalServerError("使用优惠券失败")
+	}
+	return nil
+}
+
+// RemoveCoupon 移除购物车的优惠券
+func (s *MongoService) RemoveCoupon(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的用户ID")
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"coupon_code": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+	_, err = s.collection.UpdateOne(ctx, bson.M{"user_id": objectID}, update)
+	if err != nil {
+		return errors.NewInternalServerError("移除优惠券失败")
+	}
+	return nil
+}
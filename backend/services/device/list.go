@@ -0,0 +1,116 @@
+package device
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/device"
+)
+
+// deviceSortFields 列表可用的排序字段，按评分排序使用贝叶斯平均，避免评测很少的设备排在前面
+var deviceSortFields = map[string]string{
+	"name":      "name",
+	"brand":     "brand",
+	"createdAt": "createdAt",
+	"rating":    "rating.bayesian",
+	"reviews":   "rating.count",
+}
+
+// GetDeviceList 按类型获取设备列表
+func (s *ServiceImpl) GetDeviceList(ctx context.Context, deviceType string, page, pageSize int) (*device.DeviceListResponse, error) {
+	return s.ListDevices(ctx, device.DeviceListFilter{Type: deviceType, Page: page, PageSize: pageSize})
+}
+
+// ListDevices 列出设备，支持按类型、品牌、评分筛选和排序，回收站中的设备不出现在列表中
+func (s *ServiceImpl) ListDevices(ctx context.Context, filter device.DeviceListFilter) (*device.DeviceListResponse, error) {
+	query, sort, err := buildDeviceListQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := filter.Page, filter.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.DevicesCollection)
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取设备列表失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取设备列表失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var devices []models.HardwareDevice
+	if err := cursor.All(ctx, &devices); err != nil {
+		return nil, errors.NewInternalServerError("解析设备列表失败: " + err.Error())
+	}
+
+	response := &device.DeviceListResponse{
+		Total:    int(total),
+		Page:     page,
+		PageSize: pageSize,
+		Devices:  make([]device.DevicePreview, len(devices)),
+	}
+	for i, d := range devices {
+		response.Devices[i] = device.DevicePreview{
+			ID:          d.ID.Hex(),
+			Name:        d.Name,
+			Brand:       d.Brand,
+			Type:        string(d.Type),
+			ImageURL:    d.ImageURL,
+			Description: d.Description,
+			Rating:      d.Rating,
+			CreatedAt:   d.CreatedAt,
+		}
+	}
+	return response, nil
+}
+
+// buildDeviceListQuery 构建列表的查询条件和排序，默认按创建时间倒序
+func buildDeviceListQuery(filter device.DeviceListFilter) (bson.M, bson.D, error) {
+	query := bson.M{"deletedAt": nil}
+	if filter.Type != "" {
+		query["type"] = strings.ToLower(filter.Type)
+	}
+	if filter.Brand != "" {
+		query["brand"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.Brand) + "$", Options: "i"}
+	}
+	if filter.MinRating > 0 {
+		query["rating.average"] = bson.M{"$gte": filter.MinRating}
+	}
+	if filter.MinReviews > 0 {
+		query["rating.count"] = bson.M{"$gte": filter.MinReviews}
+	}
+
+	field := "createdAt"
+	if filter.SortBy != "" {
+		var ok bool
+		if field, ok = deviceSortFields[filter.SortBy]; !ok {
+			return nil, nil, errors.NewBadRequestError("不支持的排序字段: " + filter.SortBy)
+		}
+	}
+	order := -1
+	if filter.SortOrder == "asc" {
+		order = 1
+	}
+	return query, bson.D{{Key: field, Value: order}, {Key: "_id", Value: order}}, nil
+}
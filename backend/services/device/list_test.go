@@ -0,0 +1,37 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/types/device"
+)
+
+func TestBuildDeviceListQuery(t *testing.T) {
+	query, sort, err := buildDeviceListQuery(device.DeviceListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"deletedAt": nil}, query)
+	assert.Equal(t, bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}, sort)
+
+	query, sort, err = buildDeviceListQuery(device.DeviceListFilter{
+		Type:       "Mouse",
+		Brand:      "Logitech G",
+		MinRating:  4,
+		MinReviews: 3,
+		SortBy:     "rating",
+		SortOrder:  "asc",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mouse", query["type"])
+	assert.Equal(t, primitive.Regex{Pattern: `^Logitech G$`, Options: "i"}, query["brand"])
+	assert.Equal(t, bson.M{"$gte": 4.0}, query["rating.average"])
+	assert.Equal(t, bson.M{"$gte": 3}, query["rating.count"])
+	assert.Equal(t, bson.D{{Key: "rating.bayesian", Value: 1}, {Key: "_id", Value: 1}}, sort)
+
+	_, _, err = buildDeviceListQuery(device.DeviceListFilter{SortBy: "price"})
+	assert.Error(t, err)
+}
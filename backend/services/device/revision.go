@@ -0,0 +1,457 @@
+package device
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/device"
+)
+
+// revisionIgnoredFields 不参与对比和回滚的字段
+var revisionIgnoredFields = map[string]bool{
+	"_id":       true,
+	"createdAt": true,
+	"updatedAt": true,
+	"rating":    true, // 评分汇总由评测服务根据评测重新计算，回滚不能恢复旧的汇总
+}
+
+// revisionMeta 修改记录的元信息
+type revisionMeta struct {
+	Source       models.DeviceRevisionSource
+	ActorID      *primitive.ObjectID
+	RolledBackTo int
+}
+
+// GetDeviceRevisions 获取设备修改历史，按版本号倒序
+func (s *ServiceImpl) GetDeviceRevisions(ctx context.Context, deviceID string, page, pageSize int) (*device.DeviceRevisionListResponse, error) {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.DeviceRevisionsCollection)
+	filter := bson.M{"deviceId": id}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取修改历史失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetProjection(bson.M{"snapshot": 0})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取修改历史失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []models.DeviceRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, errors.NewInternalServerError("解析修改历史失败: " + err.Error())
+	}
+
+	response := &device.DeviceRevisionListResponse{
+		Revisions: make([]device.DeviceRevisionInfo, 0, len(revisions)),
+		Total:     int(total),
+		Page:      page,
+		PageSize:  pageSize,
+	}
+	for i := range revisions {
+		response.Revisions = append(response.Revisions, mapDeviceRevisionToInfo(&revisions[i]))
+	}
+	return response, nil
+}
+
+// DiffDeviceRevisions 对比设备的两个版本
+func (s *ServiceImpl) DiffDeviceRevisions(ctx context.Context, deviceID string, from, to int) (*device.DeviceRevisionDiffResponse, error) {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+
+	fromRev, err := s.findDeviceRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.findDeviceRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device.DeviceRevisionDiffResponse{
+		DeviceID: deviceID,
+		From:     from,
+		To:       to,
+		Changes:  diffDocuments(fromRev.Snapshot, toRev.Snapshot),
+	}, nil
+}
+
+// RollbackDevice 将设备恢复到指定版本的内容，回滚本身也会生成一条新的修改记录
+func (s *ServiceImpl) RollbackDevice(ctx context.Context, operatorID, deviceID string, revision int) (*device.DeviceRevisionInfo, error) {
+	actorID, err := primitive.ObjectIDFromHex(operatorID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+
+	target, err := s.findDeviceRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var current bson.M
+	err = s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": id, "deletedAt": nil}).Decode(&current)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("设备不存在")
+		}
+		return nil, errors.NewInternalServerError("获取设备失败: " + err.Error())
+	}
+
+	update := buildRollbackUpdate(current, target.Snapshot)
+	_, rev, err := s.updateDeviceWithRevision(ctx, bson.M{"_id": id, "deletedAt": nil}, update, revisionMeta{
+		Source:       models.DeviceRevisionRollback,
+		ActorID:      &actorID,
+		RolledBackTo: revision,
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("设备不存在")
+		}
+		return nil, errors.NewInternalServerError("回滚设备失败: " + err.Error())
+	}
+	if rev == nil {
+		return nil, errors.NewBadRequestError("设备内容与该版本一致，无需回滚")
+	}
+
+	info := mapDeviceRevisionToInfo(rev)
+	return &info, nil
+}
+
+// UpdateDeviceByScript 维护脚本修改设备，和后台编辑一样记录修改历史，已删除的设备不处理
+// 没有实际变化时返回nil
+func (s *ServiceImpl) UpdateDeviceByScript(ctx context.Context, deviceID primitive.ObjectID, update bson.M) (*models.DeviceRevision, error) {
+	_, rev, err := s.updateDeviceWithRevision(ctx, bson.M{"_id": deviceID, "deletedAt": nil}, update, revisionMeta{
+		Source: models.DeviceRevisionScript,
+	})
+	return rev, err
+}
+
+// updateDeviceWithRevision 更新设备并记录修改历史，返回更新后的文档
+// 读取修改前的状态、更新和写入记录在同一个事务中完成，记录写入失败时修改一并撤销；没有实际变化时不生成记录
+func (s *ServiceImpl) updateDeviceWithRevision(ctx context.Context, filter, update bson.M, meta revisionMeta) (bson.Raw, *models.DeviceRevision, error) {
+	var after bson.Raw
+	var rev *models.DeviceRevision
+	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		var err error
+		after, rev, err = s.updateDeviceInTransaction(sc, filter, update, meta)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return after, rev, nil
+}
+
+// updateDeviceInTransaction 在调用方的事务中更新设备并记录修改历史，供需要同时写入其他数据的修改使用
+func (s *ServiceImpl) updateDeviceInTransaction(sc mongo.SessionContext, filter, update bson.M, meta revisionMeta) (bson.Raw, *models.DeviceRevision, error) {
+	collection := s.db.Collection(models.DevicesCollection)
+
+	var before bson.M
+	if err := collection.FindOne(sc, filter).Decode(&before); err != nil {
+		return nil, nil, err
+	}
+
+	// 事务中并发修改同一设备会写冲突并整体重试，before和after对应同一次修改
+	query := bson.M{"_id": before["_id"]}
+	for key, value := range filter {
+		query[key] = value
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	raw, err := collection.FindOneAndUpdate(sc, query, update, opts).Raw()
+	if err != nil {
+		return nil, nil, err
+	}
+	var afterDoc bson.M
+	if err := bson.Unmarshal(raw, &afterDoc); err != nil {
+		return nil, nil, err
+	}
+
+	deviceID, _ := before["_id"].(primitive.ObjectID)
+	rev, err := s.recordDeviceRevision(sc, deviceID, before, afterDoc, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, rev, nil
+}
+
+// withTransaction 在事务中执行fn，遇到写冲突等临时错误时整体重试
+func (s *ServiceImpl) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// recordDeviceRevision 写入一条修改记录，需要在修改设备的事务中调用
+// 设备第一次被记录时，先把修改前的状态保存为baseline，保证可以回滚到最初的内容
+// 同一设备的修改在事务中串行执行，版本号不会冲突
+func (s *ServiceImpl) recordDeviceRevision(ctx context.Context, deviceID primitive.ObjectID, before, after bson.M, meta revisionMeta) (*models.DeviceRevision, error) {
+	changes := diffDocuments(before, after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	latest, err := s.latestDeviceRevision(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var docs []interface{}
+	if latest == 0 && before != nil {
+		latest = 1
+		docs = append(docs, models.DeviceRevision{
+			ID:        primitive.NewObjectID(),
+			DeviceID:  deviceID,
+			Revision:  latest,
+			Source:    models.DeviceRevisionBaseline,
+			Changes:   []models.FieldChange{},
+			Snapshot:  revisionSnapshot(before),
+			CreatedAt: now,
+		})
+	}
+
+	rev := models.DeviceRevision{
+		ID:           primitive.NewObjectID(),
+		DeviceID:     deviceID,
+		Revision:     latest + 1,
+		Source:       meta.Source,
+		ActorID:      meta.ActorID,
+		Changes:      changes,
+		RolledBackTo: meta.RolledBackTo,
+		Snapshot:     revisionSnapshot(after),
+		CreatedAt:    now,
+	}
+	docs = append(docs, rev)
+
+	if _, err := s.db.Collection(models.DeviceRevisionsCollection).InsertMany(ctx, docs); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// latestDeviceRevision 获取设备当前最大的版本号，没有记录时返回0
+func (s *ServiceImpl) latestDeviceRevision(ctx context.Context, deviceID primitive.ObjectID) (int, error) {
+	var latest struct {
+		Revision int `bson:"revision"`
+	}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetProjection(bson.M{"revision": 1})
+	err := s.db.Collection(models.DeviceRevisionsCollection).FindOne(ctx, bson.M{"deviceId": deviceID}, opts).Decode(&latest)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return latest.Revision, nil
+}
+
+// findDeviceRevision 查找设备的指定版本
+func (s *ServiceImpl) findDeviceRevision(ctx context.Context, deviceID primitive.ObjectID, revision int) (*models.DeviceRevision, error) {
+	if revision <= 0 {
+		return nil, errors.NewBadRequestError("无效的版本号")
+	}
+
+	var rev models.DeviceRevision
+	err := s.db.Collection(models.DeviceRevisionsCollection).FindOne(ctx, bson.M{
+		"deviceId": deviceID,
+		"revision": revision,
+	}).Decode(&rev)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("版本不存在")
+		}
+		return nil, errors.NewInternalServerError("获取版本失败: " + err.Error())
+	}
+	return &rev, nil
+}
+
+// buildRollbackUpdate 生成把当前文档恢复为快照内容的更新
+// 删除状态不随回滚变化
+func buildRollbackUpdate(current, snapshot bson.M) bson.M {
+	set := bson.M{"updatedAt": time.Now()}
+	unset := bson.M{}
+
+	for key, value := range snapshot {
+		if revisionIgnoredFields[key] || key == "deletedAt" {
+			continue
+		}
+		set[key] = value
+	}
+	for key := range current {
+		if revisionIgnoredFields[key] || key == "deletedAt" {
+			continue
+		}
+		if _, ok := snapshot[key]; !ok {
+			unset[key] = ""
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// revisionSnapshot 去掉不需要保存的字段
+func revisionSnapshot(doc bson.M) bson.M {
+	snapshot := make(bson.M, len(doc))
+	for key, value := range doc {
+		if key == "_id" {
+			continue
+		}
+		snapshot[key] = value
+	}
+	return snapshot
+}
+
+// diffDocuments 按字段路径对比两个文档，嵌套文档逐层展开，数组整体比较
+func diffDocuments(before, after bson.M) []models.FieldChange {
+	oldFields := make(map[string]interface{})
+	newFields := make(map[string]interface{})
+	flattenDocument("", before, oldFields)
+	flattenDocument("", after, newFields)
+
+	paths := make([]string, 0, len(oldFields)+len(newFields))
+	for path := range oldFields {
+		paths = append(paths, path)
+	}
+	for path := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]models.FieldChange, 0)
+	for _, path := range paths {
+		oldValue, newValue := oldFields[path], newFields[path]
+		if valuesEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{Path: path, OldValue: oldValue, NewValue: newValue})
+	}
+	return changes
+}
+
+// flattenDocument 展开嵌套文档为 路径 -> 值
+func flattenDocument(prefix string, doc bson.M, out map[string]interface{}) {
+	for key, value := range doc {
+		path := key
+		if prefix == "" {
+			if revisionIgnoredFields[key] {
+				continue
+			}
+		} else {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := asDocument(value); ok && len(nested) > 0 {
+			flattenDocument(path, nested, out)
+			continue
+		}
+		out[path] = value
+	}
+}
+
+func asDocument(value interface{}) (bson.M, bool) {
+	switch v := value.(type) {
+	case bson.M:
+		return v, true
+	case bson.D:
+		return v.Map(), true
+	}
+	return nil, false
+}
+
+// valuesEqual 比较两个字段值，数字类型统一按float64比较
+// 旧数据中的整数可能是int32/double，避免产生无意义的变更
+func valuesEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeValue(a), normalizeValue(b))
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case bson.A:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalizeValue(item)
+		}
+		return items
+	case bson.M, bson.D:
+		doc, _ := asDocument(v)
+		normalized := make(map[string]interface{}, len(doc))
+		for key, item := range doc {
+			normalized[key] = normalizeValue(item)
+		}
+		return normalized
+	}
+	return value
+}
+
+// mapDeviceRevisionToInfo 转换为响应格式
+func mapDeviceRevisionToInfo(rev *models.DeviceRevision) device.DeviceRevisionInfo {
+	info := device.DeviceRevisionInfo{
+		Revision:     rev.Revision,
+		Source:       string(rev.Source),
+		Changes:      rev.Changes,
+		RolledBackTo: rev.RolledBackTo,
+		CreatedAt:    rev.CreatedAt,
+	}
+	if info.Changes == nil {
+		info.Changes = []models.FieldChange{}
+	}
+	if rev.ActorID != nil {
+		info.ActorID = rev.ActorID.Hex()
+	}
+	return info
+}
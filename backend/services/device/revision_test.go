@@ -0,0 +1,99 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+	"project/backend/tests/testutil"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	before := bson.M{
+		"_id":        "ignored",
+		"name":       "Viper V3 Pro",
+		"updatedAt":  1,
+		"dimensions": bson.M{"length": int32(127), "weight": 54.0},
+		"technical":  bson.D{{Key: "connectivity", Value: bson.A{"wired", "wireless"}}},
+	}
+	after := bson.M{
+		"_id":        "ignored",
+		"name":       "Viper V3 Pro",
+		"updatedAt":  2,
+		"dimensions": bson.M{"length": 127.0, "weight": 55.0},
+		"technical":  bson.M{"connectivity": bson.A{"wired"}},
+		"svgData":    bson.M{"topView": "<svg/>"},
+	}
+
+	changes := diffDocuments(before, after)
+	require.Len(t, changes, 3)
+
+	// 按路径排序，数字类型不同但值相同时不算变更
+	assert.Equal(t, "dimensions.weight", changes[0].Path)
+	assert.Equal(t, 54.0, changes[0].OldValue)
+	assert.Equal(t, 55.0, changes[0].NewValue)
+	assert.Equal(t, "svgData.topView", changes[1].Path)
+	assert.Nil(t, changes[1].OldValue)
+	assert.Equal(t, "technical.connectivity", changes[2].Path)
+
+	assert.Empty(t, diffDocuments(after, after))
+}
+
+func TestBuildRollbackUpdate(t *testing.T) {
+	current := bson.M{
+		"_id":       "id",
+		"name":      "new",
+		"svgData":   bson.M{"topView": "<svg/>"},
+		"createdAt": 1,
+		"deletedAt": nil,
+		"rating":    bson.M{"count": 3},
+	}
+	snapshot := bson.M{"name": "old", "createdAt": 0, "deletedAt": 5, "rating": bson.M{"count": 1}}
+
+	update := buildRollbackUpdate(current, snapshot)
+	set := update["$set"].(bson.M)
+	assert.Equal(t, "old", set["name"])
+	assert.Contains(t, set, "updatedAt")
+	assert.NotContains(t, set, "createdAt")
+	assert.NotContains(t, set, "deletedAt")
+	assert.NotContains(t, set, "rating")
+	assert.Equal(t, bson.M{"svgData": ""}, update["$unset"])
+}
+
+func TestUpdateDeviceByScriptRecordsRevision(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := &ServiceImpl{db: db}
+
+	deviceID := primitive.NewObjectID()
+	_, err := db.Collection(models.DevicesCollection).InsertOne(ctx, bson.M{
+		"_id":     deviceID,
+		"name":    "Viper V3 Pro",
+		"svgData": bson.M{"topView": "<svg onload=\"x\"/>"},
+	})
+	require.NoError(t, err)
+
+	update := bson.M{"$set": bson.M{"svgData.topView": "<svg/>"}}
+	rev, err := svc.UpdateDeviceByScript(ctx, deviceID, update)
+	require.NoError(t, err)
+	require.NotNil(t, rev)
+	assert.Equal(t, 2, rev.Revision)
+	assert.Equal(t, models.DeviceRevisionScript, rev.Source)
+	assert.Nil(t, rev.ActorID)
+
+	// 重复执行没有变化，不生成新记录
+	rev, err = svc.UpdateDeviceByScript(ctx, deviceID, update)
+	require.NoError(t, err)
+	assert.Nil(t, rev)
+
+	count, err := db.Collection(models.DeviceRevisionsCollection).CountDocuments(ctx, bson.M{"deviceId": deviceID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
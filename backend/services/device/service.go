@@ -2,7 +2,6 @@ package device
 
 import (
 	"context"
-	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -10,7 +9,6 @@ import (
 	"project/backend/internal/errors"
 	"project/backend/models"
 	"project/backend/types/device"
-	"strings"
 	"time"
 )
 
@@ -23,7 +21,7 @@ type Service interface {
 	
 	// 兼容层新增方法
 	GetMouseDevice(ctx context.Context, deviceID primitive.ObjectID) (*models.MouseDevice, error)
-	UpdateMouseDevice(ctx context.Context, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error)
+	UpdateMouseDevice(ctx context.Context, operatorID, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error)
 
 	DeleteDevice(ctx context.Context, deviceID string) error
 	ListDevices(ctx context.Context, filter device.DeviceListFilter) (*device.DeviceListResponse, error)
@@ -32,6 +30,24 @@ type Service interface {
 	CompareMice(ctx context.Context, ids []string) (*device.ComparisonResponse, error)
 	FindSimilarMice(ctx context.Context, mouseID string, limit int) (*device.SimilarityResponse, error)
 
+	// SVG批量上传相关
+	PreviewSVGUpload(ctx context.Context, uploaderID string, files []device.SVGUploadFile) (*device.SVGUploadPreviewResponse, error)
+	GetSVGUploadBatch(ctx context.Context, batchID string) (*device.SVGUploadPreviewResponse, error)
+	ConfirmSVGUpload(ctx context.Context, operatorID, batchID string, request device.ConfirmSVGUploadRequest) (*device.SVGUploadResultResponse, error)
+
+	// 修改历史相关
+	GetDeviceRevisions(ctx context.Context, deviceID string, page, pageSize int) (*device.DeviceRevisionListResponse, error)
+	DiffDeviceRevisions(ctx context.Context, deviceID string, from, to int) (*device.DeviceRevisionDiffResponse, error)
+	RollbackDevice(ctx context.Context, operatorID, deviceID string, revision int) (*device.DeviceRevisionInfo, error)
+	UpdateDeviceByScript(ctx context.Context, deviceID primitive.ObjectID, update bson.M) (*models.DeviceRevision, error)
+
+	// 回收站相关
+	ListDeletedDevices(ctx context.Context, request device.DeviceTrashListRequest) (*device.DeviceTrashListResponse, error)
+	RestoreDevice(ctx context.Context, deviceID string) error
+	PurgeDevice(ctx context.Context, deviceID string) (*device.DevicePurgeResult, error)
+	PurgeExpiredDevices(ctx context.Context, retention time.Duration) (int, error)
+	SetReviewPurger(purger ReviewPurger)
+
 	
 	// 用户设备相关
 	CreateUserDevice(ctx context.Context, userID string, request device.CreateUserDeviceRequest) (*models.UserDevice, error)
@@ -46,31 +62,12 @@ type Service interface {
 	GetUserDevice(ctx context.Context, userID string, userDeviceID string) (*models.UserDevice, error)
 
 	ListUserDevices(ctx context.Context, request device.UserDeviceListRequest) (*device.UserDeviceListResponse, error)
-	
-	// 评测相关
-	SubmitReview(ctx context.Context, userID string, request device.SubmitReviewRequest) (*models.DeviceReview, error)
-
-	GetReviewsByUserID(ctx context.Context, userID string, page, pageSize int) (*device.DeviceReviewListResponse, error)
-	GetReviewByID(ctx context.Context, reviewID string) (*models.DeviceReview, error)
-	UpdateReview(ctx context.Context, userID, reviewID string, request device.UpdateDeviceReviewRequest) (*models.DeviceReview, error)
-	DeleteReview(ctx context.Context, userID, reviewID string) error
-	ApproveReview(ctx context.Context, reviewerID, reviewID string) (*models.DeviceReview, error)
-	RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.DeviceReview, error)
-	FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.DeviceReview, error)
-	GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*device.DeviceReviewListResponse, error)
-	
-	// 兼容层新增方法
-	CreateDeviceReview(ctx context.Context, userID string, request device.CreateReviewRequest) (*models.DeviceReview, error)
-	GetDeviceReview(ctx context.Context, reviewID string) (*models.DeviceReview, error)
-
-	UpdateDeviceReview(ctx context.Context, userID string, reviewID string, request device.UpdateReviewRequest) (*models.DeviceReview, error)
-	DeleteDeviceReview(ctx context.Context, userID string, reviewID string) error
-	ListDeviceReviews(ctx context.Context, request device.DeviceReviewListRequest) (*device.DeviceReviewListResponse, error)
 }
 
 // 服务实现
 type ServiceImpl struct {
-	db *mongo.Database
+	db      *mongo.Database
+	reviews ReviewPurger
 }
 
 // DefaultService 默认外设服务实现
@@ -92,12 +89,6 @@ func NewDefaultService(db *mongo.Database) *DefaultService {
 	}
 }
 
-// ApproveReview 批准评测
-func (s *DefaultService) ApproveReview(ctx context.Context, reviewerID, reviewID string) (*models.DeviceReview, error) {
-	// 空实现，仅为了满足接口
-	return nil, nil
-}
-
 // CreateMouseDevice 创建鼠标设备
 func (s *DefaultService) CreateMouseDevice(ctx context.Context, request device.CreateMouseRequest) (*models.MouseDevice, error) {
 	// 空实现，仅为了满足接口
@@ -123,7 +114,7 @@ func (s *DefaultService) GetMouseDevice(ctx context.Context, deviceID primitive.
 }
 
 // UpdateMouseDevice 更新鼠标设备
-func (s *DefaultService) UpdateMouseDevice(ctx context.Context, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error) {
+func (s *DefaultService) UpdateMouseDevice(ctx context.Context, operatorID, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
@@ -157,136 +148,121 @@ func (s *DefaultService) FindSimilarMice(ctx context.Context, mouseID string, li
 	return nil, nil
 }
 
-// UpdateUserDevice 更新用户设备配置
-func (s *DefaultService) UpdateUserDevice(ctx context.Context, userID string, userDeviceID string, request device.UpdateUserDeviceRequest) (*models.UserDevice, error) {
+// PreviewSVGUpload 预览SVG批量上传
+func (s *DefaultService) PreviewSVGUpload(ctx context.Context, uploaderID string, files []device.SVGUploadFile) (*device.SVGUploadPreviewResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// DeleteUserDevice 已在前面定义
-
-// GetDeviceReview 获取评测
-func (s *DefaultService) GetDeviceReview(ctx context.Context, reviewID string) (*models.DeviceReview, error) {
+// GetSVGUploadBatch 获取SVG上传批次
+func (s *DefaultService) GetSVGUploadBatch(ctx context.Context, batchID string) (*device.SVGUploadPreviewResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// CreateUserDevice 创建用户设备
-func (s *DefaultService) CreateUserDevice(ctx context.Context, userID string, request device.CreateUserDeviceRequest) (*models.UserDevice, error) {
+// ConfirmSVGUpload 确认SVG批量上传
+func (s *DefaultService) ConfirmSVGUpload(ctx context.Context, operatorID, batchID string, request device.ConfirmSVGUploadRequest) (*device.SVGUploadResultResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// UpdateUserDevice 已在前面定义
-
-// GetUserDeviceByID 根据ID获取用户设备
-func (s *DefaultService) GetUserDeviceByID(ctx context.Context, userID, userDeviceID string) (*models.UserDevice, error) {
+// GetDeviceRevisions 获取设备修改历史
+func (s *DefaultService) GetDeviceRevisions(ctx context.Context, deviceID string, page, pageSize int) (*device.DeviceRevisionListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetUserDevices 获取用户设备列表
-func (s *DefaultService) GetUserDevices(ctx context.Context, userID string, page, pageSize int) (*device.UserDeviceListResponse, error) {
+// DiffDeviceRevisions 对比设备的两个版本
+func (s *DefaultService) DiffDeviceRevisions(ctx context.Context, deviceID string, from, to int) (*device.DeviceRevisionDiffResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetPublicUserDevices 获取公开用户设备
-func (s *DefaultService) GetPublicUserDevices(ctx context.Context, page, pageSize int) (*device.UserDeviceListResponse, error) {
+// RollbackDevice 回滚设备到指定版本
+func (s *DefaultService) RollbackDevice(ctx context.Context, operatorID, deviceID string, revision int) (*device.DeviceRevisionInfo, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetUserDevice 获取用户设备
-func (s *DefaultService) GetUserDevice(ctx context.Context, userID string, userDeviceID string) (*models.UserDevice, error) {
+// ListDeletedDevices 获取回收站中的设备
+func (s *DefaultService) ListDeletedDevices(ctx context.Context, request device.DeviceTrashListRequest) (*device.DeviceTrashListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// DeleteUserDevice 已在前面定义
-
-// ListUserDevices 列出用户设备
-func (s *DefaultService) ListUserDevices(ctx context.Context, request device.UserDeviceListRequest) (*device.UserDeviceListResponse, error) {
+// RestoreDevice 从回收站恢复设备
+func (s *DefaultService) RestoreDevice(ctx context.Context, deviceID string) error {
 	// 空实现，仅为了满足接口
-	return nil, nil
+	return nil
 }
 
-// SubmitReview 提交评测
-func (s *DefaultService) SubmitReview(ctx context.Context, userID string, request device.SubmitReviewRequest) (*models.DeviceReview, error) {
+// PurgeDevice 彻底删除设备
+func (s *DefaultService) PurgeDevice(ctx context.Context, deviceID string) (*device.DevicePurgeResult, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetReviewsByDeviceID 获取设备评测
-func (s *DefaultService) GetReviewsByDeviceID(ctx context.Context, deviceID string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
+// PurgeExpiredDevices 清理超过保留期的设备
+func (s *DefaultService) PurgeExpiredDevices(ctx context.Context, retention time.Duration) (int, error) {
 	// 空实现，仅为了满足接口
-	return nil, nil
+	return 0, nil
 }
 
-// GetReviewsByUserID 获取用户评测
-func (s *DefaultService) GetReviewsByUserID(ctx context.Context, userID string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
+// UpdateDeviceByScript 维护脚本修改设备
+func (s *DefaultService) UpdateDeviceByScript(ctx context.Context, deviceID primitive.ObjectID, update bson.M) (*models.DeviceRevision, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetReviewByID 获取评测
-func (s *DefaultService) GetReviewByID(ctx context.Context, reviewID string) (*models.DeviceReview, error) {
+// SetReviewPurger 设置评测清理服务
+func (s *DefaultService) SetReviewPurger(purger ReviewPurger) {
 	// 空实现，仅为了满足接口
-	return nil, nil
 }
 
-// UpdateReview 更新评测
-func (s *DefaultService) UpdateReview(ctx context.Context, userID, reviewID string, request device.UpdateDeviceReviewRequest) (*models.DeviceReview, error) {
+// UpdateUserDevice 更新用户设备配置
+func (s *DefaultService) UpdateUserDevice(ctx context.Context, userID string, userDeviceID string, request device.UpdateUserDeviceRequest) (*models.UserDevice, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// DeleteReview 删除评测
-func (s *DefaultService) DeleteReview(ctx context.Context, userID, reviewID string) error {
-	// 空实现，仅为了满足接口
-	return nil
-}
+// DeleteUserDevice 已在前面定义
 
-// RejectReview 拒绝评测
-func (s *DefaultService) RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.DeviceReview, error) {
+// CreateUserDevice 创建用户设备
+func (s *DefaultService) CreateUserDevice(ctx context.Context, userID string, request device.CreateUserDeviceRequest) (*models.UserDevice, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// FeaturedReview 推荐评测
-func (s *DefaultService) FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.DeviceReview, error) {
+// UpdateUserDevice 已在前面定义
+
+// GetUserDeviceByID 根据ID获取用户设备
+func (s *DefaultService) GetUserDeviceByID(ctx context.Context, userID, userDeviceID string) (*models.UserDevice, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetPendingReviews 获取待审核评测
-func (s *DefaultService) GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
+// GetUserDevices 获取用户设备列表
+func (s *DefaultService) GetUserDevices(ctx context.Context, userID string, page, pageSize int) (*device.UserDeviceListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// CreateDeviceReview 创建设备评测
-func (s *DefaultService) CreateDeviceReview(ctx context.Context, userID string, request device.CreateReviewRequest) (*models.DeviceReview, error) {
+// GetPublicUserDevices 获取公开用户设备
+func (s *DefaultService) GetPublicUserDevices(ctx context.Context, page, pageSize int) (*device.UserDeviceListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// GetDeviceReview 已在前面定义
-
-// UpdateDeviceReview 更新设备评测
-func (s *DefaultService) UpdateDeviceReview(ctx context.Context, userID string, reviewID string, request device.UpdateReviewRequest) (*models.DeviceReview, error) {
+// GetUserDevice 获取用户设备
+func (s *DefaultService) GetUserDevice(ctx context.Context, userID string, userDeviceID string) (*models.UserDevice, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
 
-// DeleteDeviceReview 删除设备评测
-func (s *DefaultService) DeleteDeviceReview(ctx context.Context, userID string, reviewID string) error {
-	// 空实现，仅为了满足接口
-	return nil
-}
+// DeleteUserDevice 已在前面定义
 
-// ListDeviceReviews 列出设备评测
-func (s *DefaultService) ListDeviceReviews(ctx context.Context, request device.DeviceReviewListRequest) (*device.DeviceReviewListResponse, error) {
+// ListUserDevices 列出用户设备
+func (s *DefaultService) ListUserDevices(ctx context.Context, request device.UserDeviceListRequest) (*device.UserDeviceListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
@@ -329,7 +305,7 @@ func (s *ServiceImpl) GetDeviceByID(ctx context.Context, deviceID string) (*mode
 	}
 	
 	var deviceDoc models.HardwareDevice
-	err = s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&deviceDoc)
+	err = s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": id, "deletedAt": nil}).Decode(&deviceDoc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.NewNotFoundError("设备不存在")
@@ -340,138 +316,6 @@ func (s *ServiceImpl) GetDeviceByID(ctx context.Context, deviceID string) (*mode
 	return &deviceDoc, nil
 }
 
-// GetDeviceList 获取设备列表
-func (s *ServiceImpl) GetDeviceList(ctx context.Context, deviceType string, page, pageSize int) (*device.DeviceListResponse, error) {
-	// 创建一个空的默认响应，以防查询失败
-	defaultResponse := &device.DeviceListResponse{
-		Devices:  []device.DevicePreview{},
-		Total:    0,
-		Page:     page,
-		PageSize: pageSize,
-	}
-	
-	fmt.Printf("DEBUG: GetDeviceList called with type=%s, page=%d, pageSize=%d\n", deviceType, page, pageSize)
-	
-	filter := bson.M{}
-	
-	if deviceType != "" {
-		// 将类型转为小写，确保匹配不区分大小写
-		filter["type"] = strings.ToLower(deviceType)
-		fmt.Printf("DEBUG: Query filter set to %+v\n", filter)
-	}
-	
-	// 设置分页
-	if page <= 0 {
-		page = 1
-	}
-	
-	if pageSize <= 0 || pageSize > 100 {
-		pageSize = 20
-	}
-	
-	skip := (page - 1) * pageSize
-	skipInt64 := int64(skip)
-	limitInt64 := int64(pageSize)
-	
-	// 首先尝试在devices集合中查询
-	totalInDevices, totalErr := s.db.Collection(models.DevicesCollection).CountDocuments(ctx, filter)
-	if totalErr != nil {
-		return defaultResponse, nil
-	}
-	
-	// 如果devices集合中没有数据，则尝试查询原始的devices集合
-	if totalInDevices == 0 {
-		mouseTotal, mouseErr := s.db.Collection("devices").CountDocuments(ctx, filter)
-		if mouseErr != nil || mouseTotal == 0 {
-			// 如果还是找不到，则返回空响应而不是错误
-			return defaultResponse, nil
-		}
-		
-		// 在原始的devices集合中查询
-		mouseCursor, mouseErr := s.db.Collection("devices").Find(ctx, filter, &options.FindOptions{
-			Skip:  &skipInt64,
-			Limit: &limitInt64,
-			Sort:  bson.D{{Key: "createdAt", Value: -1}},
-		})
-		
-		if mouseErr != nil {
-			return defaultResponse, nil
-		}
-		defer mouseCursor.Close(ctx)
-		
-		// 直接解析为MouseDevice类型
-		var mouseDevices []models.MouseDevice
-		if err := mouseCursor.All(ctx, &mouseDevices); err != nil {
-			return defaultResponse, nil
-		}
-		
-		// 检查是否找到了设备
-		if len(mouseDevices) == 0 {
-			return defaultResponse, nil
-		}
-		
-		// 转换为响应格式
-		deviceList := make([]device.DevicePreview, len(mouseDevices))
-		for i, d := range mouseDevices {
-			deviceList[i] = device.DevicePreview{
-				ID:          d.ID.Hex(),
-				Name:        d.Name,
-				Brand:       d.Brand,
-				Type:        string(d.Type),
-				ImageURL:    d.ImageURL,
-				Description: d.Description,
-				CreatedAt:   d.CreatedAt,
-			}
-		}
-		
-		return &device.DeviceListResponse{
-			Devices:  deviceList,
-			Total:    int(mouseTotal),
-			Page:     page,
-			PageSize: pageSize,
-		}, nil
-	}
-	
-	// 原来的查询逻辑
-	cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx, filter, &options.FindOptions{
-		Skip:  &skipInt64,
-		Limit: &limitInt64,
-		Sort:  bson.D{{Key: "createdAt", Value: -1}},
-	})
-	if err != nil {
-		return defaultResponse, nil // 返回空响应而不是错误
-	}
-	defer cursor.Close(ctx)
-	
-	// 解析基本设备信息
-	var devices []models.HardwareDevice
-	if err := cursor.All(ctx, &devices); err != nil {
-		return defaultResponse, nil // 返回空响应而不是错误
-	}
-	
-	// 转换为响应格式
-	var response device.DeviceListResponse
-	
-	response.Total = int(totalInDevices)
-	response.Page = page
-	response.PageSize = pageSize
-	response.Devices = make([]device.DevicePreview, len(devices))
-	
-	for i, d := range devices {
-		response.Devices[i] = device.DevicePreview{
-			ID:          d.ID.Hex(),
-			Name:        d.Name,
-			Brand:       d.Brand,
-			Type:        string(d.Type),
-			ImageURL:    d.ImageURL,
-			Description: d.Description,
-			CreatedAt:   d.CreatedAt,
-		}
-	}
-	
-	return &response, nil
-}
-
 // CreateUserDevice 创建用户设备配置
 func (s *ServiceImpl) CreateUserDevice(ctx context.Context, userID string, request device.CreateUserDeviceRequest) (*models.UserDevice, error) {
 	// 将用户ID转换为ObjectID
@@ -781,7 +625,7 @@ func (s *ServiceImpl) GetPublicUserDevices(ctx context.Context, page, pageSize i
 func (s *ServiceImpl) GetMouseDevice(ctx context.Context, deviceID primitive.ObjectID) (*models.MouseDevice, error) {
 	// 查询设备
 	var mouseDevice models.MouseDevice
-	err := s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": deviceID}).Decode(&mouseDevice)
+	err := s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": deviceID, "deletedAt": nil}).Decode(&mouseDevice)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.NewNotFoundError("未找到鼠标设备")
@@ -792,46 +636,61 @@ func (s *ServiceImpl) GetMouseDevice(ctx context.Context, deviceID primitive.Obj
 	return &mouseDevice, nil
 }
 
-// UpdateMouseDevice 更新鼠标设备
-func (s *ServiceImpl) UpdateMouseDevice(ctx context.Context, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-// DeleteDevice 删除设备
-func (s *ServiceImpl) DeleteDevice(ctx context.Context, deviceID string) error {
-	// 实现待完成
-	return errors.NewInternalServerError("功能待实现")
-}
-
-// ListDevices 列出设备
-func (s *ServiceImpl) ListDevices(ctx context.Context, filter device.DeviceListFilter) (*device.DeviceListResponse, error) {
-	return s.GetDeviceList(ctx, filter.Type, filter.Page, filter.PageSize)
-}
-
-// CreateDeviceReview 创建设备评测
-func (s *ServiceImpl) CreateDeviceReview(ctx context.Context, userID string, request device.CreateReviewRequest) (*models.DeviceReview, error) {
-	return s.SubmitReview(ctx, userID, device.SubmitReviewRequest(request))
-}
-
-// GetDeviceReview 获取设备评测
-func (s *ServiceImpl) GetDeviceReview(ctx context.Context, reviewID string) (*models.DeviceReview, error) {
-	return s.GetReviewByID(ctx, reviewID)
-}
+// UpdateMouseDevice 更新鼠标设备，并记录修改历史
+func (s *ServiceImpl) UpdateMouseDevice(ctx context.Context, operatorID, deviceID string, request device.UpdateMouseRequest) (*models.MouseDevice, error) {
+	actorID, err := primitive.ObjectIDFromHex(operatorID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
 
-// UpdateDeviceReview 更新设备评测
-func (s *ServiceImpl) UpdateDeviceReview(ctx context.Context, userID string, reviewID string, request device.UpdateReviewRequest) (*models.DeviceReview, error) {
-	return s.UpdateReview(ctx, userID, reviewID, device.UpdateDeviceReviewRequest(request))
-}
+	// 只更新请求中提供的字段
+	update := bson.M{"updatedAt": time.Now()}
+	if request.Name != nil {
+		update["name"] = *request.Name
+	}
+	if request.Brand != nil {
+		update["brand"] = *request.Brand
+	}
+	if request.ImageURL != nil {
+		update["imageUrl"] = *request.ImageURL
+	}
+	if request.Description != nil {
+		update["description"] = *request.Description
+	}
+	if request.Dimensions != nil {
+		update["dimensions"] = *request.Dimensions
+	}
+	if request.Shape != nil {
+		update["shape"] = *request.Shape
+	}
+	if request.Technical != nil {
+		update["technical"] = *request.Technical
+	}
+	if request.Recommended != nil {
+		update["recommended"] = *request.Recommended
+	}
 
-// DeleteDeviceReview 删除设备评测
-func (s *ServiceImpl) DeleteDeviceReview(ctx context.Context, userID string, reviewID string) error {
-	return s.DeleteReview(ctx, userID, reviewID)
-}
+	filter := bson.M{"_id": id, "type": models.DeviceTypeMouse, "deletedAt": nil}
+	after, _, err := s.updateDeviceWithRevision(ctx, filter, bson.M{"$set": update}, revisionMeta{
+		Source:  models.DeviceRevisionAdminEdit,
+		ActorID: &actorID,
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("未找到鼠标设备")
+		}
+		return nil, errors.NewInternalServerError("更新鼠标设备失败: " + err.Error())
+	}
 
-// ListDeviceReviews 列出设备评测
-func (s *ServiceImpl) ListDeviceReviews(ctx context.Context, request device.DeviceReviewListRequest) (*device.DeviceReviewListResponse, error) {
-	return s.GetReviewsByDeviceID(ctx, request.DeviceID, request.Page, request.PageSize)
+	var mouseDevice models.MouseDevice
+	if err := bson.Unmarshal(after, &mouseDevice); err != nil {
+		return nil, errors.NewInternalServerError("解析鼠标设备失败: " + err.Error())
+	}
+	return &mouseDevice, nil
 }
 
 // GetUserDevice 获取用户设备
@@ -876,54 +735,3 @@ func (s *ServiceImpl) ListUserDevices(ctx context.Context, request device.UserDe
 	}
 	return s.GetUserDevices(ctx, request.UserID, request.Page, request.PageSize)
 }
-
-// 以下是评测相关方法（在此省略，有需要时再实现）
-func (s *ServiceImpl) SubmitReview(ctx context.Context, userID string, request device.SubmitReviewRequest) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) GetReviewsByDeviceID(ctx context.Context, deviceID string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) GetReviewsByUserID(ctx context.Context, userID string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) GetReviewByID(ctx context.Context, reviewID string) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) UpdateReview(ctx context.Context, userID, reviewID string, request device.UpdateDeviceReviewRequest) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) DeleteReview(ctx context.Context, userID, reviewID string) error {
-	// 实现待完成
-	return errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) ApproveReview(ctx context.Context, reviewerID, reviewID string) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.DeviceReview, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
-
-func (s *ServiceImpl) GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*device.DeviceReviewListResponse, error) {
-	// 实现待完成
-	return nil, errors.NewInternalServerError("功能待实现")
-}
\ No newline at end of file
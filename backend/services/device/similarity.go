@@ -18,8 +18,8 @@ func (s *ServiceImpl) CompareMice(ctx context.Context, ids []string) (*device.Co
 	if len(ids) < 2 {
 		return nil, errors.NewBadRequestError("至少需要两个鼠标才能进行比较")
 	}
-	if len(ids) > 3 {
-		return nil, errors.NewBadRequestError("最多只能比较三个鼠标")
+	if len(ids) > models.MaxComparisonDevices {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("最多只能比较%d个鼠标", models.MaxComparisonDevices))
 	}
 
 	// 先获取鼠标设备
@@ -104,7 +104,7 @@ func (s *ServiceImpl) FindSimilarMice(ctx context.Context, id string, limit int)
 	}
 
 	// 直接查询所有鼠标设备，避免 ListDevices 的分页限制和 N+1 查询
-	cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx, bson.M{"type": string(models.DeviceTypeMouse)})
+	cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx, bson.M{"type": string(models.DeviceTypeMouse), "deletedAt": nil})
 	if err != nil {
 		return nil, errors.NewInternalServerError("查询鼠标设备失败: " + err.Error())
 	}
@@ -322,6 +322,35 @@ func handleShapeDiff(mice []*models.MouseDevice, differences map[string]device.P
 		Values:            handCompValues,
 		DifferencePercent: handCompDiff,
 	}
+
+	thumbRestValues := make([]any, len(mice))
+	ringFingerRestValues := make([]any, len(mice))
+	materialValues := make([]any, len(mice))
+
+	for i, mouse := range mice {
+		thumbRestValues[i] = mouse.Shape.ThumbRest
+		ringFingerRestValues[i] = mouse.Shape.RingFingerRest
+		materialValues[i] = mouse.Shape.Material
+	}
+
+	differences["thumb_rest"] = device.PropertyDiff{
+		Property:          "拇指托",
+		Values:            thumbRestValues,
+		DifferencePercent: calculateCategoryDiff(thumbRestValues),
+	}
+	differences["ring_finger_rest"] = device.PropertyDiff{
+		Property:          "无名指托",
+		Values:            ringFingerRestValues,
+		DifferencePercent: calculateCategoryDiff(ringFingerRestValues),
+	}
+	// 材质在旧数据中可能缺失，全部已知时才参与比较
+	if allKnown(materialValues) {
+		differences["material"] = device.PropertyDiff{
+			Property:          "外壳材质",
+			Values:            materialValues,
+			DifferencePercent: calculateCategoryDiff(materialValues),
+		}
+	}
 }
 
 func handleTechnicalDiff(mice []*models.MouseDevice, differences map[string]device.PropertyDiff) {
@@ -357,6 +386,75 @@ func handleTechnicalDiff(mice []*models.MouseDevice, differences map[string]devi
 		Values:            sideButtonsValues,
 		DifferencePercent: sideButtonsDiff,
 	}
+
+	middleButtonsValues := make([]any, len(mice))
+	sensorTechValues := make([]any, len(mice))
+	sensorPositionValues := make([]any, len(mice))
+	trackingSpeedValues := make([]any, len(mice))
+	accelerationValues := make([]any, len(mice))
+
+	for i, mouse := range mice {
+		middleButtonsValues[i] = mouse.Technical.MiddleButtons
+		sensorTechValues[i] = mouse.Technical.SensorTechnology
+		sensorPositionValues[i] = mouse.Technical.SensorPosition
+		trackingSpeedValues[i] = mouse.Technical.TrackingSpeed
+		accelerationValues[i] = mouse.Technical.Acceleration
+	}
+
+	differences["middle_buttons"] = device.PropertyDiff{
+		Property:          "中键数量",
+		Values:            middleButtonsValues,
+		DifferencePercent: calculateNumericDiff(middleButtonsValues),
+	}
+
+	// 以下参数在CSV中可能为未知("-")，全部已知时才参与比较
+	if allKnown(sensorTechValues) {
+		differences["sensor_technology"] = device.PropertyDiff{
+			Property:          "传感器技术",
+			Values:            sensorTechValues,
+			DifferencePercent: calculateCategoryDiff(sensorTechValues),
+		}
+	}
+	if allKnown(sensorPositionValues) {
+		differences["sensor_position"] = device.PropertyDiff{
+			Property:          "传感器位置",
+			Values:            sensorPositionValues,
+			DifferencePercent: calculateCategoryDiff(sensorPositionValues),
+		}
+	}
+	if allKnown(trackingSpeedValues) {
+		differences["tracking_speed"] = device.PropertyDiff{
+			Property:          "追踪速度 (IPS)",
+			Values:            trackingSpeedValues,
+			DifferencePercent: calculateNumericDiff(trackingSpeedValues),
+		}
+	}
+	if allKnown(accelerationValues) {
+		differences["acceleration"] = device.PropertyDiff{
+			Property:          "加速度 (G)",
+			Values:            accelerationValues,
+			DifferencePercent: calculateNumericDiff(accelerationValues),
+		}
+	}
+}
+
+// allKnown 检查所有值是否已知（非零值、非空字符串）
+func allKnown(values []any) bool {
+	for _, val := range values {
+		switch v := val.(type) {
+		case string:
+			if v == "" {
+				return false
+			}
+		case int:
+			if v == 0 {
+				return false
+			}
+		case nil:
+			return false
+		}
+	}
+	return true
 }
 
 // 单独比较两只鼠标
@@ -441,23 +539,31 @@ func calculateSimilarityScore(differences map[string]device.PropertyDiff) float6
 
 	// 定义权重
 	weights := map[string]float64{
-		// 尺寸参数 (总权重: 0.5)
-		"length": 0.15,
-		"width":  0.15,
-		"height": 0.10,
-		"weight": 0.10,
+		// 尺寸参数 (总权重: 0.45)
+		"length": 0.14,
+		"width":  0.13,
+		"height": 0.09,
+		"weight": 0.09,
 
 		// 形状参数 (总权重: 0.35)
-		"shape_type":         0.07,
-		"hump_placement":     0.07,
-		"front_flare":        0.07,
-		"side_curvature":     0.07,
-		"hand_compatibility": 0.07,
-
-		// 技术参数 (总权重: 0.15)
-		"max_dpi":      0.05,
-		"polling_rate": 0.05,
-		"side_buttons": 0.05,
+		"shape_type":         0.05,
+		"hump_placement":     0.05,
+		"front_flare":        0.05,
+		"side_curvature":     0.05,
+		"hand_compatibility": 0.05,
+		"thumb_rest":         0.04,
+		"ring_finger_rest":   0.03,
+		"material":           0.03,
+
+		// 技术参数 (总权重: 0.2)
+		"max_dpi":           0.03,
+		"polling_rate":      0.03,
+		"side_buttons":      0.03,
+		"middle_buttons":    0.02,
+		"sensor_technology": 0.03,
+		"sensor_position":   0.02,
+		"tracking_speed":    0.02,
+		"acceleration":      0.02,
 	}
 
 	// 计算加权平均差异
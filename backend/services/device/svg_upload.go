@@ -0,0 +1,615 @@
+package device
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/internal/svg"
+	"project/backend/models"
+	"project/backend/types/device"
+)
+
+const (
+	// svgUploadBatchTTL 上传批次等待确认的有效期
+	svgUploadBatchTTL = 24 * time.Hour
+
+	// 文件名与鼠标名称的相似度阈值
+	svgAutoMatchScore     = 0.85 // 高于该值且明显领先第二名时自动匹配
+	svgAutoMatchMargin    = 0.05
+	svgCandidateScore     = 0.5 // 低于该值不作为候选
+	svgMaxCandidatesShown = 3
+)
+
+// svgMatchTarget 参与匹配的鼠标
+type svgMatchTarget struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Name  string             `bson:"name"`
+	Brand string             `bson:"brand"`
+}
+
+// PreviewSVGUpload 校验、清洗上传的SVG并与已有鼠标进行模糊匹配
+// 结果保存为待确认批次，不会修改任何设备
+func (s *ServiceImpl) PreviewSVGUpload(ctx context.Context, uploaderID string, files []device.SVGUploadFile) (*device.SVGUploadPreviewResponse, error) {
+	uploaderObjID, err := primitive.ObjectIDFromHex(uploaderID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	if len(files) == 0 {
+		return nil, errors.NewBadRequestError("没有可处理的SVG文件")
+	}
+
+	targets, err := s.loadSVGMatchTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.SVGUploadItem, 0, len(files))
+	for _, file := range files {
+		items = append(items, buildSVGUploadItem(file, targets))
+	}
+	resolveSVGMatchConflicts(items)
+
+	now := time.Now()
+	batch := models.SVGUploadBatch{
+		ID:         primitive.NewObjectID(),
+		UploadedBy: uploaderObjID,
+		Status:     models.SVGUploadStatusPending,
+		ItemCount:  len(items),
+		Items:      items,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(svgUploadBatchTTL),
+	}
+
+	// 每个文件单独保存，批次最后写入；中途失败时已写入的文件由TTL索引清理
+	docs := make([]interface{}, len(items))
+	for i := range items {
+		items[i].ID = primitive.NewObjectID()
+		items[i].BatchID = batch.ID
+		items[i].Index = i
+		items[i].ExpiresAt = batch.ExpiresAt
+		docs[i] = items[i]
+	}
+	if _, err := s.db.Collection(models.SVGUploadItemsCollection).InsertMany(ctx, docs); err != nil {
+		return nil, errors.NewInternalServerError("保存上传文件失败: " + err.Error())
+	}
+	if _, err := s.db.Collection(models.SVGUploadBatchesCollection).InsertOne(ctx, batch); err != nil {
+		return nil, errors.NewInternalServerError("保存上传批次失败: " + err.Error())
+	}
+
+	return mapSVGUploadBatchToResponse(&batch), nil
+}
+
+// GetSVGUploadBatch 获取上传批次的匹配结果
+func (s *ServiceImpl) GetSVGUploadBatch(ctx context.Context, batchID string) (*device.SVGUploadPreviewResponse, error) {
+	batch, err := s.findSVGUploadBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return mapSVGUploadBatchToResponse(batch), nil
+}
+
+// ConfirmSVGUpload 确认上传批次，将SVG写入匹配的鼠标
+// 只会更新已存在的鼠标，未匹配的文件会被跳过
+// 每个文件的处理结果单独记录，写入鼠标和标记已写入在同一个事务中完成；
+// 有文件因读写失败未写入时批次保持待确认，再次确认会跳过已写入的文件继续处理
+func (s *ServiceImpl) ConfirmSVGUpload(ctx context.Context, operatorID, batchID string, request device.ConfirmSVGUploadRequest) (*device.SVGUploadResultResponse, error) {
+	actorID, err := primitive.ObjectIDFromHex(operatorID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+
+	batch, err := s.findSVGUploadBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != models.SVGUploadStatusPending {
+		return nil, errors.NewBadRequestError("该上传批次已确认")
+	}
+
+	decisions, err := buildSVGConfirmDecisions(batch, request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &device.SVGUploadResultResponse{
+		BatchID: batch.ID.Hex(),
+		Status:  string(models.SVGUploadStatusPending),
+		Updated: []device.SVGUploadResultItem{},
+		Skipped: []device.SVGUploadResultItem{},
+	}
+
+	failed := false
+	for _, d := range decisions {
+		item := batch.Items[d.index]
+		resultItem := device.SVGUploadResultItem{
+			Index:    d.index,
+			FileName: item.FileName,
+			View:     d.view,
+		}
+
+		// 之前确认时已写入，保留当时的结果
+		if item.Result == models.SVGUploadResultUpdated {
+			if item.AppliedDeviceID != nil {
+				resultItem.DeviceID = item.AppliedDeviceID.Hex()
+			}
+			resultItem.View = item.AppliedView
+			resultItem.Reason = item.ResultReason
+			result.Updated = append(result.Updated, resultItem)
+			continue
+		}
+
+		if d.skipReason != "" {
+			resultItem.Reason = d.skipReason
+			result.Skipped = append(result.Skipped, resultItem)
+			s.recordSVGUploadSkipped(ctx, item.ID, resultItem.Reason)
+			continue
+		}
+
+		resultItem.DeviceID = d.deviceID.Hex()
+		filter := bson.M{"_id": d.deviceID, "type": models.DeviceTypeMouse, "deletedAt": nil}
+
+		var target struct {
+			Dimensions models.MouseDimensions `bson:"dimensions"`
+		}
+		err := s.db.Collection(models.DevicesCollection).FindOne(ctx, filter,
+			options.FindOne().SetProjection(bson.M{"dimensions.length": 1}),
+		).Decode(&target)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				resultItem.Reason = "鼠标不存在"
+			} else {
+				resultItem.Reason = "获取鼠标失败: " + err.Error()
+				failed = true
+			}
+			result.Skipped = append(result.Skipped, resultItem)
+			s.recordSVGUploadSkipped(ctx, item.ID, resultItem.Reason)
+			continue
+		}
+
+		content, err := s.loadSVGUploadContent(ctx, item.ID)
+		if err != nil {
+			resultItem.Reason = "获取上传文件失败: " + err.Error()
+			failed = true
+			result.Skipped = append(result.Skipped, resultItem)
+			continue
+		}
+
+		now := time.Now()
+		fields := bson.M{
+			"svgData." + d.view + "View": content,
+			"updatedAt":                  now,
+		}
+		update := bson.M{"$set": fields}
+		canonical, err := canonicalSVG(content, target.Dimensions.Length)
+		if err != nil {
+			// 原始SVG照常写入，规范版本可在补齐尺寸后通过脚本生成
+			// 旧的规范版本来自被替换的SVG，一并删除
+			resultItem.Reason = "未生成规范版本: " + err.Error()
+			update["$unset"] = bson.M{"svgData." + d.view + "ViewCanonical": ""}
+		} else {
+			fields["svgData."+d.view+"ViewCanonical"] = canonical
+		}
+
+		err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+			// 并发确认同一批次时，只有先标记的一方写入鼠标
+			marked, err := s.db.Collection(models.SVGUploadItemsCollection).UpdateOne(sc,
+				bson.M{"_id": item.ID, "result": bson.M{"$ne": models.SVGUploadResultUpdated}},
+				bson.M{"$set": bson.M{
+					"result":          models.SVGUploadResultUpdated,
+					"resultReason":    resultItem.Reason,
+					"appliedDeviceId": d.deviceID,
+					"appliedView":     d.view,
+					"processedAt":     now,
+				}},
+			)
+			if err != nil {
+				return err
+			}
+			if marked.MatchedCount == 0 {
+				return nil
+			}
+			_, _, err = s.updateDeviceInTransaction(sc, filter, update, revisionMeta{
+				Source:  models.DeviceRevisionSVGImport,
+				ActorID: &actorID,
+			})
+			return err
+		})
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				resultItem.Reason = "鼠标不存在"
+			} else {
+				resultItem.Reason = "写入失败: " + err.Error()
+				failed = true
+			}
+			result.Skipped = append(result.Skipped, resultItem)
+			s.recordSVGUploadSkipped(ctx, item.ID, resultItem.Reason)
+			continue
+		}
+		result.Updated = append(result.Updated, resultItem)
+	}
+
+	// 全部处理完才标记为已确认，否则保留待确认状态以便重试
+	if !failed {
+		now := time.Now()
+		_, err := s.db.Collection(models.SVGUploadBatchesCollection).UpdateOne(ctx,
+			bson.M{"_id": batch.ID, "status": models.SVGUploadStatusPending},
+			bson.M{"$set": bson.M{"status": models.SVGUploadStatusConfirmed, "confirmedAt": now}},
+		)
+		if err != nil {
+			return nil, errors.NewInternalServerError("更新上传批次失败: " + err.Error())
+		}
+		result.Status = string(models.SVGUploadStatusConfirmed)
+	}
+
+	return result, nil
+}
+
+// recordSVGUploadSkipped 记录未写入的文件及原因，已写入的文件不会被覆盖
+func (s *ServiceImpl) recordSVGUploadSkipped(ctx context.Context, itemID primitive.ObjectID, reason string) {
+	_, err := s.db.Collection(models.SVGUploadItemsCollection).UpdateOne(ctx,
+		bson.M{"_id": itemID, "result": bson.M{"$ne": models.SVGUploadResultUpdated}},
+		bson.M{"$set": bson.M{
+			"result":       models.SVGUploadResultSkipped,
+			"resultReason": reason,
+			"processedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		log.Printf("记录上传文件%s的处理结果失败: %v", itemID.Hex(), err)
+	}
+}
+
+// canonicalSVG 根据鼠标长度生成毫米坐标系下的规范轮廓
+func canonicalSVG(content string, lengthMM float64) (string, error) {
+	outline, err := svg.ParseOutline([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	return outline.Canonical(lengthMM)
+}
+
+// svgConfirmDecision 单个文件的确认结果
+type svgConfirmDecision struct {
+	index      int
+	deviceID   primitive.ObjectID
+	view       string
+	skipReason string
+}
+
+// buildSVGConfirmDecisions 根据确认请求决定每个文件写入哪只鼠标
+func buildSVGConfirmDecisions(batch *models.SVGUploadBatch, request device.ConfirmSVGUploadRequest) ([]svgConfirmDecision, error) {
+	var decisions []svgConfirmDecision
+
+	// 未指定时只接受自动匹配的结果
+	if len(request.Items) == 0 {
+		for i, item := range batch.Items {
+			d := svgConfirmDecision{index: i, view: item.View}
+			if item.Status == models.SVGMatchMatched && item.MatchedDeviceID != nil {
+				d.deviceID = *item.MatchedDeviceID
+			} else {
+				d.skipReason = "未自动匹配，需要手动选择鼠标"
+			}
+			decisions = append(decisions, d)
+		}
+		return decisions, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, confirmItem := range request.Items {
+		if confirmItem.Index < 0 || confirmItem.Index >= len(batch.Items) {
+			return nil, errors.NewBadRequestError("无效的文件序号")
+		}
+		if seen[confirmItem.Index] {
+			return nil, errors.NewBadRequestError("文件序号重复")
+		}
+		seen[confirmItem.Index] = true
+
+		item := batch.Items[confirmItem.Index]
+		d := svgConfirmDecision{index: confirmItem.Index, view: item.View}
+		if confirmItem.View != "" {
+			d.view = confirmItem.View
+		}
+
+		switch {
+		case confirmItem.Skip:
+			d.skipReason = "已跳过"
+		case item.Status == models.SVGMatchInvalid:
+			d.skipReason = "文件校验失败: " + item.Error
+		case d.view != "top" && d.view != "side":
+			d.skipReason = "未指定视图"
+		case confirmItem.DeviceID != "":
+			id, err := primitive.ObjectIDFromHex(confirmItem.DeviceID)
+			if err != nil {
+				return nil, errors.NewBadRequestError("无效的设备ID")
+			}
+			d.deviceID = id
+		case item.MatchedDeviceID != nil:
+			d.deviceID = *item.MatchedDeviceID
+		default:
+			d.skipReason = "未匹配到鼠标"
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, nil
+}
+
+// findSVGUploadBatch 查找未过期的上传批次，文件按序号加载，不包含SVG内容
+func (s *ServiceImpl) findSVGUploadBatch(ctx context.Context, batchID string) (*models.SVGUploadBatch, error) {
+	id, err := primitive.ObjectIDFromHex(batchID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的批次ID")
+	}
+
+	var batch models.SVGUploadBatch
+	err = s.db.Collection(models.SVGUploadBatchesCollection).FindOne(ctx, bson.M{
+		"_id":       id,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}).Decode(&batch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("上传批次不存在或已过期")
+		}
+		return nil, errors.NewInternalServerError("获取上传批次失败: " + err.Error())
+	}
+
+	opts := options.Find().SetSort(bson.M{"index": 1}).SetProjection(bson.M{"svg": 0})
+	cursor, err := s.db.Collection(models.SVGUploadItemsCollection).Find(ctx, bson.M{"batchId": batch.ID}, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取上传文件失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &batch.Items); err != nil {
+		return nil, errors.NewInternalServerError("解析上传文件失败: " + err.Error())
+	}
+	if len(batch.Items) != batch.ItemCount {
+		return nil, errors.NewNotFoundError("上传批次不完整，请重新上传")
+	}
+	return &batch, nil
+}
+
+// loadSVGUploadContent 读取单个上传文件清洗后的SVG
+func (s *ServiceImpl) loadSVGUploadContent(ctx context.Context, itemID primitive.ObjectID) (string, error) {
+	var item models.SVGUploadItem
+	err := s.db.Collection(models.SVGUploadItemsCollection).FindOne(ctx, bson.M{"_id": itemID},
+		options.FindOne().SetProjection(bson.M{"svg": 1}),
+	).Decode(&item)
+	if err != nil {
+		return "", err
+	}
+	return item.SVG, nil
+}
+
+// loadSVGMatchTargets 加载所有未删除的鼠标名称用于匹配
+func (s *ServiceImpl) loadSVGMatchTargets(ctx context.Context) ([]svgMatchTarget, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "brand": 1})
+	cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx, bson.M{
+		"type":      models.DeviceTypeMouse,
+		"deletedAt": nil,
+	}, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取鼠标列表失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var targets []svgMatchTarget
+	if err := cursor.All(ctx, &targets); err != nil {
+		return nil, errors.NewInternalServerError("解析鼠标列表失败: " + err.Error())
+	}
+	return targets, nil
+}
+
+// buildSVGUploadItem 校验、清洗单个文件并计算匹配结果
+func buildSVGUploadItem(file device.SVGUploadFile, targets []svgMatchTarget) models.SVGUploadItem {
+	stem, view := parseSVGFileName(file.Name)
+	item := models.SVGUploadItem{
+		FileName: file.Name,
+		View:     view,
+	}
+
+	cleaned, err := svg.Sanitize(file.Content)
+	if err != nil {
+		item.Status = models.SVGMatchInvalid
+		item.Error = err.Error()
+		return item
+	}
+	item.SVG = cleaned
+
+	if _, err := svg.ParseOutline(file.Content); err != nil {
+		item.Status = models.SVGMatchInvalid
+		item.Error = "轮廓校验失败: " + err.Error()
+		return item
+	}
+
+	if view == "" {
+		item.Status = models.SVGMatchInvalid
+		item.Error = "无法从文件名识别视图(top/side)"
+		return item
+	}
+
+	item.Candidates = matchSVGTargets(stem, targets)
+	switch {
+	case len(item.Candidates) == 0:
+		item.Status = models.SVGMatchUnmatched
+	case isConfidentMatch(item.Candidates):
+		item.Status = models.SVGMatchMatched
+		item.MatchedDeviceID = &item.Candidates[0].DeviceID
+	default:
+		item.Status = models.SVGMatchAmbiguous
+	}
+	return item
+}
+
+// resolveSVGMatchConflicts 同一批次中多个文件自动匹配到同一鼠标的同一视图时，全部改为需人工确认
+func resolveSVGMatchConflicts(items []models.SVGUploadItem) {
+	counts := make(map[string]int)
+	key := func(item models.SVGUploadItem) string {
+		return item.MatchedDeviceID.Hex() + ":" + item.View
+	}
+
+	for _, item := range items {
+		if item.Status == models.SVGMatchMatched {
+			counts[key(item)]++
+		}
+	}
+	for i, item := range items {
+		if item.Status == models.SVGMatchMatched && counts[key(item)] > 1 {
+			items[i].Status = models.SVGMatchAmbiguous
+			items[i].MatchedDeviceID = nil
+		}
+	}
+}
+
+func isConfidentMatch(candidates []models.SVGMatchCandidate) bool {
+	if candidates[0].Score < svgAutoMatchScore {
+		return false
+	}
+	return len(candidates) == 1 || candidates[0].Score-candidates[1].Score >= svgAutoMatchMargin
+}
+
+// parseSVGFileName 从文件名中解析鼠标名称和视图，例如 "PMM AIM 8K side.svg"
+func parseSVGFileName(fileName string) (string, string) {
+	base := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	tokens := strings.FieldsFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	view := ""
+	nameTokens := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		switch strings.ToLower(token) {
+		case "top", "topview":
+			view = "top"
+		case "side", "sideview":
+			view = "side"
+		default:
+			nameTokens = append(nameTokens, token)
+		}
+	}
+	return strings.Join(nameTokens, " "), view
+}
+
+// matchSVGTargets 按名称相似度返回候选鼠标
+func matchSVGTargets(stem string, targets []svgMatchTarget) []models.SVGMatchCandidate {
+	key := compactName(stem)
+	if key == "" {
+		return nil
+	}
+
+	var candidates []models.SVGMatchCandidate
+	for _, target := range targets {
+		// 设备名称可能已包含品牌，也可能不包含
+		score := nameSimilarity(key, compactName(target.Name))
+		if withBrand := nameSimilarity(key, compactName(target.Brand+" "+target.Name)); withBrand > score {
+			score = withBrand
+		}
+		if score < svgCandidateScore {
+			continue
+		}
+		candidates = append(candidates, models.SVGMatchCandidate{
+			DeviceID: target.ID,
+			Name:     target.Name,
+			Brand:    target.Brand,
+			Score:    float64(int(score*1000)) / 1000,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > svgMaxCandidatesShown {
+		candidates = candidates[:svgMaxCandidatesShown]
+	}
+	return candidates
+}
+
+// compactName 转为小写并去掉空格和标点
+func compactName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// nameSimilarity 基于编辑距离的相似度，范围0-1
+func nameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// mapSVGUploadBatchToResponse 将上传批次转换为API响应
+func mapSVGUploadBatchToResponse(batch *models.SVGUploadBatch) *device.SVGUploadPreviewResponse {
+	response := &device.SVGUploadPreviewResponse{
+		BatchID:   batch.ID.Hex(),
+		Status:    string(batch.Status),
+		ExpiresAt: batch.ExpiresAt,
+		Items:     make([]device.SVGUploadItemResponse, 0, len(batch.Items)),
+	}
+
+	for _, item := range batch.Items {
+		itemResponse := device.SVGUploadItemResponse{
+			Index:        item.Index,
+			FileName:     item.FileName,
+			View:         item.View,
+			Status:       string(item.Status),
+			Error:        item.Error,
+			Result:       string(item.Result),
+			ResultReason: item.ResultReason,
+		}
+		for _, candidate := range item.Candidates {
+			info := device.SVGMatchCandidateInfo{
+				DeviceID: candidate.DeviceID.Hex(),
+				Name:     candidate.Name,
+				Brand:    candidate.Brand,
+				Score:    candidate.Score,
+			}
+			itemResponse.Candidates = append(itemResponse.Candidates, info)
+			if item.MatchedDeviceID != nil && *item.MatchedDeviceID == candidate.DeviceID {
+				matched := info
+				itemResponse.MatchedDevice = &matched
+			}
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+	return response
+}
@@ -0,0 +1,179 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+	"project/backend/tests/testutil"
+	"project/backend/types/device"
+)
+
+func TestParseSVGFileName(t *testing.T) {
+	stem, view := parseSVGFileName("PMM AIM 8K side.svg")
+	assert.Equal(t, "PMM AIM 8K", stem)
+	assert.Equal(t, "side", view)
+
+	stem, view = parseSVGFileName("outlines/WLmouse_Beast-X-Mini_top.svg")
+	assert.Equal(t, "WLmouse Beast X Mini", stem)
+	assert.Equal(t, "top", view)
+
+	// Desktop 中包含 top，但不是独立的单词
+	stem, view = parseSVGFileName("Desktop Mouse.svg")
+	assert.Equal(t, "Desktop Mouse", stem)
+	assert.Equal(t, "", view)
+}
+
+func TestMatchSVGTargets(t *testing.T) {
+	targets := []svgMatchTarget{
+		{ID: primitive.NewObjectID(), Name: "Beast X Mini", Brand: "WLMouse"},
+		{ID: primitive.NewObjectID(), Name: "Beast X Mini Pro", Brand: "WLMouse"},
+		{ID: primitive.NewObjectID(), Name: "Darmoshark M5 Pro", Brand: "Darmoshark"},
+		{ID: primitive.NewObjectID(), Name: "G Pro X Superlight 2", Brand: "Logitech"},
+	}
+
+	t.Run("大小写和拼写差异", func(t *testing.T) {
+		candidates := matchSVGTargets("damoshark m5 pro", targets)
+		assert.NotEmpty(t, candidates)
+		assert.Equal(t, targets[2].ID, candidates[0].DeviceID)
+		assert.True(t, isConfidentMatch(candidates))
+	})
+
+	t.Run("品牌不在设备名称中", func(t *testing.T) {
+		candidates := matchSVGTargets("WLmouse Beast X Mini Pro", targets)
+		assert.Equal(t, targets[1].ID, candidates[0].DeviceID)
+		assert.Equal(t, 1.0, candidates[0].Score)
+	})
+
+	t.Run("没有候选", func(t *testing.T) {
+		assert.Empty(t, matchSVGTargets("Razer Viper V3", targets))
+	})
+}
+
+func TestBuildSVGUploadItem(t *testing.T) {
+	targets := []svgMatchTarget{
+		{ID: primitive.NewObjectID(), Name: "AIM 8K", Brand: "PMM"},
+	}
+	content := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><script>alert(1)</script><path d="M0 0L10 0L10 10Z"/></svg>`)
+
+	item := buildSVGUploadItem(device.SVGUploadFile{Name: "PMM AIM 8K side.svg", Content: content}, targets)
+	assert.Equal(t, models.SVGMatchMatched, item.Status)
+	assert.Equal(t, "side", item.View)
+	assert.Equal(t, targets[0].ID, *item.MatchedDeviceID)
+	assert.NotContains(t, item.SVG, "script")
+
+	item = buildSVGUploadItem(device.SVGUploadFile{Name: "PMM AIM 8K side.svg", Content: []byte("not svg")}, targets)
+	assert.Equal(t, models.SVGMatchInvalid, item.Status)
+
+	// 轮廓没有闭合
+	openPath := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0L10 0L10 10"/></svg>`)
+	item = buildSVGUploadItem(device.SVGUploadFile{Name: "PMM AIM 8K side.svg", Content: openPath}, targets)
+	assert.Equal(t, models.SVGMatchInvalid, item.Status)
+
+	// 同一鼠标同一视图出现两次时需要人工确认
+	items := []models.SVGUploadItem{
+		buildSVGUploadItem(device.SVGUploadFile{Name: "PMM AIM 8K side.svg", Content: content}, targets),
+		buildSVGUploadItem(device.SVGUploadFile{Name: "pmm aim 8k (1) side.svg", Content: content}, targets),
+	}
+	resolveSVGMatchConflicts(items)
+	assert.Equal(t, models.SVGMatchAmbiguous, items[0].Status)
+	assert.Nil(t, items[0].MatchedDeviceID)
+}
+
+func TestSVGUploadStoresItemsSeparately(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := &ServiceImpl{db: db}
+	mouseID := primitive.NewObjectID()
+	_, err := db.Collection(models.DevicesCollection).InsertOne(ctx, bson.M{
+		"_id": mouseID, "type": models.DeviceTypeMouse, "name": "AIM 8K", "brand": "PMM",
+		"dimensions": bson.M{"length": 120},
+	})
+	require.NoError(t, err)
+
+	content := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><path d="M0 0L10 0L10 10Z"/></svg>`)
+	preview, err := svc.PreviewSVGUpload(ctx, primitive.NewObjectID().Hex(), []device.SVGUploadFile{
+		{Name: "PMM AIM 8K side.svg", Content: content},
+		{Name: "unknown top.svg", Content: content},
+	})
+	require.NoError(t, err)
+	require.Len(t, preview.Items, 2)
+
+	// 批次文档只保存元信息，文件各自一个文档
+	var raw bson.M
+	batchID, _ := primitive.ObjectIDFromHex(preview.BatchID)
+	require.NoError(t, db.Collection(models.SVGUploadBatchesCollection).FindOne(ctx, bson.M{"_id": batchID}).Decode(&raw))
+	assert.NotContains(t, raw, "items")
+	count, err := db.Collection(models.SVGUploadItemsCollection).CountDocuments(ctx, bson.M{"batchId": batchID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	loaded, err := svc.GetSVGUploadBatch(ctx, preview.BatchID)
+	require.NoError(t, err)
+	assert.Equal(t, preview.Items, loaded.Items)
+
+	result, err := svc.ConfirmSVGUpload(ctx, primitive.NewObjectID().Hex(), preview.BatchID, device.ConfirmSVGUploadRequest{})
+	require.NoError(t, err)
+	require.Len(t, result.Updated, 1)
+	assert.Equal(t, mouseID.Hex(), result.Updated[0].DeviceID)
+
+	var mouse bson.M
+	require.NoError(t, db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": mouseID}).Decode(&mouse))
+	assert.Contains(t, mouse["svgData"].(bson.M)["sideView"], "<path")
+}
+
+func TestConfirmSVGUploadResumesAfterAppliedItems(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := &ServiceImpl{db: db}
+	aimID, viperID := primitive.NewObjectID(), primitive.NewObjectID()
+	_, err := db.Collection(models.DevicesCollection).InsertMany(ctx, []interface{}{
+		bson.M{"_id": aimID, "type": models.DeviceTypeMouse, "name": "AIM 8K", "brand": "PMM", "svgData": bson.M{"sideView": "old"}},
+		bson.M{"_id": viperID, "type": models.DeviceTypeMouse, "name": "Viper V3 Pro", "brand": "Razer"},
+	})
+	require.NoError(t, err)
+
+	content := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><path d="M0 0L10 0L10 10Z"/></svg>`)
+	preview, err := svc.PreviewSVGUpload(ctx, primitive.NewObjectID().Hex(), []device.SVGUploadFile{
+		{Name: "PMM AIM 8K side.svg", Content: content},
+		{Name: "Razer Viper V3 Pro side.svg", Content: content},
+	})
+	require.NoError(t, err)
+
+	// 上次确认在写入第一个文件后中断
+	batchID, _ := primitive.ObjectIDFromHex(preview.BatchID)
+	_, err = db.Collection(models.SVGUploadItemsCollection).UpdateOne(ctx,
+		bson.M{"batchId": batchID, "index": 0},
+		bson.M{"$set": bson.M{"result": models.SVGUploadResultUpdated, "appliedDeviceId": aimID, "appliedView": "side"}},
+	)
+	require.NoError(t, err)
+
+	operatorID := primitive.NewObjectID().Hex()
+	result, err := svc.ConfirmSVGUpload(ctx, operatorID, preview.BatchID, device.ConfirmSVGUploadRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, string(models.SVGUploadStatusConfirmed), result.Status)
+	require.Len(t, result.Updated, 2)
+
+	// 已写入的文件不会再次写入
+	var aim, viper bson.M
+	require.NoError(t, db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": aimID}).Decode(&aim))
+	assert.Equal(t, "old", aim["svgData"].(bson.M)["sideView"])
+	require.NoError(t, db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": viperID}).Decode(&viper))
+	assert.Contains(t, viper["svgData"].(bson.M)["sideView"], "<path")
+
+	loaded, err := svc.GetSVGUploadBatch(ctx, preview.BatchID)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.SVGUploadResultUpdated), loaded.Items[1].Result)
+
+	_, err = svc.ConfirmSVGUpload(ctx, operatorID, preview.BatchID, device.ConfirmSVGUploadRequest{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,321 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/rating"
+	"project/backend/services/scheduler"
+	"project/backend/types/device"
+)
+
+const (
+	// DefaultTrashRetention 回收站中设备的默认保留期
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// DefaultTrashPurgeInterval 默认的自动清理间隔
+	DefaultTrashPurgeInterval = time.Hour
+	// TrashPurgeJobName 清理回收站任务名
+	TrashPurgeJobName = "device-trash-purge"
+)
+
+// ReviewPurger 彻底删除回收站中的评测，同时清理投票、评论、修改历史和图片
+type ReviewPurger interface {
+	PurgeReview(ctx context.Context, reviewID string) error
+}
+
+// SetReviewPurger 设置彻底删除设备时使用的评测清理服务
+func (s *ServiceImpl) SetReviewPurger(purger ReviewPurger) {
+	s.reviews = purger
+}
+
+// DeleteDevice 将设备移入回收站，保留期内可以恢复
+func (s *ServiceImpl) DeleteDevice(ctx context.Context, deviceID string) error {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的设备ID")
+	}
+
+	now := time.Now()
+	res, err := s.db.Collection(models.DevicesCollection).UpdateOne(ctx,
+		bson.M{"_id": id, "deletedAt": nil},
+		bson.M{"$set": bson.M{"deletedAt": now, "updatedAt": now}},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("删除设备失败: " + err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return errors.NewNotFoundError("设备不存在")
+	}
+	return nil
+}
+
+// ListDeletedDevices 获取回收站中的设备
+func (s *ServiceImpl) ListDeletedDevices(ctx context.Context, request device.DeviceTrashListRequest) (*device.DeviceTrashListResponse, error) {
+	page, pageSize := request.Page, request.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	filter := bson.M{"deletedAt": bson.M{"$ne": nil}}
+	if request.Type != "" {
+		filter["type"] = request.Type
+	}
+
+	collection := s.db.Collection(models.DevicesCollection)
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取回收站失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deletedAt", Value: -1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取回收站失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var devices []models.HardwareDevice
+	if err := cursor.All(ctx, &devices); err != nil {
+		return nil, errors.NewInternalServerError("解析回收站设备失败: " + err.Error())
+	}
+
+	response := &device.DeviceTrashListResponse{
+		Devices:  make([]device.DeviceTrashItem, 0, len(devices)),
+		Total:    int(total),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, d := range devices {
+		item := device.DeviceTrashItem{
+			ID:    d.ID.Hex(),
+			Name:  d.Name,
+			Brand: d.Brand,
+			Type:  string(d.Type),
+		}
+		if d.DeletedAt != nil {
+			item.DeletedAt = *d.DeletedAt
+		}
+		response.Devices = append(response.Devices, item)
+	}
+	return response, nil
+}
+
+// RestoreDevice 从回收站恢复设备
+func (s *ServiceImpl) RestoreDevice(ctx context.Context, deviceID string) error {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的设备ID")
+	}
+
+	res, err := s.db.Collection(models.DevicesCollection).UpdateOne(ctx,
+		bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deletedAt": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("恢复设备失败: " + err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return errors.NewNotFoundError("回收站中没有该设备")
+	}
+	return nil
+}
+
+// PurgeDevice 彻底删除回收站中的设备，并级联清理相关数据
+func (s *ServiceImpl) PurgeDevice(ctx context.Context, deviceID string) (*device.DevicePurgeResult, error) {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+
+	result, err := s.purgeDevice(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("回收站中没有该设备")
+		}
+		return nil, errors.NewInternalServerError("彻底删除设备失败: " + err.Error())
+	}
+	return result, nil
+}
+
+// PurgeExpiredDevices 清理超过保留期的设备，返回清理的数量
+func (s *ServiceImpl) PurgeExpiredDevices(ctx context.Context, retention time.Duration) (int, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx, bson.M{
+		"deletedAt": bson.M{"$ne": nil, "$lt": time.Now().Add(-retention)},
+	}, opts)
+	if err != nil {
+		return 0, errors.NewInternalServerError("查询过期设备失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var expired []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &expired); err != nil {
+		return 0, errors.NewInternalServerError("解析过期设备失败: " + err.Error())
+	}
+
+	purged := 0
+	for _, d := range expired {
+		if _, err := s.purgeDevice(ctx, d.ID); err != nil {
+			// 已被恢复或被其他实例清理
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			return purged, errors.NewInternalServerError("清理设备失败: " + err.Error())
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeDevice 先清理引用再删除设备本身，中途失败时设备仍留在回收站，可以重试
+// 订单中保存的是商品快照，不受影响
+func (s *ServiceImpl) purgeDevice(ctx context.Context, id primitive.ObjectID) (*device.DevicePurgeResult, error) {
+	trashed := bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}}
+	if err := s.db.Collection(models.DevicesCollection).FindOne(ctx, trashed).Err(); err != nil {
+		return nil, err
+	}
+
+	result := &device.DevicePurgeResult{DeviceID: id.Hex()}
+
+	reviews, err := s.purgeDeviceReviews(ctx, id)
+	result.Reviews = reviews
+	if err != nil {
+		return nil, err
+	}
+
+	carts, err := s.db.Collection(models.CartCollection).UpdateMany(ctx,
+		bson.M{"items.product_id": id},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"product_id": id}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	result.Carts = carts.ModifiedCount
+
+	userDevices, err := s.db.Collection(models.UserDevicesCollection).UpdateMany(ctx,
+		bson.M{"devices.deviceId": id},
+		bson.M{
+			"$pull": bson.M{"devices": bson.M{"deviceId": id}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	result.UserDevices = userDevices.ModifiedCount
+
+	if _, err := s.db.Collection(models.DeviceRevisionsCollection).DeleteMany(ctx, bson.M{"deviceId": id}); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Collection(models.ProductSKUsCollection).DeleteMany(ctx, bson.M{"deviceId": id}); err != nil {
+		return nil, err
+	}
+
+	res, err := s.db.Collection(models.DevicesCollection).DeleteOne(ctx, trashed)
+	if err != nil {
+		return nil, err
+	}
+	if res.DeletedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return result, nil
+}
+
+// purgeDeviceReviews 彻底删除设备的评测和引用了该设备的对比评测，返回删除的数量
+// 评测先移入回收站，再逐个交给评测服务清理关联数据，最后重新计算对比评测中其他设备的评分
+func (s *ServiceImpl) purgeDeviceReviews(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	if s.reviews == nil {
+		return 0, fmt.Errorf("未设置评测清理服务")
+	}
+
+	filter := bson.M{"$or": []bson.M{
+		{"externalItemId": id},
+		{"comparison.items.deviceId": id},
+	}}
+	cursor, err := s.db.Collection(models.ReviewsCollection).Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"itemType": 1, "externalItemId": 1, "comparison.items.deviceId": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var reviews []models.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return 0, err
+	}
+	if len(reviews) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	_, err = s.db.Collection(models.ReviewsCollection).UpdateMany(ctx,
+		bson.M{"$and": []bson.M{filter, {"deletedAt": nil}}},
+		bson.M{"$set": bson.M{"deletedAt": now, "updatedAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	touched := make(map[primitive.ObjectID]bool)
+	for i := range reviews {
+		for _, deviceID := range reviews[i].DeviceIDs() {
+			if deviceID != id {
+				touched[deviceID] = true
+			}
+		}
+		if err := s.reviews.PurgeReview(ctx, reviews[i].ID.Hex()); err != nil && !errors.IsNotFoundError(err) {
+			return purged, err
+		}
+		purged++
+	}
+
+	for deviceID := range touched {
+		if err := rating.Refresh(ctx, s.db, deviceID); err != nil {
+			log.Printf("更新设备%s的评分汇总失败: %v", deviceID.Hex(), err)
+		}
+	}
+	return purged, nil
+}
+
+// TrashPurgeJob 定期清理回收站中超过保留期的设备的定时任务
+func TrashPurgeJob(svc Service, interval, retention time.Duration) scheduler.Job {
+	if interval <= 0 {
+		interval = DefaultTrashPurgeInterval
+	}
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	return scheduler.Job{
+		Name:     TrashPurgeJobName,
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			purged, err := svc.PurgeExpiredDevices(ctx, retention)
+			if purged > 0 {
+				log.Printf("已清理回收站中的%d个过期设备", purged)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package device
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/models"
+	"project/backend/services/scheduler"
+	"project/backend/tests/testutil"
+)
+
+// recordingPurger 删除评测文档并记录删除的评测
+type recordingPurger struct {
+	db     *mongo.Database
+	purged []string
+}
+
+func (p *recordingPurger) PurgeReview(ctx context.Context, reviewID string) error {
+	id, _ := primitive.ObjectIDFromHex(reviewID)
+	p.purged = append(p.purged, reviewID)
+	_, err := p.db.Collection(models.ReviewsCollection).DeleteOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}})
+	return err
+}
+
+func TestPurgeDeviceRoutesReviewsThroughReviewPurger(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	purger := &recordingPurger{db: db}
+	svc := &ServiceImpl{db: db}
+	svc.SetReviewPurger(purger)
+
+	now := time.Now()
+	deviceID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	_, err := db.Collection(models.DevicesCollection).InsertMany(ctx, []interface{}{
+		bson.M{"_id": deviceID, "name": "A", "deletedAt": now},
+		bson.M{"_id": otherID, "name": "B"},
+	})
+	require.NoError(t, err)
+
+	single := models.Review{ID: primitive.NewObjectID(), ItemType: models.ReviewItemTypeDevice, ExternalItemID: deviceID, Status: models.ReviewStatusApproved, Score: 4}
+	comparison := models.Review{ID: primitive.NewObjectID(), ItemType: models.ReviewItemTypeDevice, ExternalItemID: otherID, Status: models.ReviewStatusApproved, Score: 4,
+		Comparison: &models.ReviewComparison{Items: []models.ComparisonItem{{DeviceID: otherID, Score: 4}, {DeviceID: deviceID, Score: 3}}}}
+	unrelated := models.Review{ID: primitive.NewObjectID(), ItemType: models.ReviewItemTypeDevice, ExternalItemID: otherID, Status: models.ReviewStatusApproved, Score: 5}
+	_, err = db.Collection(models.ReviewsCollection).InsertMany(ctx, []interface{}{single, comparison, unrelated})
+	require.NoError(t, err)
+
+	result, err := svc.PurgeDevice(ctx, deviceID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Reviews)
+	assert.ElementsMatch(t, []string{single.ID.Hex(), comparison.ID.Hex()}, purger.purged)
+
+	// 对比评测删除后，另一台设备的评分只剩无关的评测
+	var other models.HardwareDevice
+	require.NoError(t, db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": otherID}).Decode(&other))
+	require.NotNil(t, other.Rating)
+	assert.Equal(t, 1, other.Rating.Count)
+	count, err := db.Collection(models.ReviewsCollection).CountDocuments(ctx, bson.M{"_id": unrelated.ID, "deletedAt": nil})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTrashPurgeJob(t *testing.T) {
+	job := TrashPurgeJob(nil, 0, 0)
+	assert.Equal(t, TrashPurgeJobName, job.Name)
+	assert.Equal(t, DefaultTrashPurgeInterval, job.Interval)
+
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := &ServiceImpl{db: db}
+	svc.SetReviewPurger(&recordingPurger{db: db})
+
+	expired, recent := primitive.NewObjectID(), primitive.NewObjectID()
+	_, err := db.Collection(models.DevicesCollection).InsertMany(ctx, []interface{}{
+		bson.M{"_id": expired, "name": "A", "deletedAt": time.Now().Add(-DefaultTrashRetention - time.Hour)},
+		bson.M{"_id": recent, "name": "B", "deletedAt": time.Now().Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+
+	scheduler.New(scheduler.RealClock{}, nil).RunOnce(ctx, TrashPurgeJob(svc, time.Minute, 0))
+
+	count, err := db.Collection(models.DevicesCollection).CountDocuments(ctx, bson.M{"_id": bson.M{"$in": bson.A{expired, recent}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -1,10 +1,16 @@
 package email
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"html"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 
 	"project/backend/config"
@@ -24,8 +30,20 @@ func NewService(cfg config.EmailConfig) *Service {
 	}
 }
 
+// Attachment 邮件附件
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
 // SendEmail 使用SMTP发送一封电子邮件
 func (s *Service) SendEmail(to, subject, body string) error {
+	return s.SendEmailWithAttachments(to, subject, body, nil)
+}
+
+// SendEmailWithAttachments 发送带附件的邮件，没有附件时与SendEmail相同
+func (s *Service) SendEmailWithAttachments(to, subject, body string, attachments []Attachment) error {
 	if s.config.SMTP.Host == "" || s.config.SMTP.Username == "" || s.config.SMTP.Password == "" {
 		return fmt.Errorf("email service is not configured")
 	}
@@ -35,13 +53,49 @@ func (s *Service) SendEmail(to, subject, body string) error {
 		from = s.config.SMTP.Username
 	}
 
-	addr := fmt.Sprintf("%s:%d", s.config.SMTP.Host, s.config.SMTP.Port)
+	return s.deliver(from, to, buildMessage(from, to, subject, body, attachments))
+}
+
+// buildMessage 组装邮件内容，有附件时使用multipart/mixed
+func buildMessage(from, to, subject, body string, attachments []Attachment) []byte {
+	headers := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", from, to, subject)
+	if len(attachments) == 0 {
+		return []byte(headers + "Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n" + body)
+	}
 
-	headers := fmt.Sprintf(
-		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n",
-		from, to, subject,
-	)
-	msg := []byte(headers + body)
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	buf.WriteString(headers)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {`text/html; charset="UTF-8"`}})
+	part.Write([]byte(body))
+
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		// base64每行不超过76个字符
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+	mw.Close()
+	return buf.Bytes()
+}
+
+// deliver 通过SMTP投递组装好的邮件
+func (s *Service) deliver(from, to string, msg []byte) error {
+	addr := fmt.Sprintf("%s:%d", s.config.SMTP.Host, s.config.SMTP.Port)
 
 	auth := smtp.PlainAuth("", s.config.SMTP.Username, s.config.SMTP.Password, s.config.SMTP.Host)
 
@@ -166,6 +220,162 @@ func (s *Service) SendPasswordResetEmail(to, username, token string) error {
 	return s.SendEmail(to, subject, body)
 }
 
+// SendOrderCancelledEmail 发送订单取消通知
+func (s *Service) SendOrderCancelledEmail(to, orderNumber, reason string) error {
+	subject := fmt.Sprintf("您的订单 %s 已取消", orderNumber)
+	ordersURL := fmt.Sprintf("%s/orders", s.baseURL)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>订单已取消</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>订单已取消</h1>
+        <p>您的订单 %s 已取消，原因：%s</p>
+        <p>订单占用的库存和优惠券已释放，如仍需购买请重新下单。</p>
+        <p>查看我的订单：<a href="%s">%s</a></p>
+    </div>
+</body>
+</html>
+`, html.EscapeString(orderNumber), html.EscapeString(reason), ordersURL, ordersURL)
+
+	return s.SendEmail(to, subject, body)
+}
+
+// SendOrderPaidEmail 发送支付成功通知，附带PDF发票
+func (s *Service) SendOrderPaidEmail(to, orderNumber string, invoicePDF []byte) error {
+	subject := fmt.Sprintf("您的订单 %s 已支付成功", orderNumber)
+	orderURL := fmt.Sprintf("%s/orders", s.baseURL)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>支付成功</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>支付成功</h1>
+        <p>您的订单 %s 已支付成功，我们会尽快为您发货。</p>
+        <p>本次订单的发票见附件，也可以在订单详情中随时下载。</p>
+        <p>查看我的订单：<a href="%s">%s</a></p>
+    </div>
+</body>
+</html>
+`, html.EscapeString(orderNumber), orderURL, orderURL)
+
+	var attachments []Attachment
+	if len(invoicePDF) > 0 {
+		attachments = append(attachments, Attachment{
+			Filename:    fmt.Sprintf("invoice-%s.pdf", orderNumber),
+			ContentType: "application/pdf",
+			Data:        invoicePDF,
+		})
+	}
+	return s.SendEmailWithAttachments(to, subject, body, attachments)
+}
+
+// SendReviewerApprovedEmail 发送评测员申请通过通知
+func (s *Service) SendReviewerApprovedEmail(to, username string) error {
+	subject := "您的评测员申请已通过"
+	loginURL := fmt.Sprintf("%s/login", s.baseURL)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>评测员申请已通过</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>评测员申请已通过</h1>
+        <p>%s，恭喜！您的评测员申请已通过审核。</p>
+        <p>请重新登录以使用评测员权限：<a href="%s">%s</a></p>
+    </div>
+</body>
+</html>
+`, html.EscapeString(username), loginURL, loginURL)
+
+	return s.SendEmail(to, subject, body)
+}
+
+// SendReviewerRejectedEmail 发送评测员申请被拒绝通知
+func (s *Service) SendReviewerRejectedEmail(to, username, reason string) error {
+	subject := "您的评测员申请未通过"
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>评测员申请未通过</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>评测员申请未通过</h1>
+        <p>%s，感谢您的申请。很遗憾，您的评测员申请未通过审核，原因：%s</p>
+        <p>您可以在30天后再次提交申请。</p>
+    </div>
+</body>
+</html>
+`, html.EscapeString(username), html.EscapeString(reason))
+
+	return s.SendEmail(to, subject, body)
+}
+
+// SendReviewCommentEmail 通知评测作者有新评论
+func (s *Service) SendReviewCommentEmail(to, username, reviewID, excerpt string) error {
+	subject := "您的评测收到了新评论"
+	reviewURL := fmt.Sprintf("%s/reviews/%s", s.baseURL, reviewID)
+
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>评测收到新评论</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .excerpt { color: #555; border-left: 3px solid #ddd; padding-left: 10px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>评测收到新评论</h1>
+        <p>%s，您的评测收到了一条新评论：</p>
+        <p class="excerpt">%s</p>
+        <p>查看评测：<a href="%s">%s</a></p>
+    </div>
+</body>
+</html>
+`, html.EscapeString(username), html.EscapeString(excerpt), reviewURL, reviewURL)
+
+	return s.SendEmail(to, subject, body)
+}
+
 // TestConnection 测试邮件配置
 func (s *Service) TestConnection() error {
 	if s.config.SMTP.Host == "" {
@@ -50,6 +50,7 @@ func NewService() Service {
 		"common.error": "错误",
 		"common.bad_request": "请求错误",
 	}
+	addTranslations(svc.translations, invoiceTranslations)
 	
 	return svc
 }
@@ -78,6 +79,7 @@ func NewDefaultService() *DefaultService {
 		"common.error": "错误",
 		"common.bad_request": "请求错误",
 	}
+	addTranslations(svc.translations, invoiceTranslations)
 	
 	return svc
 }
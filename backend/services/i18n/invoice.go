@@ -0,0 +1,83 @@
+package i18n
+
+// invoiceTranslations 发票和发货单使用的文案
+var invoiceTranslations = map[string]map[string]string{
+	"en-US": {
+		"invoice.title":               "INVOICE",
+		"invoice.packing_slip":        "PACKING SLIP",
+		"invoice.order_number":        "Order No.",
+		"invoice.order_date":          "Order date",
+		"invoice.paid_date":           "Paid on",
+		"invoice.payment_method":      "Payment method",
+		"invoice.ship_to":             "Ship to",
+		"invoice.shipping_method":     "Shipping method",
+		"invoice.tracking":            "Tracking",
+		"invoice.item":                "Item",
+		"invoice.sku":                 "SKU",
+		"invoice.variant":             "Variant",
+		"invoice.quantity":            "Qty",
+		"invoice.unit_price":          "Unit price",
+		"invoice.discount":            "Discount",
+		"invoice.amount":              "Amount",
+		"invoice.subtotal":            "Subtotal",
+		"invoice.coupon":              "Coupon {{code}}",
+		"invoice.shipping":            "Shipping",
+		"invoice.tax":                 "Tax",
+		"invoice.tax_included":        "Incl. tax",
+		"invoice.total":               "Total",
+		"invoice.refunded":            "Refunded",
+		"invoice.page":                "Page {{page}} of {{total}}",
+		"invoice.thanks":              "Thank you for your order.",
+		"invoice.packing_note":        "Please check that the package contents match this list.",
+		"invoice.payment.credit_card": "Credit card",
+		"invoice.payment.debit_card":  "Debit card",
+		"invoice.payment.paypal":      "PayPal",
+		"invoice.payment.alipay":      "Alipay",
+		"invoice.payment.wechat":      "WeChat Pay",
+	},
+	"zh-CN": {
+		"invoice.title":               "发票",
+		"invoice.packing_slip":        "发货单",
+		"invoice.order_number":        "订单号",
+		"invoice.order_date":          "下单日期",
+		"invoice.paid_date":           "支付日期",
+		"invoice.payment_method":      "支付方式",
+		"invoice.ship_to":             "收货信息",
+		"invoice.shipping_method":     "配送方式",
+		"invoice.tracking":            "物流单号",
+		"invoice.item":                "商品",
+		"invoice.sku":                 "SKU",
+		"invoice.variant":             "规格",
+		"invoice.quantity":            "数量",
+		"invoice.unit_price":          "单价",
+		"invoice.discount":            "优惠",
+		"invoice.amount":              "金额",
+		"invoice.subtotal":            "商品小计",
+		"invoice.coupon":              "优惠券 {{code}}",
+		"invoice.shipping":            "运费",
+		"invoice.tax":                 "税费",
+		"invoice.tax_included":        "其中含税",
+		"invoice.total":               "合计",
+		"invoice.refunded":            "已退款",
+		"invoice.page":                "第 {{page}} 页，共 {{total}} 页",
+		"invoice.thanks":              "感谢您的购买。",
+		"invoice.packing_note":        "请核对包裹内的商品与本清单是否一致。",
+		"invoice.payment.credit_card": "信用卡",
+		"invoice.payment.debit_card":  "借记卡",
+		"invoice.payment.paypal":      "PayPal",
+		"invoice.payment.alipay":      "支付宝",
+		"invoice.payment.wechat":      "微信支付",
+	},
+}
+
+// addTranslations 将src中的文案合并到dst
+func addTranslations(dst, src map[string]map[string]string) {
+	for locale, messages := range src {
+		if dst[locale] == nil {
+			dst[locale] = make(map[string]string, len(messages))
+		}
+		for key, value := range messages {
+			dst[locale][key] = value
+		}
+	}
+}
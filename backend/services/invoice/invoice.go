@@ -0,0 +1,334 @@
+// Package invoice 生成订单的发票和发货单PDF
+package invoice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"project/backend/internal/pdf"
+	"project/backend/models"
+	"project/backend/services/i18n"
+)
+
+// 文档类型
+const (
+	KindInvoice     = "invoice"
+	KindPackingSlip = "packing-slip"
+)
+
+// EmailLocale 随邮件发送的发票使用的语言，与邮件正文一致
+const EmailLocale = "zh-CN"
+
+const (
+	marginX      = 50.0
+	rightEdge    = pdf.A4Width - marginX
+	rowHeight    = 18.0
+	bodySize     = 9.5
+	footerY      = pdf.A4Height - 36
+	bottomMargin = pdf.A4Height - 70
+	dateLayout   = "2006-01-02"
+)
+
+// column 表格列，right为true时按右边缘对齐
+type column struct {
+	label string
+	x     float64
+	width float64
+	right bool
+}
+
+// Renderer 按语言渲染订单文档
+type Renderer struct {
+	i18n i18n.Service
+}
+
+// NewRenderer 创建渲染器
+func NewRenderer(i18nService i18n.Service) *Renderer {
+	return &Renderer{i18n: i18nService}
+}
+
+// Render 按类型渲染文档，类型无效时返回false
+func (r *Renderer) Render(kind string, o *models.Order, locale string) ([]byte, bool) {
+	switch kind {
+	case KindInvoice:
+		return r.Invoice(o, locale), true
+	case KindPackingSlip:
+		return r.PackingSlip(o, locale), true
+	default:
+		return nil, false
+	}
+}
+
+// FileName 下载时使用的文件名
+func FileName(kind string, o *models.Order) string {
+	return fmt.Sprintf("%s-%s.pdf", kind, o.OrderNumber)
+}
+
+// Invoice 渲染发票：商品明细、优惠、运费、税费和合计
+func (r *Renderer) Invoice(o *models.Order, locale string) []byte {
+	t := r.translator(locale)
+	doc := pdf.New()
+	doc.Title = t("invoice.title") + " " + o.OrderNumber
+
+	meta := [][2]string{
+		{t("invoice.order_number"), o.OrderNumber},
+		{t("invoice.order_date"), o.CreatedAt.Format(dateLayout)},
+	}
+	if o.PaidAt != nil {
+		meta = append(meta, [2]string{t("invoice.paid_date"), o.PaidAt.Format(dateLayout)})
+	}
+	if o.PaymentInfo.Method != "" {
+		meta = append(meta, [2]string{t("invoice.payment_method"), r.paymentMethod(locale, o.PaymentInfo.Method)})
+	}
+
+	columns := []column{
+		{label: t("invoice.item"), x: marginX, width: 185},
+		{label: t("invoice.sku"), x: 240, width: 85},
+		{label: t("invoice.quantity"), x: 360, right: true},
+		{label: t("invoice.unit_price"), x: 430, right: true},
+		{label: t("invoice.discount"), x: 490, right: true},
+		{label: t("invoice.amount"), x: rightEdge, right: true},
+	}
+	rows := make([][]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		discount := ""
+		if item.Discount > 0 {
+			discount = formatMoney(-item.Discount, o.Currency)
+		}
+		rows = append(rows, []string{
+			item.Name,
+			item.SKUCode,
+			strconv.Itoa(item.Quantity),
+			formatMoney(item.Price, o.Currency),
+			discount,
+			formatMoney(item.Subtotal-item.Discount, o.Currency),
+		})
+	}
+
+	y := header(doc, t("invoice.title"), meta, t("invoice.ship_to"), shipTo(o))
+	y = table(doc, y, columns, rows)
+
+	// 合计
+	totals := [][2]string{{t("invoice.subtotal"), formatMoney(o.Subtotal, o.Currency)}}
+	if o.Discount > 0 {
+		totals = append(totals, [2]string{t("invoice.coupon", "code", o.CouponCode), formatMoney(-o.Discount, o.Currency)})
+	}
+	totals = append(totals, [2]string{t("invoice.shipping"), formatMoney(o.ShippingFee, o.Currency)})
+	if !o.TaxInclusive {
+		totals = append(totals, [2]string{t("invoice.tax"), formatMoney(o.Tax, o.Currency)})
+	}
+
+	if y+float64(len(totals)+4)*16 > bottomMargin {
+		doc.AddPage()
+		y = 60
+	}
+	y += 10
+	for _, line := range totals {
+		y += 16
+		doc.TextRight(430, y, bodySize, false, line[0])
+		doc.TextRight(rightEdge, y, bodySize, false, line[1])
+	}
+	y += 8
+	doc.Line(340, y, rightEdge, y, 0.5)
+	y += 18
+	doc.TextRight(430, y, 11, true, t("invoice.total"))
+	doc.TextRight(rightEdge, y, 11, true, formatMoney(o.Total, o.Currency))
+	if o.TaxInclusive && o.Tax > 0 {
+		y += 16
+		doc.TextRight(430, y, bodySize, false, t("invoice.tax_included"))
+		doc.TextRight(rightEdge, y, bodySize, false, formatMoney(o.Tax, o.Currency))
+	}
+	if o.RefundedAmount > 0 {
+		y += 16
+		doc.TextRight(430, y, bodySize, false, t("invoice.refunded"))
+		doc.TextRight(rightEdge, y, bodySize, false, formatMoney(-o.RefundedAmount, o.Currency))
+	}
+
+	doc.Text(marginX, y+40, bodySize, false, t("invoice.thanks"))
+	footers(doc, t)
+	return doc.Bytes()
+}
+
+// PackingSlip 渲染发货单：收货信息和商品清单，不包含价格
+func (r *Renderer) PackingSlip(o *models.Order, locale string) []byte {
+	t := r.translator(locale)
+	doc := pdf.New()
+	doc.Title = t("invoice.packing_slip") + " " + o.OrderNumber
+
+	meta := [][2]string{
+		{t("invoice.order_number"), o.OrderNumber},
+		{t("invoice.order_date"), o.CreatedAt.Format(dateLayout)},
+	}
+	if o.ShippingInfo.ShippingMethod != "" {
+		meta = append(meta, [2]string{t("invoice.shipping_method"), o.ShippingInfo.ShippingMethod})
+	}
+	if o.ShippingInfo.TrackingNumber != "" {
+		meta = append(meta, [2]string{t("invoice.tracking"), strings.TrimSpace(o.ShippingInfo.Carrier + " " + o.ShippingInfo.TrackingNumber)})
+	}
+
+	columns := []column{
+		{label: t("invoice.item"), x: marginX, width: 235},
+		{label: t("invoice.sku"), x: 290, width: 110},
+		{label: t("invoice.variant"), x: 405, width: 90},
+		{label: t("invoice.quantity"), x: rightEdge, right: true},
+	}
+	rows := make([][]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		rows = append(rows, []string{
+			item.Name,
+			item.SKUCode,
+			variant(item.Variant),
+			strconv.Itoa(item.Quantity),
+		})
+	}
+
+	y := header(doc, t("invoice.packing_slip"), meta, t("invoice.ship_to"), shipTo(o))
+	y = table(doc, y, columns, rows)
+
+	doc.Text(marginX, y+30, bodySize, false, t("invoice.packing_note"))
+	footers(doc, t)
+	return doc.Bytes()
+}
+
+// header 绘制标题、订单信息和收货地址，返回表格的起始位置
+func header(doc *pdf.Document, title string, meta [][2]string, shipToLabel string, address []string) float64 {
+	doc.Text(marginX, 70, 22, true, title)
+
+	y := 100.0
+	for _, line := range meta {
+		doc.Text(marginX, y, bodySize, true, line[0])
+		doc.Text(marginX+85, y, bodySize, false, pdf.Truncate(line[1], 170, bodySize, false))
+		y += 15
+	}
+
+	addressY := 100.0
+	doc.Text(320, addressY, bodySize, true, shipToLabel)
+	for _, line := range address {
+		addressY += 15
+		doc.Text(320, addressY, bodySize, false, pdf.Truncate(line, rightEdge-320, bodySize, false))
+	}
+
+	if addressY > y {
+		y = addressY
+	}
+	return y + 25
+}
+
+// table 绘制表格，超出页面时换页并重复表头，返回表格底部的位置
+func table(doc *pdf.Document, y float64, columns []column, rows [][]string) float64 {
+	drawHeader := func(y float64) float64 {
+		doc.FillRect(marginX-4, y-12, rightEdge-marginX+8, rowHeight, 0.92)
+		for _, col := range columns {
+			cell(doc, col, y, true, col.label)
+		}
+		return y + rowHeight
+	}
+
+	y = drawHeader(y)
+	for _, row := range rows {
+		if y+rowHeight > bottomMargin {
+			doc.AddPage()
+			y = drawHeader(60)
+		}
+		for i, col := range columns {
+			cell(doc, col, y, false, row[i])
+		}
+		doc.Line(marginX-4, y+6, rightEdge+4, y+6, 0.25)
+		y += rowHeight
+	}
+	return y
+}
+
+func cell(doc *pdf.Document, col column, y float64, bold bool, text string) {
+	if col.right {
+		doc.TextRight(col.x, y, bodySize, bold, text)
+		return
+	}
+	doc.Text(col.x, y, bodySize, bold, pdf.Truncate(text, col.width, bodySize, bold))
+}
+
+// footers 在每页底部绘制页码
+func footers(doc *pdf.Document, t func(key string, params ...string) string) {
+	total := doc.PageCount()
+	for i := 0; i < total; i++ {
+		doc.SetPage(i)
+		doc.TextRight(rightEdge, footerY, 8, false, t("invoice.page", "page", strconv.Itoa(i+1), "total", strconv.Itoa(total)))
+	}
+}
+
+// translator 返回指定语言的翻译函数，params为键值对
+func (r *Renderer) translator(locale string) func(key string, params ...string) string {
+	return func(key string, params ...string) string {
+		var values map[string]interface{}
+		if len(params) > 0 {
+			values = make(map[string]interface{}, len(params)/2)
+			for i := 0; i+1 < len(params); i += 2 {
+				values[params[i]] = params[i+1]
+			}
+		}
+		return r.i18n.T(locale, key, values)
+	}
+}
+
+func (r *Renderer) paymentMethod(locale string, method models.PaymentMethodEnum) string {
+	key := "invoice.payment." + string(method)
+	if label := r.i18n.T(locale, key, nil); label != key {
+		return label
+	}
+	return string(method)
+}
+
+// shipTo 收货地址的各行
+func shipTo(o *models.Order) []string {
+	info := o.ShippingInfo
+	lines := []string{}
+	for _, line := range []string{
+		strings.TrimSpace(info.Name + "  " + info.Phone),
+		info.Address,
+		strings.Join(nonEmpty(info.City, info.State, info.ZipCode), ", "),
+		info.Country,
+	} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func variant(v models.ProductVariant) string {
+	return strings.Join(nonEmpty(v.Color, v.Connection), " / ")
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// formatMoney 金额以分为单位，输出带千分位的两位小数，如 CNY 1,299.00
+func formatMoney(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	units := strconv.FormatInt(amount/100, 10)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	return fmt.Sprintf("%s%s %s.%02d", sign, currency, grouped.String(), amount%100)
+}
@@ -0,0 +1,73 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"project/backend/models"
+	"project/backend/services/i18n"
+)
+
+func testOrder(items int) *models.Order {
+	paidAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	o := &models.Order{
+		OrderNumber:  "20250101-000013",
+		Currency:     models.DefaultCurrency,
+		ShippingInfo: models.ShippingInfo{Name: "张三", Phone: "13800000000", Address: "Main St 1", City: "Shanghai"},
+		PaymentInfo:  models.PaymentInfo{Method: models.PaymentMethodAlipay},
+		CreatedAt:    paidAt.Add(-time.Hour),
+		PaidAt:       &paidAt,
+	}
+	for i := 0; i < items; i++ {
+		o.Items = append(o.Items, models.OrderItem{
+			Name:     fmt.Sprintf("Mouse %d", i),
+			SKUCode:  fmt.Sprintf("SKU-%d", i),
+			Price:    29900,
+			Quantity: 1,
+			Subtotal: 29900,
+			Variant:  models.ProductVariant{Color: "black", Connection: "wireless"},
+		})
+		o.Subtotal += 29900
+	}
+	o.Total = o.Subtotal
+	return o
+}
+
+func TestInvoiceLocalized(t *testing.T) {
+	r := NewRenderer(i18n.NewService())
+	o := testOrder(2)
+
+	en := string(r.Invoice(o, "en-US"))
+	assert.True(t, strings.HasPrefix(en, "%PDF-"))
+	assert.Contains(t, en, "(INVOICE)")
+	assert.Contains(t, en, "(20250101-000013)")
+	assert.Contains(t, en, "(CNY 598.00)")
+
+	// zh-CN的标题“发票”以UCS-2编码输出
+	zh := string(r.Invoice(o, "zh-CN"))
+	assert.Contains(t, zh, "<53D17968>")
+	assert.NotContains(t, zh, "(INVOICE)")
+}
+
+func TestDocumentsPaginate(t *testing.T) {
+	r := NewRenderer(i18n.NewService())
+	o := testOrder(80)
+
+	assert.Contains(t, string(r.Invoice(o, "en-US")), "(Page 3 of 3)")
+	assert.Contains(t, string(r.Invoice(testOrder(1), "en-US")), "/Count 1")
+
+	slip := string(r.PackingSlip(o, "en-US"))
+	assert.Contains(t, slip, "(PACKING SLIP)")
+	assert.Contains(t, slip, "(black / wireless)")
+	assert.NotContains(t, slip, "299.00")
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "CNY 0.05", formatMoney(5, "CNY"))
+	assert.Equal(t, "CNY 1,234,567.89", formatMoney(123456789, ""))
+	assert.Equal(t, "-USD 12.00", formatMoney(-1200, "USD"))
+}
@@ -0,0 +1,107 @@
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"project/backend/models"
+)
+
+// DefaultBlocklistLocale 对所有语言生效的屏蔽词
+const DefaultBlocklistLocale = "default"
+
+// BlocklistCheck 按语言检查屏蔽词
+// 英文等单词按整词匹配，避免误伤包含屏蔽词的正常单词；中文等没有分词的语言按子串匹配
+type BlocklistCheck struct {
+	lists map[string][]blockedTerm
+}
+
+type blockedTerm struct {
+	term    string
+	pattern *regexp.Regexp // 整词匹配时使用
+}
+
+// NewBlocklistCheck 创建屏蔽词检查，lists的键为语言（如zh-CN），也可以只写语言前缀（如zh）
+func NewBlocklistCheck(lists map[string][]string) *BlocklistCheck {
+	check := &BlocklistCheck{lists: make(map[string][]blockedTerm, len(lists))}
+	for locale, terms := range lists {
+		key := strings.ToLower(locale)
+		for _, term := range terms {
+			term = strings.ToLower(strings.TrimSpace(term))
+			if term == "" {
+				continue
+			}
+			blocked := blockedTerm{term: term}
+			if isWordTerm(term) {
+				blocked.pattern = regexp.MustCompile(`\b` + regexp.QuoteMeta(term) + `\b`)
+			}
+			check.lists[key] = append(check.lists[key], blocked)
+		}
+	}
+	return check
+}
+
+// Name 规则名称
+func (c *BlocklistCheck) Name() string {
+	return "blocklist"
+}
+
+// Check 命中第一个屏蔽词计40分，之后每个加10分，最多60分
+func (c *BlocklistCheck) Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error) {
+	text := strings.ToLower(submission.Text())
+	var hits []string
+	seen := make(map[string]bool)
+	for _, term := range c.termsFor(submission.Locale) {
+		if seen[term.term] {
+			continue
+		}
+		matched := false
+		if term.pattern != nil {
+			matched = term.pattern.MatchString(text)
+		} else {
+			matched = strings.Contains(text, term.term)
+		}
+		if matched {
+			seen[term.term] = true
+			hits = append(hits, term.term)
+		}
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	score := 40 + 10*(len(hits)-1)
+	if score > 60 {
+		score = 60
+	}
+	return []models.ModerationFlag{{
+		Code:   "blocklist",
+		Detail: "包含屏蔽词: " + strings.Join(hits, ", "),
+		Score:  score,
+	}}, nil
+}
+
+// termsFor 合并通用屏蔽词、语言前缀和完整语言的屏蔽词
+func (c *BlocklistCheck) termsFor(locale string) []blockedTerm {
+	locale = strings.ToLower(locale)
+	terms := append([]blockedTerm{}, c.lists[DefaultBlocklistLocale]...)
+	if locale == "" {
+		return terms
+	}
+	if prefix, _, found := strings.Cut(locale, "-"); found {
+		terms = append(terms, c.lists[prefix]...)
+	}
+	return append(terms, c.lists[locale]...)
+}
+
+// isWordTerm 只包含拉丁字母、数字和空格的屏蔽词按整词匹配
+func isWordTerm(term string) bool {
+	for _, r := range term {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/models"
+)
+
+const (
+	// ShingleSize 按字符切分的片段长度，对中文和英文都适用
+	ShingleSize = 5
+	// exactDuplicateThreshold 相似度达到该值视为完全重复
+	exactDuplicateThreshold = 0.95
+	// duplicateCandidateLimit 每类候选评测最多比较的数量
+	duplicateCandidateLimit = 200
+)
+
+// DuplicateCheck 与已有评测比较内容相似度
+// 候选评测为同一作者、同一项目的评测和最近提交的评测
+type DuplicateCheck struct {
+	db        *mongo.Database
+	threshold float64
+}
+
+// NewDuplicateCheck 创建重复内容检查
+func NewDuplicateCheck(db *mongo.Database, threshold float64) *DuplicateCheck {
+	return &DuplicateCheck{db: db, threshold: threshold}
+}
+
+// Name 规则名称
+func (c *DuplicateCheck) Name() string {
+	return "duplicate"
+}
+
+// Check 只标记最相似的一篇评测
+func (c *DuplicateCheck) Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error) {
+	shingles := Shingles(submission.Content, ShingleSize)
+	if len(shingles) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := c.candidates(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.Review
+	bestSimilarity := 0.0
+	for i := range candidates {
+		similarity := Jaccard(shingles, Shingles(candidates[i].Content, ShingleSize))
+		if similarity > bestSimilarity {
+			best, bestSimilarity = &candidates[i], similarity
+		}
+	}
+	if best == nil || bestSimilarity < c.threshold {
+		return nil, nil
+	}
+
+	flag := models.ModerationFlag{
+		Code:   "near_duplicate",
+		Detail: fmt.Sprintf("与评测%s的相似度为%.0f%%", best.ID.Hex(), bestSimilarity*100),
+		Score:  35,
+	}
+	if bestSimilarity >= exactDuplicateThreshold {
+		flag.Code = "duplicate"
+		flag.Score = 60
+	}
+	if best.UserID == submission.UserID {
+		flag.Detail += "（同一作者）"
+	}
+	return []models.ModerationFlag{flag}, nil
+}
+
+func (c *DuplicateCheck) candidates(ctx context.Context, submission *Submission) ([]models.Review, error) {
+	collection := c.db.Collection(models.ReviewsCollection)
+	projection := bson.M{"content": 1, "userId": 1}
+	exclude := bson.M{"$ne": submission.ReviewID}
+
+	related := []bson.M{{"userId": submission.UserID}}
+	if !submission.ExternalItemID.IsZero() {
+		related = append(related, bson.M{"externalItemId": submission.ExternalItemID})
+	}
+	filters := []bson.M{
+		{"_id": exclude, "$or": related},
+		{"_id": exclude},
+	}
+
+	seen := make(map[primitive.ObjectID]bool)
+	var result []models.Review
+	for _, filter := range filters {
+		cursor, err := collection.Find(ctx, filter, options.Find().
+			SetProjection(projection).
+			SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+			SetLimit(duplicateCandidateLimit))
+		if err != nil {
+			return nil, err
+		}
+		var reviews []models.Review
+		if err := cursor.All(ctx, &reviews); err != nil {
+			return nil, err
+		}
+		for _, r := range reviews {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				result = append(result, r)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Shingles 把文本规范化后按size个字符切片并取哈希，忽略大小写、标点和空白
+// 文本短于size时整段作为一个片段
+func Shingles(text string, size int) map[uint64]struct{} {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	result := make(map[uint64]struct{})
+	if len(runes) == 0 {
+		return result
+	}
+	if len(runes) < size {
+		result[hashRunes(runes)] = struct{}{}
+		return result
+	}
+	for i := 0; i+size <= len(runes); i++ {
+		result[hashRunes(runes[i:i+size])] = struct{}{}
+	}
+	return result
+}
+
+// Jaccard 两个片段集合的Jaccard相似度
+func Jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	intersection := 0
+	for shingle := range a {
+		if _, ok := b[shingle]; ok {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+func hashRunes(runes []rune) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(string(runes)))
+	return h.Sum64()
+}
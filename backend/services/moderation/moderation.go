@@ -0,0 +1,128 @@
+// Package moderation 评测提交时的自动预审
+// 预审只标记风险，不直接拒绝评测，最终结果仍由评测员决定
+package moderation
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/config"
+	"project/backend/models"
+)
+
+// 未配置时使用的默认值
+const (
+	DefaultMaxLinks            = 2
+	DefaultDuplicateThreshold  = 0.8
+	DefaultRateLimit           = 5
+	DefaultRateWindow          = 24 * time.Hour
+	DefaultTrustedMinPublished = 5
+	DefaultAutoApproveMaxRisk  = 20
+)
+
+// MaxRiskScore 风险分上限
+const MaxRiskScore = 100
+
+// Submission 待预审的评测内容
+type Submission struct {
+	ReviewID       primitive.ObjectID // 修改评测时为评测ID，新建时为空
+	UserID         primitive.ObjectID
+	ExternalItemID primitive.ObjectID
+	Locale         string
+	Content        string
+	Pros           []string
+	Cons           []string
+	Usage          string
+}
+
+// Text 评测的全部文字
+func (s *Submission) Text() string {
+	parts := make([]string, 0, len(s.Pros)+len(s.Cons)+2)
+	parts = append(parts, s.Content, s.Usage)
+	parts = append(parts, s.Pros...)
+	parts = append(parts, s.Cons...)
+	return strings.Join(parts, "\n")
+}
+
+// Check 预审规则，返回命中的标记
+type Check interface {
+	Name() string
+	Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error)
+}
+
+// Pipeline 依次运行已注册的预审规则
+type Pipeline struct {
+	checks []Check
+}
+
+// NewPipeline 创建预审流程
+func NewPipeline(checks ...Check) *Pipeline {
+	return &Pipeline{checks: checks}
+}
+
+// NewDefaultPipeline 创建包含屏蔽词、垃圾内容、重复内容和提交频率检查的预审流程
+func NewDefaultPipeline(db *mongo.Database, cfg config.ModerationConfig) *Pipeline {
+	cfg = WithDefaults(cfg)
+	return NewPipeline(
+		NewBlocklistCheck(cfg.Blocklists),
+		NewSpamCheck(cfg.MaxLinks),
+		NewDuplicateCheck(db, cfg.DuplicateThreshold),
+		NewRateCheck(db, cfg.RateLimit, cfg.RateWindow),
+	)
+}
+
+// Register 添加预审规则
+func (p *Pipeline) Register(check Check) {
+	p.checks = append(p.checks, check)
+}
+
+// Run 运行所有规则并汇总风险分，单个规则出错只记录日志，不影响评测提交
+func (p *Pipeline) Run(ctx context.Context, submission *Submission) *models.ReviewModeration {
+	result := &models.ReviewModeration{
+		Flags:     []models.ModerationFlag{},
+		CheckedAt: time.Now(),
+	}
+	for _, check := range p.checks {
+		flags, err := check.Check(ctx, submission)
+		if err != nil {
+			log.Printf("评测预审规则%s执行失败: %v", check.Name(), err)
+			continue
+		}
+		for _, flag := range flags {
+			result.Flags = append(result.Flags, flag)
+			result.RiskScore += flag.Score
+		}
+	}
+	if result.RiskScore > MaxRiskScore {
+		result.RiskScore = MaxRiskScore
+	}
+	return result
+}
+
+// WithDefaults 为未配置的项填充默认值
+func WithDefaults(cfg config.ModerationConfig) config.ModerationConfig {
+	if cfg.MaxLinks <= 0 {
+		cfg.MaxLinks = DefaultMaxLinks
+	}
+	if cfg.DuplicateThreshold <= 0 || cfg.DuplicateThreshold > 1 {
+		cfg.DuplicateThreshold = DefaultDuplicateThreshold
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = DefaultRateLimit
+	}
+	if cfg.RateWindow <= 0 {
+		cfg.RateWindow = DefaultRateWindow
+	}
+	if cfg.TrustedMinPublished <= 0 {
+		cfg.TrustedMinPublished = DefaultTrustedMinPublished
+	}
+	if cfg.AutoApproveMaxRisk <= 0 {
+		cfg.AutoApproveMaxRisk = DefaultAutoApproveMaxRisk
+	}
+	return cfg
+}
@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/models"
+)
+
+type fixedCheck struct {
+	flags []models.ModerationFlag
+	err   error
+}
+
+func (c fixedCheck) Name() string { return "fixed" }
+
+func (c fixedCheck) Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error) {
+	return c.flags, c.err
+}
+
+func TestPipelineSumsAndCapsRisk(t *testing.T) {
+	pipeline := NewPipeline(
+		fixedCheck{flags: []models.ModerationFlag{{Code: "a", Score: 60}}},
+		fixedCheck{err: errors.New("boom")},
+	)
+	pipeline.Register(fixedCheck{flags: []models.ModerationFlag{{Code: "b", Score: 50}}})
+
+	result := pipeline.Run(context.Background(), &Submission{})
+	assert.Equal(t, MaxRiskScore, result.RiskScore)
+	require.Len(t, result.Flags, 2)
+	assert.Equal(t, "a", result.Flags[0].Code)
+	assert.Equal(t, "b", result.Flags[1].Code)
+}
+
+func TestBlocklistCheck(t *testing.T) {
+	check := NewBlocklistCheck(map[string][]string{
+		"default": {"scam"},
+		"zh":      {"垃圾"},
+		"en-US":   {"crap"},
+	})
+	ctx := context.Background()
+
+	flags, err := check.Check(ctx, &Submission{Locale: "zh-CN", Content: "这个鼠标就是垃圾，SCAM"})
+	require.NoError(t, err)
+	require.Len(t, flags, 1)
+	assert.Equal(t, 50, flags[0].Score)
+
+	// 英文按整词匹配
+	flags, err = check.Check(ctx, &Submission{Locale: "en-US", Content: "Scrappy little mouse"})
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	// 其他语言的屏蔽词不生效
+	flags, err = check.Check(ctx, &Submission{Locale: "en-US", Content: "垃圾"})
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+}
+
+func TestSpamCheck(t *testing.T) {
+	check := NewSpamCheck(2)
+	ctx := context.Background()
+
+	flags, err := check.Check(ctx, &Submission{
+		Content: "Buy now http://a.example http://b.example www.c.example, contact me at spam@example.com",
+	})
+	require.NoError(t, err)
+	codes := make([]string, len(flags))
+	for i, flag := range flags {
+		codes[i] = flag.Code
+	}
+	assert.ElementsMatch(t, []string{"links", "contact"}, codes)
+
+	flags, err = check.Check(ctx, &Submission{Content: "THIS MOUSE IS THE BEST EVER!!!!!!!!!"})
+	require.NoError(t, err)
+	codes = codes[:0]
+	for _, flag := range flags {
+		codes = append(codes, flag.Code)
+	}
+	assert.ElementsMatch(t, []string{"repetition", "shouting"}, codes)
+
+	flags, err = check.Check(ctx, &Submission{Content: "手感不错，侧键位置合适，传感器表现稳定。"})
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+}
+
+func TestShinglesSimilarity(t *testing.T) {
+	original := "This mouse has a comfortable shape and a very reliable sensor for FPS games."
+	assert.Equal(t, 1.0, Jaccard(Shingles(original, ShingleSize), Shingles(strings.ToUpper(original)+"!!", ShingleSize)))
+
+	edited := strings.Replace(original, "FPS games", "FPS and MOBA games", 1)
+	similarity := Jaccard(Shingles(original, ShingleSize), Shingles(edited, ShingleSize))
+	assert.Greater(t, similarity, 0.7)
+	assert.Less(t, similarity, 1.0)
+
+	unrelated := "键盘的轴体偏重，长时间打字容易疲劳。"
+	assert.Equal(t, 0.0, Jaccard(Shingles(original, ShingleSize), Shingles(unrelated, ShingleSize)))
+	assert.Len(t, Shingles("短", ShingleSize), 1)
+}
@@ -0,0 +1,49 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/models"
+)
+
+// RateCheck 检查用户在时间窗口内提交的评测数，已删除的评测也计入
+type RateCheck struct {
+	db     *mongo.Database
+	limit  int
+	window time.Duration
+}
+
+// NewRateCheck 创建提交频率检查
+func NewRateCheck(db *mongo.Database, limit int, window time.Duration) *RateCheck {
+	return &RateCheck{db: db, limit: limit, window: window}
+}
+
+// Name 规则名称
+func (c *RateCheck) Name() string {
+	return "rate"
+}
+
+// Check 窗口内已有limit篇评测时标记
+func (c *RateCheck) Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error) {
+	count, err := c.db.Collection(models.ReviewsCollection).CountDocuments(ctx, bson.M{
+		"_id":       bson.M{"$ne": submission.ReviewID},
+		"userId":    submission.UserID,
+		"createdAt": bson.M{"$gte": time.Now().Add(-c.window)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int(count) < c.limit {
+		return nil, nil
+	}
+	return []models.ModerationFlag{{
+		Code:   "rate",
+		Detail: fmt.Sprintf("%s内已提交%d篇评测", c.window, count),
+		Score:  30,
+	}}, nil
+}
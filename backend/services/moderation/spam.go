@@ -0,0 +1,115 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"project/backend/models"
+)
+
+var (
+	linkPattern    = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	emailPattern   = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.-]+`)
+	phonePattern   = regexp.MustCompile(`\d[\d -]{9,}\d`)
+	contactPattern = regexp.MustCompile(`(?i)(微信|vx|wechat|whatsapp|telegram|qq)\s*[:：]?\s*[\w-]{5,}`)
+)
+
+// SpamCheck 链接和垃圾内容的启发式检查
+type SpamCheck struct {
+	maxLinks int
+}
+
+// NewSpamCheck 创建垃圾内容检查
+func NewSpamCheck(maxLinks int) *SpamCheck {
+	return &SpamCheck{maxLinks: maxLinks}
+}
+
+// Name 规则名称
+func (c *SpamCheck) Name() string {
+	return "spam"
+}
+
+// Check 检查链接数量、联系方式、重复字符和全大写
+func (c *SpamCheck) Check(ctx context.Context, submission *Submission) ([]models.ModerationFlag, error) {
+	text := submission.Text()
+	var flags []models.ModerationFlag
+
+	if links := linkPattern.FindAllString(text, -1); len(links) > c.maxLinks {
+		flags = append(flags, models.ModerationFlag{
+			Code:   "links",
+			Detail: fmt.Sprintf("包含%d个链接", len(links)),
+			Score:  30,
+		})
+	}
+	if emailPattern.MatchString(text) || phonePattern.MatchString(text) || contactPattern.MatchString(text) {
+		flags = append(flags, models.ModerationFlag{
+			Code:   "contact",
+			Detail: "包含邮箱、电话或即时通讯账号",
+			Score:  20,
+		})
+	}
+	if longestRun(text) >= 8 || lowDiversity(text) {
+		flags = append(flags, models.ModerationFlag{
+			Code:   "repetition",
+			Detail: "大量重复的字符或词语",
+			Score:  15,
+		})
+	}
+	if shouting(text) {
+		flags = append(flags, models.ModerationFlag{
+			Code:   "shouting",
+			Detail: "大部分字母为大写",
+			Score:  10,
+		})
+	}
+	return flags, nil
+}
+
+// longestRun 同一个非空白字符连续出现的最大次数
+func longestRun(text string) int {
+	longest, current := 0, 0
+	var previous rune
+	for _, r := range text {
+		if r == previous && !unicode.IsSpace(r) {
+			current++
+		} else {
+			current = 1
+		}
+		previous = r
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// lowDiversity 词数较多但不同的词不到30%
+func lowDiversity(text string) bool {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) < 30 {
+		return false
+	}
+	unique := make(map[string]bool, len(words))
+	for _, word := range words {
+		unique[word] = true
+	}
+	return float64(len(unique)) < 0.3*float64(len(words))
+}
+
+// shouting 拉丁字母不少于20个且70%以上为大写
+func shouting(text string) bool {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return letters >= 20 && float64(upper) > 0.7*float64(letters)
+}
@@ -0,0 +1,405 @@
+package order
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/order"
+)
+
+// 统计粒度
+const (
+	StatsIntervalDay   = "day"
+	StatsIntervalWeek  = "week"
+	StatsIntervalMonth = "month"
+)
+
+const (
+	// dateLayout 查询和统计参数的日期格式
+	dateLayout = "2006-01-02"
+	// defaultStatsDays 未指定起始日期时统计的天数
+	defaultStatsDays = 30
+	// maxStatsDays 单次统计允许的最大天数
+	maxStatsDays = 366
+)
+
+// statsPeriodFormats 各统计粒度对应的$dateToString格式，按周统计使用ISO周
+var statsPeriodFormats = map[string]string{
+	StatsIntervalDay:   "%Y-%m-%d",
+	StatsIntervalWeek:  "%G-W%V",
+	StatsIntervalMonth: "%Y-%m",
+}
+
+// GetOrderByID 按ID获取订单，不校验所属用户，仅供管理员使用
+func (s *Service) GetOrderByID(ctx context.Context, orderID primitive.ObjectID) (*models.Order, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	var o models.Order
+	err := s.db.Collection(models.OrdersCollection).FindOne(ctx, bson.M{"_id": orderID}).Decode(&o)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("订单不存在")
+		}
+		return nil, errors.NewInternalServerError("获取订单失败")
+	}
+	return &o, nil
+}
+
+// SearchOrders 管理员按状态、用户、订单号和下单日期查询订单
+func (s *Service) SearchOrders(ctx context.Context, req order.AdminOrderListRequest) ([]models.Order, int64, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, 0, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	filter, err := buildOrderFilter(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.M{"createdAt": -1})
+
+	cursor, err := s.db.Collection(models.OrdersCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取订单列表失败")
+	}
+	defer cursor.Close(ctx)
+
+	orders := []models.Order{}
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, 0, errors.NewInternalServerError("解析订单列表失败")
+	}
+
+	total, err := s.db.Collection(models.OrdersCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取订单数量失败")
+	}
+	return orders, total, nil
+}
+
+// BulkUpdateStatus 管理员批量变更订单状态
+// 每个订单单独校验和更新，单个订单失败不影响其他订单
+func (s *Service) BulkUpdateStatus(ctx context.Context, req order.BulkUpdateStatusRequest) (*order.BulkUpdateStatusResponse, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	status := models.OrderStatusEnum(req.Status)
+	response := &order.BulkUpdateStatusResponse{
+		Updated: []string{},
+		Failed:  []order.BulkUpdateFailure{},
+	}
+	for _, id := range req.OrderIDs {
+		if err := s.bulkUpdateOne(ctx, id, status, req.CancelReason); err != nil {
+			response.Failed = append(response.Failed, order.BulkUpdateFailure{OrderID: id, Error: err.Error()})
+			continue
+		}
+		response.Updated = append(response.Updated, id)
+	}
+	return response, nil
+}
+
+func (s *Service) bulkUpdateOne(ctx context.Context, id string, status models.OrderStatusEnum, cancelReason string) error {
+	orderID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.NewBadRequestError("无效的订单ID")
+	}
+	o, err := s.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	_, err = s.transitionOrder(ctx, o, status, cancelReason, nil)
+	return err
+}
+
+// UpdateShipment 填写或修改物流信息
+// 已支付的订单同时标记为已发货；已发货、已送达的订单只更新物流信息
+func (s *Service) UpdateShipment(ctx context.Context, orderID primitive.ObjectID, req order.UpdateShipmentRequest) (*models.Order, error) {
+	o, err := s.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := bson.M{
+		"shippingInfo.carrier":        strings.TrimSpace(req.Carrier),
+		"shippingInfo.trackingNumber": strings.TrimSpace(req.TrackingNumber),
+	}
+
+	switch o.Status {
+	case models.OrderStatusPaid:
+		return s.transitionOrder(ctx, o, models.OrderStatusShipped, "", shipment)
+	case models.OrderStatusShipped, models.OrderStatusDelivered:
+	default:
+		return nil, errors.NewBadRequestError("只有已支付或已发货的订单可以填写物流信息")
+	}
+
+	shipment["updatedAt"] = time.Now()
+	var updated models.Order
+	err = s.db.Collection(models.OrdersCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": orderID, "status": o.Status},
+		bson.M{"$set": shipment},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewBadRequestError("订单状态已变更，请刷新后重试")
+		}
+		return nil, errors.NewInternalServerError("更新物流信息失败")
+	}
+	return &updated, nil
+}
+
+// AddOrderNote 添加内部备注，备注只追加不修改
+func (s *Service) AddOrderNote(ctx context.Context, authorID, orderID primitive.ObjectID, content string) (*models.Order, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.NewBadRequestError("备注内容不能为空")
+	}
+
+	now := time.Now()
+	note := models.OrderNote{
+		ID:        primitive.NewObjectID(),
+		AuthorID:  authorID,
+		Content:   content,
+		CreatedAt: now,
+	}
+
+	var updated models.Order
+	err := s.db.Collection(models.OrdersCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": orderID},
+		bson.M{
+			"$push": bson.M{"adminNotes": note},
+			"$set":  bson.M{"updatedAt": now},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("订单不存在")
+		}
+		return nil, errors.NewInternalServerError("添加备注失败")
+	}
+	return &updated, nil
+}
+
+// statsRow 聚合结果
+type statsRow struct {
+	ID struct {
+		Period   string `bson:"period"`
+		Currency string `bson:"currency"`
+	} `bson:"_id"`
+	OrderCount     int64 `bson:"orderCount"`
+	Revenue        int64 `bson:"revenue"`
+	RefundedAmount int64 `bson:"refundedAmount"`
+}
+
+// OrderStats 按支付时间统计营收、订单数和客单价
+// 未支付和已取消的订单没有支付时间，不计入统计
+func (s *Service) OrderStats(ctx context.Context, req order.OrderStatsRequest) (*order.OrderStatsResponse, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = StatsIntervalDay
+	}
+	format, ok := statsPeriodFormats[interval]
+	if !ok {
+		return nil, errors.NewBadRequestError("无效的统计粒度")
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.NewBadRequestError("无效的时区")
+		}
+	}
+
+	from, to, err := statsRange(req.From, req.To, loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"paidAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"period": bson.M{"$dateToString": bson.M{
+					"format":   format,
+					"date":     "$paidAt",
+					"timezone": loc.String(),
+				}},
+				"currency": "$currency",
+			},
+			"orderCount":     bson.M{"$sum": 1},
+			"revenue":        bson.M{"$sum": "$total"},
+			"refundedAmount": bson.M{"$sum": "$refundedAmount"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.period", Value: 1}, {Key: "_id.currency", Value: 1}}}},
+	}
+
+	cursor, err := s.db.Collection(models.OrdersCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取订单统计失败")
+	}
+	defer cursor.Close(ctx)
+
+	var rows []statsRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, errors.NewInternalServerError("解析订单统计失败")
+	}
+
+	series, totals := summarizeStats(rows)
+	return &order.OrderStatsResponse{
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Totals:   totals,
+		Series:   series,
+	}, nil
+}
+
+// buildOrderFilter 将查询条件转换为Mongo过滤条件
+func buildOrderFilter(req order.AdminOrderListRequest) (bson.M, error) {
+	filter := bson.M{}
+
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+	if req.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(req.UserID)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的用户ID")
+		}
+		filter["userId"] = userID
+	}
+	if number := strings.TrimSpace(req.OrderNumber); number != "" {
+		// 前缀匹配可以使用订单号索引
+		filter["orderNumber"] = bson.M{"$regex": "^" + regexp.QuoteMeta(number)}
+	}
+
+	createdAt := bson.M{}
+	if req.From != "" {
+		from, err := time.Parse(dateLayout, req.From)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的起始日期")
+		}
+		createdAt["$gte"] = from
+	}
+	if req.To != "" {
+		to, err := time.Parse(dateLayout, req.To)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的结束日期")
+		}
+		createdAt["$lt"] = to.AddDate(0, 0, 1)
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+	return filter, nil
+}
+
+// statsRange 解析统计的起止日期，返回[from, to)
+// 结束日期包含当天，默认统计到今天；起始日期默认为结束日期前30天
+func statsRange(fromStr, toStr string, loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if toStr != "" {
+		day, err := time.ParseInLocation(dateLayout, toStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewBadRequestError("无效的结束日期")
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -defaultStatsDays)
+	if fromStr != "" {
+		day, err := time.ParseInLocation(dateLayout, fromStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.NewBadRequestError("无效的起始日期")
+		}
+		from = day
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.NewBadRequestError("起始日期不能晚于结束日期")
+	}
+	if to.Sub(from) > maxStatsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.NewBadRequestError("统计时间范围不能超过一年")
+	}
+	return from, to, nil
+}
+
+// summarizeStats 计算各时间段的净营收和客单价，并按币种汇总
+// 不同币种的金额不能相加，总计按币种分别给出
+func summarizeStats(rows []statsRow) ([]order.OrderStatsBucket, []order.OrderStatsBucket) {
+	series := make([]order.OrderStatsBucket, 0, len(rows))
+	totals := []order.OrderStatsBucket{}
+	totalIndex := map[string]int{}
+
+	for _, row := range rows {
+		series = append(series, newStatsBucket(row.ID.Period, row.ID.Currency, row.OrderCount, row.Revenue, row.RefundedAmount))
+
+		i, ok := totalIndex[row.ID.Currency]
+		if !ok {
+			i = len(totals)
+			totalIndex[row.ID.Currency] = i
+			totals = append(totals, order.OrderStatsBucket{Currency: row.ID.Currency})
+		}
+		totals[i].OrderCount += row.OrderCount
+		totals[i].Revenue += row.Revenue
+		totals[i].RefundedAmount += row.RefundedAmount
+	}
+
+	for i := range totals {
+		totals[i] = newStatsBucket("", totals[i].Currency, totals[i].OrderCount, totals[i].Revenue, totals[i].RefundedAmount)
+	}
+	return series, totals
+}
+
+func newStatsBucket(period, currency string, count, revenue, refunded int64) order.OrderStatsBucket {
+	bucket := order.OrderStatsBucket{
+		Period:         period,
+		Currency:       currency,
+		OrderCount:     count,
+		Revenue:        revenue,
+		RefundedAmount: refunded,
+		NetRevenue:     revenue - refunded,
+	}
+	if count > 0 {
+		bucket.AverageOrderValue = roundDiv(revenue, count)
+	}
+	return bucket
+}
@@ -0,0 +1,133 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/tests/testutil"
+	"project/backend/types/order"
+)
+
+func TestBuildOrderFilter(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	filter, err := buildOrderFilter(order.AdminOrderListRequest{
+		Status:      "paid",
+		UserID:      userID.Hex(),
+		OrderNumber: "20250101-",
+		From:        "2025-01-01",
+		To:          "2025-01-31",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "paid", filter["status"])
+	assert.Equal(t, userID, filter["userId"])
+	assert.Equal(t, bson.M{"$regex": `^20250101-`}, filter["orderNumber"])
+	assert.Equal(t, bson.M{
+		"$gte": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"$lt":  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, filter["createdAt"])
+
+	_, err = buildOrderFilter(order.AdminOrderListRequest{UserID: "bad"})
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+	_, err = buildOrderFilter(order.AdminOrderListRequest{From: "01/01/2025"})
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestStatsRange(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	now := time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC) // 上海时间已是3月11日
+
+	from, to, err := statsRange("", "", shanghai, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 12, 0, 0, 0, 0, shanghai), to)
+	assert.Equal(t, to.AddDate(0, 0, -defaultStatsDays), from)
+
+	from, to, err = statsRange("2025-01-01", "2025-01-31", time.UTC, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = statsRange("2025-02-01", "2025-01-31", time.UTC, now)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+	_, _, err = statsRange("2023-01-01", "2025-01-31", time.UTC, now)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestSummarizeStats(t *testing.T) {
+	row := func(period, currency string, count, revenue, refunded int64) statsRow {
+		r := statsRow{OrderCount: count, Revenue: revenue, RefundedAmount: refunded}
+		r.ID.Period = period
+		r.ID.Currency = currency
+		return r
+	}
+
+	series, totals := summarizeStats([]statsRow{
+		row("2025-01-01", "CNY", 2, 30000, 0),
+		row("2025-01-01", "USD", 1, 5000, 5000),
+		row("2025-01-02", "CNY", 1, 10001, 1000),
+	})
+
+	require.Len(t, series, 3)
+	assert.Equal(t, int64(15000), series[0].AverageOrderValue)
+	assert.Equal(t, int64(0), series[1].NetRevenue)
+
+	require.Len(t, totals, 2)
+	assert.Equal(t, order.OrderStatsBucket{
+		Currency:          "CNY",
+		OrderCount:        3,
+		Revenue:           40001,
+		RefundedAmount:    1000,
+		NetRevenue:        39001,
+		AverageOrderValue: 13334,
+	}, totals[0])
+	assert.Equal(t, "USD", totals[1].Currency)
+}
+
+func TestAdminCanUpdateOtherUsersOrder(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	skuID := seedSKU(t, db, 5)
+
+	created, err := svc.CreateOrder(ctx, primitive.NewObjectID(), orderRequest(skuID, 1))
+	require.NoError(t, err)
+
+	// 其他普通用户查不到该订单
+	_, err = svc.UpdateOrderStatus(ctx, primitive.NewObjectID(), created.ID, string(models.RoleUser), models.OrderStatusCancelled, "")
+	assert.Equal(t, errors.NotFound, errors.GetErrorCode(err))
+
+	result, err := svc.BulkUpdateStatus(ctx, order.BulkUpdateStatusRequest{
+		OrderIDs: []string{created.ID.Hex(), primitive.NewObjectID().Hex()},
+		Status:   string(models.OrderStatusPaid),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{created.ID.Hex()}, result.Updated)
+	assert.Len(t, result.Failed, 1)
+
+	shipped, err := svc.UpdateShipment(ctx, created.ID, order.UpdateShipmentRequest{Carrier: "SF", TrackingNumber: "SF123"})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusShipped, shipped.Status)
+	assert.Equal(t, "SF123", shipped.ShippingInfo.TrackingNumber)
+
+	noted, err := svc.AddOrderNote(ctx, primitive.NewObjectID(), created.ID, "客户要求周末送达")
+	require.NoError(t, err)
+	require.Len(t, noted.AdminNotes, 1)
+
+	stats, err := svc.OrderStats(ctx, order.OrderStatsRequest{})
+	require.NoError(t, err)
+	require.Len(t, stats.Totals, 1)
+	assert.Equal(t, int64(1), stats.Totals[0].OrderCount)
+	assert.Equal(t, created.Total, stats.Totals[0].Revenue)
+}
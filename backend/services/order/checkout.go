@@ -0,0 +1,115 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/promotion"
+)
+
+// DefaultReservationTTL 未支付订单的默认库存预留时长
+const DefaultReservationTTL = 30 * time.Minute
+
+// checkout 在一个事务中预留库存、保存订单和预留记录、占用优惠券次数，并从购物车移除已购买的商品
+// 任意一步失败整体回滚，并发抢购最后一件商品时只有一个订单能成功
+// 注意：事务要求MongoDB以副本集方式运行
+func (s *Service) checkout(ctx context.Context, order *models.Order, quote *promotion.Quote) error {
+	expiresAt := order.CreatedAt.Add(s.reservationTTL)
+	order.ReservationExpiresAt = &expiresAt
+
+	reservation := &models.InventoryReservation{
+		ID:        primitive.NewObjectID(),
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Items:     make([]models.ReservedItem, 0, len(order.Items)),
+		Status:    models.ReservationStatusReserved,
+		ExpiresAt: expiresAt,
+		CreatedAt: order.CreatedAt,
+		UpdatedAt: order.CreatedAt,
+	}
+	skuIDs := make([]primitive.ObjectID, 0, len(order.Items))
+	for _, item := range order.Items {
+		reservation.Items = append(reservation.Items, models.ReservedItem{SKUID: item.SKUID, Quantity: item.Quantity})
+		skuIDs = append(skuIDs, item.SKUID)
+	}
+
+	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		if err := s.productService.ReserveStock(sc, reservation.Items); err != nil {
+			return err
+		}
+		if _, err := s.db.Collection(models.OrdersCollection).InsertOne(sc, order); err != nil {
+			return err
+		}
+		if _, err := s.db.Collection(models.InventoryReservationsCollection).InsertOne(sc, reservation); err != nil {
+			return err
+		}
+		if quote != nil {
+			if err := s.promotionService.Redeem(sc, quote, order.UserID, order.ID); err != nil {
+				return err
+			}
+		}
+		if s.cartService != nil {
+			return s.cartService.RemoveItems(sc, order.UserID.Hex(), skuIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(*errors.AppError); ok {
+			return err
+		}
+		return errors.NewInternalServerError("创建订单失败: " + err.Error())
+	}
+	return nil
+}
+
+// releaseReservation 归还订单预留的库存，需在事务中调用
+// 没有预留记录或已归还、已支付时不做处理
+func (s *Service) releaseReservation(sc mongo.SessionContext, orderID primitive.ObjectID) error {
+	now := time.Now()
+
+	var reservation models.InventoryReservation
+	err := s.db.Collection(models.InventoryReservationsCollection).FindOneAndUpdate(sc,
+		bson.M{"orderId": orderID, "status": models.ReservationStatusReserved},
+		bson.M{"$set": bson.M{
+			"status":     models.ReservationStatusReleased,
+			"releasedAt": now,
+			"updatedAt":  now,
+		}},
+	).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	return s.productService.ReleaseStock(sc, reservation.Items)
+}
+
+// couponCode 订单使用的券码，没有用券时为空
+func couponCode(quote *promotion.Quote) string {
+	if quote == nil {
+		return ""
+	}
+	return quote.Coupon.Code
+}
+
+// withTransaction 在事务中执行fn，遇到写冲突等临时错误时由驱动自动重试
+func (s *Service) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
@@ -0,0 +1,152 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/tests/testutil"
+	"project/backend/types/order"
+)
+
+// seedSKU 创建一个设备和指定库存的SKU
+func seedSKU(t *testing.T, db *mongo.Database, stock int) primitive.ObjectID {
+	ctx := context.Background()
+	now := time.Now()
+
+	device := models.HardwareDevice{
+		ID:        primitive.NewObjectID(),
+		Name:      "G Pro",
+		Brand:     "Logitech",
+		Type:      models.DeviceTypeMouse,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	_, err := db.Collection(models.DevicesCollection).InsertOne(ctx, device)
+	require.NoError(t, err)
+
+	sku := models.ProductSKU{
+		ID:        primitive.NewObjectID(),
+		DeviceID:  device.ID,
+		Code:      "GPRO-" + primitive.NewObjectID().Hex(),
+		Price:     79900,
+		Currency:  models.DefaultCurrency,
+		Stock:     stock,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	_, err = db.Collection(models.ProductSKUsCollection).InsertOne(ctx, sku)
+	require.NoError(t, err)
+	return sku.ID
+}
+
+func orderRequest(skuID primitive.ObjectID, quantity int) order.CreateOrderRequest {
+	return order.CreateOrderRequest{
+		Items:         []order.OrderItemRequest{{SKUID: skuID.Hex(), Quantity: quantity}},
+		PaymentMethod: string(models.PaymentMethodAlipay),
+		ShippingInfo:  order.ShippingInfoRequest{Name: "test", Phone: "13800000000", Address: "test", Country: "CN"},
+	}
+}
+
+func skuStock(t *testing.T, db *mongo.Database, skuID primitive.ObjectID) int {
+	var sku models.ProductSKU
+	require.NoError(t, db.Collection(models.ProductSKUsCollection).FindOne(context.Background(), bson.M{"_id": skuID}).Decode(&sku))
+	return sku.Stock
+}
+
+// TestCheckoutConcurrentLastUnit 完整下单流程需要副本集，运行方式见testutil.SetupTransactionTest
+// 库存扣减条件的并发测试见product.TestReserveStockConcurrentLastUnit，单机MongoDB即可运行
+func TestCheckoutConcurrentLastUnit(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	skuID := seedSKU(t, db, 1)
+
+	const buyers = 8
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		failures  []error
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.CreateOrder(ctx, primitive.NewObjectID(), orderRequest(skuID, 1))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, err)
+				return
+			}
+			succeeded++
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	for _, err := range failures {
+		assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err), err.Error())
+	}
+	assert.Equal(t, 0, skuStock(t, db, skuID))
+
+	orders, err := db.Collection(models.OrdersCollection).CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), orders)
+
+	reservations, err := db.Collection(models.InventoryReservationsCollection).CountDocuments(ctx, bson.M{"status": models.ReservationStatusReserved})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), reservations)
+}
+
+func TestCheckoutClearsCartAndCancelReleasesStock(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cartSvc := cart.NewService(db)
+	svc := NewService(db, cartSvc, nil, config.OrderConfig{})
+	skuID := seedSKU(t, db, 3)
+	otherSKU := seedSKU(t, db, 3)
+	userID := primitive.NewObjectID()
+
+	require.NoError(t, cartSvc.AddToCart(ctx, userID.Hex(), skuID.Hex(), 2))
+	require.NoError(t, cartSvc.AddToCart(ctx, userID.Hex(), otherSKU.Hex(), 1))
+
+	created, err := svc.CreateOrder(ctx, userID, orderRequest(skuID, 2))
+	require.NoError(t, err)
+	require.NotNil(t, created.ReservationExpiresAt)
+	assert.WithinDuration(t, created.CreatedAt.Add(DefaultReservationTTL), *created.ReservationExpiresAt, time.Second)
+	assert.Equal(t, int64(159800), created.Subtotal)
+	assert.Equal(t, 1, skuStock(t, db, skuID))
+
+	// 只移除已购买的商品
+	userCart, err := cartSvc.GetCart(ctx, userID.Hex())
+	require.NoError(t, err)
+	require.Len(t, userCart.Items, 1)
+	assert.Equal(t, otherSKU, userCart.Items[0].SKUID)
+
+	cancelled, err := svc.UpdateOrderStatus(ctx, userID, created.ID, string(models.RoleUser), models.OrderStatusCancelled, "不想要了")
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, cancelled.Status)
+	assert.Equal(t, 3, skuStock(t, db, skuID))
+
+	var reservation models.InventoryReservation
+	require.NoError(t, db.Collection(models.InventoryReservationsCollection).FindOne(ctx, bson.M{"orderId": created.ID}).Decode(&reservation))
+	assert.Equal(t, models.ReservationStatusReleased, reservation.Status)
+}
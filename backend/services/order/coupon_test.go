@@ -0,0 +1,104 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/config"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/tests/testutil"
+)
+
+func seedCoupon(t *testing.T, db *mongo.Database, coupon models.Coupon) {
+	now := time.Now()
+	coupon.ID = primitive.NewObjectID()
+	coupon.Active = true
+	coupon.CreatedAt = now
+	coupon.UpdatedAt = now
+	_, err := db.Collection(models.CouponsCollection).InsertOne(context.Background(), coupon)
+	require.NoError(t, err)
+}
+
+func couponUsedCount(t *testing.T, db *mongo.Database, code string) int {
+	var coupon models.Coupon
+	require.NoError(t, db.Collection(models.CouponsCollection).FindOne(context.Background(), bson.M{"code": code}).Decode(&coupon))
+	return coupon.UsedCount
+}
+
+func TestCheckoutCouponUsageLimitUnderConcurrency(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	skuID := seedSKU(t, db, 20)
+	seedCoupon(t, db, models.Coupon{Code: "LAUNCH", Type: models.CouponTypePercentage, Value: 10, UsageLimit: 2})
+
+	const buyers = 6
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := orderRequest(skuID, 1)
+			req.CouponCode = "launch"
+			_, err := svc.CreateOrder(ctx, primitive.NewObjectID(), req)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 2, couponUsedCount(t, db, "LAUNCH"))
+	// 失败的订单整体回滚，不占用库存
+	assert.Equal(t, 18, skuStock(t, db, skuID))
+}
+
+func TestCheckoutCouponPerUserLimitAndCancelRelease(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	skuID := seedSKU(t, db, 10)
+	seedCoupon(t, db, models.Coupon{Code: "WELCOME", Type: models.CouponTypeFixed, Value: 5000, Currency: models.DefaultCurrency, PerUserLimit: 1})
+	userID := primitive.NewObjectID()
+
+	req := orderRequest(skuID, 1)
+	req.CouponCode = "WELCOME"
+	created, err := svc.CreateOrder(ctx, userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5000), created.Discount)
+	assert.Equal(t, int64(5000), created.Items[0].Discount)
+	assert.Equal(t, "WELCOME", created.CouponCode)
+	assert.Equal(t, created.Subtotal+created.ShippingFee+created.Tax-created.Discount, created.Total)
+
+	_, err = svc.CreateOrder(ctx, userID, req)
+	require.Error(t, err)
+
+	// 取消订单后归还次数，可以再次使用
+	_, err = svc.UpdateOrderStatus(ctx, userID, created.ID, string(models.RoleUser), models.OrderStatusCancelled, "重新下单")
+	require.NoError(t, err)
+	assert.Equal(t, 0, couponUsedCount(t, db, "WELCOME"))
+
+	_, err = svc.CreateOrder(ctx, userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, couponUsedCount(t, db, "WELCOME"))
+}
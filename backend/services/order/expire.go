@@ -0,0 +1,132 @@
+package order
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/scheduler"
+)
+
+const (
+	// AutoCancelJobName 自动取消任务名，多实例部署时按任务名选主
+	AutoCancelJobName = "order-auto-cancel"
+	// AutoCancelReason 超时未支付自动取消的原因
+	AutoCancelReason = "超时未支付，订单已自动取消"
+	// DefaultAutoCancelInterval 默认检查间隔
+	DefaultAutoCancelInterval = time.Minute
+	// autoCancelBatchSize 每次最多取消的订单数，剩余的留到下一轮
+	autoCancelBatchSize = 100
+)
+
+// Notifier 订单通知
+type Notifier interface {
+	SendOrderCancelledEmail(to, orderNumber, reason string) error
+	SendOrderPaidEmail(to, orderNumber string, invoicePDF []byte) error
+}
+
+// SetNotifier 设置订单通知，未设置时不发送通知
+func (s *Service) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// AutoCancelJob 定期取消超时未支付订单的定时任务
+func (s *Service) AutoCancelJob(interval time.Duration) scheduler.Job {
+	if interval <= 0 {
+		interval = DefaultAutoCancelInterval
+	}
+	return scheduler.Job{
+		Name:     AutoCancelJobName,
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			cancelled, err := s.CancelExpiredOrders(ctx, now)
+			if cancelled > 0 {
+				log.Printf("已自动取消%d个超时未支付的订单", cancelled)
+			}
+			return err
+		},
+	}
+}
+
+// CancelExpiredOrders 取消支付期限已过的待支付订单，归还库存和优惠券并通知用户
+// 支付期限为库存预留到期时间，没有预留记录的旧订单按下单时间加预留时长计算
+func (s *Service) CancelExpiredOrders(ctx context.Context, now time.Time) (int, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return 0, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	filter := bson.M{
+		"status": models.OrderStatusPending,
+		"$or": []bson.M{
+			{"reservationExpiresAt": bson.M{"$lte": now}},
+			{
+				"reservationExpiresAt": bson.M{"$exists": false},
+				"createdAt":            bson.M{"$lte": now.Add(-s.reservationTTL)},
+			},
+		},
+	}
+	cursor, err := s.db.Collection(models.OrdersCollection).Find(ctx, filter,
+		options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(autoCancelBatchSize))
+	if err != nil {
+		return 0, errors.NewInternalServerError("获取超时订单失败")
+	}
+	defer cursor.Close(ctx)
+
+	var orders []models.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return 0, errors.NewInternalServerError("解析超时订单失败")
+	}
+
+	cancelled := 0
+	for i := range orders {
+		updated, err := s.transitionOrder(ctx, &orders[i], models.OrderStatusCancelled, AutoCancelReason, nil)
+		if err != nil {
+			// 支付回调可能在此期间将订单标记为已支付，跳过即可
+			log.Printf("自动取消订单%s失败: %v", orders[i].OrderNumber, err)
+			continue
+		}
+		cancelled++
+		s.notifyCancelled(ctx, updated)
+	}
+	return cancelled, nil
+}
+
+// notifyCancelled 发送取消通知，失败只记录日志
+func (s *Service) notifyCancelled(ctx context.Context, o *models.Order) {
+	if s.notifier == nil {
+		return
+	}
+	to := s.recipientEmail(ctx, o)
+	if to == "" {
+		return
+	}
+
+	if err := s.notifier.SendOrderCancelledEmail(to, o.OrderNumber, o.CancelReason); err != nil {
+		log.Printf("发送订单%s取消通知失败: %v", o.OrderNumber, err)
+	}
+}
+
+// recipientEmail 通知的收件人，优先使用收货信息中的邮箱
+func (s *Service) recipientEmail(ctx context.Context, o *models.Order) string {
+	if o.ShippingInfo.Email != "" {
+		return o.ShippingInfo.Email
+	}
+
+	var user struct {
+		Email string `bson:"email"`
+	}
+	err := s.db.Collection(models.UsersCollection).FindOne(ctx,
+		bson.M{"_id": o.UserID},
+		options.FindOne().SetProjection(bson.M{"email": 1}),
+	).Decode(&user)
+	if err != nil {
+		return ""
+	}
+	return user.Email
+}
@@ -0,0 +1,74 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/config"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/services/scheduler"
+	"project/backend/tests/testutil"
+)
+
+type recordingNotifier struct {
+	sent []string
+	paid []string
+}
+
+func (n *recordingNotifier) SendOrderCancelledEmail(to, orderNumber, reason string) error {
+	n.sent = append(n.sent, to+" "+orderNumber)
+	return nil
+}
+
+func (n *recordingNotifier) SendOrderPaidEmail(to, orderNumber string, invoicePDF []byte) error {
+	n.paid = append(n.paid, to+" "+orderNumber)
+	return nil
+}
+
+func TestAutoCancelExpiredOrders(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{ReservationTTL: 30 * time.Minute})
+	notifier := &recordingNotifier{}
+	svc.SetNotifier(notifier)
+	skuID := seedSKU(t, db, 5)
+
+	req := orderRequest(skuID, 2)
+	req.ShippingInfo.Email = "buyer@example.com"
+	created, err := svc.CreateOrder(ctx, primitive.NewObjectID(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, skuStock(t, db, skuID))
+
+	clock := scheduler.NewFakeClock(created.CreatedAt)
+	s := scheduler.New(clock, nil)
+	job := svc.AutoCancelJob(time.Minute)
+
+	// 未到支付期限不取消
+	clock.Advance(29 * time.Minute)
+	s.RunOnce(ctx, job)
+	o, err := svc.GetOrderByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusPending, o.Status)
+
+	clock.Advance(2 * time.Minute)
+	s.RunOnce(ctx, job)
+	o, err = svc.GetOrderByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, o.Status)
+	assert.Equal(t, AutoCancelReason, o.CancelReason)
+	assert.Equal(t, 5, skuStock(t, db, skuID))
+	assert.Equal(t, []string{"buyer@example.com " + created.OrderNumber}, notifier.sent)
+
+	// 已取消的订单不会重复处理
+	clock.Advance(time.Minute)
+	s.RunOnce(ctx, job)
+	assert.Len(t, notifier.sent, 1)
+}
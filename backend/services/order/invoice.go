@@ -0,0 +1,41 @@
+package order
+
+import (
+	"context"
+	"log"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/invoice"
+)
+
+// RenderDocument 渲染订单的发票或发货单PDF，只有已支付过的订单才能开具
+func (s *Service) RenderDocument(o *models.Order, kind, locale string) ([]byte, error) {
+	if o.PaidAt == nil {
+		return nil, errors.NewBadRequestError("订单未支付")
+	}
+	data, ok := s.documents.Render(kind, o, locale)
+	if !ok {
+		return nil, errors.NewBadRequestError("不支持的文档类型")
+	}
+	return data, nil
+}
+
+// notifyPaid 发送支付成功通知并附带发票，失败只记录日志
+func (s *Service) notifyPaid(ctx context.Context, o *models.Order) {
+	if s.notifier == nil {
+		return
+	}
+	to := s.recipientEmail(ctx, o)
+	if to == "" {
+		return
+	}
+
+	var invoicePDF []byte
+	if s.documents != nil && o.PaidAt != nil {
+		invoicePDF = s.documents.Invoice(o, invoice.EmailLocale)
+	}
+	if err := s.notifier.SendOrderPaidEmail(to, o.OrderNumber, invoicePDF); err != nil {
+		log.Printf("发送订单%s支付通知失败: %v", o.OrderNumber, err)
+	}
+}
@@ -0,0 +1,86 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/models"
+)
+
+// orderNumberDateLayout 订单号的日期部分
+const orderNumberDateLayout = "20060102"
+
+// nextOrderNumber 按天递增的序号生成订单号，格式为 年月日-序号+校验位，如 20250101-000013
+// 序号在事务外分配，下单失败会留下空号但不会重复
+func (s *Service) nextOrderNumber(ctx context.Context, now time.Time) (string, error) {
+	day := now.Format(orderNumberDateLayout)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	increment := func() error {
+		return s.db.Collection(models.OrderCountersCollection).FindOneAndUpdate(ctx,
+			bson.M{"_id": day},
+			bson.M{"$inc": bson.M{"seq": 1}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&counter)
+	}
+
+	err := increment()
+	// 当天的第一个订单并发创建计数器时，失败的一方重试即可
+	if mongo.IsDuplicateKeyError(err) {
+		err = increment()
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatOrderNumber(day, counter.Seq), nil
+}
+
+// formatOrderNumber 序号至少5位，末尾追加Luhn校验位，便于客服核对手工输入的订单号
+func formatOrderNumber(day string, seq int64) string {
+	serial := fmt.Sprintf("%05d", seq)
+	return fmt.Sprintf("%s-%s%d", day, serial, luhnCheckDigit(day+serial))
+}
+
+// ValidOrderNumber 校验订单号格式和校验位
+func ValidOrderNumber(number string) bool {
+	day, rest, ok := strings.Cut(number, "-")
+	if !ok || len(day) != len(orderNumberDateLayout) || len(rest) < 6 {
+		return false
+	}
+	if _, err := time.Parse(orderNumberDateLayout, day); err != nil {
+		return false
+	}
+	for _, r := range rest {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	serial, check := rest[:len(rest)-1], int(rest[len(rest)-1]-'0')
+	return luhnCheckDigit(day+serial) == check
+}
+
+// luhnCheckDigit 计算数字串的Luhn校验位
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
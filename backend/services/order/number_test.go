@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/config"
+	"project/backend/services/cart"
+	"project/backend/tests/testutil"
+)
+
+func TestFormatOrderNumber(t *testing.T) {
+	assert.Equal(t, "20250101-000013", formatOrderNumber("20250101", 1))
+	assert.True(t, ValidOrderNumber(formatOrderNumber("20250101", 1)))
+	// 序号超过5位时自动加长
+	assert.True(t, ValidOrderNumber(formatOrderNumber("20250101", 123456)))
+
+	assert.False(t, ValidOrderNumber("20250101-000014"))
+	// 相邻数字互换可以被校验位发现
+	assert.False(t, ValidOrderNumber("20250101-000103"))
+	assert.False(t, ValidOrderNumber("20250101-1234"))
+	assert.False(t, ValidOrderNumber("2025010-0000013"))
+	assert.False(t, ValidOrderNumber("20251301-000013"))
+}
+
+func TestNextOrderNumberIsUniqueUnderConcurrency(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+
+	const workers = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		numbers = map[string]bool{}
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			number, err := svc.nextOrderNumber(context.Background(), now)
+			require.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			numbers[number] = true
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, numbers, workers)
+	assert.True(t, numbers[formatOrderNumber("20250101", workers)])
+}
@@ -0,0 +1,239 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/payment"
+)
+
+// RegisterPaymentProvider 注册支付渠道，同名渠道会被覆盖
+func (s *Service) RegisterPaymentProvider(provider payment.Provider) {
+	if s.paymentProviders == nil {
+		s.paymentProviders = make(map[string]payment.Provider)
+	}
+	s.paymentProviders[provider.Name()] = provider
+}
+
+// paymentProvider 按名称获取支付渠道
+func (s *Service) paymentProvider(name string) (payment.Provider, error) {
+	provider, ok := s.paymentProviders[name]
+	if !ok {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("不支持的支付渠道: %s", name))
+	}
+	return provider, nil
+}
+
+// CreatePaymentIntent 为待支付订单创建支付意图
+func (s *Service) CreatePaymentIntent(ctx context.Context, userID, orderID primitive.ObjectID, providerName string) (*payment.Intent, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	provider, err := s.paymentProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, errors.NewBadRequestError("只有待支付的订单可以发起支付")
+	}
+
+	intent, err := provider.CreateIntent(ctx, payment.IntentRequest{
+		OrderID:     order.ID.Hex(),
+		OrderNumber: order.OrderNumber,
+		Amount:      order.Total,
+		Currency:    order.Currency,
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("创建支付失败: " + err.Error())
+	}
+
+	res, err := s.db.Collection(models.OrdersCollection).UpdateOne(ctx,
+		bson.M{"_id": order.ID, "status": models.OrderStatusPending},
+		bson.M{"$set": bson.M{
+			"paymentInfo.paymentProvider": provider.Name(),
+			"paymentInfo.intentId":        intent.ID,
+			"updatedAt":                   time.Now(),
+		}},
+	)
+	if err != nil {
+		return nil, errors.NewInternalServerError("保存支付信息失败")
+	}
+	if res.MatchedCount == 0 {
+		return nil, errors.NewBadRequestError("订单状态已变更，请刷新后重试")
+	}
+
+	return intent, nil
+}
+
+// HandlePaymentWebhook 处理支付渠道的回调
+// 签名校验通过后在事务中记录事件并更新订单，同一事件重复推送时直接返回成功
+func (s *Service) HandlePaymentWebhook(ctx context.Context, providerName string, payload []byte, header http.Header) error {
+	// 检查数据库连接
+	if s.db == nil {
+		return errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	provider, err := s.paymentProvider(providerName)
+	if err != nil {
+		return err
+	}
+
+	event, err := provider.VerifyWebhook(payload, header)
+	if err != nil {
+		if err == payment.ErrInvalidSignature {
+			return errors.NewUnauthorizedError("回调签名无效")
+		}
+		return errors.NewBadRequestError("无效的回调内容")
+	}
+
+	orderID, err := primitive.ObjectIDFromHex(event.OrderID)
+	if err != nil {
+		return errors.NewBadRequestError("回调中的订单ID无效")
+	}
+
+	record := &models.PaymentEvent{
+		ID:            primitive.NewObjectID(),
+		Provider:      provider.Name(),
+		EventID:       event.ID,
+		Type:          event.Type,
+		OrderID:       orderID,
+		IntentID:      event.IntentID,
+		TransactionID: event.TransactionID,
+		Amount:        event.Amount,
+		Currency:      event.Currency,
+		CreatedAt:     time.Now(),
+	}
+
+	// 事务可能重试，只有最终提交时订单确实由本次回调标记为已支付才发送通知
+	paid := false
+	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		paid = false
+		record.Status, record.Reason = models.PaymentEventProcessed, ""
+		// 先写入事件记录，唯一索引保证同一事件只处理一次
+		if _, err := s.db.Collection(models.PaymentEventsCollection).InsertOne(sc, record); err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case payment.EventPaymentSucceeded:
+			var reason string
+			var err error
+			paid, reason, err = s.markOrderPaid(sc, orderID, provider.Name(), event)
+			if err != nil || reason == "" {
+				return err
+			}
+			// 无法处理的事件也要返回成功，否则支付渠道会一直重试
+			record.Status, record.Reason = models.PaymentEventIgnored, reason
+			_, err = s.db.Collection(models.PaymentEventsCollection).UpdateOne(sc,
+				bson.M{"_id": record.ID},
+				bson.M{"$set": bson.M{"status": record.Status, "reason": reason}},
+			)
+			return err
+		case payment.EventPaymentFailed:
+			_, err := s.db.Collection(models.OrdersCollection).UpdateOne(sc,
+				bson.M{"_id": orderID, "status": models.OrderStatusPending},
+				bson.M{"$set": bson.M{
+					"paymentInfo.paymentStatus": "failed",
+					"updatedAt":                 time.Now(),
+				}},
+			)
+			return err
+		default:
+			// 其他事件只做记录
+			return nil
+		}
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		if _, ok := err.(*errors.AppError); ok {
+			return err
+		}
+		return errors.NewInternalServerError("处理支付回调失败: " + err.Error())
+	}
+
+	if paid {
+		// 发送邮件较慢，不阻塞对支付渠道的响应
+		go func() {
+			notifyCtx := context.Background()
+			if o, err := s.GetOrderByID(notifyCtx, orderID); err == nil {
+				s.notifyPaid(notifyCtx, o)
+			}
+		}()
+	}
+	return nil
+}
+
+// markOrderPaid 将订单标记为已支付，并把预留的库存转为正式扣除
+// 返回订单是否由本次调用变更为已支付；订单状态不允许支付时返回忽略的原因
+func (s *Service) markOrderPaid(sc mongo.SessionContext, orderID primitive.ObjectID, providerName string, event *payment.WebhookEvent) (bool, string, error) {
+	var order models.Order
+	if err := s.db.Collection(models.OrdersCollection).FindOne(sc, bson.M{"_id": orderID}).Decode(&order); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, "", errors.NewNotFoundError("订单不存在")
+		}
+		return false, "", err
+	}
+
+	if order.PaymentInfo.IntentID != "" && event.IntentID != "" && order.PaymentInfo.IntentID != event.IntentID {
+		return false, "", errors.NewBadRequestError("支付意图与订单不匹配")
+	}
+	if event.Amount != order.Total || (event.Currency != "" && event.Currency != order.Currency) {
+		return false, "", errors.NewBadRequestError("支付金额与订单金额不一致")
+	}
+
+	// 同一笔交易的其他事件，订单已经是已支付
+	if order.Status == models.OrderStatusPaid && order.PaymentInfo.TransactionID == event.TransactionID {
+		return false, "", nil
+	}
+	if !isValidStatusTransition(order.Status, models.OrderStatusPaid) {
+		// 订单已取消等情况下收到支付成功，需要人工退款
+		log.Printf("订单%s处于%s状态，收到支付成功回调%s，需要人工退款", order.OrderNumber, order.Status, event.ID)
+		return false, fmt.Sprintf("订单处于%s状态，无法标记为已支付", order.Status), nil
+	}
+
+	now := time.Now()
+	res, err := s.db.Collection(models.OrdersCollection).UpdateOne(sc,
+		bson.M{"_id": orderID, "status": order.Status},
+		bson.M{"$set": bson.M{
+			"status":                      models.OrderStatusPaid,
+			"paidAt":                      now,
+			"paymentInfo.transactionId":   event.TransactionID,
+			"paymentInfo.paymentStatus":   "success",
+			"paymentInfo.paymentProvider": providerName,
+			"updatedAt":                   now,
+		}},
+	)
+	if err != nil {
+		return false, "", err
+	}
+	if res.MatchedCount == 0 {
+		return false, "", errors.NewBadRequestError("订单状态已变更")
+	}
+
+	_, err = s.db.Collection(models.InventoryReservationsCollection).UpdateOne(sc,
+		bson.M{"orderId": orderID, "status": models.ReservationStatusReserved},
+		bson.M{"$set": bson.M{
+			"status":    models.ReservationStatusCommitted,
+			"updatedAt": now,
+		}},
+	)
+	return err == nil, "", err
+}
@@ -0,0 +1,112 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/services/payment"
+	"project/backend/tests/testutil"
+)
+
+func signedWebhook(provider *payment.FakeProvider, payload string) http.Header {
+	header := http.Header{}
+	header.Set(payment.FakeSignatureHeader, provider.SignatureHeader([]byte(payload), time.Now()))
+	return header
+}
+
+func TestPaymentWebhookMarksOrderPaidOnce(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := db.Collection(models.PaymentEventsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "eventId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	provider := payment.NewFakeProvider("test-secret")
+	svc.RegisterPaymentProvider(provider)
+
+	userID := primitive.NewObjectID()
+	created, err := svc.CreateOrder(ctx, userID, orderRequest(seedSKU(t, db, 1), 1))
+	require.NoError(t, err)
+
+	intent, err := svc.CreatePaymentIntent(ctx, userID, created.ID, payment.FakeProviderName)
+	require.NoError(t, err)
+	assert.Equal(t, created.Total, intent.Amount)
+
+	payload := fmt.Sprintf(`{"id":"evt_paid","type":%q,"data":{"orderId":%q,"intentId":%q,"transactionId":"tx_1","amount":%d,"currency":%q}}`,
+		payment.EventPaymentSucceeded, created.ID.Hex(), intent.ID, created.Total, created.Currency)
+
+	// 签名错误
+	err = svc.HandlePaymentWebhook(ctx, payment.FakeProviderName, []byte(payload), http.Header{})
+	assert.Equal(t, errors.Unauthorized, errors.GetErrorCode(err))
+
+	// 重复推送同一事件只处理一次
+	for i := 0; i < 2; i++ {
+		require.NoError(t, svc.HandlePaymentWebhook(ctx, payment.FakeProviderName, []byte(payload), signedWebhook(provider, payload)))
+	}
+
+	paid, err := svc.GetOrder(ctx, userID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusPaid, paid.Status)
+	assert.Equal(t, "tx_1", paid.PaymentInfo.TransactionID)
+	require.NotNil(t, paid.PaidAt)
+
+	events, err := db.Collection(models.PaymentEventsCollection).CountDocuments(ctx, bson.M{"eventId": "evt_paid"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), events)
+
+	var reservation models.InventoryReservation
+	require.NoError(t, db.Collection(models.InventoryReservationsCollection).FindOne(ctx, bson.M{"orderId": created.ID}).Decode(&reservation))
+	assert.Equal(t, models.ReservationStatusCommitted, reservation.Status)
+}
+
+func TestPaymentWebhookForCancelledOrderIsIgnored(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	provider := payment.NewFakeProvider("test-secret")
+	svc.RegisterPaymentProvider(provider)
+
+	userID := primitive.NewObjectID()
+	created, err := svc.CreateOrder(ctx, userID, orderRequest(seedSKU(t, db, 1), 1))
+	require.NoError(t, err)
+	_, err = svc.UpdateOrderStatus(ctx, userID, created.ID, "user", models.OrderStatusCancelled, "不想要了")
+	require.NoError(t, err)
+
+	payload := fmt.Sprintf(`{"id":"evt_late","type":%q,"data":{"orderId":%q,"transactionId":"tx_late","amount":%d,"currency":%q}}`,
+		payment.EventPaymentSucceeded, created.ID.Hex(), created.Total, created.Currency)
+
+	// 已取消订单的支付成功回调记录为忽略并返回成功，重复推送也一样
+	for i := 0; i < 2; i++ {
+		require.NoError(t, svc.HandlePaymentWebhook(ctx, payment.FakeProviderName, []byte(payload), signedWebhook(provider, payload)))
+	}
+
+	var event models.PaymentEvent
+	require.NoError(t, db.Collection(models.PaymentEventsCollection).FindOne(ctx, bson.M{"eventId": "evt_late"}).Decode(&event))
+	assert.Equal(t, models.PaymentEventIgnored, event.Status)
+	assert.NotEmpty(t, event.Reason)
+
+	order, err := svc.GetOrder(ctx, userID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, order.Status)
+}
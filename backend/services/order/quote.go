@@ -0,0 +1,141 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/pricing"
+	"project/backend/services/product"
+	"project/backend/services/promotion"
+	"project/backend/types/order"
+)
+
+// orderCharges 订单的商品、折扣、税费和运费
+type orderCharges struct {
+	items     []models.OrderItem
+	subtotal  int64
+	discount  int64
+	currency  string
+	coupon    *promotion.Quote
+	breakdown *pricing.Breakdown
+}
+
+// QuoteOrder 下单前试算订单金额，不占用库存和优惠券
+func (s *Service) QuoteOrder(ctx context.Context, userID primitive.ObjectID, req order.QuoteOrderRequest) (*order.OrderQuoteResponse, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	charges, err := s.priceOrder(ctx, userID, req.Items, req.ShippingInfo, req.CouponCode)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]OrderItemResponse, 0, len(charges.items))
+	for _, item := range charges.items {
+		items = append(items, OrderItemResponse{
+			ProductID:   item.ProductID.Hex(),
+			SKUID:       item.SKUID.Hex(),
+			SKUCode:     item.SKUCode,
+			Variant:     item.Variant,
+			ProductType: item.ProductType,
+			Name:        item.Name,
+			Price:       item.Price,
+			Quantity:    item.Quantity,
+			Subtotal:    item.Subtotal,
+			Discount:    item.Discount,
+			ImageURL:    item.ImageURL,
+		})
+	}
+
+	return &order.OrderQuoteResponse{
+		Items:          items,
+		Subtotal:       charges.subtotal,
+		Discount:       charges.discount,
+		CouponCode:     couponCode(charges.coupon),
+		ShippingMethod: charges.breakdown.ShippingMethod,
+		ShippingWeight: charges.breakdown.Weight,
+		ShippingFee:    charges.breakdown.ShippingFee,
+		TaxRate:        charges.breakdown.TaxRate,
+		TaxInclusive:   charges.breakdown.TaxInclusive,
+		Tax:            charges.breakdown.Tax,
+		Total:          charges.breakdown.Total,
+		Currency:       charges.currency,
+	}, nil
+}
+
+// priceOrder 按商品目录定价，校验优惠券，再按收货地址计算税费和运费
+// 下单和试算共用，保证两者金额一致
+func (s *Service) priceOrder(ctx context.Context, userID primitive.ObjectID, reqItems []order.OrderItemRequest, shipping order.ShippingInfoRequest, code string) (*orderCharges, error) {
+	if len(reqItems) == 0 {
+		return nil, errors.NewBadRequestError("订单必须包含至少一件商品")
+	}
+
+	// 按商品目录定价，不使用客户端传入的价格
+	lines := make([]product.Line, 0, len(reqItems))
+	for _, item := range reqItems {
+		skuID, err := primitive.ObjectIDFromHex(item.SKUID)
+		if err != nil {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("无效的商品ID: %s", item.SKUID))
+		}
+		lines = append(lines, product.Line{SKUID: skuID, Quantity: item.Quantity})
+	}
+
+	priced, err := s.productService.ResolveLines(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	charges := &orderCharges{currency: priced[0].Currency}
+
+	// 校验优惠券并计算折扣，使用次数在下单事务中占用
+	if code != "" {
+		charges.coupon, err = s.promotionService.Quote(ctx, userID, code, priced)
+		if err != nil {
+			return nil, err
+		}
+		charges.discount = charges.coupon.Discount
+	}
+
+	weights := make([]pricing.Item, 0, len(priced))
+	charges.items = make([]models.OrderItem, 0, len(priced))
+	for _, line := range priced {
+		charges.subtotal += line.Subtotal
+		item := models.OrderItem{
+			ProductID:   line.DeviceID,
+			SKUID:       line.SKUID,
+			SKUCode:     line.SKU.Code,
+			Variant:     line.SKU.Variant,
+			ProductType: line.ProductType,
+			Name:        line.Name,
+			Price:       line.UnitPrice,
+			Quantity:    line.Quantity,
+			Subtotal:    line.Subtotal,
+			ImageURL:    line.ImageURL,
+		}
+		if charges.coupon != nil {
+			item.Discount = charges.coupon.LineDiscounts[line.SKUID]
+		}
+		charges.items = append(charges.items, item)
+		weights = append(weights, pricing.Item{ProductType: line.ProductType, Weight: line.Weight, Quantity: line.Quantity})
+	}
+
+	charges.breakdown, err = s.pricingCalculator.Calculate(ctx, pricing.Input{
+		Country:  shipping.Country,
+		State:    shipping.State,
+		Method:   shipping.ShippingMethod,
+		Currency: charges.currency,
+		Subtotal: charges.subtotal,
+		Discount: charges.discount,
+		Items:    weights,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
@@ -0,0 +1,677 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/payment"
+	"project/backend/services/scheduler"
+	"project/backend/types/order"
+)
+
+const (
+	// RefundRecoveryJobName 补齐退款结果任务名
+	RefundRecoveryJobName = "order-refund-recovery"
+	// DefaultRefundRecoveryInterval 默认检查间隔
+	DefaultRefundRecoveryInterval = 5 * time.Minute
+	// refundProcessingTimeout 退款处于处理中超过该时长视为中断
+	refundProcessingTimeout = 10 * time.Minute
+	// refundRecoveryBatchSize 每次最多处理的退款数，剩余的留到下一轮
+	refundRecoveryBatchSize = 100
+	// refundInterruptedReason 没有渠道结果的中断退款的失败原因
+	refundInterruptedReason = "退款处理中断，未记录到支付渠道结果，请到支付渠道核对后再处理"
+)
+
+// errRefundConflict 订单在读取后被其他退款修改
+var errRefundConflict = errors.NewAppError(errors.Conflict, "订单退款状态已变化，请重试")
+
+// RequestRefund 用户申请退款，Items为空时退还全部未退款的商品
+func (s *Service) RequestRefund(ctx context.Context, userID, orderID primitive.ObjectID, req order.CreateRefundRequest) (*models.Refund, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	requested := make(map[primitive.ObjectID]int)
+	for _, item := range req.Items {
+		skuID, err := primitive.ObjectIDFromHex(item.SKUID)
+		if err != nil {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("无效的商品ID: %s", item.SKUID))
+		}
+		if item.Quantity <= 0 {
+			return nil, errors.NewBadRequestError("退款数量必须大于0")
+		}
+		requested[skuID] += item.Quantity
+	}
+
+	// 在事务中计算可退数量并递增订单的退款版本号，同一订单的并发申请会冲突重试，不会超退
+	var refund *models.Refund
+	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		o, err := s.GetOrder(sc, userID, orderID)
+		if err != nil {
+			return err
+		}
+		if !isRefundableStatus(o.Status) {
+			return errors.NewBadRequestError("只有已支付或已送达的订单可以申请退款")
+		}
+
+		// 审核中的退款也占用可退数量
+		pending, pendingAmount, err := s.pendingRefunds(sc, orderID, primitive.NilObjectID)
+		if err != nil {
+			return err
+		}
+
+		// buildRefundItems会修改传入的map，事务重试时需要重新复制
+		wanted := make(map[primitive.ObjectID]int, len(requested))
+		for skuID, quantity := range requested {
+			wanted[skuID] = quantity
+		}
+		items, amount, err := buildRefundItems(o, wanted, pending)
+		if err != nil {
+			return err
+		}
+		if err := checkRefundFits(o, items, amount, pending, pendingAmount); err != nil {
+			return err
+		}
+		if err := s.claimOrderRefund(sc, o); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		refund = &models.Refund{
+			ID:        primitive.NewObjectID(),
+			OrderID:   o.ID,
+			UserID:    o.UserID,
+			Items:     items,
+			Amount:    amount,
+			Currency:  o.Currency,
+			Reason:    req.Reason,
+			Status:    models.RefundStatusRequested,
+			Restock:   true,
+			Provider:  o.PaymentInfo.PaymentProvider,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if _, err := s.db.Collection(models.RefundsCollection).InsertOne(sc, refund); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(*errors.AppError); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalServerError("创建退款申请失败")
+	}
+	return refund, nil
+}
+
+// ListOrderRefunds 获取订单的退款记录
+func (s *Service) ListOrderRefunds(ctx context.Context, userID, orderID primitive.ObjectID) ([]models.Refund, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	if _, err := s.GetOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.db.Collection(models.RefundsCollection).Find(ctx,
+		bson.M{"orderId": orderID},
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取退款记录失败")
+	}
+	defer cursor.Close(ctx)
+
+	refunds := []models.Refund{}
+	if err := cursor.All(ctx, &refunds); err != nil {
+		return nil, errors.NewInternalServerError("解析退款记录失败")
+	}
+	return refunds, nil
+}
+
+// ListRefunds 管理员获取退款列表，可按状态筛选
+func (s *Service) ListRefunds(ctx context.Context, status string, page, pageSize int) ([]models.Refund, int64, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, 0, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.M{"createdAt": -1})
+
+	cursor, err := s.db.Collection(models.RefundsCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取退款列表失败")
+	}
+	defer cursor.Close(ctx)
+
+	refunds := []models.Refund{}
+	if err := cursor.All(ctx, &refunds); err != nil {
+		return nil, 0, errors.NewInternalServerError("解析退款列表失败")
+	}
+
+	total, err := s.db.Collection(models.RefundsCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取退款数量失败")
+	}
+	return refunds, total, nil
+}
+
+// ApproveRefund 管理员批准退款，向支付渠道发起退款并更新订单
+func (s *Service) ApproveRefund(ctx context.Context, reviewerID, refundID primitive.ObjectID, req order.ReviewRefundRequest) (*models.Refund, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	restock := true
+	if req.Restock != nil {
+		restock = *req.Restock
+	}
+
+	// 先把状态改为处理中，避免重复批准时重复退款
+	now := time.Now()
+	var refund models.Refund
+	err := s.db.Collection(models.RefundsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": refundID, "status": models.RefundStatusRequested},
+		bson.M{"$set": bson.M{
+			"status":     models.RefundStatusProcessing,
+			"restock":    restock,
+			"reviewerId": reviewerID,
+			"reviewNote": req.Note,
+			"reviewedAt": now,
+			"updatedAt":  now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&refund)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewBadRequestError("退款申请不存在或已处理")
+		}
+		return nil, errors.NewInternalServerError("更新退款申请失败")
+	}
+
+	var o models.Order
+	if err := s.db.Collection(models.OrdersCollection).FindOne(ctx, bson.M{"_id": refund.OrderID}).Decode(&o); err != nil {
+		return nil, s.failRefund(ctx, &refund, "获取订单失败")
+	}
+
+	provider, err := s.paymentProvider(o.PaymentInfo.PaymentProvider)
+	if err != nil {
+		return nil, s.failRefund(ctx, &refund, "支付渠道不可用")
+	}
+
+	// 调用渠道前在事务中重新检查这笔退款仍在可退范围内，并递增订单的退款版本号
+	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		if err := s.db.Collection(models.OrdersCollection).FindOne(sc, bson.M{"_id": refund.OrderID}).Decode(&o); err != nil {
+			return err
+		}
+		pending, pendingAmount, err := s.pendingRefunds(sc, o.ID, refund.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.settleFinalRefund(sc, &o, &refund, pending); err != nil {
+			return err
+		}
+		if err := checkRefundFits(&o, refund.Items, refund.Amount, pending, pendingAmount); err != nil {
+			return err
+		}
+		return s.claimOrderRefund(sc, &o)
+	})
+	if err != nil {
+		reason := "计算退款金额失败"
+		if appErr, ok := err.(*errors.AppError); ok {
+			reason = appErr.Message
+		}
+		return nil, s.failRefund(ctx, &refund, reason)
+	}
+
+	// 以退款ID作为幂等键，重试或补偿时渠道不会重复退款
+	result, err := provider.Refund(ctx, payment.RefundRequest{
+		TransactionID:  o.PaymentInfo.TransactionID,
+		Amount:         refund.Amount,
+		Currency:       refund.Currency,
+		Reason:         refund.Reason,
+		IdempotencyKey: refund.ID.Hex(),
+	})
+	if err != nil {
+		return nil, s.failRefund(ctx, &refund, "支付渠道退款失败: "+err.Error())
+	}
+
+	// 渠道已退款，先记录渠道结果，后面的事务失败时由定时任务补齐
+	refundedAt := time.Now()
+	_, err = s.db.Collection(models.RefundsCollection).UpdateOne(ctx,
+		bson.M{"_id": refund.ID, "status": models.RefundStatusProcessing},
+		bson.M{"$set": bson.M{
+			"providerRefundId": result.ID,
+			"refundedAt":       refundedAt,
+			"updatedAt":        refundedAt,
+		}},
+	)
+	if err != nil {
+		log.Printf("记录退款%s的渠道结果%s失败: %v", refund.ID.Hex(), result.ID, err)
+	}
+
+	// 在事务中更新订单和库存
+	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		return s.completeRefund(sc, &refund, result.ID, refundedAt)
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("渠道已退款，更新退款结果失败，稍后将自动重试: " + err.Error())
+	}
+
+	refund.Status = models.RefundStatusSucceeded
+	refund.ProviderRefundID = result.ID
+	refund.RefundedAt = &refundedAt
+	return &refund, nil
+}
+
+// RejectRefund 管理员拒绝退款
+func (s *Service) RejectRefund(ctx context.Context, reviewerID, refundID primitive.ObjectID, req order.ReviewRefundRequest) (*models.Refund, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	now := time.Now()
+	var refund models.Refund
+	err := s.db.Collection(models.RefundsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": refundID, "status": models.RefundStatusRequested},
+		bson.M{"$set": bson.M{
+			"status":     models.RefundStatusRejected,
+			"reviewerId": reviewerID,
+			"reviewNote": req.Note,
+			"reviewedAt": now,
+			"updatedAt":  now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&refund)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewBadRequestError("退款申请不存在或已处理")
+		}
+		return nil, errors.NewInternalServerError("更新退款申请失败")
+	}
+	return &refund, nil
+}
+
+// completeRefund 记录退款结果：累计订单和商品行的退款，归还库存，全部退完时订单变为已退款
+// 只处理仍在处理中的退款，已完成的直接返回，重复执行不会重复累计
+func (s *Service) completeRefund(sc mongo.SessionContext, refund *models.Refund, providerRefundID string, refundedAt time.Time) error {
+	result, err := s.db.Collection(models.RefundsCollection).UpdateOne(sc,
+		bson.M{"_id": refund.ID, "status": models.RefundStatusProcessing},
+		bson.M{"$set": bson.M{
+			"status":           models.RefundStatusSucceeded,
+			"providerRefundId": providerRefundID,
+			"refundedAt":       refundedAt,
+			"updatedAt":        refundedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return nil
+	}
+
+	// 在事务中重新读取订单，累计其他已完成的退款
+	o := &models.Order{}
+	if err := s.db.Collection(models.OrdersCollection).FindOne(sc, bson.M{"_id": refund.OrderID}).Decode(o); err != nil {
+		return err
+	}
+
+	// 累计后不能超过订单的商品数量和实付金额
+	if err := checkRefundFits(o, refund.Items, refund.Amount, nil, 0); err != nil {
+		return err
+	}
+
+	inc := bson.M{"refundedAmount": refund.Amount, "refundVersion": 1}
+	filters := make([]interface{}, 0, len(refund.Items))
+	refunded := make(map[primitive.ObjectID]int, len(refund.Items))
+	for i, item := range refund.Items {
+		inc[fmt.Sprintf("items.$[i%d].refundedQuantity", i)] = item.Quantity
+		filters = append(filters, bson.M{fmt.Sprintf("i%d.skuId", i): item.SKUID})
+		refunded[item.SKUID] = item.Quantity
+	}
+
+	// 商品全部退完且累计退款达到实付金额才算已退款，否则还可以申请退还剩余金额
+	set := bson.M{"updatedAt": refundedAt}
+	if fullyRefunded(o, refunded) && o.RefundedAmount+refund.Amount >= o.Total && isRefundableStatus(o.Status) {
+		set["status"] = models.OrderStatusRefunded
+	}
+
+	updateOptions := options.Update()
+	if len(filters) > 0 {
+		updateOptions.SetArrayFilters(options.ArrayFilters{Filters: filters})
+	}
+	result, err = s.db.Collection(models.OrdersCollection).UpdateOne(sc,
+		refundVersionFilter(o),
+		bson.M{
+			"$inc": inc,
+			"$set": set,
+			"$push": bson.M{"paymentInfo.refunds": models.PaymentRefund{
+				RefundID:         refund.ID,
+				ProviderRefundID: providerRefundID,
+				Amount:           refund.Amount,
+				RefundedAt:       refundedAt,
+			}},
+		},
+		updateOptions,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errRefundConflict
+	}
+
+	if !refund.Restock || len(refund.Items) == 0 {
+		return nil
+	}
+	restock := make([]models.ReservedItem, 0, len(refund.Items))
+	for _, item := range refund.Items {
+		restock = append(restock, models.ReservedItem{SKUID: item.SKUID, Quantity: item.Quantity})
+	}
+	return s.productService.ReleaseStock(sc, restock)
+}
+
+// RefundRecoveryJob 定期处理中断的退款的定时任务
+func (s *Service) RefundRecoveryJob(interval time.Duration) scheduler.Job {
+	if interval <= 0 {
+		interval = DefaultRefundRecoveryInterval
+	}
+	return scheduler.Job{
+		Name:     RefundRecoveryJobName,
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			resolved, err := s.ResolveStaleRefunds(ctx, now)
+			if resolved > 0 {
+				log.Printf("已处理%d个中断的退款", resolved)
+			}
+			return err
+		},
+	}
+}
+
+// ResolveStaleRefunds 处理长时间停留在处理中的退款
+// 已记录渠道结果的重新执行completeRefund；没有渠道结果的无法确定是否已退款，标记为失败释放占用的可退数量，由管理员到渠道核对
+func (s *Service) ResolveStaleRefunds(ctx context.Context, now time.Time) (int, error) {
+	// 检查数据库连接
+	if s.db == nil {
+		return 0, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
+	}
+
+	cursor, err := s.db.Collection(models.RefundsCollection).Find(ctx,
+		bson.M{
+			"status":    models.RefundStatusProcessing,
+			"updatedAt": bson.M{"$lte": now.Add(-refundProcessingTimeout)},
+		},
+		options.Find().SetSort(bson.M{"updatedAt": 1}).SetLimit(refundRecoveryBatchSize),
+	)
+	if err != nil {
+		return 0, errors.NewInternalServerError("获取处理中的退款失败")
+	}
+	defer cursor.Close(ctx)
+
+	var refunds []models.Refund
+	if err := cursor.All(ctx, &refunds); err != nil {
+		return 0, errors.NewInternalServerError("解析处理中的退款失败")
+	}
+
+	resolved := 0
+	for i := range refunds {
+		refund := &refunds[i]
+		if refund.ProviderRefundID == "" {
+			_, err = s.db.Collection(models.RefundsCollection).UpdateOne(ctx,
+				bson.M{"_id": refund.ID, "status": models.RefundStatusProcessing, "providerRefundId": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{
+					"status":        models.RefundStatusFailed,
+					"failureReason": refundInterruptedReason,
+					"updatedAt":     now,
+				}},
+			)
+		} else {
+			refundedAt := now
+			if refund.RefundedAt != nil {
+				refundedAt = *refund.RefundedAt
+			}
+			err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+				return s.completeRefund(sc, refund, refund.ProviderRefundID, refundedAt)
+			})
+		}
+		if err != nil {
+			log.Printf("处理中断的退款%s失败: %v", refund.ID.Hex(), err)
+			continue
+		}
+		resolved++
+	}
+	return resolved, nil
+}
+
+// settleFinalRefund 批准时确定最后一笔退款的金额
+// 申请时有其他审核中的退款就不会计入剩余金额；批准时这次退完剩余全部商品且没有其他待处理的退款，改为退还剩余的全部金额（含运费）
+func (s *Service) settleFinalRefund(ctx context.Context, o *models.Order, refund *models.Refund, pending map[primitive.ObjectID]int) error {
+	refunded := make(map[primitive.ObjectID]int, len(refund.Items))
+	for _, item := range refund.Items {
+		refunded[item.SKUID] += item.Quantity
+	}
+	rest := o.Total - o.RefundedAmount
+	if len(refund.Items) == 0 || !fullyRefunded(o, refunded) || rest == refund.Amount || len(pending) > 0 {
+		return nil
+	}
+
+	refund.Items[len(refund.Items)-1].Amount += rest - refund.Amount
+	refund.Amount = rest
+	_, err := s.db.Collection(models.RefundsCollection).UpdateOne(ctx,
+		bson.M{"_id": refund.ID, "status": models.RefundStatusProcessing},
+		bson.M{"$set": bson.M{
+			"items":     refund.Items,
+			"amount":    refund.Amount,
+			"updatedAt": time.Now(),
+		}},
+	)
+	return err
+}
+
+// failRefund 记录渠道退款失败，返回给调用方的错误
+func (s *Service) failRefund(ctx context.Context, refund *models.Refund, reason string) error {
+	_, err := s.db.Collection(models.RefundsCollection).UpdateOne(ctx,
+		bson.M{"_id": refund.ID},
+		bson.M{"$set": bson.M{
+			"status":        models.RefundStatusFailed,
+			"failureReason": reason,
+			"updatedAt":     time.Now(),
+		}},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("更新退款申请失败")
+	}
+	return errors.NewInternalServerError(reason)
+}
+
+// pendingRefunds 统计审核中和处理中的退款数量和金额，exclude为正在批准的退款
+func (s *Service) pendingRefunds(ctx context.Context, orderID, exclude primitive.ObjectID) (map[primitive.ObjectID]int, int64, error) {
+	cursor, err := s.db.Collection(models.RefundsCollection).Find(ctx, bson.M{
+		"orderId": orderID,
+		"_id":     bson.M{"$ne": exclude},
+		"status":  bson.M{"$in": []models.RefundStatusEnum{models.RefundStatusRequested, models.RefundStatusProcessing}},
+	})
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取退款记录失败")
+	}
+	defer cursor.Close(ctx)
+
+	var refunds []models.Refund
+	if err := cursor.All(ctx, &refunds); err != nil {
+		return nil, 0, errors.NewInternalServerError("解析退款记录失败")
+	}
+
+	pending := make(map[primitive.ObjectID]int)
+	var amount int64
+	for _, refund := range refunds {
+		// 只退剩余金额的退款没有商品，记在空ID下，避免重复申请
+		if len(refund.Items) == 0 {
+			pending[primitive.NilObjectID]++
+		}
+		for _, item := range refund.Items {
+			pending[item.SKUID] += item.Quantity
+		}
+		amount += refund.Amount
+	}
+	return pending, amount, nil
+}
+
+// claimOrderRefund 递增订单的退款版本号，订单在读取后被其他退款修改过时返回冲突
+// 在事务中调用，同一订单的并发退款会写冲突并由驱动重试，重试时读到最新的订单和退款
+func (s *Service) claimOrderRefund(sc mongo.SessionContext, o *models.Order) error {
+	result, err := s.db.Collection(models.OrdersCollection).UpdateOne(sc,
+		refundVersionFilter(o),
+		bson.M{"$inc": bson.M{"refundVersion": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errRefundConflict
+	}
+	o.RefundVersion++
+	return nil
+}
+
+// refundVersionFilter 按读取时的退款版本号匹配订单，旧订单没有该字段按0处理
+func refundVersionFilter(o *models.Order) bson.M {
+	if o.RefundVersion == 0 {
+		return bson.M{"_id": o.ID, "refundVersion": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": o.ID, "refundVersion": o.RefundVersion}
+}
+
+// checkRefundFits 检查加上待处理的退款和本次退款后，每行的退款数量和累计退款金额没有超过订单
+func checkRefundFits(o *models.Order, items []models.RefundItem, amount int64, pending map[primitive.ObjectID]int, pendingAmount int64) error {
+	lines := make(map[primitive.ObjectID]models.OrderItem, len(o.Items))
+	for _, line := range o.Items {
+		lines[line.SKUID] = line
+	}
+	for _, item := range items {
+		line, ok := lines[item.SKUID]
+		if !ok {
+			return errors.NewBadRequestError("退款商品不在订单中")
+		}
+		if available := line.Quantity - line.RefundedQuantity - pending[item.SKUID]; item.Quantity > available {
+			return errors.NewBadRequestError(fmt.Sprintf("%s最多可退%d件", line.Name, available))
+		}
+	}
+	if o.RefundedAmount+pendingAmount+amount > o.Total {
+		return errors.NewBadRequestError("退款金额超过订单可退金额")
+	}
+	return nil
+}
+
+// buildRefundItems 计算每行可退数量和退款金额
+// requested为空时退还全部可退商品；扣除该行的优惠券折扣，不含税价时税费按折后金额比例分摊
+// 退完最后一件商品时退还剩余的全部金额（含运费），保证累计退款等于实付金额
+// 商品都已退完但还有剩余金额时（并发退款的最后一笔没有计入运费），requested为空可以单独退还剩余金额
+func buildRefundItems(o *models.Order, requested, pending map[primitive.ObjectID]int) ([]models.RefundItem, int64, error) {
+	refundAll := len(requested) == 0
+
+	var items []models.RefundItem
+	var amount int64
+	remainingAfter := 0
+	for _, line := range o.Items {
+		available := line.Quantity - line.RefundedQuantity - pending[line.SKUID]
+		quantity := requested[line.SKUID]
+		if refundAll {
+			quantity = available
+		}
+		delete(requested, line.SKUID)
+
+		if quantity > available {
+			return nil, 0, errors.NewBadRequestError(fmt.Sprintf("%s最多可退%d件", line.Name, available))
+		}
+		remainingAfter += line.Quantity - line.RefundedQuantity - quantity
+		if quantity == 0 {
+			continue
+		}
+
+		// 该行的优惠券折扣按数量折算，税费按折后金额分摊
+		lineAmount := line.Price*int64(quantity) - roundDiv(line.Discount*int64(quantity), int64(line.Quantity))
+		if taxable := o.Subtotal - o.Discount; taxable > 0 && !o.TaxInclusive {
+			lineAmount += roundDiv(o.Tax*lineAmount, taxable)
+		}
+		items = append(items, models.RefundItem{
+			SKUID:    line.SKUID,
+			Name:     line.Name,
+			Quantity: quantity,
+			Amount:   lineAmount,
+		})
+		amount += lineAmount
+	}
+
+	if len(requested) > 0 {
+		return nil, 0, errors.NewBadRequestError("退款商品不在订单中")
+	}
+	if len(items) == 0 {
+		if rest := o.Total - o.RefundedAmount; refundAll && remainingAfter == 0 && len(pending) == 0 && rest > 0 {
+			return []models.RefundItem{}, rest, nil
+		}
+		return nil, 0, errors.NewBadRequestError("没有可退款的商品")
+	}
+
+	// 没有审核中的退款且这次退完全部商品，退还剩余全部金额
+	if remainingAfter == 0 && len(pending) == 0 {
+		rest := o.Total - o.RefundedAmount
+		items[len(items)-1].Amount += rest - amount
+		amount = rest
+	}
+	return items, amount, nil
+}
+
+// fullyRefunded 加上本次退款后是否所有商品都已退款
+func fullyRefunded(o *models.Order, refunded map[primitive.ObjectID]int) bool {
+	for _, line := range o.Items {
+		if line.RefundedQuantity+refunded[line.SKUID] < line.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// isRefundableStatus 订单是否可以退款，退款完成后也只有这些状态可以变为已退款
+func isRefundableStatus(status models.OrderStatusEnum) bool {
+	return status == models.OrderStatusPaid || status == models.OrderStatusDelivered
+}
+
+// roundDiv 四舍五入的整数除法
+func roundDiv(a, b int64) int64 {
+	if (a < 0) != (b < 0) {
+		return (a - b/2) / b
+	}
+	return (a + b/2) / b
+}
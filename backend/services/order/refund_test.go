@@ -0,0 +1,333 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/cart"
+	"project/backend/services/payment"
+	"project/backend/tests/testutil"
+	"project/backend/types/order"
+)
+
+func refundTestOrder() (*models.Order, primitive.ObjectID, primitive.ObjectID) {
+	mouse := primitive.NewObjectID()
+	pad := primitive.NewObjectID()
+	return &models.Order{
+		Items: []models.OrderItem{
+			{SKUID: mouse, Name: "mouse", Price: 30000, Quantity: 2, Subtotal: 60000},
+			{SKUID: pad, Name: "pad", Price: 10000, Quantity: 1, Subtotal: 10000},
+		},
+		Subtotal:    70000,
+		Tax:         3500,
+		ShippingFee: 1000,
+		Discount:    0,
+		Total:       74500,
+	}, mouse, pad
+}
+
+func TestBuildRefundItemsPartial(t *testing.T) {
+	o, mouse, _ := refundTestOrder()
+
+	items, amount, err := buildRefundItems(o, map[primitive.ObjectID]int{mouse: 1}, nil)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 1, items[0].Quantity)
+	// 30000 + 分摊的税费 3500*30000/70000 = 1500
+	assert.Equal(t, int64(31500), amount)
+	assert.Equal(t, amount, items[0].Amount)
+}
+
+func TestBuildRefundItemsRemainderIncludesShipping(t *testing.T) {
+	o, mouse, pad := refundTestOrder()
+	o.Items[0].RefundedQuantity = 1
+	o.RefundedAmount = 31500
+
+	items, amount, err := buildRefundItems(o, map[primitive.ObjectID]int{mouse: 1, pad: 1}, nil)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, o.Total-o.RefundedAmount, amount)
+	assert.Equal(t, amount, items[0].Amount+items[1].Amount)
+}
+
+func TestBuildRefundItemsAll(t *testing.T) {
+	o, _, _ := refundTestOrder()
+
+	items, amount, err := buildRefundItems(o, map[primitive.ObjectID]int{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, o.Total, amount)
+}
+
+func TestBuildRefundItemsRejectsOverRefund(t *testing.T) {
+	o, mouse, pad := refundTestOrder()
+
+	// 审核中的退款占用数量
+	_, _, err := buildRefundItems(o, map[primitive.ObjectID]int{mouse: 2}, map[primitive.ObjectID]int{mouse: 1})
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+
+	_, _, err = buildRefundItems(o, map[primitive.ObjectID]int{pad: 2}, nil)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+
+	_, _, err = buildRefundItems(o, map[primitive.ObjectID]int{primitive.NewObjectID(): 1}, nil)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestBuildRefundItemsRemainingAmountOnly(t *testing.T) {
+	o, mouse, pad := refundTestOrder()
+	// 两笔并发的退款退完了全部商品，但都没有计入运费
+	o.Items[0].RefundedQuantity = 2
+	o.Items[1].RefundedQuantity = 1
+	o.RefundedAmount = 73500
+
+	items, amount, err := buildRefundItems(o, map[primitive.ObjectID]int{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Equal(t, int64(1000), amount)
+
+	// 已有审核中的退款或指定了商品时不能再退
+	_, _, err = buildRefundItems(o, map[primitive.ObjectID]int{}, map[primitive.ObjectID]int{primitive.NilObjectID: 1})
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+	_, _, err = buildRefundItems(o, map[primitive.ObjectID]int{mouse: 1, pad: 1}, nil)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestCheckRefundFits(t *testing.T) {
+	o, mouse, pad := refundTestOrder()
+	o.Items[0].RefundedQuantity = 1
+	o.RefundedAmount = 31500
+	items := []models.RefundItem{{SKUID: mouse, Quantity: 1, Amount: 31500}}
+
+	assert.NoError(t, checkRefundFits(o, items, 31500, nil, 0))
+
+	// 审核中的退款占用了剩余数量
+	err := checkRefundFits(o, items, 31500, map[primitive.ObjectID]int{mouse: 1}, 31500)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+
+	// 累计金额超过实付金额
+	err = checkRefundFits(o, []models.RefundItem{{SKUID: pad, Quantity: 1}}, 31500, map[primitive.ObjectID]int{mouse: 1}, 31500)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+
+	err = checkRefundFits(o, []models.RefundItem{{SKUID: primitive.NewObjectID(), Quantity: 1}}, 0, nil, 0)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestFullyRefunded(t *testing.T) {
+	o, mouse, pad := refundTestOrder()
+
+	assert.False(t, fullyRefunded(o, map[primitive.ObjectID]int{mouse: 2}))
+	assert.True(t, fullyRefunded(o, map[primitive.ObjectID]int{mouse: 2, pad: 1}))
+}
+
+func TestRefundedStatusOnlySetByRefunds(t *testing.T) {
+	// 手动变更和批量变更都不能直接标记为已退款
+	for _, from := range []models.OrderStatusEnum{models.OrderStatusPaid, models.OrderStatusShipped, models.OrderStatusDelivered} {
+		assert.False(t, isValidStatusTransition(from, models.OrderStatusRefunded), from)
+	}
+	assert.True(t, isRefundableStatus(models.OrderStatusPaid))
+	assert.True(t, isRefundableStatus(models.OrderStatusDelivered))
+	assert.False(t, isRefundableStatus(models.OrderStatusRefunded))
+}
+
+func TestBuildRefundItemsDeductsLineDiscount(t *testing.T) {
+	o, mouse, _ := refundTestOrder()
+	// 优惠券减免6000，全部分摊到mouse行，税费按折后金额计算
+	o.Items[0].Discount = 6000
+	o.Discount = 6000
+	o.Tax = 3200
+	o.Total = o.Subtotal + o.ShippingFee + o.Tax - o.Discount
+
+	items, amount, err := buildRefundItems(o, map[primitive.ObjectID]int{mouse: 1}, nil)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	// 30000 - 3000 + 3200*27000/64000 = 28350
+	assert.Equal(t, int64(28350), amount)
+}
+
+func TestResolveStaleRefunds(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	o, mouse, _ := refundTestOrder()
+	o.ID = primitive.NewObjectID()
+	o.Status = models.OrderStatusPaid
+	_, err := db.Collection(models.OrdersCollection).InsertOne(ctx, o)
+	require.NoError(t, err)
+
+	now := time.Now()
+	stale := now.Add(-time.Hour)
+	// 渠道已退款但更新订单失败的退款
+	paid := models.Refund{
+		ID:               primitive.NewObjectID(),
+		OrderID:          o.ID,
+		Items:            []models.RefundItem{{SKUID: mouse, Quantity: 1, Amount: 31500}},
+		Amount:           31500,
+		Status:           models.RefundStatusProcessing,
+		ProviderRefundID: "re_fake_1",
+		RefundedAt:       &stale,
+		UpdatedAt:        stale,
+	}
+	// 调用渠道前中断的退款
+	interrupted := models.Refund{
+		ID:        primitive.NewObjectID(),
+		OrderID:   o.ID,
+		Items:     []models.RefundItem{{SKUID: mouse, Quantity: 1, Amount: 31500}},
+		Amount:    31500,
+		Status:    models.RefundStatusProcessing,
+		UpdatedAt: stale,
+	}
+	_, err = db.Collection(models.RefundsCollection).InsertMany(ctx, []interface{}{paid, interrupted})
+	require.NoError(t, err)
+
+	resolved, err := svc.ResolveStaleRefunds(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resolved)
+
+	// 重复执行不会重复累计
+	resolved, err = svc.ResolveStaleRefunds(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, resolved)
+
+	updated, err := svc.GetOrderByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(31500), updated.RefundedAmount)
+	assert.Equal(t, 1, updated.Items[0].RefundedQuantity)
+	assert.Len(t, updated.PaymentInfo.Refunds, 1)
+
+	var refund models.Refund
+	require.NoError(t, db.Collection(models.RefundsCollection).FindOne(ctx, bson.M{"_id": paid.ID}).Decode(&refund))
+	assert.Equal(t, models.RefundStatusSucceeded, refund.Status)
+	require.NoError(t, db.Collection(models.RefundsCollection).FindOne(ctx, bson.M{"_id": interrupted.ID}).Decode(&refund))
+	assert.Equal(t, models.RefundStatusFailed, refund.Status)
+	assert.Equal(t, refundInterruptedReason, refund.FailureReason)
+}
+
+func TestConcurrentRefundRequestsRefundFullTotal(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	svc.RegisterPaymentProvider(payment.NewFakeProvider("secret"))
+	o, mouse, pad := refundTestOrder()
+	o.ID = primitive.NewObjectID()
+	o.UserID = primitive.NewObjectID()
+	o.Status = models.OrderStatusPaid
+	o.PaymentInfo.PaymentProvider = payment.FakeProviderName
+	o.PaymentInfo.TransactionID = "txn_fake_1"
+	_, err := db.Collection(models.OrdersCollection).InsertOne(ctx, o)
+	require.NoError(t, err)
+
+	// 第二笔申请时第一笔还在审核中，申请金额不含运费
+	first, err := svc.RequestRefund(ctx, o.UserID, o.ID, order.CreateRefundRequest{
+		Items:  []order.RefundItemRequest{{SKUID: mouse.Hex(), Quantity: 1}},
+		Reason: "不想要了",
+	})
+	require.NoError(t, err)
+	second, err := svc.RequestRefund(ctx, o.UserID, o.ID, order.CreateRefundRequest{
+		Items:  []order.RefundItemRequest{{SKUID: mouse.Hex(), Quantity: 1}, {SKUID: pad.Hex(), Quantity: 1}},
+		Reason: "不想要了",
+	})
+	require.NoError(t, err)
+	assert.Less(t, first.Amount+second.Amount, o.Total)
+
+	noRestock := false
+	_, err = svc.ApproveRefund(ctx, primitive.NewObjectID(), first.ID, order.ReviewRefundRequest{Restock: &noRestock})
+	require.NoError(t, err)
+	approved, err := svc.ApproveRefund(ctx, primitive.NewObjectID(), second.ID, order.ReviewRefundRequest{Restock: &noRestock})
+	require.NoError(t, err)
+	assert.Equal(t, o.Total-first.Amount, approved.Amount)
+
+	updated, err := svc.GetOrderByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, o.Total, updated.RefundedAmount)
+	assert.Equal(t, models.OrderStatusRefunded, updated.Status)
+}
+
+func TestConcurrentRefundRequestsDoNotOverRefund(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	o, _, _ := refundTestOrder()
+	o.ID = primitive.NewObjectID()
+	o.UserID = primitive.NewObjectID()
+	o.Status = models.OrderStatusPaid
+	_, err := db.Collection(models.OrdersCollection).InsertOne(ctx, o)
+	require.NoError(t, err)
+
+	// 同时申请退还全部商品，只能有一笔成功
+	const attempts = 5
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			_, err := svc.RequestRefund(ctx, o.UserID, o.ID, order.CreateRefundRequest{Reason: "不想要了"})
+			errs <- err
+		}()
+	}
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		if err := <-errs; err == nil {
+			succeeded++
+		} else {
+			assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	count, err := db.Collection(models.RefundsCollection).CountDocuments(ctx, bson.M{"orderId": o.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestApproveRefundRechecksOrder(t *testing.T) {
+	db, cleanup := testutil.SetupTransactionTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := NewService(db, cart.NewService(db), nil, config.OrderConfig{})
+	svc.RegisterPaymentProvider(payment.NewFakeProvider("secret"))
+	o, mouse, _ := refundTestOrder()
+	o.ID = primitive.NewObjectID()
+	o.UserID = primitive.NewObjectID()
+	o.Status = models.OrderStatusPaid
+	o.PaymentInfo.PaymentProvider = payment.FakeProviderName
+	o.PaymentInfo.TransactionID = "txn_fake_1"
+	_, err := db.Collection(models.OrdersCollection).InsertOne(ctx, o)
+	require.NoError(t, err)
+
+	refund, err := svc.RequestRefund(ctx, o.UserID, o.ID, order.CreateRefundRequest{
+		Items:  []order.RefundItemRequest{{SKUID: mouse.Hex(), Quantity: 2}},
+		Reason: "不想要了",
+	})
+	require.NoError(t, err)
+
+	// 申请后订单已在其他地方退过一件
+	_, err = db.Collection(models.OrdersCollection).UpdateOne(ctx, bson.M{"_id": o.ID}, bson.M{"$set": bson.M{"items.0.refundedQuantity": 1}})
+	require.NoError(t, err)
+
+	noRestock := false
+	_, err = svc.ApproveRefund(ctx, primitive.NewObjectID(), refund.ID, order.ReviewRefundRequest{Restock: &noRestock})
+	require.Error(t, err)
+
+	var failed models.Refund
+	require.NoError(t, db.Collection(models.RefundsCollection).FindOne(ctx, bson.M{"_id": refund.ID}).Decode(&failed))
+	assert.Equal(t, models.RefundStatusFailed, failed.Status)
+	assert.Empty(t, failed.ProviderRefundID)
+
+	updated, err := svc.GetOrderByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Zero(t, updated.RefundedAmount)
+}
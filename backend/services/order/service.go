@@ -3,7 +3,6 @@ package order
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,31 +10,51 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"project/backend/config"
 	"project/backend/internal/errors"
 	"project/backend/models"
 	"project/backend/services/cart"
 	"project/backend/services/device"
+	"project/backend/services/i18n"
+	"project/backend/services/invoice"
+	"project/backend/services/payment"
+	"project/backend/services/pricing"
+	"project/backend/services/product"
+	"project/backend/services/promotion"
 	"project/backend/types/order"
 )
 
 // 类型别名，避免使用带包名的类型引用
 type (
-	OrderResponse        = order.OrderResponse
-	OrderItemResponse    = order.OrderItemResponse
-	ShippingInfoResponse = order.ShippingInfoResponse
-	PaymentInfoResponse  = order.PaymentInfoResponse
-	OrderListResponse    = order.OrderListResponse
+	OrderResponse         = order.OrderResponse
+	OrderItemResponse     = order.OrderItemResponse
+	ShippingInfoResponse  = order.ShippingInfoResponse
+	PaymentInfoResponse   = order.PaymentInfoResponse
+	OrderListResponse     = order.OrderListResponse
+	PaymentRefundResponse = order.PaymentRefundResponse
 )
 
 // Service 订单服务
 type Service struct {
-	db            *mongo.Database
-	cartService   *cart.Service
-	deviceService *device.Service
+	db             *mongo.Database
+	cartService    cart.Service
+	deviceService  device.Service
+	productService product.Service
+	// 优惠券，下单时在同一事务中占用次数，取消时归还
+	promotionService promotion.Service
+	// 按地区计算税费和运费
+	pricingCalculator *pricing.Calculator
+	reservationTTL    time.Duration
+	// 支付渠道，按名称索引
+	paymentProviders map[string]payment.Provider
+	// 订单取消等通知
+	notifier Notifier
+	// 发票和发货单
+	documents *invoice.Renderer
 }
 
 // NewService 创建订单服务
-func NewService(db *mongo.Database, cartService *cart.Service, deviceService *device.Service) *Service {
+func NewService(db *mongo.Database, cartService cart.Service, deviceService device.Service, cfg config.OrderConfig) *Service {
 	if db == nil {
 		// 如果数据库为nil，返回一个基本服务实例
 		// 各方法会检查db是否为nil
@@ -45,10 +64,21 @@ func NewService(db *mongo.Database, cartService *cart.Service, deviceService *de
 			deviceService: nil,
 		}
 	}
+
+	reservationTTL := cfg.ReservationTTL
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
+
 	return &Service{
-		db:            db,
-		cartService:   cartService,
-		deviceService: deviceService,
+		db:                db,
+		cartService:       cartService,
+		deviceService:     deviceService,
+		productService:    product.New(db),
+		promotionService:  promotion.New(db),
+		pricingCalculator: pricing.NewCalculator(db, cfg.Pricing),
+		reservationTTL:    reservationTTL,
+		documents:         invoice.NewRenderer(i18n.NewService()),
 	}
 }
 
@@ -59,56 +89,24 @@ func (s *Service) CreateOrder(ctx context.Context, userID primitive.ObjectID, re
 		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
 	}
 
-	// 验证请求
-	if len(req.Items) == 0 {
-		return nil, errors.NewBadRequestError("订单必须包含至少一件商品")
-	}
-
-	// 创建订单项
-	orderItems := make([]models.OrderItem, 0, len(req.Items))
-	subtotal := 0.0
-
-	for _, item := range req.Items {
-		productID, err := primitive.ObjectIDFromHex(item.ProductID)
-		if err != nil {
-			return nil, errors.NewBadRequestError(fmt.Sprintf("无效的商品ID: %s", item.ProductID))
-		}
-
-		// 这里应该从产品服务获取商品信息
-		// 简化起见，我们使用固定值
-		price := 99.99                              // 这应该从产品服务获取
-		name := "鼠标设备"                              // 这应该从产品服务获取
-		imageURL := "https://example.com/image.jpg" // 这应该从产品服务获取
-
-		// 计算小计
-		itemSubtotal := price * float64(item.Quantity)
-		subtotal += itemSubtotal
-
-		orderItems = append(orderItems, models.OrderItem{
-			ProductID:   productID,
-			ProductType: item.ProductType,
-			Name:        name,
-			Price:       price,
-			Quantity:    item.Quantity,
-			Subtotal:    itemSubtotal,
-			ImageURL:    imageURL,
-		})
+	// 按商品目录、优惠券和收货地址计算金额
+	charges, err := s.priceOrder(ctx, userID, req.Items, req.ShippingInfo, req.CouponCode)
+	if err != nil {
+		return nil, err
 	}
 
-	// 计算费用
-	shippingFee := 10.0    // 固定运费，实际应该根据配送方式和地址计算
-	tax := subtotal * 0.05 // 5%的税率，实际应该根据地区计算
-	discount := 0.0        // 无折扣
-	total := subtotal + shippingFee + tax - discount
-
 	// 创建订单
 	now := time.Now()
+	orderNumber, err := s.nextOrderNumber(ctx, now)
+	if err != nil {
+		return nil, errors.NewInternalServerError("生成订单号失败")
+	}
 	order := &models.Order{
 		ID:          primitive.NewObjectID(),
 		UserID:      userID,
-		OrderNumber: generateOrderNumber(),
+		OrderNumber: orderNumber,
 		Status:      models.OrderStatusPending,
-		Items:       orderItems,
+		Items:       charges.items,
 		ShippingInfo: models.ShippingInfo{
 			Name:           req.ShippingInfo.Name,
 			Phone:          req.ShippingInfo.Phone,
@@ -118,30 +116,31 @@ func (s *Service) CreateOrder(ctx context.Context, userID primitive.ObjectID, re
 			State:          req.ShippingInfo.State,
 			ZipCode:        req.ShippingInfo.ZipCode,
 			Country:        req.ShippingInfo.Country,
-			ShippingMethod: req.ShippingInfo.ShippingMethod,
+			ShippingMethod: charges.breakdown.ShippingMethod,
 		},
 		PaymentInfo: models.PaymentInfo{
 			Method:        models.PaymentMethodEnum(req.PaymentMethod),
 			PaymentStatus: "pending",
 		},
-		Subtotal:    subtotal,
-		ShippingFee: shippingFee,
-		Tax:         tax,
-		Discount:    discount,
-		Total:       total,
-		Notes:       req.Notes,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	// 保存到数据库
-	_, err := s.db.Collection(models.OrdersCollection).InsertOne(ctx, order)
-	if err != nil {
-		return nil, errors.NewInternalServerError("创建订单失败")
+		Subtotal:       charges.subtotal,
+		ShippingFee:    charges.breakdown.ShippingFee,
+		ShippingWeight: charges.breakdown.Weight,
+		Tax:            charges.breakdown.Tax,
+		TaxInclusive:   charges.breakdown.TaxInclusive,
+		Discount:       charges.discount,
+		Total:          charges.breakdown.Total,
+		Currency:       charges.currency,
+		CouponCode:     couponCode(charges.coupon),
+		Notes:          req.Notes,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	// 在事务中预留库存、保存订单并移除购物车中已购买的商品
+	if err := s.checkout(ctx, order, charges.coupon); err != nil {
+		return nil, err
 	}
 
-	// 成功后应该清空购物车，但这里简化处理
-
 	return order, nil
 }
 
@@ -249,15 +248,23 @@ func (s *Service) ListUserOrders(ctx context.Context, userID primitive.ObjectID,
 }
 
 // UpdateOrderStatus 更新订单状态
-// 普通用户只能取消自己的订单；管理员可以执行其他状态变更
+// 普通用户只能取消自己的订单；管理员可以变更任意用户的订单
 func (s *Service) UpdateOrderStatus(ctx context.Context, userID, orderID primitive.ObjectID, role string, status models.OrderStatusEnum, cancelReason string) (*models.Order, error) {
 	// 检查数据库连接
 	if s.db == nil {
 		return nil, errors.NewInternalServerError("数据库连接失败，订单服务暂不可用")
 	}
 
-	// 先获取订单
-	order, err := s.GetOrder(ctx, userID, orderID)
+	// 管理员按订单ID查询，普通用户只能查到自己的订单
+	var (
+		order *models.Order
+		err   error
+	)
+	if role == string(models.RoleAdmin) {
+		order, err = s.GetOrderByID(ctx, orderID)
+	} else {
+		order, err = s.GetOrder(ctx, userID, orderID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -267,54 +274,74 @@ func (s *Service) UpdateOrderStatus(ctx context.Context, userID, orderID primiti
 		return nil, errors.NewForbiddenError("无权执行此操作")
 	}
 
+	return s.transitionOrder(ctx, order, status, cancelReason, nil)
+}
+
+// transitionOrder 校验并执行状态变更，extra为需要一并写入的字段
+// 取消订单时在同一事务中归还预留的库存和优惠券
+func (s *Service) transitionOrder(ctx context.Context, order *models.Order, status models.OrderStatusEnum, cancelReason string, extra bson.M) (*models.Order, error) {
 	// 验证状态变更是否合法
 	if !isValidStatusTransition(order.Status, status) {
 		return nil, errors.NewBadRequestError(fmt.Sprintf("无法从 %s 状态变更为 %s 状态", order.Status, status))
 	}
 
 	// 准备更新数据
-	update := bson.M{
-		"$set": bson.M{
-			"status":    status,
-			"updatedAt": time.Now(),
-		},
+	now := time.Now()
+	set := bson.M{
+		"status":    status,
+		"updatedAt": now,
+	}
+	for key, value := range extra {
+		set[key] = value
 	}
 
 	// 根据状态添加额外字段
 	switch status {
 	case models.OrderStatusPaid:
-		now := time.Now()
-		update["$set"].(bson.M)["paidAt"] = now
+		set["paidAt"] = now
 	case models.OrderStatusShipped:
-		now := time.Now()
-		update["$set"].(bson.M)["shippedAt"] = now
+		set["shippedAt"] = now
 	case models.OrderStatusDelivered:
-		now := time.Now()
-		update["$set"].(bson.M)["deliveredAt"] = now
+		set["deliveredAt"] = now
 	case models.OrderStatusCancelled:
-		now := time.Now()
-		update["$set"].(bson.M)["cancelledAt"] = now
-		update["$set"].(bson.M)["cancelReason"] = cancelReason
+		set["cancelledAt"] = now
+		set["cancelReason"] = cancelReason
 	}
 
-	// 执行更新
-	result := s.db.Collection(models.OrdersCollection).FindOneAndUpdate(
-		ctx,
-		bson.M{"_id": orderID, "userId": userID},
-		update,
-		options.FindOneAndUpdate().SetReturnDocument(options.After),
-	)
+	// 条件中带上原状态，避免与支付回调等并发变更互相覆盖
+	var updatedOrder models.Order
+	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		err := s.db.Collection(models.OrdersCollection).FindOneAndUpdate(
+			sc,
+			bson.M{"_id": order.ID, "status": order.Status},
+			bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updatedOrder)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return errors.NewBadRequestError("订单状态已变更，请刷新后重试")
+			}
+			return err
+		}
 
-	if result.Err() != nil {
+		if status == models.OrderStatusCancelled {
+			if err := s.releaseReservation(sc, order.ID); err != nil {
+				return err
+			}
+			return s.promotionService.Release(sc, order.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(*errors.AppError); ok {
+			return nil, err
+		}
 		return nil, errors.NewInternalServerError("更新订单状态失败")
 	}
 
-	// 解析更新后的订单
-	var updatedOrder models.Order
-	if err := result.Decode(&updatedOrder); err != nil {
-		return nil, errors.NewInternalServerError("解析更新后的订单失败")
+	if status == models.OrderStatusPaid {
+		s.notifyPaid(ctx, &updatedOrder)
 	}
-
 	return &updatedOrder, nil
 }
 
@@ -377,18 +404,6 @@ func (s *Service) ProcessPayment(ctx context.Context, orderID primitive.ObjectID
 	return &updatedOrder, nil
 }
 
-// 生成订单号
-func generateOrderNumber() string {
-	now := time.Now()
-	// 在Go 1.20+中不再需要显式设置随机数种子
-
-	// 格式：年月日-随机数字
-	return fmt.Sprintf("%s-%04d",
-		now.Format("20060102"),
-		rand.Intn(10000),
-	)
-}
-
 // 验证订单状态变更是否合法
 func isValidStatusTransition(from, to models.OrderStatusEnum) bool {
 	// 定义允许的状态转换
@@ -399,14 +414,11 @@ func isValidStatusTransition(from, to models.OrderStatusEnum) bool {
 		},
 		models.OrderStatusPaid: {
 			models.OrderStatusShipped,
-			models.OrderStatusRefunded,
 		},
 		models.OrderStatusShipped: {
 			models.OrderStatusDelivered,
 		},
-		models.OrderStatusDelivered: {
-			models.OrderStatusRefunded,
-		},
+		// 已退款只能由退款流程在退完全部金额后设置，见completeRefund
 		// 已取消和已退款是终态，不允许再变更
 	}
 
@@ -430,13 +442,18 @@ func convertToOrderResponse(order models.Order) OrderResponse {
 	items := make([]OrderItemResponse, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = OrderItemResponse{
-			ProductID:   item.ProductID.Hex(),
-			ProductType: item.ProductType,
-			Name:        item.Name,
-			Price:       item.Price,
-			Quantity:    item.Quantity,
-			Subtotal:    item.Subtotal,
-			ImageURL:    item.ImageURL,
+			ProductID:        item.ProductID.Hex(),
+			SKUID:            item.SKUID.Hex(),
+			SKUCode:          item.SKUCode,
+			Variant:          item.Variant,
+			ProductType:      item.ProductType,
+			Name:             item.Name,
+			Price:            item.Price,
+			Quantity:         item.Quantity,
+			Subtotal:         item.Subtotal,
+			Discount:         item.Discount,
+			RefundedQuantity: item.RefundedQuantity,
+			ImageURL:         item.ImageURL,
 		}
 	}
 
@@ -451,6 +468,8 @@ func convertToOrderResponse(order models.Order) OrderResponse {
 		ZipCode:        order.ShippingInfo.ZipCode,
 		Country:        order.ShippingInfo.Country,
 		ShippingMethod: order.ShippingInfo.ShippingMethod,
+		Carrier:        order.ShippingInfo.Carrier,
+		TrackingNumber: order.ShippingInfo.TrackingNumber,
 	}
 
 	// 转换支付信息
@@ -461,27 +480,39 @@ func convertToOrderResponse(order models.Order) OrderResponse {
 		PaymentStatus:   order.PaymentInfo.PaymentStatus,
 		PaymentProvider: order.PaymentInfo.PaymentProvider,
 	}
+	for _, refund := range order.PaymentInfo.Refunds {
+		paymentInfo.Refunds = append(paymentInfo.Refunds, PaymentRefundResponse{
+			RefundID:         refund.RefundID.Hex(),
+			ProviderRefundID: refund.ProviderRefundID,
+			Amount:           refund.Amount,
+			RefundedAt:       refund.RefundedAt,
+		})
+	}
 
 	return OrderResponse{
-		ID:           order.ID.Hex(),
-		UserID:       order.UserID.Hex(),
-		OrderNumber:  order.OrderNumber,
-		Status:       string(order.Status),
-		Items:        items,
-		ShippingInfo: shippingInfo,
-		PaymentInfo:  paymentInfo,
-		Subtotal:     order.Subtotal,
-		ShippingFee:  order.ShippingFee,
-		Tax:          order.Tax,
-		Discount:     order.Discount,
-		Total:        order.Total,
-		Notes:        order.Notes,
-		CreatedAt:    order.CreatedAt,
-		UpdatedAt:    order.UpdatedAt,
-		PaidAt:       order.PaidAt,
-		ShippedAt:    order.ShippedAt,
-		DeliveredAt:  order.DeliveredAt,
-		CancelledAt:  order.CancelledAt,
-		CancelReason: order.CancelReason,
+		ID:             order.ID.Hex(),
+		UserID:         order.UserID.Hex(),
+		OrderNumber:    order.OrderNumber,
+		Status:         string(order.Status),
+		Items:          items,
+		ShippingInfo:   shippingInfo,
+		PaymentInfo:    paymentInfo,
+		Subtotal:       order.Subtotal,
+		ShippingFee:    order.ShippingFee,
+		Tax:            order.Tax,
+		TaxInclusive:   order.TaxInclusive,
+		Discount:       order.Discount,
+		CouponCode:     order.CouponCode,
+		Total:          order.Total,
+		Currency:       order.Currency,
+		RefundedAmount: order.RefundedAmount,
+		Notes:          order.Notes,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+		PaidAt:         order.PaidAt,
+		ShippedAt:      order.ShippedAt,
+		DeliveredAt:    order.DeliveredAt,
+		CancelledAt:    order.CancelledAt,
+		CancelReason:   order.CancelReason,
 	}
 }
@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// FakeProviderName 本地模拟渠道的名称
+	FakeProviderName = "fake"
+	// FakeSignatureHeader 模拟渠道的签名头，格式为 t=<unix秒>,v1=<hex(HMAC-SHA256(t.payload))>
+	FakeSignatureHeader = "X-Fake-Signature"
+	// fakeSignatureTolerance 允许的签名时间偏差，防止重放
+	fakeSignatureTolerance = 5 * time.Minute
+)
+
+// FakeProvider 本地模拟的支付渠道，用于开发和测试
+// 回调签名方式与常见支付渠道一致，便于以后替换成真实渠道
+type FakeProvider struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewFakeProvider 创建模拟支付渠道
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{
+		secret: []byte(webhookSecret),
+		now:    time.Now,
+	}
+}
+
+// fakeEvent 模拟渠道的回调内容
+type fakeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		OrderID       string `json:"orderId"`
+		IntentID      string `json:"intentId"`
+		TransactionID string `json:"transactionId"`
+		RefundID      string `json:"refundId"`
+		Amount        int64  `json:"amount"`
+		Currency      string `json:"currency"`
+	} `json:"data"`
+}
+
+// Name 渠道名称
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+// CreateIntent 创建支付意图
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	id := "pi_fake_" + primitive.NewObjectID().Hex()
+	return &Intent{
+		ID:           id,
+		Provider:     FakeProviderName,
+		ClientSecret: id + "_secret",
+		Amount:       req.Amount,
+		Currency:     req.Currency,
+	}, nil
+}
+
+// VerifyWebhook 校验回调签名并解析事件
+func (p *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	timestamp, signature, ok := parseFakeSignature(header.Get(FakeSignatureHeader))
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if diff := p.now().Sub(signedAt); diff > fakeSignatureTolerance || diff < -fakeSignatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	expected := p.sign(timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event fakeEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" || event.Type == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	return &WebhookEvent{
+		ID:            event.ID,
+		Type:          event.Type,
+		OrderID:       event.Data.OrderID,
+		IntentID:      event.Data.IntentID,
+		TransactionID: event.Data.TransactionID,
+		RefundID:      event.Data.RefundID,
+		Amount:        event.Data.Amount,
+		Currency:      event.Data.Currency,
+	}, nil
+}
+
+// Refund 模拟退款，总是立即成功，带幂等键时同一个键返回相同的退款ID
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	if req.TransactionID == "" {
+		return nil, fmt.Errorf("missing transaction id")
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("invalid refund amount %d", req.Amount)
+	}
+	id := "re_fake_" + primitive.NewObjectID().Hex()
+	if req.IdempotencyKey != "" {
+		id = "re_fake_" + req.IdempotencyKey
+	}
+	return &Refund{
+		ID:     id,
+		Status: "succeeded",
+	}, nil
+}
+
+// SignatureHeader 生成回调签名头，供测试和本地模拟回调使用
+func (p *FakeProvider) SignatureHeader(payload []byte, at time.Time) string {
+	timestamp := at.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, p.sign(timestamp, payload))
+}
+
+func (p *FakeProvider) sign(timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseFakeSignature 解析 t=<unix秒>,v1=<签名>
+func parseFakeSignature(header string) (int64, string, bool) {
+	var (
+		timestamp int64
+		signature string
+		err       error
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, "", false
+			}
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == 0 || signature == "" {
+		return 0, "", false
+	}
+	return timestamp, signature, true
+}
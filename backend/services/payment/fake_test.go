@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPayload = `{"id":"evt_1","type":"payment.succeeded","data":{"orderId":"65f000000000000000000001","intentId":"pi_fake_1","transactionId":"tx_1","amount":84895,"currency":"CNY"}}`
+
+func newTestFakeProvider(now time.Time) *FakeProvider {
+	p := NewFakeProvider("test-secret")
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestFakeProviderVerifyWebhook(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	p := newTestFakeProvider(now)
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, p.SignatureHeader([]byte(testPayload), now))
+
+	event, err := p.VerifyWebhook([]byte(testPayload), header)
+	require.NoError(t, err)
+	assert.Equal(t, "evt_1", event.ID)
+	assert.Equal(t, EventPaymentSucceeded, event.Type)
+	assert.Equal(t, "65f000000000000000000001", event.OrderID)
+	assert.Equal(t, "tx_1", event.TransactionID)
+	assert.Equal(t, int64(84895), event.Amount)
+}
+
+func TestFakeProviderRejectsBadSignatures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	p := newTestFakeProvider(now)
+	payload := []byte(testPayload)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+	}{
+		{"missing header", "", payload},
+		{"malformed header", "v1=abc", payload},
+		{"tampered payload", p.SignatureHeader(payload, now), []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"amount":1}}`)},
+		{"wrong secret", NewFakeProvider("other").SignatureHeader(payload, now), payload},
+		{"expired", p.SignatureHeader(payload, now.Add(-10*time.Minute)), payload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(FakeSignatureHeader, tt.header)
+			}
+			_, err := p.VerifyWebhook(tt.body, header)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestFakeProviderRefund(t *testing.T) {
+	p := NewFakeProvider("test-secret")
+
+	refund, err := p.Refund(context.Background(), RefundRequest{TransactionID: "tx_1", Amount: 100, Currency: "CNY"})
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", refund.Status)
+
+	_, err = p.Refund(context.Background(), RefundRequest{TransactionID: "tx_1", Amount: 0})
+	assert.Error(t, err)
+
+	// 同一个幂等键返回相同的退款
+	first, err := p.Refund(context.Background(), RefundRequest{TransactionID: "tx_1", Amount: 100, IdempotencyKey: "refund_1"})
+	require.NoError(t, err)
+	again, err := p.Refund(context.Background(), RefundRequest{TransactionID: "tx_1", Amount: 100, IdempotencyKey: "refund_1"})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+}
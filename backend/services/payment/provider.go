@@ -0,0 +1,80 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// 通用的回调事件类型，各渠道在VerifyWebhook中把自己的事件类型映射过来
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventRefundSucceeded  = "refund.succeeded"
+	EventRefundFailed     = "refund.failed"
+)
+
+var (
+	// ErrInvalidSignature 回调签名校验失败
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload 回调内容无法解析
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// Provider 支付渠道
+// 金额均以最小货币单位（分）表示
+type Provider interface {
+	// Name 渠道名称，与回调地址 /payments/webhook/:provider 对应
+	Name() string
+	// CreateIntent 创建支付意图，客户端凭返回的ClientSecret完成支付
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// VerifyWebhook 校验回调签名并解析事件
+	VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+	// Refund 对已支付的交易发起退款
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+}
+
+// IntentRequest 创建支付意图请求
+type IntentRequest struct {
+	OrderID     string
+	OrderNumber string
+	Amount      int64
+	Currency    string
+}
+
+// Intent 支付意图
+type Intent struct {
+	ID           string `json:"id"`
+	Provider     string `json:"provider"`
+	ClientSecret string `json:"clientSecret"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+}
+
+// WebhookEvent 校验通过的回调事件
+type WebhookEvent struct {
+	ID            string
+	Type          string
+	OrderID       string
+	IntentID      string
+	TransactionID string
+	RefundID      string
+	Amount        int64
+	Currency      string
+}
+
+// RefundRequest 退款请求
+type RefundRequest struct {
+	TransactionID string
+	Amount        int64
+	Currency      string
+	Reason        string
+	// IdempotencyKey 幂等键，同一个键重复请求时渠道只退款一次
+	IdempotencyKey string
+}
+
+// Refund 退款结果
+type Refund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
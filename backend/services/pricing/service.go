@@ -0,0 +1,332 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+	"project/backend/models"
+)
+
+// 规则来源
+const (
+	SourceConfig = "config"
+	SourceMongo  = "mongo"
+)
+
+const (
+	// DefaultMethod 未配置时的默认配送方式
+	DefaultMethod = "standard"
+	// defaultItemWeight 设备和类型都没有重量数据时的估算重量（克）
+	defaultItemWeight = 500
+)
+
+// Rules 税费和运费规则
+type Rules struct {
+	TaxRules      []models.TaxRule
+	ShippingRates []models.ShippingRate
+}
+
+// RuleStore 规则来源
+type RuleStore interface {
+	Load(ctx context.Context) (*Rules, error)
+}
+
+// Calculator 按收货地址、配送方式和重量计算税费和运费
+type Calculator struct {
+	store          RuleStore
+	defaultMethod  string
+	defaultWeights map[string]int
+}
+
+// Input 计算所需的订单信息，金额单位为分
+type Input struct {
+	Country  string
+	State    string
+	Method   string
+	Currency string
+	Subtotal int64 // 商品小计
+	Discount int64 // 优惠券折扣
+	Items    []Item
+}
+
+// Item 参与计重的商品
+type Item struct {
+	ProductType string
+	Weight      float64 // 单件重量（克），为0时按类型估算
+	Quantity    int
+}
+
+// Breakdown 计算结果
+// 含税价时税额已包含在商品金额中，不再计入总价
+type Breakdown struct {
+	ShippingMethod string `json:"shippingMethod"`
+	Weight         int    `json:"weight"` // 总重量（克）
+	ShippingFee    int64  `json:"shippingFee"`
+	TaxRate        int    `json:"taxRate"` // 万分之一
+	TaxInclusive   bool   `json:"taxInclusive"`
+	Tax            int64  `json:"tax"`
+	Total          int64  `json:"total"`
+}
+
+// NewCalculator 创建计算器，规则来源由配置决定
+// 没有配置任何规则时使用默认规则：统一运费10元，税率5%不含税
+func NewCalculator(db *mongo.Database, cfg config.PricingConfig) *Calculator {
+	var store RuleStore = NewConfigStore(cfg)
+	if cfg.Source == SourceMongo && db != nil {
+		store = NewMongoStore(db)
+	}
+
+	method := cfg.DefaultMethod
+	if method == "" {
+		method = DefaultMethod
+	}
+	return &Calculator{
+		store:          store,
+		defaultMethod:  method,
+		defaultWeights: cfg.DefaultWeights,
+	}
+}
+
+// Calculate 计算税费和运费
+func (c *Calculator) Calculate(ctx context.Context, in Input) (*Breakdown, error) {
+	rules, err := c.store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.Method == "" {
+		in.Method = c.defaultMethod
+	}
+	return calculate(rules, in, c.itemWeight)
+}
+
+// itemWeight 单件重量，设备没有重量数据时按类型估算
+func (c *Calculator) itemWeight(item Item) int {
+	if item.Weight > 0 {
+		return int(item.Weight + 0.5)
+	}
+	if w, ok := c.defaultWeights[item.ProductType]; ok && w > 0 {
+		return w
+	}
+	return defaultItemWeight
+}
+
+// calculate 按规则计算
+// 运费不计税；免运费门槛按折后商品金额判断
+func calculate(rules *Rules, in Input, itemWeight func(Item) int) (*Breakdown, error) {
+	goods := in.Subtotal - in.Discount
+	if goods < 0 {
+		goods = 0
+	}
+
+	weight := 0
+	for _, item := range in.Items {
+		weight += itemWeight(item) * item.Quantity
+	}
+
+	rate := matchShippingRate(rules.ShippingRates, in.Method, in.Country, in.State)
+	if rate == nil {
+		return nil, errors.NewBadRequestError("该地区不支持所选的配送方式")
+	}
+	// 运费规则没有指定币种时适用任意币种
+	if rate.Currency != "" && in.Currency != "" && rate.Currency != in.Currency {
+		return nil, errors.NewBadRequestError("该地区的运费不支持订单币种")
+	}
+
+	breakdown := &Breakdown{
+		ShippingMethod: in.Method,
+		Weight:         weight,
+		ShippingFee:    shippingFee(rate, weight, goods),
+	}
+
+	if rule := matchTaxRule(rules.TaxRules, in.Country, in.State); rule != nil {
+		breakdown.TaxRate = rule.Rate
+		breakdown.TaxInclusive = rule.Inclusive
+		if rule.Inclusive {
+			// 从含税价中拆出税额
+			breakdown.Tax = goods - roundDiv(goods*10000, int64(10000+rule.Rate))
+		} else {
+			breakdown.Tax = roundDiv(goods*int64(rule.Rate), 10000)
+		}
+	}
+
+	breakdown.Total = goods + breakdown.ShippingFee
+	if !breakdown.TaxInclusive {
+		breakdown.Total += breakdown.Tax
+	}
+	return breakdown, nil
+}
+
+// shippingFee 首重加续重计费，不足一个续重单位按一个计算
+func shippingFee(rate *models.ShippingRate, weight int, goods int64) int64 {
+	if rate.FreeOver > 0 && goods >= rate.FreeOver {
+		return 0
+	}
+	fee := rate.BaseFee
+	if extra := weight - rate.IncludedWeight; extra > 0 && rate.StepWeight > 0 {
+		steps := (extra + rate.StepWeight - 1) / rate.StepWeight
+		fee += int64(steps) * rate.StepFee
+	}
+	return fee
+}
+
+// matchTaxRule 依次匹配国家+省/州、国家、默认规则
+func matchTaxRule(rules []models.TaxRule, country, state string) *models.TaxRule {
+	var countryRule, defaultRule *models.TaxRule
+	for i := range rules {
+		rule := &rules[i]
+		switch {
+		case rule.Country == "":
+			if defaultRule == nil {
+				defaultRule = rule
+			}
+		case strings.EqualFold(rule.Country, country):
+			if rule.State != "" && strings.EqualFold(rule.State, state) {
+				return rule
+			}
+			if rule.State == "" && countryRule == nil {
+				countryRule = rule
+			}
+		}
+	}
+	if countryRule != nil {
+		return countryRule
+	}
+	return defaultRule
+}
+
+// matchShippingRate 优先匹配指定配送方式的规则，没有时使用不限配送方式的规则
+func matchShippingRate(rates []models.ShippingRate, method, country, state string) *models.ShippingRate {
+	if rate := matchDestination(rates, method, country, state); rate != nil {
+		return rate
+	}
+	return matchDestination(rates, "", country, state)
+}
+
+// matchDestination 在同一配送方式的规则中依次匹配国家+省/州、国家、默认目的地
+func matchDestination(rates []models.ShippingRate, method, country, state string) *models.ShippingRate {
+	var countryRate, defaultRate *models.ShippingRate
+	for i := range rates {
+		rate := &rates[i]
+		if !strings.EqualFold(rate.Method, method) {
+			continue
+		}
+		switch {
+		case rate.Country == "":
+			if defaultRate == nil {
+				defaultRate = rate
+			}
+		case strings.EqualFold(rate.Country, country):
+			if rate.State != "" && strings.EqualFold(rate.State, state) {
+				return rate
+			}
+			if rate.State == "" && countryRate == nil {
+				countryRate = rate
+			}
+		}
+	}
+	if countryRate != nil {
+		return countryRate
+	}
+	return defaultRate
+}
+
+// roundDiv 四舍五入的整数除法，参数均为非负数
+func roundDiv(a, b int64) int64 {
+	return (a + b/2) / b
+}
+
+// ConfigStore 使用配置文件中的规则
+type ConfigStore struct {
+	rules *Rules
+}
+
+// NewConfigStore 从配置创建规则，没有配置时使用默认规则
+func NewConfigStore(cfg config.PricingConfig) *ConfigStore {
+	rules := &Rules{}
+	for _, r := range cfg.TaxRules {
+		rules.TaxRules = append(rules.TaxRules, models.TaxRule{
+			Country:   r.Country,
+			State:     r.State,
+			Rate:      r.Rate,
+			Inclusive: r.Inclusive,
+			Name:      r.Name,
+		})
+	}
+	for _, r := range cfg.ShippingRates {
+		rules.ShippingRates = append(rules.ShippingRates, models.ShippingRate{
+			Method:         r.Method,
+			Country:        r.Country,
+			State:          r.State,
+			Currency:       r.Currency,
+			BaseFee:        r.BaseFee,
+			IncludedWeight: r.IncludedWeight,
+			StepWeight:     r.StepWeight,
+			StepFee:        r.StepFee,
+			FreeOver:       r.FreeOver,
+		})
+	}
+	if len(rules.TaxRules) == 0 {
+		rules.TaxRules = DefaultRules().TaxRules
+	}
+	if len(rules.ShippingRates) == 0 {
+		rules.ShippingRates = DefaultRules().ShippingRates
+	}
+	return &ConfigStore{rules: rules}
+}
+
+// Load 返回配置的规则
+func (s *ConfigStore) Load(ctx context.Context) (*Rules, error) {
+	return s.rules, nil
+}
+
+// DefaultRules 默认规则：任意地区和配送方式统一运费10元，税率5%不含税
+func DefaultRules() *Rules {
+	return &Rules{
+		TaxRules:      []models.TaxRule{{Rate: 500}},
+		ShippingRates: []models.ShippingRate{{BaseFee: 1000}},
+	}
+}
+
+// MongoStore 从数据库读取规则，修改后无需重启即可生效
+type MongoStore struct {
+	db *mongo.Database
+}
+
+// NewMongoStore 创建数据库规则来源
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{db: db}
+}
+
+// Load 读取全部规则，集合为空时使用默认规则
+func (s *MongoStore) Load(ctx context.Context) (*Rules, error) {
+	rules := &Rules{}
+
+	cursor, err := s.db.Collection(models.TaxRulesCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取税率规则失败: " + err.Error())
+	}
+	if err := cursor.All(ctx, &rules.TaxRules); err != nil {
+		return nil, errors.NewInternalServerError("解析税率规则失败: " + err.Error())
+	}
+
+	cursor, err = s.db.Collection(models.ShippingRatesCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取运费规则失败: " + err.Error())
+	}
+	if err := cursor.All(ctx, &rules.ShippingRates); err != nil {
+		return nil, errors.NewInternalServerError("解析运费规则失败: " + err.Error())
+	}
+
+	if len(rules.TaxRules) == 0 {
+		rules.TaxRules = DefaultRules().TaxRules
+	}
+	if len(rules.ShippingRates) == 0 {
+		rules.ShippingRates = DefaultRules().ShippingRates
+	}
+	return rules, nil
+}
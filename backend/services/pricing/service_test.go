@@ -0,0 +1,94 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/config"
+	"project/backend/internal/errors"
+)
+
+func testCalculator() *Calculator {
+	return NewCalculator(nil, config.PricingConfig{
+		DefaultWeights: map[string]int{"keyboard": 1200},
+		TaxRules: []config.TaxRuleConfig{
+			{Country: "CN", Rate: 1300, Inclusive: true},
+			{Country: "US", Rate: 0},
+			{Country: "US", State: "CA", Rate: 725},
+			{Rate: 500},
+		},
+		ShippingRates: []config.ShippingRateConfig{
+			{Method: "standard", Country: "CN", BaseFee: 1000, IncludedWeight: 1000, StepWeight: 500, StepFee: 300, FreeOver: 29900},
+			{Method: "express", Country: "CN", BaseFee: 2300},
+			{Method: "standard", BaseFee: 8000, IncludedWeight: 500, StepWeight: 500, StepFee: 3000},
+		},
+	})
+}
+
+func TestCalculateInclusiveVAT(t *testing.T) {
+	b, err := testCalculator().Calculate(context.Background(), Input{
+		Country:  "cn",
+		Subtotal: 11300,
+		Items:    []Item{{ProductType: "mouse", Weight: 60, Quantity: 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "standard", b.ShippingMethod)
+	assert.Equal(t, 120, b.Weight)
+	assert.Equal(t, int64(1000), b.ShippingFee)
+	assert.True(t, b.TaxInclusive)
+	assert.Equal(t, int64(1300), b.Tax)
+	// 含税价不再加税
+	assert.Equal(t, int64(12300), b.Total)
+}
+
+func TestCalculateStateRuleAndWeightSteps(t *testing.T) {
+	b, err := testCalculator().Calculate(context.Background(), Input{
+		Country:  "US",
+		State:    "CA",
+		Subtotal: 20000,
+		Discount: 2000,
+		Items:    []Item{{ProductType: "keyboard", Quantity: 1}},
+	})
+	require.NoError(t, err)
+	// 键盘没有重量数据按1200克估算，超出首重700克，按2个续重单位计费
+	assert.Equal(t, 1200, b.Weight)
+	assert.Equal(t, int64(14000), b.ShippingFee)
+	assert.False(t, b.TaxInclusive)
+	assert.Equal(t, int64(1305), b.Tax)
+	assert.Equal(t, int64(18000+14000+1305), b.Total)
+
+	b, err = testCalculator().Calculate(context.Background(), Input{Country: "US", State: "NY", Subtotal: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), b.Tax)
+}
+
+func TestCalculateFreeShippingAndMethods(t *testing.T) {
+	c := testCalculator()
+
+	b, err := c.Calculate(context.Background(), Input{Country: "CN", Subtotal: 29900})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), b.ShippingFee)
+
+	b, err = c.Calculate(context.Background(), Input{Country: "CN", Method: "express", Subtotal: 29900})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2300), b.ShippingFee)
+
+	// 其他国家没有加急配送
+	_, err = c.Calculate(context.Background(), Input{Country: "DE", Method: "express", Subtotal: 10000})
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestDefaultRulesMatchPreviousBehaviour(t *testing.T) {
+	b, err := NewCalculator(nil, config.PricingConfig{}).Calculate(context.Background(), Input{
+		Country:  "CN",
+		Method:   "express",
+		Subtotal: 159800,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), b.ShippingFee)
+	assert.Equal(t, int64(7990), b.Tax)
+	assert.Equal(t, int64(159800+1000+7990), b.Total)
+}
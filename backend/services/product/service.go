@@ -0,0 +1,478 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/product"
+)
+
+// Service 商品目录服务接口
+// 购物车和订单的价格、名称、图片都从这里取，不信任客户端传入的值
+type Service interface {
+	ListDeviceSKUs(ctx context.Context, deviceID string, includeInactive bool) (*product.SKUListResponse, error)
+	CreateSKU(ctx context.Context, deviceID string, req product.CreateSKURequest) (*models.ProductSKU, error)
+	UpdateSKU(ctx context.Context, deviceID, skuID string, req product.UpdateSKURequest) (*models.ProductSKU, error)
+	DeleteSKU(ctx context.Context, deviceID, skuID string) error
+
+	// PriceLines 按当前目录给商品行定价，不可购买的行通过Available标记，不返回错误
+	PriceLines(ctx context.Context, lines []Line) ([]PricedLine, error)
+	// ResolveLines 定价并校验所有行都可以购买，用于加购和下单
+	ResolveLines(ctx context.Context, lines []Line) ([]PricedLine, error)
+
+	// ReserveStock 扣减可售库存，任意一行库存不足时返回错误，需在事务中调用
+	ReserveStock(ctx context.Context, items []models.ReservedItem) error
+	// ReleaseStock 归还预留的库存
+	ReleaseStock(ctx context.Context, items []models.ReservedItem) error
+}
+
+// Line 待定价的商品行
+type Line struct {
+	SKUID    primitive.ObjectID
+	Quantity int
+}
+
+// PricedLine 按目录定价后的商品行，金额单位为分
+type PricedLine struct {
+	Line
+	SKU         *models.ProductSKU
+	DeviceID    primitive.ObjectID
+	Brand       string
+	ProductType string
+	Name        string
+	ImageURL    string
+	UnitPrice   int64
+	Currency    string
+	Subtotal    int64
+	Weight      float64 // 单件重量（克），设备没有重量数据时为0
+	Available   bool
+	Reason      string // 不可购买的原因
+}
+
+// catalogDevice 定价用到的设备字段，只有鼠标等部分类型记录了重量
+type catalogDevice struct {
+	models.HardwareDevice `bson:",inline"`
+	Dimensions            struct {
+		Weight float64 `bson:"weight"`
+	} `bson:"dimensions"`
+	Technical struct {
+		Weight float64 `bson:"weight"`
+	} `bson:"technical"`
+}
+
+// weight 设备重量（克），优先使用尺寸中的重量
+func (d *catalogDevice) weight() float64 {
+	if d.Dimensions.Weight > 0 {
+		return d.Dimensions.Weight
+	}
+	return d.Technical.Weight
+}
+
+// ServiceImpl 商品目录服务实现
+type ServiceImpl struct {
+	db *mongo.Database
+}
+
+// New 创建商品目录服务
+func New(db *mongo.Database) Service {
+	return &ServiceImpl{db: db}
+}
+
+// ListDeviceSKUs 获取设备的SKU列表
+func (s *ServiceImpl) ListDeviceSKUs(ctx context.Context, deviceID string, includeInactive bool) (*product.SKUListResponse, error) {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+
+	filter := bson.M{"deviceId": id}
+	if !includeInactive {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "price", Value: 1}, {Key: "code", Value: 1}})
+	cursor, err := s.db.Collection(models.ProductSKUsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取SKU列表失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	skus := []models.ProductSKU{}
+	if err := cursor.All(ctx, &skus); err != nil {
+		return nil, errors.NewInternalServerError("解析SKU列表失败: " + err.Error())
+	}
+
+	return &product.SKUListResponse{DeviceID: deviceID, SKUs: skus}, nil
+}
+
+// CreateSKU 为设备创建SKU
+func (s *ServiceImpl) CreateSKU(ctx context.Context, deviceID string, req product.CreateSKURequest) (*models.ProductSKU, error) {
+	id, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return nil, errors.NewBadRequestError("SKU编码不能为空")
+	}
+	if req.Price <= 0 {
+		return nil, errors.NewBadRequestError("价格必须大于0")
+	}
+	if req.Stock < 0 {
+		return nil, errors.NewBadRequestError("库存不能为负数")
+	}
+
+	err = s.db.Collection(models.DevicesCollection).FindOne(ctx, bson.M{"_id": id, "deletedAt": nil}).Err()
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("设备不存在")
+		}
+		return nil, errors.NewInternalServerError("获取设备失败: " + err.Error())
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	now := time.Now()
+	sku := &models.ProductSKU{
+		ID:        primitive.NewObjectID(),
+		DeviceID:  id,
+		Code:      code,
+		Variant:   req.Variant,
+		Price:     req.Price,
+		Currency:  normalizeCurrency(req.Currency),
+		Stock:     req.Stock,
+		ImageURL:  req.ImageURL,
+		Active:    active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if _, err := s.db.Collection(models.ProductSKUsCollection).InsertOne(ctx, sku); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.NewBadRequestError("SKU编码已存在")
+		}
+		return nil, errors.NewInternalServerError("创建SKU失败: " + err.Error())
+	}
+	return sku, nil
+}
+
+// UpdateSKU 更新SKU
+func (s *ServiceImpl) UpdateSKU(ctx context.Context, deviceID, skuID string, req product.UpdateSKURequest) (*models.ProductSKU, error) {
+	filter, err := skuFilter(deviceID, skuID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	if req.Variant != nil {
+		set["variant"] = *req.Variant
+	}
+	if req.Price != nil {
+		if *req.Price <= 0 {
+			return nil, errors.NewBadRequestError("价格必须大于0")
+		}
+		set["price"] = *req.Price
+	}
+	if req.Currency != nil {
+		set["currency"] = normalizeCurrency(*req.Currency)
+	}
+	if req.Stock != nil {
+		if *req.Stock < 0 {
+			return nil, errors.NewBadRequestError("库存不能为负数")
+		}
+		set["stock"] = *req.Stock
+	}
+	if req.ImageURL != nil {
+		set["imageUrl"] = *req.ImageURL
+	}
+	if req.Active != nil {
+		set["active"] = *req.Active
+	}
+
+	var sku models.ProductSKU
+	err = s.db.Collection(models.ProductSKUsCollection).FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&sku)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("SKU不存在")
+		}
+		return nil, errors.NewInternalServerError("更新SKU失败: " + err.Error())
+	}
+	return &sku, nil
+}
+
+// DeleteSKU 删除SKU，并从购物车中移除
+// 已下单的订单保存的是商品快照，不受影响
+func (s *ServiceImpl) DeleteSKU(ctx context.Context, deviceID, skuID string) error {
+	filter, err := skuFilter(deviceID, skuID)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Collection(models.ProductSKUsCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return errors.NewInternalServerError("删除SKU失败: " + err.Error())
+	}
+	if res.DeletedCount == 0 {
+		return errors.NewNotFoundError("SKU不存在")
+	}
+
+	id := filter["_id"]
+	_, err = s.db.Collection(models.CartCollection).UpdateMany(ctx,
+		bson.M{"items.sku_id": id},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"sku_id": id}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("清理购物车失败: " + err.Error())
+	}
+	return nil
+}
+
+// PriceLines 按当前目录给商品行定价
+func (s *ServiceImpl) PriceLines(ctx context.Context, lines []Line) ([]PricedLine, error) {
+	if len(lines) == 0 {
+		return []PricedLine{}, nil
+	}
+
+	skuIDs := make([]primitive.ObjectID, 0, len(lines))
+	for _, line := range lines {
+		skuIDs = append(skuIDs, line.SKUID)
+	}
+
+	cursor, err := s.db.Collection(models.ProductSKUsCollection).Find(ctx, bson.M{"_id": bson.M{"$in": skuIDs}})
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取商品信息失败: " + err.Error())
+	}
+	var skus []models.ProductSKU
+	if err := cursor.All(ctx, &skus); err != nil {
+		return nil, errors.NewInternalServerError("解析商品信息失败: " + err.Error())
+	}
+
+	skuMap := make(map[primitive.ObjectID]*models.ProductSKU, len(skus))
+	deviceIDs := make([]primitive.ObjectID, 0, len(skus))
+	for i := range skus {
+		skuMap[skus[i].ID] = &skus[i]
+		deviceIDs = append(deviceIDs, skus[i].DeviceID)
+	}
+
+	// 回收站中的设备不可购买
+	deviceMap := make(map[primitive.ObjectID]*catalogDevice, len(deviceIDs))
+	if len(deviceIDs) > 0 {
+		opts := options.Find().SetProjection(bson.M{
+			"name": 1, "brand": 1, "type": 1, "imageUrl": 1,
+			"dimensions.weight": 1, "technical.weight": 1,
+		})
+		cursor, err := s.db.Collection(models.DevicesCollection).Find(ctx,
+			bson.M{"_id": bson.M{"$in": deviceIDs}, "deletedAt": nil}, opts)
+		if err != nil {
+			return nil, errors.NewInternalServerError("获取设备信息失败: " + err.Error())
+		}
+		var devices []catalogDevice
+		if err := cursor.All(ctx, &devices); err != nil {
+			return nil, errors.NewInternalServerError("解析设备信息失败: " + err.Error())
+		}
+		for i := range devices {
+			deviceMap[devices[i].ID] = &devices[i]
+		}
+	}
+
+	priced := make([]PricedLine, 0, len(lines))
+	for _, line := range lines {
+		sku := skuMap[line.SKUID]
+		var device *catalogDevice
+		if sku != nil {
+			device = deviceMap[sku.DeviceID]
+		}
+		if device == nil {
+			priced = append(priced, priceLine(line, sku, nil))
+			continue
+		}
+		pricedLine := priceLine(line, sku, &device.HardwareDevice)
+		pricedLine.Weight = device.weight()
+		priced = append(priced, pricedLine)
+	}
+	return priced, nil
+}
+
+// ResolveLines 定价并校验所有行都可以购买
+func (s *ServiceImpl) ResolveLines(ctx context.Context, lines []Line) ([]PricedLine, error) {
+	merged, err := mergeLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	priced, err := s.PriceLines(ctx, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range priced {
+		if !line.Available {
+			name := line.Name
+			if name == "" {
+				name = line.SKUID.Hex()
+			}
+			return nil, errors.NewBadRequestError(fmt.Sprintf("%s: %s", name, line.Reason))
+		}
+		if line.Currency != priced[0].Currency {
+			return nil, errors.NewBadRequestError("不支持不同币种的商品一起结算")
+		}
+	}
+	return priced, nil
+}
+
+// ReserveStock 扣减可售库存
+// 按库存条件更新，并发下单时不会超卖；部分成功的扣减由调用方的事务回滚
+func (s *ServiceImpl) ReserveStock(ctx context.Context, items []models.ReservedItem) error {
+	collection := s.db.Collection(models.ProductSKUsCollection)
+	for _, item := range items {
+		filter, update := reserveStockUpdate(item, time.Now())
+		res, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return errors.NewBadRequestError(fmt.Sprintf("商品%s库存不足", item.SKUID.Hex()))
+		}
+	}
+	return nil
+}
+
+// reserveStockUpdate 扣减库存的条件和更新，库存不足或SKU已下架时条件不匹配
+// 检查和扣减在同一次原子更新中完成，并发下单不会把库存扣成负数
+func reserveStockUpdate(item models.ReservedItem, now time.Time) (filter, update bson.M) {
+	filter = bson.M{"_id": item.SKUID, "active": true, "stock": bson.M{"$gte": item.Quantity}}
+	update = bson.M{
+		"$inc": bson.M{"stock": -item.Quantity},
+		"$set": bson.M{"updatedAt": now},
+	}
+	return filter, update
+}
+
+// ReleaseStock 归还预留的库存，SKU已删除时忽略
+func (s *ServiceImpl) ReleaseStock(ctx context.Context, items []models.ReservedItem) error {
+	collection := s.db.Collection(models.ProductSKUsCollection)
+	for _, item := range items {
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"_id": item.SKUID},
+			bson.M{
+				"$inc": bson.M{"stock": item.Quantity},
+				"$set": bson.M{"updatedAt": time.Now()},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeLines 校验数量并合并重复的SKU
+func mergeLines(lines []Line) ([]Line, error) {
+	if len(lines) == 0 {
+		return nil, errors.NewBadRequestError("至少需要一件商品")
+	}
+
+	merged := make([]Line, 0, len(lines))
+	index := make(map[primitive.ObjectID]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, errors.NewBadRequestError("商品数量必须大于0")
+		}
+		if i, ok := index[line.SKUID]; ok {
+			merged[i].Quantity += line.Quantity
+			continue
+		}
+		index[line.SKUID] = len(merged)
+		merged = append(merged, line)
+	}
+	return merged, nil
+}
+
+// priceLine 计算单行价格和可购买状态
+func priceLine(line Line, sku *models.ProductSKU, hw *models.HardwareDevice) PricedLine {
+	priced := PricedLine{Line: line}
+	if sku == nil || hw == nil {
+		priced.Reason = "商品不存在"
+		return priced
+	}
+
+	priced.SKU = sku
+	priced.DeviceID = sku.DeviceID
+	priced.Brand = hw.Brand
+	priced.ProductType = string(hw.Type)
+	priced.Name = lineName(hw, sku.Variant)
+	priced.ImageURL = sku.ImageURL
+	if priced.ImageURL == "" {
+		priced.ImageURL = hw.ImageURL
+	}
+	priced.UnitPrice = sku.Price
+	priced.Currency = sku.Currency
+	priced.Subtotal = sku.Price * int64(line.Quantity)
+
+	switch {
+	case !sku.Active:
+		priced.Reason = "商品已下架"
+	case sku.Stock < line.Quantity:
+		priced.Reason = fmt.Sprintf("库存不足，剩余%d件", sku.Stock)
+	default:
+		priced.Available = true
+	}
+	return priced
+}
+
+// lineName 生成商品行名称，例如 "Logitech G Pro (黑色 / wireless)"
+func lineName(hw *models.HardwareDevice, variant models.ProductVariant) string {
+	name := strings.TrimSpace(hw.Brand + " " + hw.Name)
+
+	var parts []string
+	if variant.Color != "" {
+		parts = append(parts, variant.Color)
+	}
+	if variant.Connection != "" {
+		parts = append(parts, variant.Connection)
+	}
+	if len(parts) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(parts, " / "))
+}
+
+// normalizeCurrency 货币代码统一为大写，为空时使用默认货币
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return models.DefaultCurrency
+	}
+	return currency
+}
+
+// skuFilter 构造按设备和SKU查询的条件
+func skuFilter(deviceID, skuID string) (bson.M, error) {
+	did, err := primitive.ObjectIDFromHex(deviceID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的设备ID")
+	}
+	sid, err := primitive.ObjectIDFromHex(skuID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的SKU ID")
+	}
+	return bson.M{"_id": sid, "deviceId": did}, nil
+}
@@ -0,0 +1,122 @@
+package product
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/tests/testutil"
+)
+
+func TestMergeLines(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+
+	merged, err := mergeLines([]Line{{SKUID: a, Quantity: 1}, {SKUID: b, Quantity: 2}, {SKUID: a, Quantity: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, []Line{{SKUID: a, Quantity: 4}, {SKUID: b, Quantity: 2}}, merged)
+
+	_, err = mergeLines([]Line{{SKUID: a, Quantity: 0}})
+	assert.Error(t, err)
+
+	_, err = mergeLines(nil)
+	assert.Error(t, err)
+}
+
+func TestPriceLine(t *testing.T) {
+	hw := &models.HardwareDevice{
+		ID:       primitive.NewObjectID(),
+		Name:     "G Pro",
+		Brand:    "Logitech",
+		Type:     models.DeviceTypeMouse,
+		ImageURL: "https://example.com/gpro.png",
+	}
+	sku := &models.ProductSKU{
+		ID:       primitive.NewObjectID(),
+		DeviceID: hw.ID,
+		Code:     "GPRO-BLK-WL",
+		Variant:  models.ProductVariant{Color: "黑色", Connection: "wireless"},
+		Price:    79900,
+		Currency: "CNY",
+		Stock:    3,
+		Active:   true,
+	}
+
+	line := priceLine(Line{SKUID: sku.ID, Quantity: 2}, sku, hw)
+	assert.True(t, line.Available)
+	assert.Equal(t, "Logitech G Pro (黑色 / wireless)", line.Name)
+	assert.Equal(t, int64(159800), line.Subtotal)
+	assert.Equal(t, hw.ImageURL, line.ImageURL)
+	assert.Equal(t, "mouse", line.ProductType)
+
+	line = priceLine(Line{SKUID: sku.ID, Quantity: 4}, sku, hw)
+	assert.False(t, line.Available)
+	assert.Contains(t, line.Reason, "库存不足")
+
+	sku.Active = false
+	line = priceLine(Line{SKUID: sku.ID, Quantity: 1}, sku, hw)
+	assert.False(t, line.Available)
+	assert.Equal(t, "商品已下架", line.Reason)
+
+	// 设备在回收站中
+	line = priceLine(Line{SKUID: sku.ID, Quantity: 1}, sku, nil)
+	assert.False(t, line.Available)
+	assert.Equal(t, "商品不存在", line.Reason)
+}
+
+func TestReserveStockUpdate(t *testing.T) {
+	item := models.ReservedItem{SKUID: primitive.NewObjectID(), Quantity: 2}
+	now := time.Now()
+
+	filter, update := reserveStockUpdate(item, now)
+	// 库存检查必须和扣减在同一条件中，不能先查询再扣减
+	assert.Equal(t, bson.M{"_id": item.SKUID, "active": true, "stock": bson.M{"$gte": 2}}, filter)
+	assert.Equal(t, bson.M{"$inc": bson.M{"stock": -2}, "$set": bson.M{"updatedAt": now}}, update)
+}
+
+// TestReserveStockConcurrentLastUnit 并发扣减最后一件库存，只有一次成功
+// 扣减本身不需要事务，单机MongoDB即可运行
+func TestReserveStockConcurrentLastUnit(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sku := models.ProductSKU{ID: primitive.NewObjectID(), DeviceID: primitive.NewObjectID(), Code: "LAST-UNIT", Price: 19900, Currency: "CNY", Stock: 1, Active: true}
+	_, err := db.Collection(models.ProductSKUsCollection).InsertOne(ctx, sku)
+	require.NoError(t, err)
+
+	svc := New(db)
+	const buyers = 8
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := svc.ReserveStock(ctx, []models.ReservedItem{{SKUID: sku.ID, Quantity: 1}})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err), err.Error())
+				return
+			}
+			succeeded++
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	var stored models.ProductSKU
+	require.NoError(t, db.Collection(models.ProductSKUsCollection).FindOne(ctx, bson.M{"_id": sku.ID}).Decode(&stored))
+	assert.Equal(t, 0, stored.Stock)
+}
@@ -0,0 +1,440 @@
+package promotion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/product"
+	"project/backend/types/promotion"
+)
+
+// Service 优惠券服务接口
+// Quote只做校验和计算，下单时由Redeem在订单事务中占用次数
+type Service interface {
+	CreateCoupon(ctx context.Context, req promotion.CreateCouponRequest) (*models.Coupon, error)
+	ListCoupons(ctx context.Context) (*promotion.CouponListResponse, error)
+	UpdateCoupon(ctx context.Context, couponID string, req promotion.UpdateCouponRequest) (*models.Coupon, error)
+	DeleteCoupon(ctx context.Context, couponID string) error
+
+	// Quote 校验优惠券对这些商品是否可用并计算折扣
+	Quote(ctx context.Context, userID primitive.ObjectID, code string, lines []product.PricedLine) (*Quote, error)
+	// Redeem 占用一次使用次数并记录到订单，需在下单事务中调用
+	Redeem(ctx context.Context, quote *Quote, userID, orderID primitive.ObjectID) error
+	// Release 订单取消时归还使用次数，订单没有用券时不做处理，需在事务中调用
+	Release(ctx context.Context, orderID primitive.ObjectID) error
+}
+
+// Quote 优惠券的计算结果，金额单位为分
+type Quote struct {
+	Coupon   *models.Coupon
+	Discount int64
+	// LineDiscounts 折扣按金额比例分摊到适用的商品行，用于按行退款
+	LineDiscounts map[primitive.ObjectID]int64
+}
+
+// ServiceImpl 优惠券服务实现
+type ServiceImpl struct {
+	db  *mongo.Database
+	now func() time.Time
+}
+
+// New 创建优惠券服务
+func New(db *mongo.Database) Service {
+	return &ServiceImpl{db: db, now: time.Now}
+}
+
+// CreateCoupon 创建优惠券
+func (s *ServiceImpl) CreateCoupon(ctx context.Context, req promotion.CreateCouponRequest) (*models.Coupon, error) {
+	now := s.now()
+	coupon := &models.Coupon{
+		ID:           primitive.NewObjectID(),
+		Code:         normalizeCode(req.Code),
+		Name:         strings.TrimSpace(req.Name),
+		Type:         req.Type,
+		Value:        req.Value,
+		MaxDiscount:  req.MaxDiscount,
+		Currency:     strings.ToUpper(req.Currency),
+		MinSpend:     req.MinSpend,
+		Brands:       req.Brands,
+		DeviceTypes:  req.DeviceTypes,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Active:       req.Active == nil || *req.Active,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if coupon.Type == models.CouponTypeFixed && coupon.Currency == "" {
+		coupon.Currency = models.DefaultCurrency
+	}
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Collection(models.CouponsCollection).InsertOne(ctx, coupon); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.NewBadRequestError("券码已存在")
+		}
+		return nil, errors.NewInternalServerError("创建优惠券失败: " + err.Error())
+	}
+	return coupon, nil
+}
+
+// ListCoupons 获取全部优惠券
+func (s *ServiceImpl) ListCoupons(ctx context.Context) (*promotion.CouponListResponse, error) {
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	cursor, err := s.db.Collection(models.CouponsCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取优惠券列表失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	coupons := []models.Coupon{}
+	if err := cursor.All(ctx, &coupons); err != nil {
+		return nil, errors.NewInternalServerError("解析优惠券列表失败: " + err.Error())
+	}
+	return &promotion.CouponListResponse{Coupons: coupons}, nil
+}
+
+// UpdateCoupon 更新优惠券
+func (s *ServiceImpl) UpdateCoupon(ctx context.Context, couponID string, req promotion.UpdateCouponRequest) (*models.Coupon, error) {
+	id, err := primitive.ObjectIDFromHex(couponID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的优惠券ID")
+	}
+
+	var coupon models.Coupon
+	if err := s.db.Collection(models.CouponsCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&coupon); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("优惠券不存在")
+		}
+		return nil, errors.NewInternalServerError("获取优惠券失败: " + err.Error())
+	}
+
+	if req.Name != nil {
+		coupon.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Value != nil {
+		coupon.Value = *req.Value
+	}
+	if req.MaxDiscount != nil {
+		coupon.MaxDiscount = *req.MaxDiscount
+	}
+	if req.MinSpend != nil {
+		coupon.MinSpend = *req.MinSpend
+	}
+	if req.Brands != nil {
+		coupon.Brands = *req.Brands
+	}
+	if req.DeviceTypes != nil {
+		coupon.DeviceTypes = *req.DeviceTypes
+	}
+	if req.UsageLimit != nil {
+		coupon.UsageLimit = *req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.StartsAt != nil {
+		coupon.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		coupon.EndsAt = req.EndsAt
+	}
+	if req.Active != nil {
+		coupon.Active = *req.Active
+	}
+	if err := validateCoupon(&coupon); err != nil {
+		return nil, err
+	}
+	coupon.UpdatedAt = s.now()
+
+	// 使用次数由下单和取消维护，这里不覆盖
+	update := bson.M{"$set": bson.M{
+		"name":         coupon.Name,
+		"value":        coupon.Value,
+		"maxDiscount":  coupon.MaxDiscount,
+		"minSpend":     coupon.MinSpend,
+		"brands":       coupon.Brands,
+		"deviceTypes":  coupon.DeviceTypes,
+		"usageLimit":   coupon.UsageLimit,
+		"perUserLimit": coupon.PerUserLimit,
+		"startsAt":     coupon.StartsAt,
+		"endsAt":       coupon.EndsAt,
+		"active":       coupon.Active,
+		"updatedAt":    coupon.UpdatedAt,
+	}}
+	err = s.db.Collection(models.CouponsCollection).FindOneAndUpdate(ctx, bson.M{"_id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&coupon)
+	if err != nil {
+		return nil, errors.NewInternalServerError("更新优惠券失败: " + err.Error())
+	}
+	return &coupon, nil
+}
+
+// DeleteCoupon 删除优惠券，已使用过的优惠券只能停用
+func (s *ServiceImpl) DeleteCoupon(ctx context.Context, couponID string) error {
+	id, err := primitive.ObjectIDFromHex(couponID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的优惠券ID")
+	}
+
+	used, err := s.db.Collection(models.CouponRedemptionsCollection).CountDocuments(ctx, bson.M{"couponId": id})
+	if err != nil {
+		return errors.NewInternalServerError("获取优惠券使用记录失败: " + err.Error())
+	}
+	if used > 0 {
+		return errors.NewBadRequestError("优惠券已被使用，只能停用")
+	}
+
+	res, err := s.db.Collection(models.CouponsCollection).DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return errors.NewInternalServerError("删除优惠券失败: " + err.Error())
+	}
+	if res.DeletedCount == 0 {
+		return errors.NewNotFoundError("优惠券不存在")
+	}
+	return nil
+}
+
+// Quote 校验优惠券并计算折扣
+// 这里检查的使用次数只用于提前提示，真正的限制在Redeem中保证
+func (s *ServiceImpl) Quote(ctx context.Context, userID primitive.ObjectID, code string, lines []product.PricedLine) (*Quote, error) {
+	code = normalizeCode(code)
+	if code == "" {
+		return nil, errors.NewBadRequestError("券码不能为空")
+	}
+
+	var coupon models.Coupon
+	if err := s.db.Collection(models.CouponsCollection).FindOne(ctx, bson.M{"code": code}).Decode(&coupon); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("优惠券不存在")
+		}
+		return nil, errors.NewInternalServerError("获取优惠券失败: " + err.Error())
+	}
+
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return nil, errors.NewBadRequestError("优惠券已被领完")
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := s.userRedemptions(ctx, coupon.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return nil, errors.NewBadRequestError("已达到该优惠券的使用次数上限")
+		}
+	}
+
+	return calculateDiscount(&coupon, lines, s.now())
+}
+
+// Redeem 占用一次使用次数并记录到订单
+// 按当前次数条件递增，超过总次数时不会更新；同一事务中写优惠券文档，
+// 并发使用同一张券的事务会产生写冲突并由驱动重试，重试时能看到已提交的使用记录，
+// 因此之后统计的用户使用次数是准确的
+func (s *ServiceImpl) Redeem(ctx context.Context, quote *Quote, userID, orderID primitive.ObjectID) error {
+	now := s.now()
+
+	var coupon models.Coupon
+	err := s.db.Collection(models.CouponsCollection).FindOneAndUpdate(ctx,
+		bson.M{
+			"_id":    quote.Coupon.ID,
+			"active": true,
+			"$or": []bson.M{
+				{"usageLimit": 0},
+				{"$expr": bson.M{"$lt": []string{"$usedCount", "$usageLimit"}}},
+			},
+		},
+		bson.M{
+			"$inc": bson.M{"usedCount": 1},
+			"$set": bson.M{"updatedAt": now},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&coupon)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errors.NewBadRequestError("优惠券已被领完或已停用")
+		}
+		return err
+	}
+
+	if coupon.PerUserLimit > 0 {
+		used, err := s.userRedemptions(ctx, coupon.ID, userID)
+		if err != nil {
+			return err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return errors.NewBadRequestError("已达到该优惠券的使用次数上限")
+		}
+	}
+
+	_, err = s.db.Collection(models.CouponRedemptionsCollection).InsertOne(ctx, &models.CouponRedemption{
+		ID:        primitive.NewObjectID(),
+		CouponID:  coupon.ID,
+		Code:      coupon.Code,
+		UserID:    userID,
+		OrderID:   orderID,
+		Discount:  quote.Discount,
+		Status:    models.CouponRedemptionApplied,
+		CreatedAt: now,
+	})
+	return err
+}
+
+// Release 归还订单占用的优惠券次数
+func (s *ServiceImpl) Release(ctx context.Context, orderID primitive.ObjectID) error {
+	now := s.now()
+
+	var redemption models.CouponRedemption
+	err := s.db.Collection(models.CouponRedemptionsCollection).FindOneAndUpdate(ctx,
+		bson.M{"orderId": orderID, "status": models.CouponRedemptionApplied},
+		bson.M{"$set": bson.M{
+			"status":     models.CouponRedemptionReleased,
+			"releasedAt": now,
+		}},
+	).Decode(&redemption)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	_, err = s.db.Collection(models.CouponsCollection).UpdateOne(ctx,
+		bson.M{"_id": redemption.CouponID, "usedCount": bson.M{"$gt": 0}},
+		bson.M{
+			"$inc": bson.M{"usedCount": -1},
+			"$set": bson.M{"updatedAt": now},
+		},
+	)
+	return err
+}
+
+// userRedemptions 统计用户未归还的使用次数
+func (s *ServiceImpl) userRedemptions(ctx context.Context, couponID, userID primitive.ObjectID) (int64, error) {
+	count, err := s.db.Collection(models.CouponRedemptionsCollection).CountDocuments(ctx, bson.M{
+		"couponId": couponID,
+		"userId":   userID,
+		"status":   models.CouponRedemptionApplied,
+	})
+	if err != nil {
+		return 0, errors.NewInternalServerError("获取优惠券使用记录失败: " + err.Error())
+	}
+	return count, nil
+}
+
+// calculateDiscount 检查有效期、适用范围和门槛，计算折扣并分摊到商品行
+// 只有可购买的商品参与计算，门槛按适用商品的金额判断
+func calculateDiscount(coupon *models.Coupon, lines []product.PricedLine, now time.Time) (*Quote, error) {
+	if !coupon.Active {
+		return nil, errors.NewBadRequestError("优惠券已停用")
+	}
+	if coupon.StartsAt != nil && now.Before(*coupon.StartsAt) {
+		return nil, errors.NewBadRequestError("优惠券尚未生效")
+	}
+	if coupon.EndsAt != nil && !now.Before(*coupon.EndsAt) {
+		return nil, errors.NewBadRequestError("优惠券已过期")
+	}
+
+	var eligible []product.PricedLine
+	var eligibleSubtotal int64
+	for _, line := range lines {
+		if !line.Available || !couponApplies(coupon, line) {
+			continue
+		}
+		if coupon.Currency != "" && line.Currency != coupon.Currency {
+			return nil, errors.NewBadRequestError("优惠券不适用于该币种")
+		}
+		eligible = append(eligible, line)
+		eligibleSubtotal += line.Subtotal
+	}
+	if eligibleSubtotal == 0 {
+		return nil, errors.NewBadRequestError("没有适用该优惠券的商品")
+	}
+	if eligibleSubtotal < coupon.MinSpend {
+		return nil, errors.NewBadRequestError("未达到优惠券的最低消费金额")
+	}
+
+	var discount int64
+	switch coupon.Type {
+	case models.CouponTypePercentage:
+		discount = (eligibleSubtotal*coupon.Value + 50) / 100
+		if coupon.MaxDiscount > 0 && discount > coupon.MaxDiscount {
+			discount = coupon.MaxDiscount
+		}
+	case models.CouponTypeFixed:
+		discount = coupon.Value
+	}
+	if discount > eligibleSubtotal {
+		discount = eligibleSubtotal
+	}
+
+	// 按金额比例分摊，最后一行补足取整误差
+	lineDiscounts := make(map[primitive.ObjectID]int64, len(eligible))
+	var allocated int64
+	for i, line := range eligible {
+		share := discount - allocated
+		if i < len(eligible)-1 {
+			share = discount * line.Subtotal / eligibleSubtotal
+		}
+		lineDiscounts[line.SKUID] = share
+		allocated += share
+	}
+
+	return &Quote{Coupon: coupon, Discount: discount, LineDiscounts: lineDiscounts}, nil
+}
+
+// couponApplies 商品是否在优惠券的适用范围内，品牌和类型不区分大小写
+func couponApplies(coupon *models.Coupon, line product.PricedLine) bool {
+	return matchesAny(coupon.Brands, line.Brand) && matchesAny(coupon.DeviceTypes, line.ProductType)
+}
+
+func matchesAny(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, v := range allowed {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCoupon 校验优惠券配置
+func validateCoupon(coupon *models.Coupon) error {
+	if coupon.Code == "" {
+		return errors.NewBadRequestError("券码不能为空")
+	}
+	switch coupon.Type {
+	case models.CouponTypePercentage:
+		if coupon.Value < 1 || coupon.Value > 100 {
+			return errors.NewBadRequestError("折扣比例必须在1到100之间")
+		}
+	case models.CouponTypeFixed:
+		if coupon.Value <= 0 {
+			return errors.NewBadRequestError("减免金额必须大于0")
+		}
+	default:
+		return errors.NewBadRequestError("无效的优惠券类型")
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt) {
+		return errors.NewBadRequestError("结束时间必须晚于开始时间")
+	}
+	return nil
+}
+
+// normalizeCode 券码去掉首尾空格并统一大写
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,92 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/product"
+)
+
+func pricedLine(brand, productType string, subtotal int64) product.PricedLine {
+	return product.PricedLine{
+		Line:        product.Line{SKUID: primitive.NewObjectID(), Quantity: 1},
+		Brand:       brand,
+		ProductType: productType,
+		UnitPrice:   subtotal,
+		Currency:    models.DefaultCurrency,
+		Subtotal:    subtotal,
+		Available:   true,
+	}
+}
+
+func TestCalculateDiscountPercentageWithCap(t *testing.T) {
+	coupon := &models.Coupon{Type: models.CouponTypePercentage, Value: 10, MaxDiscount: 5000, Active: true}
+	lines := []product.PricedLine{pricedLine("Logitech", "mouse", 30000), pricedLine("Razer", "mouse", 10000)}
+
+	quote, err := calculateDiscount(coupon, lines, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4000), quote.Discount)
+	assert.Equal(t, int64(3000), quote.LineDiscounts[lines[0].SKUID])
+	assert.Equal(t, int64(1000), quote.LineDiscounts[lines[1].SKUID])
+
+	coupon.MaxDiscount = 2500
+	quote, err = calculateDiscount(coupon, lines, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), quote.Discount)
+	assert.Equal(t, quote.Discount, quote.LineDiscounts[lines[0].SKUID]+quote.LineDiscounts[lines[1].SKUID])
+}
+
+func TestCalculateDiscountScopedToBrandAndType(t *testing.T) {
+	coupon := &models.Coupon{
+		Type:        models.CouponTypeFixed,
+		Value:       20000,
+		Currency:    models.DefaultCurrency,
+		Brands:      []string{"logitech"},
+		DeviceTypes: []string{"mouse"},
+		Active:      true,
+	}
+	mouse := pricedLine("Logitech", "mouse", 15000)
+	keyboard := pricedLine("Logitech", "keyboard", 50000)
+	other := pricedLine("Razer", "mouse", 30000)
+
+	quote, err := calculateDiscount(coupon, []product.PricedLine{mouse, keyboard, other}, time.Now())
+	require.NoError(t, err)
+	// 减免金额不超过适用商品的金额
+	assert.Equal(t, int64(15000), quote.Discount)
+	assert.Len(t, quote.LineDiscounts, 1)
+	assert.Equal(t, int64(15000), quote.LineDiscounts[mouse.SKUID])
+
+	_, err = calculateDiscount(coupon, []product.PricedLine{keyboard, other}, time.Now())
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
+
+func TestCalculateDiscountRejectsInvalid(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	lines := []product.PricedLine{pricedLine("Logitech", "mouse", 10000)}
+
+	cases := map[string]*models.Coupon{
+		"inactive":    {Type: models.CouponTypeFixed, Value: 100},
+		"not started": {Type: models.CouponTypeFixed, Value: 100, Active: true, StartsAt: &future},
+		"expired":     {Type: models.CouponTypeFixed, Value: 100, Active: true, EndsAt: &past},
+		"min spend":   {Type: models.CouponTypeFixed, Value: 100, Active: true, MinSpend: 20000},
+		"currency":    {Type: models.CouponTypeFixed, Value: 100, Active: true, Currency: "USD"},
+	}
+	for name, coupon := range cases {
+		_, err := calculateDiscount(coupon, lines, now)
+		assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err), name)
+	}
+
+	// 不可购买的商品不参与计算
+	unavailable := lines[0]
+	unavailable.Available = false
+	_, err := calculateDiscount(&models.Coupon{Type: models.CouponTypeFixed, Value: 100, Active: true}, []product.PricedLine{unavailable}, now)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
@@ -0,0 +1,125 @@
+// Package rating 评测评分和投票的汇总计算
+package rating
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/models"
+)
+
+const (
+	// PriorMean 贝叶斯平均的先验均值
+	PriorMean = 3.0
+	// PriorWeight 先验相当于多少条评测，评测数远大于该值时贝叶斯平均接近算术平均
+	PriorWeight = 5.0
+)
+
+// Sample 参与汇总的一条评测
+type Sample struct {
+	Score       float64
+	ContentType string
+}
+
+// Compute 计算评分汇总，没有评测时返回nil
+func Compute(samples []Sample, now time.Time) *models.DeviceRating {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	result := &models.DeviceRating{
+		Count:         len(samples),
+		ByContentType: make(map[string]int),
+		UpdatedAt:     now,
+	}
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample.Score
+		result.Histogram[bucket(sample.Score)]++
+		if sample.ContentType != "" {
+			result.ByContentType[sample.ContentType]++
+		}
+	}
+	result.Average = round2(sum / float64(len(samples)))
+	result.Bayesian = round2(Bayesian(sum, len(samples)))
+	return result
+}
+
+// Bayesian 贝叶斯平均：(先验权重*先验均值 + 总分) / (先验权重 + 评测数)
+func Bayesian(sum float64, count int) float64 {
+	return (PriorWeight*PriorMean + sum) / (PriorWeight + float64(count))
+}
+
+// Wilson 95%置信度下Wilson得分区间的下限，用于按"有帮助"投票排序
+// 票数少的评测即使全部好评也不会排在票数多、好评率略低的评测前面
+func Wilson(positive, negative int) float64 {
+	n := float64(positive + negative)
+	if n == 0 {
+		return 0
+	}
+	const z = 1.96
+	p := float64(positive) / n
+	return (p + z*z/(2*n) - z*math.Sqrt((p*(1-p)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
+
+// Refresh 重新计算设备的评分汇总并写回设备文档，只统计已发布且未删除的评测
+// 对比评测按该设备单独的评分计入
+func Refresh(ctx context.Context, db *mongo.Database, deviceID primitive.ObjectID) error {
+	cursor, err := db.Collection(models.ReviewsCollection).Find(ctx,
+		bson.M{
+			"$or": []bson.M{
+				{"externalItemId": deviceID},
+				{"comparison.items.deviceId": deviceID},
+			},
+			"itemType":  models.ReviewItemTypeDevice,
+			"status":    bson.M{"$in": models.PublishedReviewStatuses},
+			"deletedAt": nil,
+		},
+		options.Find().SetProjection(bson.M{"score": 1, "contentType": 1, "comparison.items": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []models.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return err
+	}
+	samples := make([]Sample, len(reviews))
+	for i := range reviews {
+		samples[i] = Sample{
+			Score:       reviews[i].ScoreFor(deviceID),
+			ContentType: string(reviews[i].ContentType),
+		}
+	}
+
+	update := bson.M{"$unset": bson.M{"rating": ""}}
+	if summary := Compute(samples, time.Now()); summary != nil {
+		update = bson.M{"$set": bson.M{"rating": summary}}
+	}
+	_, err = db.Collection(models.DevicesCollection).UpdateOne(ctx, bson.M{"_id": deviceID}, update)
+	return err
+}
+
+// bucket 分数四舍五入到1-5，返回直方图下标
+func bucket(score float64) int {
+	star := int(math.Round(score))
+	if star < 1 {
+		star = 1
+	}
+	if star > 5 {
+		star = 5
+	}
+	return star - 1
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,45 @@
+package rating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, Compute(nil, now))
+
+	summary := Compute([]Sample{
+		{Score: 5, ContentType: "single"},
+		{Score: 4.5, ContentType: "single"},
+		{Score: 4, ContentType: "gaming"},
+		{Score: 1.2, ContentType: "comparison"},
+	}, now)
+	require.NotNil(t, summary)
+
+	assert.Equal(t, 4, summary.Count)
+	assert.Equal(t, 3.68, summary.Average)
+	// (5*3 + 14.7) / (5 + 4) = 3.3
+	assert.Equal(t, 3.3, summary.Bayesian)
+	assert.Equal(t, [5]int{1, 0, 0, 1, 2}, summary.Histogram)
+	assert.Equal(t, map[string]int{"single": 2, "gaming": 1, "comparison": 1}, summary.ByContentType)
+	assert.Equal(t, now, summary.UpdatedAt)
+}
+
+func TestBayesianApproachesAverage(t *testing.T) {
+	// 评测少时向先验均值收缩
+	assert.InDelta(t, 3.33, Bayesian(5, 1), 0.01)
+	// 评测多时接近算术平均
+	assert.InDelta(t, 4.99, Bayesian(5*1000, 1000), 0.01)
+}
+
+func TestWilson(t *testing.T) {
+	assert.Equal(t, 0.0, Wilson(0, 0))
+	assert.InDelta(t, 0.2065, Wilson(1, 0), 0.001)
+	// 票数多、好评率略低的排在只有一票好评的前面
+	assert.Greater(t, Wilson(90, 10), Wilson(1, 0))
+	assert.Greater(t, Wilson(10, 0), Wilson(10, 5))
+}
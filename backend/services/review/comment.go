@@ -0,0 +1,287 @@
+package review
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// commentExcerptLength 通知邮件中评论摘要的最大字数
+const commentExcerptLength = 100
+
+// Notifier 评测相关的通知
+type Notifier interface {
+	SendReviewCommentEmail(to, username, reviewID, excerpt string) error
+}
+
+// CreateComment 在已发布的评测下发表评论或回复，并通知评测作者
+func (s *ServiceImpl) CreateComment(ctx context.Context, userID, reviewID string, request review.CreateCommentRequest) (*models.ReviewComment, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	target, err := s.publishedReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(request.Content)
+	if content == "" {
+		return nil, errors.NewBadRequestError("评论内容不能为空")
+	}
+
+	now := time.Now()
+	comment := models.ReviewComment{
+		ID:        primitive.NewObjectID(),
+		ReviewID:  target.ID,
+		UserID:    userObjectID,
+		Content:   content,
+		Status:    models.CommentStatusVisible,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if request.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(request.ParentID)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的评论ID")
+		}
+		var parent models.ReviewComment
+		err = s.db.Collection(models.ReviewCommentsCollection).FindOne(ctx, bson.M{"_id": parentID, "reviewId": target.ID}).Decode(&parent)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, errors.NewNotFoundError("回复的评论不存在")
+			}
+			return nil, errors.NewInternalServerError("获取评论失败: " + err.Error())
+		}
+		if parent.Status != models.CommentStatusVisible {
+			return nil, errors.NewBadRequestError("无法回复已隐藏或删除的评论")
+		}
+		if parent.Depth >= models.MaxCommentDepth {
+			return nil, errors.NewBadRequestError("回复层级过深")
+		}
+
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+		comment.Depth = parent.Depth + 1
+	}
+
+	if _, err := s.db.Collection(models.ReviewCommentsCollection).InsertOne(ctx, comment); err != nil {
+		return nil, errors.NewInternalServerError("发表评论失败: " + err.Error())
+	}
+	if err := s.incCommentCount(ctx, target.ID, 1); err != nil {
+		return nil, err
+	}
+
+	if s.notifier != nil && target.UserID != userObjectID {
+		// 发送邮件较慢，不阻塞评论的响应
+		go s.notifyComment(context.Background(), target, content)
+	}
+	return &comment, nil
+}
+
+// ListComments 获取评测的评论，按顶层评论分页，每个顶层评论带上完整的回复
+func (s *ServiceImpl) ListComments(ctx context.Context, reviewID string, page, pageSize int) (*review.CommentListResponse, error) {
+	target, err := s.publishedReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.ReviewCommentsCollection)
+	rootFilter := bson.M{"reviewId": target.ID, "parentId": nil}
+	total, err := collection.CountDocuments(ctx, rootFilter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取评论失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, rootFilter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取评论失败: " + err.Error())
+	}
+	var roots []models.ReviewComment
+	if err := cursor.All(ctx, &roots); err != nil {
+		return nil, errors.NewInternalServerError("解析评论失败: " + err.Error())
+	}
+
+	var replies []models.ReviewComment
+	if len(roots) > 0 {
+		rootIDs := make([]primitive.ObjectID, len(roots))
+		for i, root := range roots {
+			rootIDs[i] = root.ID
+		}
+		cursor, err := collection.Find(ctx,
+			bson.M{"rootId": bson.M{"$in": rootIDs}},
+			options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+		)
+		if err != nil {
+			return nil, errors.NewInternalServerError("获取回复失败: " + err.Error())
+		}
+		if err := cursor.All(ctx, &replies); err != nil {
+			return nil, errors.NewInternalServerError("解析回复失败: " + err.Error())
+		}
+	}
+
+	return &review.CommentListResponse{
+		Total:    int(total),
+		Page:     page,
+		PageSize: pageSize,
+		Comments: buildCommentTree(roots, replies),
+	}, nil
+}
+
+// DeleteComment 作者删除自己的评论，回复仍然保留
+func (s *ServiceImpl) DeleteComment(ctx context.Context, userID, commentID string) error {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的用户ID")
+	}
+	return s.setCommentStatus(ctx, commentID,
+		bson.M{"userId": userObjectID, "status": models.CommentStatusVisible},
+		bson.M{"status": models.CommentStatusDeleted},
+		-1, "评论不存在或无权删除")
+}
+
+// HideComment 管理员隐藏评论
+func (s *ServiceImpl) HideComment(ctx context.Context, moderatorID, commentID, note string) error {
+	moderatorObjectID, err := primitive.ObjectIDFromHex(moderatorID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的管理员ID")
+	}
+	return s.setCommentStatus(ctx, commentID,
+		bson.M{"status": models.CommentStatusVisible},
+		bson.M{"status": models.CommentStatusHidden, "moderatorId": moderatorObjectID, "moderationNote": strings.TrimSpace(note)},
+		-1, "评论不存在或已隐藏")
+}
+
+// RestoreComment 管理员恢复被隐藏的评论
+func (s *ServiceImpl) RestoreComment(ctx context.Context, moderatorID, commentID string) error {
+	moderatorObjectID, err := primitive.ObjectIDFromHex(moderatorID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的管理员ID")
+	}
+	return s.setCommentStatus(ctx, commentID,
+		bson.M{"status": models.CommentStatusHidden},
+		bson.M{"status": models.CommentStatusVisible, "moderatorId": moderatorObjectID},
+		1, "评论不存在或未被隐藏")
+}
+
+// setCommentStatus 按前置条件修改评论状态，并同步评测的可见评论数
+func (s *ServiceImpl) setCommentStatus(ctx context.Context, commentID string, filter, set bson.M, delta int, notFound string) error {
+	id, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的评论ID")
+	}
+	filter["_id"] = id
+	set["updatedAt"] = time.Now()
+
+	var comment models.ReviewComment
+	err = s.db.Collection(models.ReviewCommentsCollection).FindOneAndUpdate(ctx, filter, bson.M{"$set": set}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errors.NewNotFoundError(notFound)
+		}
+		return errors.NewInternalServerError("更新评论失败: " + err.Error())
+	}
+	return s.incCommentCount(ctx, comment.ReviewID, delta)
+}
+
+func (s *ServiceImpl) incCommentCount(ctx context.Context, reviewID primitive.ObjectID, delta int) error {
+	_, err := s.db.Collection(models.ReviewsCollection).UpdateOne(ctx,
+		bson.M{"_id": reviewID},
+		bson.M{"$inc": bson.M{"commentCount": delta}},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("更新评论数失败: " + err.Error())
+	}
+	return nil
+}
+
+// notifyComment 通知评测作者有新评论，失败只记录日志
+func (s *ServiceImpl) notifyComment(ctx context.Context, target *models.Review, content string) {
+	var author models.User
+	err := s.db.Collection(models.UsersCollection).FindOne(ctx,
+		bson.M{"_id": target.UserID},
+		options.FindOne().SetProjection(bson.M{"email": 1, "username": 1}),
+	).Decode(&author)
+	if err != nil || author.Email == "" {
+		return
+	}
+	if err := s.notifier.SendReviewCommentEmail(author.Email, author.Username, target.ID.Hex(), excerpt(content, commentExcerptLength)); err != nil {
+		log.Printf("发送评测%s的评论通知失败: %v", target.ID.Hex(), err)
+	}
+}
+
+// buildCommentTree 把回复挂到各自的父评论下，隐藏和删除的评论不返回内容
+func buildCommentTree(roots, replies []models.ReviewComment) []*review.CommentResponse {
+	nodes := make(map[primitive.ObjectID]*review.CommentResponse, len(roots)+len(replies))
+	result := make([]*review.CommentResponse, len(roots))
+	for i := range roots {
+		result[i] = mapCommentToResponse(&roots[i])
+		nodes[roots[i].ID] = result[i]
+	}
+	// 回复按时间排序，父评论总是先于子评论出现
+	for i := range replies {
+		node := mapCommentToResponse(&replies[i])
+		nodes[replies[i].ID] = node
+		if replies[i].ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*replies[i].ParentID]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+	return result
+}
+
+func mapCommentToResponse(c *models.ReviewComment) *review.CommentResponse {
+	response := &review.CommentResponse{
+		ID:        c.ID.Hex(),
+		ReviewID:  c.ReviewID.Hex(),
+		UserID:    c.UserID.Hex(),
+		Depth:     c.Depth,
+		Content:   c.Content,
+		Status:    string(c.Status),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Replies:   []*review.CommentResponse{},
+	}
+	if c.ParentID != nil {
+		response.ParentID = c.ParentID.Hex()
+	}
+	if c.Status != models.CommentStatusVisible {
+		response.Content = ""
+	}
+	return response
+}
+
+// excerpt 截取前n个字符作为摘要
+func excerpt(content string, n int) string {
+	if utf8.RuneCountInString(content) <= n {
+		return content
+	}
+	return string([]rune(content)[:n]) + "…"
+}
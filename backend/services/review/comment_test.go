@@ -0,0 +1,49 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+)
+
+func TestBuildCommentTree(t *testing.T) {
+	comment := func(parent *models.ReviewComment, content string, status models.CommentStatus) models.ReviewComment {
+		c := models.ReviewComment{ID: primitive.NewObjectID(), Content: content, Status: status}
+		if parent != nil {
+			c.ParentID = &parent.ID
+			c.Depth = parent.Depth + 1
+		}
+		return c
+	}
+
+	first := comment(nil, "第一条", models.CommentStatusVisible)
+	second := comment(nil, "第二条", models.CommentStatusDeleted)
+	reply := comment(&first, "回复", models.CommentStatusVisible)
+	hidden := comment(&first, "被隐藏", models.CommentStatusHidden)
+	nested := comment(&reply, "楼中楼", models.CommentStatusVisible)
+	orphan := comment(&models.ReviewComment{ID: primitive.NewObjectID()}, "父评论不在本页", models.CommentStatusVisible)
+
+	tree := buildCommentTree(
+		[]models.ReviewComment{first, second},
+		[]models.ReviewComment{reply, hidden, nested, orphan},
+	)
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "第一条", tree[0].Content)
+	assert.Equal(t, "", tree[1].Content, "删除的评论不返回内容")
+	assert.Empty(t, tree[1].Replies)
+
+	require.Len(t, tree[0].Replies, 2)
+	assert.Equal(t, first.ID.Hex(), tree[0].Replies[0].ParentID)
+	assert.Equal(t, "回复", tree[0].Replies[0].Content)
+	assert.Equal(t, "", tree[0].Replies[1].Content, "隐藏的评论不返回内容")
+	assert.Equal(t, string(models.CommentStatusHidden), tree[0].Replies[1].Status)
+
+	require.Len(t, tree[0].Replies[0].Replies, 1)
+	assert.Equal(t, "楼中楼", tree[0].Replies[0].Replies[0].Content)
+	assert.Equal(t, 2, tree[0].Replies[0].Replies[0].Depth)
+}
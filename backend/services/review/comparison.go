@@ -0,0 +1,133 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// buildComparison 校验并转换对比评测的内容，非对比评测不能带对比内容
+func (s *ServiceImpl) buildComparison(ctx context.Context, itemType string, contentType models.ReviewContentType, request *review.ComparisonRequest) (*models.ReviewComparison, error) {
+	if contentType != models.ReviewContentComparison {
+		if request != nil {
+			return nil, errors.NewBadRequestError("只有对比评测可以关联多个设备")
+		}
+		return nil, nil
+	}
+	if request == nil {
+		// 兼容合并前的旧对比评测，只关联单个设备
+		return nil, nil
+	}
+	if itemType != models.ReviewItemTypeDevice {
+		return nil, errors.NewBadRequestError("只有外设评测支持多设备对比")
+	}
+
+	comparison, err := parseComparison(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(comparison.Items))
+	for i, item := range comparison.Items {
+		ids[i] = item.DeviceID
+	}
+	count, err := s.db.Collection(models.DevicesCollection).CountDocuments(ctx, bson.M{
+		"_id":       bson.M{"$in": ids},
+		"deletedAt": nil,
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取对比设备失败: " + err.Error())
+	}
+	if int(count) != len(ids) {
+		return nil, errors.NewBadRequestError("对比的设备不存在")
+	}
+	return comparison, nil
+}
+
+// parseComparison 转换对比请求：设备不能重复，胜出者必须是参与对比的设备，每个维度只能有一个胜出者
+func parseComparison(request *review.ComparisonRequest) (*models.ReviewComparison, error) {
+	if len(request.Items) < 2 || len(request.Items) > models.MaxComparisonDevices {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("对比评测需要2-%d个设备", models.MaxComparisonDevices))
+	}
+
+	comparison := &models.ReviewComparison{
+		Items:   make([]models.ComparisonItem, 0, len(request.Items)),
+		Winners: make([]models.ComparisonWinner, 0, len(request.Winners)),
+	}
+	seen := make(map[primitive.ObjectID]bool, len(request.Items))
+	for _, item := range request.Items {
+		id, err := primitive.ObjectIDFromHex(item.DeviceID)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的设备ID: " + item.DeviceID)
+		}
+		if seen[id] {
+			return nil, errors.NewBadRequestError("对比的设备不能重复")
+		}
+		seen[id] = true
+		comparison.Items = append(comparison.Items, models.ComparisonItem{DeviceID: id, Score: item.Score})
+	}
+
+	decided := make(map[models.ComparisonCategory]bool, len(request.Winners))
+	for _, winner := range request.Winners {
+		category := models.ComparisonCategory(winner.Category)
+		if decided[category] {
+			return nil, errors.NewBadRequestError("每个维度只能有一个胜出设备")
+		}
+		decided[category] = true
+
+		id, err := primitive.ObjectIDFromHex(winner.DeviceID)
+		if err != nil || !seen[id] {
+			return nil, errors.NewBadRequestError("胜出设备必须是参与对比的设备")
+		}
+		comparison.Winners = append(comparison.Winners, models.ComparisonWinner{Category: category, DeviceID: id})
+	}
+	return comparison, nil
+}
+
+// primaryDeviceID 对比评测的主设备：请求指定的设备必须参与对比，未指定时取第一个
+func primaryDeviceID(externalItemID string, comparison *models.ReviewComparison) (primitive.ObjectID, error) {
+	if externalItemID == "" {
+		return comparison.Items[0].DeviceID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(externalItemID)
+	if err != nil {
+		return primitive.NilObjectID, errors.NewBadRequestError("无效的项目ID")
+	}
+	for _, item := range comparison.Items {
+		if item.DeviceID == id {
+			return id, nil
+		}
+	}
+	return primitive.NilObjectID, errors.NewBadRequestError("项目ID必须是参与对比的设备之一")
+}
+
+// MapComparisonToResponse 转换对比内容，鼠标对比附带参数对比接口地址，评测处理器也使用
+func MapComparisonToResponse(r *models.Review) *review.ComparisonResponse {
+	if r.Comparison == nil {
+		return nil
+	}
+
+	response := &review.ComparisonResponse{
+		Items:     make([]review.ComparisonItemResponse, len(r.Comparison.Items)),
+		Winners:   make([]review.ComparisonWinnerResponse, len(r.Comparison.Winners)),
+		DeviceIDs: make([]string, len(r.Comparison.Items)),
+	}
+	for i, item := range r.Comparison.Items {
+		response.Items[i] = review.ComparisonItemResponse{DeviceID: item.DeviceID.Hex(), Score: item.Score}
+		response.DeviceIDs[i] = item.DeviceID.Hex()
+	}
+	for i, winner := range r.Comparison.Winners {
+		response.Winners[i] = review.ComparisonWinnerResponse{Category: string(winner.Category), DeviceID: winner.DeviceID.Hex()}
+	}
+	if r.Type == models.ReviewTypeMouse {
+		response.CompareURL = "/api/devices/mice/compare?ids=" + strings.Join(response.DeviceIDs, ",")
+	}
+	return response
+}
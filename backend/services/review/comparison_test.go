@@ -0,0 +1,83 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+func TestParseComparison(t *testing.T) {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	items := func(ids ...string) []review.ComparisonItemRequest {
+		result := make([]review.ComparisonItemRequest, len(ids))
+		for i, id := range ids {
+			result[i] = review.ComparisonItemRequest{DeviceID: id, Score: 4}
+		}
+		return result
+	}
+
+	t.Run("转换设备和胜出者", func(t *testing.T) {
+		comparison, err := parseComparison(&review.ComparisonRequest{
+			Items: items(a.Hex(), b.Hex()),
+			Winners: []review.ComparisonWinnerRequest{
+				{Category: "shape", DeviceID: a.Hex()},
+				{Category: "weight", DeviceID: b.Hex()},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []models.ComparisonItem{{DeviceID: a, Score: 4}, {DeviceID: b, Score: 4}}, comparison.Items)
+		assert.Equal(t, []models.ComparisonWinner{
+			{Category: models.ComparisonCategoryShape, DeviceID: a},
+			{Category: models.ComparisonCategoryWeight, DeviceID: b},
+		}, comparison.Winners)
+	})
+
+	invalid := map[string]review.ComparisonRequest{
+		"设备太少":    {Items: items(a.Hex())},
+		"设备太多":    {Items: items(a.Hex(), b.Hex(), c.Hex(), primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex())},
+		"无效的设备ID": {Items: items(a.Hex(), "invalid")},
+		"设备重复":    {Items: items(a.Hex(), a.Hex())},
+		"维度重复": {
+			Items: items(a.Hex(), b.Hex()),
+			Winners: []review.ComparisonWinnerRequest{
+				{Category: "sensor", DeviceID: a.Hex()},
+				{Category: "sensor", DeviceID: b.Hex()},
+			},
+		},
+		"胜出者未参与对比": {
+			Items:   items(a.Hex(), b.Hex()),
+			Winners: []review.ComparisonWinnerRequest{{Category: "build", DeviceID: c.Hex()}},
+		},
+	}
+	for name, request := range invalid {
+		t.Run(name, func(t *testing.T) {
+			comparison, err := parseComparison(&request)
+			assert.Nil(t, comparison)
+			assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+		})
+	}
+}
+
+func TestPrimaryDeviceID(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	comparison := &models.ReviewComparison{Items: []models.ComparisonItem{{DeviceID: a}, {DeviceID: b}}}
+
+	id, err := primaryDeviceID("", comparison)
+	require.NoError(t, err)
+	assert.Equal(t, a, id, "未指定时取第一个设备")
+
+	id, err = primaryDeviceID(b.Hex(), comparison)
+	require.NoError(t, err)
+	assert.Equal(t, b, id)
+
+	_, err = primaryDeviceID(primitive.NewObjectID().Hex(), comparison)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+	_, err = primaryDeviceID("invalid", comparison)
+	assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+}
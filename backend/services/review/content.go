@@ -0,0 +1,81 @@
+package review
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"project/backend/internal/errors"
+	"project/backend/internal/markdown"
+	"project/backend/models"
+)
+
+const (
+	// minContentLength 正文的最少字数，Markdown按渲染后的纯文本计算
+	minContentLength = 50
+	// ExcerptLength 列表展示和搜索使用的摘要长度
+	ExcerptLength = 200
+)
+
+// applyContent 设置正文和格式，content或format为空时保持不变
+// 纯文本转义后保存；Markdown保存原文，渲染时转义。只切换格式时转换已保存的正文
+func applyContent(r *models.Review, content, format string) error {
+	previous := r.Format()
+	if format != "" {
+		r.ContentFormat = models.ReviewContentFormat(format)
+	} else {
+		r.ContentFormat = previous
+	}
+
+	switch {
+	case strings.TrimSpace(content) != "":
+		r.Content = strings.TrimSpace(content)
+		if !isMarkdown(r) {
+			r.Content = html.EscapeString(r.Content)
+		}
+	case previous != r.ContentFormat && isMarkdown(r):
+		r.Content = html.UnescapeString(r.Content)
+	case previous != r.ContentFormat:
+		r.Content = html.EscapeString(r.Content)
+	}
+
+	// 只有标记和图片的Markdown不算有效内容
+	if isMarkdown(r) && utf8.RuneCountInString(markdown.Parse(r.Content, markdown.Options{}).Text()) < minContentLength {
+		return errors.NewBadRequestError("评测内容不能少于50个字")
+	}
+	return nil
+}
+
+// RenderContent 根据正文生成HTML和摘要，需要在关联图片之后调用
+// Markdown中只能引用评测已关联的图片，其他图片只保留替代文本
+func RenderContent(r *models.Review) {
+	if !isMarkdown(r) {
+		r.ContentHTML = ""
+		r.Excerpt = markdown.Excerpt(html.UnescapeString(r.Content), ExcerptLength)
+		return
+	}
+
+	attached := make(map[string]bool, len(r.Images)*2)
+	for _, image := range r.Images {
+		attached[image.URL] = true
+		attached[image.ThumbnailURL] = true
+	}
+	doc := markdown.Parse(r.Content, markdown.Options{AllowImage: func(src string) bool {
+		return attached[src]
+	}})
+	r.ContentHTML = doc.HTML()
+	r.Excerpt = doc.Excerpt(ExcerptLength)
+}
+
+// moderationText 预审使用的正文，Markdown取纯文本和链接地址，已上传图片的地址不计为链接
+func moderationText(r *models.Review) string {
+	if !isMarkdown(r) {
+		return r.Content
+	}
+	doc := markdown.Parse(r.Content, markdown.Options{})
+	return strings.Join(append([]string{doc.Text()}, doc.Links()...), "\n")
+}
+
+func isMarkdown(r *models.Review) bool {
+	return r.Format() == models.ReviewContentFormatMarkdown
+}
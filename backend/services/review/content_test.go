@@ -0,0 +1,70 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+)
+
+func TestApplyContent(t *testing.T) {
+	t.Run("纯文本转义后保存", func(t *testing.T) {
+		r := &models.Review{}
+		require.NoError(t, applyContent(r, "  <b>手感</b> & 做工  ", ""))
+		assert.Equal(t, models.ReviewContentFormatPlain, r.ContentFormat)
+		assert.Equal(t, "&lt;b&gt;手感&lt;/b&gt; &amp; 做工", r.Content)
+	})
+
+	t.Run("Markdown保存原文", func(t *testing.T) {
+		r := &models.Review{}
+		content := "## 总结\n\n" + strings.Repeat("握持舒适，", 12) + "<b>"
+		require.NoError(t, applyContent(r, content, string(models.ReviewContentFormatMarkdown)))
+		assert.Equal(t, models.ReviewContentFormatMarkdown, r.ContentFormat)
+		assert.Equal(t, content, r.Content)
+	})
+
+	t.Run("只切换格式时转换已保存的正文", func(t *testing.T) {
+		text := strings.Repeat("侧键<偏软>", 10)
+		r := &models.Review{}
+		require.NoError(t, applyContent(r, text, ""))
+		require.NoError(t, applyContent(r, "", string(models.ReviewContentFormatMarkdown)))
+		assert.Equal(t, text, r.Content)
+		require.NoError(t, applyContent(r, "", string(models.ReviewContentFormatPlain)))
+		assert.Equal(t, "侧键&lt;偏软&gt;", r.Content[:len("侧键&lt;偏软&gt;")])
+	})
+
+	t.Run("内容和格式为空时保持不变", func(t *testing.T) {
+		r := &models.Review{Content: "原有内容", ContentFormat: models.ReviewContentFormatPlain}
+		require.NoError(t, applyContent(r, "", ""))
+		assert.Equal(t, "原有内容", r.Content)
+	})
+
+	t.Run("Markdown按纯文本计算字数", func(t *testing.T) {
+		r := &models.Review{}
+		content := "## **很轻**\n\n![侧面](/uploads/a.jpg) [官网](https://example.com/" + strings.Repeat("mouse/", 20) + ")"
+		err := applyContent(r, content, string(models.ReviewContentFormatMarkdown))
+		assert.Equal(t, errors.BadRequest, errors.GetErrorCode(err))
+	})
+}
+
+func TestRenderContent(t *testing.T) {
+	r := &models.Review{
+		Content:       "正文 ![侧面](/uploads/reviews/a.jpg) ![外链](https://evil.test/x.png)",
+		ContentFormat: models.ReviewContentFormatMarkdown,
+		Images:        []models.ReviewAttachment{{URL: "/uploads/reviews/a.jpg"}},
+	}
+	RenderContent(r)
+	// 只显示评测已关联的图片
+	assert.Contains(t, r.ContentHTML, `<img src="/uploads/reviews/a.jpg"`)
+	assert.NotContains(t, r.ContentHTML, "evil.test")
+	assert.Equal(t, "正文 侧面 外链", r.Excerpt)
+
+	plain := &models.Review{Content: "a &lt; b"}
+	RenderContent(plain)
+	assert.Empty(t, plain.ContentHTML)
+	assert.Equal(t, "a < b", plain.Excerpt)
+}
@@ -0,0 +1,279 @@
+package review
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/internal/upload"
+	"project/backend/models"
+	"project/backend/services/scheduler"
+	"project/backend/types/review"
+)
+
+const (
+	// OrphanImageCleanupJobName 清理未关联图片的任务名
+	OrphanImageCleanupJobName = "review-orphan-images"
+	// DefaultOrphanImageTTL 上传后超过该时长仍未关联评测的图片会被清理
+	DefaultOrphanImageTTL = 24 * time.Hour
+	// DefaultOrphanImageCleanupInterval 默认清理间隔
+	DefaultOrphanImageCleanupInterval = time.Hour
+	// orphanImageBatchSize 每次最多清理的图片数，剩余的留到下一轮
+	orphanImageBatchSize = 100
+)
+
+// UploadReviewImage 校验并重新编码图片，保存原图和缩略图，关联评测前为未关联状态
+func (s *ServiceImpl) UploadReviewImage(ctx context.Context, userID string, content []byte) (*models.ReviewImage, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	if s.storage == nil {
+		return nil, errors.NewInternalServerError("图片存储未配置")
+	}
+
+	img, err := upload.ProcessImage(content)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的图片: " + err.Error())
+	}
+
+	id := primitive.NewObjectID()
+	prefix := "reviews/" + userObjectID.Hex() + "/" + id.Hex()
+	image := models.ReviewImage{
+		ID:           id,
+		UserID:       userObjectID,
+		Key:          prefix + img.Ext,
+		ThumbnailKey: prefix + "_thumb" + img.Ext,
+		ContentType:  img.ContentType,
+		Size:         len(img.Data),
+		Width:        img.Width,
+		Height:       img.Height,
+		CreatedAt:    time.Now(),
+	}
+	image.URL = s.storage.URL(image.Key)
+	image.ThumbnailURL = s.storage.URL(image.ThumbnailKey)
+
+	if err := s.storage.Save(ctx, image.Key, img.Data, img.ContentType); err != nil {
+		return nil, errors.NewInternalServerError("保存图片失败: " + err.Error())
+	}
+	if err := s.storage.Save(ctx, image.ThumbnailKey, img.Thumbnail, img.ContentType); err != nil {
+		s.removeImageFiles(ctx, &image)
+		return nil, errors.NewInternalServerError("保存缩略图失败: " + err.Error())
+	}
+	if _, err := s.db.Collection(models.ReviewImagesCollection).InsertOne(ctx, image); err != nil {
+		s.removeImageFiles(ctx, &image)
+		return nil, errors.NewInternalServerError("保存图片失败: " + err.Error())
+	}
+	return &image, nil
+}
+
+// attachImages 把作者上传的图片按顺序关联到评测，替换原有的图片，不再使用的图片直接删除
+func (s *ServiceImpl) attachImages(ctx context.Context, r *models.Review, imageIDs []string) error {
+	if err := s.linkImages(ctx, r, imageIDs); err != nil {
+		return err
+	}
+	return s.pruneImages(ctx, r.ID, r.Images)
+}
+
+// linkImages 把图片按顺序关联到评测并设置r.Images，不删除原有的图片
+// 已发布评测的修改审核通过前，公开内容仍在使用原有的图片
+func (s *ServiceImpl) linkImages(ctx context.Context, r *models.Review, imageIDs []string) error {
+	if len(imageIDs) > upload.ImageConfig.MaxFiles {
+		return errors.NewBadRequestError("评测图片数量超过上限")
+	}
+	ids := make([]primitive.ObjectID, 0, len(imageIDs))
+	seen := make(map[primitive.ObjectID]bool, len(imageIDs))
+	for _, imageID := range imageIDs {
+		id, err := primitive.ObjectIDFromHex(imageID)
+		if err != nil {
+			return errors.NewBadRequestError("无效的图片ID: " + imageID)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	collection := s.db.Collection(models.ReviewImagesCollection)
+	attachments := make([]models.ReviewAttachment, 0, len(ids))
+	if len(ids) > 0 {
+		cursor, err := collection.Find(ctx, bson.M{
+			"_id":    bson.M{"$in": ids},
+			"userId": r.UserID,
+			"$or":    []bson.M{{"reviewId": nil}, {"reviewId": r.ID}},
+		})
+		if err != nil {
+			return errors.NewInternalServerError("获取评测图片失败: " + err.Error())
+		}
+		var images []models.ReviewImage
+		if err := cursor.All(ctx, &images); err != nil {
+			return errors.NewInternalServerError("解析评测图片失败: " + err.Error())
+		}
+		if len(images) != len(ids) {
+			return errors.NewBadRequestError("图片不存在或已被其他评测使用")
+		}
+
+		byID := make(map[primitive.ObjectID]models.ReviewImage, len(images))
+		unattached := 0
+		for _, image := range images {
+			byID[image.ID] = image
+			if image.ReviewID == nil {
+				unattached++
+			}
+		}
+		for _, id := range ids {
+			image := byID[id]
+			attachments = append(attachments, models.ReviewAttachment{
+				ID:           image.ID,
+				URL:          image.URL,
+				ThumbnailURL: image.ThumbnailURL,
+				Width:        image.Width,
+				Height:       image.Height,
+			})
+		}
+
+		now := time.Now()
+		res, err := collection.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}, "reviewId": nil},
+			bson.M{"$set": bson.M{"reviewId": r.ID, "attachedAt": now}},
+		)
+		if err != nil {
+			return errors.NewInternalServerError("关联评测图片失败: " + err.Error())
+		}
+		// 并发关联同一张图片时只有一篇评测能成功
+		if int(res.ModifiedCount) != unattached {
+			return errors.NewBadRequestError("图片已被其他评测使用")
+		}
+	}
+
+	r.Images = attachments
+	return nil
+}
+
+// pruneImages 删除评测中不再被keep中任何一组图片引用的图片
+func (s *ServiceImpl) pruneImages(ctx context.Context, reviewID primitive.ObjectID, keep ...[]models.ReviewAttachment) error {
+	ids := make([]primitive.ObjectID, 0)
+	for _, images := range keep {
+		for _, image := range images {
+			ids = append(ids, image.ID)
+		}
+	}
+	_, err := s.deleteImages(ctx, bson.M{"reviewId": reviewID, "_id": bson.M{"$nin": ids}})
+	return err
+}
+
+// releaseImages 评测保存失败时解除图片关联，图片之后按未关联图片清理
+func (s *ServiceImpl) releaseImages(ctx context.Context, reviewID primitive.ObjectID) {
+	_, err := s.db.Collection(models.ReviewImagesCollection).UpdateMany(ctx,
+		bson.M{"reviewId": reviewID},
+		bson.M{"$unset": bson.M{"reviewId": "", "attachedAt": ""}},
+	)
+	if err != nil {
+		log.Printf("解除评测%s的图片关联失败: %v", reviewID.Hex(), err)
+	}
+}
+
+// CleanupOrphanImages 删除上传时间早于before且没有关联评测的图片
+func (s *ServiceImpl) CleanupOrphanImages(ctx context.Context, before time.Time) (int, error) {
+	cursor, err := s.db.Collection(models.ReviewImagesCollection).Find(ctx,
+		bson.M{"reviewId": nil, "createdAt": bson.M{"$lt": before}},
+		options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(orphanImageBatchSize),
+	)
+	if err != nil {
+		return 0, errors.NewInternalServerError("获取未关联图片失败: " + err.Error())
+	}
+	var images []models.ReviewImage
+	if err := cursor.All(ctx, &images); err != nil {
+		return 0, errors.NewInternalServerError("解析未关联图片失败: " + err.Error())
+	}
+	if len(images) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(images))
+	for i := range images {
+		ids[i] = images[i].ID
+	}
+	// 删除时再次确认未被关联，避免与关联操作并发时误删
+	return s.deleteImages(ctx, bson.M{"_id": bson.M{"$in": ids}, "reviewId": nil})
+}
+
+// deleteImages 删除符合条件的图片记录和文件，返回删除的图片数，文件删除失败只记录日志
+// 逐条删除时仍带上filter，查询后被重新关联的图片不会被删除，只删除记录已删除的图片文件
+func (s *ServiceImpl) deleteImages(ctx context.Context, filter bson.M) (int, error) {
+	collection := s.db.Collection(models.ReviewImagesCollection)
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return 0, errors.NewInternalServerError("获取评测图片失败: " + err.Error())
+	}
+	var images []models.ReviewImage
+	if err := cursor.All(ctx, &images); err != nil {
+		return 0, errors.NewInternalServerError("解析评测图片失败: " + err.Error())
+	}
+
+	deleted := 0
+	for i := range images {
+		res, err := collection.DeleteOne(ctx, bson.M{"$and": bson.A{filter, bson.M{"_id": images[i].ID}}})
+		if err != nil {
+			return deleted, errors.NewInternalServerError("删除评测图片失败: " + err.Error())
+		}
+		if res.DeletedCount == 0 {
+			continue
+		}
+		s.removeImageFiles(ctx, &images[i])
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *ServiceImpl) removeImageFiles(ctx context.Context, image *models.ReviewImage) {
+	if s.storage == nil {
+		return
+	}
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("删除图片文件%s失败: %v", key, err)
+		}
+	}
+}
+
+// OrphanImageCleanupJob 定期清理上传后长时间未关联评测的图片
+func OrphanImageCleanupJob(svc Service, interval, ttl time.Duration) scheduler.Job {
+	if interval <= 0 {
+		interval = DefaultOrphanImageCleanupInterval
+	}
+	if ttl <= 0 {
+		ttl = DefaultOrphanImageTTL
+	}
+	return scheduler.Job{
+		Name:     OrphanImageCleanupJobName,
+		Interval: interval,
+		Run: func(ctx context.Context, now time.Time) error {
+			removed, err := svc.CleanupOrphanImages(ctx, now.Add(-ttl))
+			if removed > 0 {
+				log.Printf("已清理%d张未关联评测的图片", removed)
+			}
+			return err
+		},
+	}
+}
+
+// MapImagesToResponse 转换评测图片，评测处理器也使用
+func MapImagesToResponse(r *models.Review) []review.ReviewImageResponse {
+	images := make([]review.ReviewImageResponse, len(r.Images))
+	for i, image := range r.Images {
+		images[i] = review.ReviewImageResponse{
+			ID:           image.ID.Hex(),
+			URL:          image.URL,
+			ThumbnailURL: image.ThumbnailURL,
+			Width:        image.Width,
+			Height:       image.Height,
+		}
+	}
+	return images
+}
@@ -0,0 +1,75 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+	"project/backend/tests/testutil"
+)
+
+// recordingStorage 记录删除的文件，onDelete在删除时调用
+type recordingStorage struct {
+	deleted  []string
+	onDelete func(key string)
+}
+
+func (s *recordingStorage) Save(ctx context.Context, key string, content []byte, contentType string) error {
+	return nil
+}
+
+func (s *recordingStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	if s.onDelete != nil {
+		s.onDelete(key)
+	}
+	return nil
+}
+
+func (s *recordingStorage) URL(key string) string {
+	return "/uploads/" + key
+}
+
+func TestCleanupOrphanImagesSkipsImagesAttachedMeanwhile(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store := &recordingStorage{}
+	svc := &ServiceImpl{db: db, storage: store}
+
+	old := time.Now().Add(-48 * time.Hour)
+	reviewID := primitive.NewObjectID()
+	first := models.ReviewImage{ID: primitive.NewObjectID(), Key: "a.webp", ThumbnailKey: "a_thumb.webp", CreatedAt: old}
+	second := models.ReviewImage{ID: primitive.NewObjectID(), Key: "b.webp", ThumbnailKey: "b_thumb.webp", CreatedAt: old.Add(time.Minute)}
+	attached := models.ReviewImage{ID: primitive.NewObjectID(), ReviewID: &reviewID, Key: "c.webp", ThumbnailKey: "c_thumb.webp", CreatedAt: old}
+	_, err := db.Collection(models.ReviewImagesCollection).InsertMany(ctx, []interface{}{first, second, attached})
+	require.NoError(t, err)
+
+	// 清理第一张图片时，第二张被关联到评测
+	store.onDelete = func(key string) {
+		if key != first.Key {
+			return
+		}
+		_, err := db.Collection(models.ReviewImagesCollection).UpdateOne(ctx,
+			bson.M{"_id": second.ID},
+			bson.M{"$set": bson.M{"reviewId": reviewID}},
+		)
+		require.NoError(t, err)
+	}
+
+	removed, err := svc.CleanupOrphanImages(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []string{first.Key, first.ThumbnailKey}, store.deleted)
+
+	count, err := db.Collection(models.ReviewImagesCollection).CountDocuments(ctx, bson.M{"_id": bson.M{"$in": bson.A{second.ID, attached.ID}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
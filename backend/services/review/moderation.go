@@ -0,0 +1,89 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+)
+
+// ApproveReview 批准待审核的评测并发布
+func (s *ServiceImpl) ApproveReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error) {
+	now := time.Now()
+	approved, err := s.moderate(ctx, reviewerID, reviewID, models.ReviewStatusPending, bson.M{
+		"status":        models.ReviewStatusApproved,
+		"reviewerNotes": notes,
+		"reviewedAt":    now,
+		"publishedAt":   now,
+		"updatedAt":     now,
+	}, "未找到待审核的评测")
+	if err != nil {
+		return nil, err
+	}
+	s.resolveLatestRevision(ctx, approved, models.ReviewRevisionApproved)
+	s.refreshRating(ctx, approved)
+	return approved, nil
+}
+
+// RejectReview 拒绝待审核的评测
+func (s *ServiceImpl) RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error) {
+	now := time.Now()
+	rejected, err := s.moderate(ctx, reviewerID, reviewID, models.ReviewStatusPending, bson.M{
+		"status":        models.ReviewStatusRejected,
+		"reviewerNotes": notes,
+		"reviewedAt":    now,
+		"updatedAt":     now,
+	}, "未找到待审核的评测")
+	if err != nil {
+		return nil, err
+	}
+	s.resolveLatestRevision(ctx, rejected, models.ReviewRevisionRejected)
+	return rejected, nil
+}
+
+// FeaturedReview 将已批准的评测设为推荐，rank越小越靠前
+func (s *ServiceImpl) FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.Review, error) {
+	return s.moderate(ctx, reviewerID, reviewID, models.ReviewStatusApproved, bson.M{
+		"status":       models.ReviewStatusFeatured,
+		"featuredRank": rank,
+		"updatedAt":    time.Now(),
+	}, "未找到已批准的评测")
+}
+
+// moderate 审核操作的公共部分，只有评测处于from状态时才会更新，避免并发审核互相覆盖
+// 作者不能审核自己的评测
+func (s *ServiceImpl) moderate(ctx context.Context, reviewerID, reviewID string, from models.ReviewStatus, set bson.M, notFound string) (*models.Review, error) {
+	reviewObjectID, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的评测ID")
+	}
+	reviewerObjectID, err := primitive.ObjectIDFromHex(reviewerID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的审核员ID")
+	}
+	set["reviewerId"] = reviewerObjectID
+
+	var updated models.Review
+	err = s.db.Collection(models.ReviewsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": reviewObjectID, "status": from, "deletedAt": nil, "userId": bson.M{"$ne": reviewerObjectID}},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			own, countErr := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, bson.M{"_id": reviewObjectID, "userId": reviewerObjectID})
+			if countErr == nil && own > 0 {
+				return nil, errors.NewForbiddenError("不能审核自己的评测")
+			}
+			return nil, errors.NewNotFoundError(notFound)
+		}
+		return nil, errors.NewInternalServerError("更新评测状态失败: " + err.Error())
+	}
+	return &updated, nil
+}
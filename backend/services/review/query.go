@@ -0,0 +1,255 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/viewcount"
+	"project/backend/types/review"
+)
+
+// ListReviews 列出已发布的评测，可按项目、作者、类型筛选
+// 待审核和已拒绝的评测只能通过审核接口查看
+func (s *ServiceImpl) ListReviews(ctx context.Context, request review.ReviewListRequest) (*review.ReviewListResponse, error) {
+	filter := bson.M{
+		"status":    bson.M{"$in": models.PublishedReviewStatuses},
+		"deletedAt": nil,
+	}
+
+	switch models.ReviewStatus(request.Status) {
+	case "":
+	case models.ReviewStatusApproved, models.ReviewStatusFeatured:
+		filter["status"] = request.Status
+	default:
+		return nil, errors.NewBadRequestError("只能查询已发布的评测")
+	}
+
+	if request.ExternalItemID != "" {
+		itemID, err := primitive.ObjectIDFromHex(request.ExternalItemID)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的项目ID")
+		}
+		// 对比评测出现在每个参与对比的设备下
+		filter["$or"] = []bson.M{
+			{"externalItemId": itemID},
+			{"comparison.items.deviceId": itemID},
+		}
+	}
+	if request.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(request.UserID)
+		if err != nil {
+			return nil, errors.NewBadRequestError("无效的用户ID")
+		}
+		filter["userId"] = userID
+	}
+	if request.ItemType != "" {
+		filter["itemType"] = request.ItemType
+	}
+	if request.Type != "" {
+		filter["type"] = request.Type
+	}
+	if request.ContentType != "" {
+		filter["contentType"] = request.ContentType
+	}
+
+	return s.listReviews(ctx, filter, request.Page, request.PageSize, reviewSort(request.SortBy, request.SortOrder), mapReviewToResponse)
+}
+
+// GetPendingReviews 获取待审核的评测，默认先提交的排在前面，也可以按预审风险分从高到低排序
+func (s *ServiceImpl) GetPendingReviews(ctx context.Context, request review.PendingReviewListRequest) (*review.ReviewListResponse, error) {
+	filter := bson.M{
+		"status":    models.ReviewStatusPending,
+		"deletedAt": nil,
+	}
+	if request.Type != "" {
+		filter["type"] = request.Type
+	}
+
+	sort := bson.D{{Key: "createdAt", Value: 1}}
+	if request.SortBy == "risk" {
+		sort = bson.D{{Key: "moderation.riskScore", Value: -1}, {Key: "createdAt", Value: 1}}
+	}
+	return s.listReviews(ctx, filter, request.Page, request.PageSize, sort, mapPendingReviewToResponse)
+}
+
+// GetFeaturedReviews 获取推荐评测，按推荐排名排序
+func (s *ServiceImpl) GetFeaturedReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	filter := bson.M{
+		"status":       models.ReviewStatusFeatured,
+		"deletedAt":    nil,
+		"featuredRank": bson.M{"$ne": nil},
+	}
+	if reviewType != "" {
+		filter["type"] = reviewType
+	}
+
+	return s.listReviews(ctx, filter, 1, limit, bson.D{{Key: "featuredRank", Value: 1}, {Key: "createdAt", Value: -1}}, mapReviewToResponse)
+}
+
+// GetTrendingReviews 获取本周热门评测，按时间衰减后的浏览热度排序
+func (s *ServiceImpl) GetTrendingReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	match := bson.M{
+		"status":    bson.M{"$in": models.PublishedReviewStatuses},
+		"deletedAt": nil,
+	}
+	if reviewType != "" {
+		match["type"] = reviewType
+	}
+
+	cursor, err := s.db.Collection(models.ReviewsCollection).Aggregate(ctx, viewcount.TrendingPipeline(match, time.Now(), s.viewCfg, limit))
+	if err != nil {
+		return nil, errors.NewInternalServerError("查询热门评测失败: " + err.Error())
+	}
+	var reviews []models.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, errors.NewInternalServerError("解析热门评测失败: " + err.Error())
+	}
+
+	response := &review.ReviewListResponse{
+		Total:    len(reviews),
+		Page:     1,
+		PageSize: limit,
+		Reviews:  make([]review.ReviewResponse, len(reviews)),
+	}
+	for i := range reviews {
+		response.Reviews[i] = mapReviewToResponse(&reviews[i])
+	}
+	return response, nil
+}
+
+// GetUserReviewStats 获取用户评测统计，平均分只计算已发布的评测
+func (s *ServiceImpl) GetUserReviewStats(ctx context.Context, userID string) (*review.UserReviewStats, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+
+	countStatus := func(status models.ReviewStatus) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", status}}, 1, 0}}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userObjectID, "deletedAt": nil}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      nil,
+			"total":    bson.M{"$sum": 1},
+			"approved": countStatus(models.ReviewStatusApproved),
+			"pending":  countStatus(models.ReviewStatusPending),
+			"rejected": countStatus(models.ReviewStatusRejected),
+			"featured": countStatus(models.ReviewStatusFeatured),
+			"averageScore": bson.M{"$avg": bson.M{"$cond": bson.A{
+				bson.M{"$in": bson.A{"$status", models.PublishedReviewStatuses}}, "$score", nil,
+			}}},
+			"views": bson.M{"$sum": "$viewCount"},
+		}}},
+	}
+
+	cursor, err := s.db.Collection(models.ReviewsCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.NewInternalServerError("统计用户评测失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Total        int      `bson:"total"`
+		Approved     int      `bson:"approved"`
+		Pending      int      `bson:"pending"`
+		Rejected     int      `bson:"rejected"`
+		Featured     int      `bson:"featured"`
+		AverageScore *float64 `bson:"averageScore"`
+		Views        int      `bson:"views"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, errors.NewInternalServerError("解析用户评测统计失败: " + err.Error())
+	}
+
+	stats := &review.UserReviewStats{}
+	if len(rows) > 0 {
+		row := rows[0]
+		stats.TotalReviews = row.Total
+		stats.ApprovedCount = row.Approved
+		stats.PendingCount = row.Pending
+		stats.RejectedCount = row.Rejected
+		stats.FeaturedCount = row.Featured
+		stats.TotalViewCount = row.Views
+		if row.AverageScore != nil {
+			stats.AverageScore = *row.AverageScore
+		}
+	}
+	return stats, nil
+}
+
+// listReviews 按条件分页查询评测，mapReview决定返回哪些字段，预审结果只在审核接口返回
+func (s *ServiceImpl) listReviews(ctx context.Context, filter bson.M, page, pageSize int, sort bson.D, mapReview func(*models.Review) review.ReviewResponse) (*review.ReviewListResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.ReviewsCollection)
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("计算评测总数失败: " + err.Error())
+	}
+
+	// 列表使用摘要，不返回渲染后的正文
+	opts := options.Find().
+		SetProjection(bson.M{"contentHtml": 0}).
+		SetSort(sort).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("查询评测列表失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []models.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, errors.NewInternalServerError("解析评测列表失败: " + err.Error())
+	}
+
+	response := &review.ReviewListResponse{
+		Total:    int(total),
+		Page:     page,
+		PageSize: pageSize,
+		Reviews:  make([]review.ReviewResponse, len(reviews)),
+	}
+	for i := range reviews {
+		response.Reviews[i] = mapReview(&reviews[i])
+	}
+	return response, nil
+}
+
+// reviewSort 列表排序，默认按创建时间倒序，其他字段相同时按创建时间倒序
+func reviewSort(sortBy, sortOrder string) bson.D {
+	order := -1
+	if sortOrder == "asc" {
+		order = 1
+	}
+
+	switch sortBy {
+	case "score", "viewCount":
+		return bson.D{{Key: sortBy, Value: order}, {Key: "createdAt", Value: -1}}
+	case "helpful":
+		return bson.D{{Key: "helpfulScore", Value: order}, {Key: "createdAt", Value: -1}}
+	default:
+		return bson.D{{Key: "createdAt", Value: order}}
+	}
+}
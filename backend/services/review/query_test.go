@@ -0,0 +1,51 @@
+package review
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+)
+
+func TestReviewSort(t *testing.T) {
+	tests := []struct {
+		sortBy, sortOrder string
+		want              bson.D
+	}{
+		{"", "", bson.D{{Key: "createdAt", Value: -1}}},
+		{"createdAt", "asc", bson.D{{Key: "createdAt", Value: 1}}},
+		{"unknown", "desc", bson.D{{Key: "createdAt", Value: -1}}},
+		{"score", "", bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{"viewCount", "asc", bson.D{{Key: "viewCount", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{"helpful", "desc", bson.D{{Key: "helpfulScore", Value: -1}, {Key: "createdAt", Value: -1}}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, reviewSort(tt.sortBy, tt.sortOrder), "%s %s", tt.sortBy, tt.sortOrder)
+	}
+}
+
+func TestModerationOnlyInPendingList(t *testing.T) {
+	r := &models.Review{
+		ID:     primitive.NewObjectID(),
+		Status: models.ReviewStatusPending,
+		Moderation: &models.ReviewModeration{
+			RiskScore: 80,
+			Flags:     []models.ModerationFlag{{Code: "blocklist", Detail: "命中屏蔽词", Score: 80}},
+		},
+	}
+
+	assert.Nil(t, mapReviewToResponse(r).Moderation)
+	pending := mapPendingReviewToResponse(r).Moderation
+	require.NotNil(t, pending)
+	assert.Equal(t, 80, pending.RiskScore)
+
+	// 模型直接序列化时也不包含预审结果
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "moderation")
+}
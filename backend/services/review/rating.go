@@ -0,0 +1,39 @@
+package review
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+	"project/backend/services/rating"
+)
+
+// refreshRating 评测发布、修改或删除后重新计算涉及设备的评分，汇总失败不影响评测本身的操作
+func (s *ServiceImpl) refreshRating(ctx context.Context, r *models.Review) {
+	if r == nil {
+		return
+	}
+	for _, deviceID := range r.DeviceIDs() {
+		if err := rating.Refresh(ctx, s.db, deviceID); err != nil {
+			log.Printf("更新设备%s的评分汇总失败: %v", deviceID.Hex(), err)
+		}
+	}
+}
+
+// refreshRatings 修改后的内容通过后，重新计算修改前后涉及设备的评分，同一设备只计算一次
+func (s *ServiceImpl) refreshRatings(ctx context.Context, before, after *models.Review) {
+	seen := make(map[primitive.ObjectID]bool)
+	for _, r := range []*models.Review{before, after} {
+		for _, deviceID := range r.DeviceIDs() {
+			if seen[deviceID] {
+				continue
+			}
+			seen[deviceID] = true
+			if err := rating.Refresh(ctx, s.db, deviceID); err != nil {
+				log.Printf("更新设备%s的评分汇总失败: %v", deviceID.Hex(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,531 @@
+package review
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// reviseReview 修改已发布的评测：修改保存为待审核的版本，通过前继续展示上一次通过的内容
+// 可信评测员的低风险修改和新评测一样直接通过
+func (s *ServiceImpl) reviseReview(ctx context.Context, current *models.Review, request review.UpdateReviewRequest) (*models.Review, error) {
+	// 有待审核的修改时在其基础上继续修改
+	draft := *current
+	if current.PendingRevision > 0 {
+		pending, err := s.findRevision(ctx, current.ID, current.PendingRevision)
+		if err != nil {
+			return nil, err
+		}
+		draft.ApplySnapshot(pending.Snapshot)
+	}
+	if err := s.applyUpdate(ctx, &draft, request); err != nil {
+		return nil, err
+	}
+	if request.ImageIDs != nil {
+		if err := s.linkImages(ctx, &draft, *request.ImageIDs); err != nil {
+			return nil, err
+		}
+	}
+	RenderContent(&draft)
+
+	draft.Status = models.ReviewStatusPending
+	s.prescreen(ctx, &draft)
+	autoApproved := draft.Status == models.ReviewStatusApproved
+
+	now := time.Now()
+	rev := &models.ReviewRevision{
+		Status:     models.ReviewRevisionPending,
+		Snapshot:   draft.Snapshot(),
+		Changes:    diffSnapshots(current.Snapshot(), draft.Snapshot()),
+		Moderation: draft.Moderation,
+	}
+	if autoApproved {
+		rev.Status = models.ReviewRevisionApproved
+		rev.ReviewerNotes = draft.ReviewerNotes
+		rev.ReviewedAt = &now
+	}
+	if err := s.recordRevision(ctx, current, rev); err != nil {
+		return nil, err
+	}
+	s.supersedeRevisions(ctx, current.ID, rev.Revision)
+
+	set := bson.M{"revision": rev.Revision, "updatedAt": now}
+	update := bson.M{}
+	if autoApproved {
+		for key, value := range snapshotFields(rev.Snapshot) {
+			set[key] = value
+		}
+		set["moderation"] = draft.Moderation
+		update["$unset"] = bson.M{"pendingRevision": ""}
+	} else {
+		set["pendingRevision"] = rev.Revision
+	}
+	update["$set"] = set
+
+	var updated models.Review
+	err := s.db.Collection(models.ReviewsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": current.ID, "deletedAt": nil, "revision": revisionFilter(current.Revision)},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, revisionConflictError()
+		}
+		return nil, errors.NewInternalServerError("更新评测失败: " + err.Error())
+	}
+
+	if autoApproved {
+		s.refreshRatings(ctx, current, &updated)
+		s.logPruneImages(ctx, &updated)
+	} else if request.ImageIDs != nil {
+		// 被新修改替换掉的待审核图片不再需要
+		if err := s.pruneImages(ctx, current.ID, updated.Images, draft.Images); err != nil {
+			log.Printf("清理评测%s的图片失败: %v", current.ID.Hex(), err)
+		}
+	}
+	return &updated, nil
+}
+
+// recordRevision 写入一条修改记录并设置rev的版本号
+// 评测第一次修改时，先把修改前的内容保存为第1版，保证可以和最初的内容对比
+func (s *ServiceImpl) recordRevision(ctx context.Context, previous *models.Review, rev *models.ReviewRevision) error {
+	now := time.Now()
+	latest := previous.Revision
+	var docs []interface{}
+	if latest == 0 {
+		latest = 1
+		baseline := models.ReviewRevision{
+			ID:            primitive.NewObjectID(),
+			ReviewID:      previous.ID,
+			UserID:        previous.UserID,
+			Revision:      latest,
+			Status:        models.ReviewRevisionSuperseded,
+			Snapshot:      previous.Snapshot(),
+			Changes:       []models.FieldChange{},
+			Moderation:    previous.Moderation,
+			ReviewerID:    previous.ReviewerID,
+			ReviewerNotes: previous.ReviewerNotes,
+			ReviewedAt:    previous.ReviewedAt,
+			CreatedAt:     previous.CreatedAt,
+		}
+		if isPublished(previous.Status) {
+			baseline.Status = models.ReviewRevisionApproved
+		}
+		docs = append(docs, baseline)
+	}
+
+	rev.ID = primitive.NewObjectID()
+	rev.ReviewID = previous.ID
+	rev.UserID = previous.UserID
+	rev.Revision = latest + 1
+	rev.CreatedAt = now
+	docs = append(docs, *rev)
+
+	// reviewId+revision 上有唯一索引，并发修改同一评测时只有一个能写入
+	if _, err := s.db.Collection(models.ReviewRevisionsCollection).InsertMany(ctx, docs); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return revisionConflictError()
+		}
+		return errors.NewInternalServerError("保存修改记录失败: " + err.Error())
+	}
+	return nil
+}
+
+// supersedeRevisions 作者再次修改后，之前待审核的版本不再需要审核
+func (s *ServiceImpl) supersedeRevisions(ctx context.Context, reviewID primitive.ObjectID, latest int) {
+	_, err := s.db.Collection(models.ReviewRevisionsCollection).UpdateMany(ctx,
+		bson.M{"reviewId": reviewID, "status": models.ReviewRevisionPending, "revision": bson.M{"$lt": latest}},
+		bson.M{"$set": bson.M{"status": models.ReviewRevisionSuperseded}},
+	)
+	if err != nil {
+		log.Printf("更新评测%s的修改记录状态失败: %v", reviewID.Hex(), err)
+	}
+}
+
+// resolveLatestRevision 审核未发布的评测后同步最新修改记录的状态
+func (s *ServiceImpl) resolveLatestRevision(ctx context.Context, r *models.Review, status models.ReviewRevisionStatus) {
+	if r.Revision == 0 {
+		return
+	}
+	_, err := s.db.Collection(models.ReviewRevisionsCollection).UpdateOne(ctx,
+		bson.M{"reviewId": r.ID, "revision": r.Revision, "status": models.ReviewRevisionPending},
+		bson.M{"$set": bson.M{
+			"status":        status,
+			"reviewerId":    r.ReviewerID,
+			"reviewerNotes": r.ReviewerNotes,
+			"reviewedAt":    r.ReviewedAt,
+		}},
+	)
+	if err != nil {
+		log.Printf("更新评测%s的修改记录状态失败: %v", r.ID.Hex(), err)
+	}
+}
+
+// ApproveRevision 通过已发布评测的修改，修改后的内容替换公开内容
+func (s *ServiceImpl) ApproveRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error) {
+	current, rev, err := s.pendingRevision(ctx, moderatorID, reviewID, revision)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	moderatorObjectID, _ := primitive.ObjectIDFromHex(moderatorID)
+
+	set := snapshotFields(rev.Snapshot)
+	set["moderation"] = rev.Moderation
+	set["reviewerId"] = moderatorObjectID
+	set["reviewerNotes"] = notes
+	set["reviewedAt"] = now
+	set["updatedAt"] = now
+	updated, err := s.finishRevision(ctx, current.ID, revision, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"pendingRevision": ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.resolveRevision(ctx, rev, models.ReviewRevisionApproved, moderatorObjectID, notes, now)
+
+	// 对比设备可能发生变化，新旧设备都需要重新计算评分
+	s.refreshRatings(ctx, current, updated)
+	s.logPruneImages(ctx, updated)
+	return updated, nil
+}
+
+// RejectRevision 拒绝已发布评测的修改，评测继续展示上一次通过的内容
+func (s *ServiceImpl) RejectRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error) {
+	current, rev, err := s.pendingRevision(ctx, moderatorID, reviewID, revision)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.finishRevision(ctx, current.ID, revision, bson.M{
+		"$unset": bson.M{"pendingRevision": ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	moderatorObjectID, _ := primitive.ObjectIDFromHex(moderatorID)
+	s.resolveRevision(ctx, rev, models.ReviewRevisionRejected, moderatorObjectID, notes, time.Now())
+	s.logPruneImages(ctx, updated)
+	return updated, nil
+}
+
+// pendingRevision 查找已发布评测待审核的修改，作者不能审核自己的修改
+func (s *ServiceImpl) pendingRevision(ctx context.Context, moderatorID, reviewID string, revision int) (*models.Review, *models.ReviewRevision, error) {
+	moderatorObjectID, err := primitive.ObjectIDFromHex(moderatorID)
+	if err != nil {
+		return nil, nil, errors.NewBadRequestError("无效的审核员ID")
+	}
+	id, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return nil, nil, errors.NewBadRequestError("无效的评测ID")
+	}
+
+	var current models.Review
+	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{
+		"_id":             id,
+		"pendingRevision": revision,
+		"deletedAt":       nil,
+	}).Decode(&current)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, errors.NewNotFoundError("未找到待审核的修改")
+		}
+		return nil, nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
+	}
+	if current.UserID == moderatorObjectID {
+		return nil, nil, errors.NewForbiddenError("不能审核自己的修改")
+	}
+
+	rev, err := s.findRevision(ctx, id, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &current, rev, nil
+}
+
+// resolveRevision 记录修改的审核结果，在finishRevision成功之后调用，失败只记录日志
+// finishRevision按待审核的版本号更新评测，并发审核只有一个能走到这里
+func (s *ServiceImpl) resolveRevision(ctx context.Context, rev *models.ReviewRevision, status models.ReviewRevisionStatus, moderatorObjectID primitive.ObjectID, notes string, now time.Time) {
+	_, err := s.db.Collection(models.ReviewRevisionsCollection).UpdateOne(ctx,
+		bson.M{"_id": rev.ID, "status": models.ReviewRevisionPending},
+		bson.M{"$set": bson.M{
+			"status":        status,
+			"reviewerId":    moderatorObjectID,
+			"reviewerNotes": notes,
+			"reviewedAt":    now,
+		}},
+	)
+	if err != nil {
+		log.Printf("更新评测%s第%d版修改记录的审核结果失败: %v", rev.ReviewID.Hex(), rev.Revision, err)
+		return
+	}
+	rev.Status = status
+	rev.ReviewerID = &moderatorObjectID
+	rev.ReviewerNotes = notes
+	rev.ReviewedAt = &now
+}
+
+// finishRevision 审核完成后更新评测，期间作者又提交了新的修改时返回冲突
+func (s *ServiceImpl) finishRevision(ctx context.Context, reviewID primitive.ObjectID, revision int, update bson.M) (*models.Review, error) {
+	var updated models.Review
+	err := s.db.Collection(models.ReviewsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": reviewID, "pendingRevision": revision, "deletedAt": nil},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, revisionConflictError()
+		}
+		return nil, errors.NewInternalServerError("更新评测失败: " + err.Error())
+	}
+	return &updated, nil
+}
+
+// GetPendingRevisionReviews 获取有待审核修改的已发布评测，先提交修改的排在前面
+func (s *ServiceImpl) GetPendingRevisionReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error) {
+	filter := bson.M{
+		"pendingRevision": bson.M{"$gt": 0},
+		"deletedAt":       nil,
+	}
+	return s.listReviews(ctx, filter, page, pageSize, bson.D{{Key: "updatedAt", Value: 1}}, mapReviewToResponse)
+}
+
+// ListReviewRevisions 获取评测的修改历史，按版本号倒序
+func (s *ServiceImpl) ListReviewRevisions(ctx context.Context, reviewID string, page, pageSize int) (*review.ReviewRevisionListResponse, error) {
+	id, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的评测ID")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.ReviewRevisionsCollection)
+	filter := bson.M{"reviewId": id}
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取修改历史失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.NewInternalServerError("获取修改历史失败: " + err.Error())
+	}
+	var revisions []models.ReviewRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, errors.NewInternalServerError("解析修改历史失败: " + err.Error())
+	}
+
+	response := &review.ReviewRevisionListResponse{
+		Revisions: make([]review.ReviewRevisionResponse, len(revisions)),
+		Total:     int(total),
+		Page:      page,
+		PageSize:  pageSize,
+	}
+	for i := range revisions {
+		response.Revisions[i] = mapRevisionToResponse(&revisions[i])
+	}
+	return response, nil
+}
+
+// DiffReviewRevisions 对比评测的两个版本
+// to默认为待审核的版本（没有时为最新版本），from默认为to之前最近一次通过的版本
+func (s *ServiceImpl) DiffReviewRevisions(ctx context.Context, reviewID string, from, to int) (*review.ReviewRevisionDiffResponse, error) {
+	current, err := s.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Revision == 0 {
+		return nil, errors.NewBadRequestError("该评测没有修改记录")
+	}
+
+	if to == 0 {
+		to = current.PendingRevision
+		if to == 0 {
+			to = current.Revision
+		}
+	}
+	toRev, err := s.findRevision(ctx, current.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromRev *models.ReviewRevision
+	if from == 0 {
+		fromRev, err = s.previousApprovedRevision(ctx, current.ID, to)
+	} else {
+		fromRev, err = s.findRevision(ctx, current.ID, from)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &review.ReviewRevisionDiffResponse{
+		ReviewID: reviewID,
+		From:     fromRev.Revision,
+		To:       to,
+		Changes:  diffSnapshots(fromRev.Snapshot, toRev.Snapshot),
+	}, nil
+}
+
+// previousApprovedRevision 查找before之前最近一次通过的版本，没有时取前一个版本
+func (s *ServiceImpl) previousApprovedRevision(ctx context.Context, reviewID primitive.ObjectID, before int) (*models.ReviewRevision, error) {
+	var rev models.ReviewRevision
+	err := s.db.Collection(models.ReviewRevisionsCollection).FindOne(ctx,
+		bson.M{"reviewId": reviewID, "status": models.ReviewRevisionApproved, "revision": bson.M{"$lt": before}},
+		options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}),
+	).Decode(&rev)
+	if err == nil {
+		return &rev, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, errors.NewInternalServerError("获取版本失败: " + err.Error())
+	}
+	if before <= 1 {
+		return nil, errors.NewBadRequestError("没有可以对比的版本")
+	}
+	return s.findRevision(ctx, reviewID, before-1)
+}
+
+// findRevision 查找评测的指定版本
+func (s *ServiceImpl) findRevision(ctx context.Context, reviewID primitive.ObjectID, revision int) (*models.ReviewRevision, error) {
+	if revision <= 0 {
+		return nil, errors.NewBadRequestError("无效的版本号")
+	}
+
+	var rev models.ReviewRevision
+	err := s.db.Collection(models.ReviewRevisionsCollection).FindOne(ctx, bson.M{
+		"reviewId": reviewID,
+		"revision": revision,
+	}).Decode(&rev)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("版本不存在")
+		}
+		return nil, errors.NewInternalServerError("获取版本失败: " + err.Error())
+	}
+	return &rev, nil
+}
+
+// logPruneImages 删除公开内容不再使用的图片，失败只记录日志
+func (s *ServiceImpl) logPruneImages(ctx context.Context, r *models.Review) {
+	if err := s.pruneImages(ctx, r.ID, r.Images); err != nil {
+		log.Printf("清理评测%s的图片失败: %v", r.ID.Hex(), err)
+	}
+}
+
+// revisionFilter 按版本号做乐观锁，开始记录历史前的评测没有revision字段
+func revisionFilter(revision int) interface{} {
+	if revision == 0 {
+		return bson.M{"$in": bson.A{nil, 0}}
+	}
+	return revision
+}
+
+func revisionConflictError() error {
+	return errors.NewAppError(errors.Conflict, "评测已被修改，请刷新后重试")
+}
+
+func isPublished(status models.ReviewStatus) bool {
+	for _, published := range models.PublishedReviewStatuses {
+		if status == published {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotFields 用于把修改记录的内容写回评测
+func snapshotFields(snapshot models.ReviewSnapshot) bson.M {
+	return bson.M{
+		"externalItemId": snapshot.ExternalItemID,
+		"content":        snapshot.Content,
+		"contentFormat":  snapshot.ContentFormat,
+		"contentHtml":    snapshot.ContentHTML,
+		"excerpt":        snapshot.Excerpt,
+		"pros":           snapshot.Pros,
+		"cons":           snapshot.Cons,
+		"score":          snapshot.Score,
+		"usage":          snapshot.Usage,
+		"comparison":     snapshot.Comparison,
+		"images":         snapshot.Images,
+	}
+}
+
+// diffSnapshots 按字段对比两个版本的内容，列表和对比结论整体比较，渲染结果和摘要随正文变化不单独列出
+func diffSnapshots(before, after models.ReviewSnapshot) []models.FieldChange {
+	fields := []struct {
+		path     string
+		old, new interface{}
+	}{
+		{"externalItemId", before.ExternalItemID, after.ExternalItemID},
+		{"content", before.Content, after.Content},
+		{"contentFormat", before.ContentFormat, after.ContentFormat},
+		{"pros", before.Pros, after.Pros},
+		{"cons", before.Cons, after.Cons},
+		{"score", before.Score, after.Score},
+		{"usage", before.Usage, after.Usage},
+		{"comparison", before.Comparison, after.Comparison},
+		{"images", before.Images, after.Images},
+	}
+
+	changes := make([]models.FieldChange, 0)
+	for _, field := range fields {
+		if sameValue(field.old, field.new) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{Path: field.path, OldValue: field.old, NewValue: field.new})
+	}
+	return changes
+}
+
+// sameValue 比较两个字段值，空列表和nil视为相同
+func sameValue(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == reflect.Slice && vb.Kind() == reflect.Slice && va.Len() == 0 && vb.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// mapRevisionToResponse 转换修改记录
+func mapRevisionToResponse(rev *models.ReviewRevision) review.ReviewRevisionResponse {
+	response := review.ReviewRevisionResponse{
+		ReviewID:      rev.ReviewID.Hex(),
+		UserID:        rev.UserID.Hex(),
+		Revision:      rev.Revision,
+		Status:        string(rev.Status),
+		Snapshot:      rev.Snapshot,
+		Changes:       rev.Changes,
+		Moderation:    moderationResponse(rev.Moderation),
+		ReviewerNotes: rev.ReviewerNotes,
+		ReviewedAt:    rev.ReviewedAt,
+		CreatedAt:     rev.CreatedAt,
+	}
+	if response.Changes == nil {
+		response.Changes = []models.FieldChange{}
+	}
+	if rev.ReviewerID != nil {
+		response.ReviewerID = rev.ReviewerID.Hex()
+	}
+	return response
+}
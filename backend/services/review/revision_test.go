@@ -0,0 +1,62 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/models"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	deviceID := primitive.NewObjectID()
+	before := models.ReviewSnapshot{
+		ExternalItemID: deviceID,
+		Content:        "原内容",
+		ContentHTML:    "<p>原内容</p>",
+		Excerpt:        "原内容",
+		Pros:           []string{"轻"},
+		Cons:           nil,
+		Score:          4,
+		Usage:          "办公",
+	}
+
+	t.Run("内容相同时没有变更", func(t *testing.T) {
+		after := before
+		after.Cons = []string{}
+		after.Images = []models.ReviewAttachment{}
+		assert.Empty(t, diffSnapshots(before, after), "空列表和nil视为相同")
+	})
+
+	t.Run("只列出修改的字段", func(t *testing.T) {
+		after := before
+		after.Content = "新内容"
+		after.ContentHTML = "<p>新内容</p>"
+		after.Excerpt = "新内容"
+		after.Pros = []string{"轻", "稳"}
+		after.Score = 4.5
+
+		assert.Equal(t, []models.FieldChange{
+			{Path: "content", OldValue: "原内容", NewValue: "新内容"},
+			{Path: "pros", OldValue: []string{"轻"}, NewValue: []string{"轻", "稳"}},
+			{Path: "score", OldValue: 4.0, NewValue: 4.5},
+		}, diffSnapshots(before, after))
+	})
+
+	t.Run("对比结论整体比较", func(t *testing.T) {
+		other := primitive.NewObjectID()
+		comparison := &models.ReviewComparison{Items: []models.ComparisonItem{{DeviceID: deviceID, Score: 4}, {DeviceID: other, Score: 3}}}
+		withWinner := &models.ReviewComparison{
+			Items:   comparison.Items,
+			Winners: []models.ComparisonWinner{{Category: models.ComparisonCategoryWeight, DeviceID: other}},
+		}
+		a, b := before, before
+		a.Comparison, b.Comparison = comparison, withWinner
+
+		changes := diffSnapshots(a, b)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, "comparison", changes[0].Path)
+		}
+	})
+}
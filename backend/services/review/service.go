@@ -1,20 +1,29 @@
 package review
 
 import (
+	"project/backend/config"
 	"project/backend/internal/errors"
+	"project/backend/internal/storage"
 	"project/backend/models"
+	"project/backend/services/moderation"
+	"project/backend/services/viewcount"
 	"project/backend/types/review"
 	"context"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
 )
 
 // ServiceImpl 评测服务实现
 type ServiceImpl struct {
-	db *mongo.Database
+	db            *mongo.Database
+	notifier      Notifier
+	storage       storage.Storage
+	moderation    *moderation.Pipeline
+	moderationCfg config.ModerationConfig
+	views         *viewcount.Counter
+	viewCfg       config.ViewConfig
 }
 
 // DefaultService 默认评测服务实现
@@ -23,9 +32,16 @@ type DefaultService struct {
 }
 
 // New 创建新的评测服务
-func New(db *mongo.Database) Service {
+// views为空时浏览数直接写入数据库，不做去重
+func New(db *mongo.Database, notifier Notifier, store storage.Storage, views *viewcount.Counter, cfg config.ReviewConfig) Service {
 	return &ServiceImpl{
-		db: db,
+		db:            db,
+		notifier:      notifier,
+		storage:       store,
+		moderation:    moderation.NewDefaultPipeline(db, cfg.Moderation),
+		moderationCfg: moderation.WithDefaults(cfg.Moderation),
+		views:         views,
+		viewCfg:       viewcount.WithDefaults(cfg.Views),
 	}
 }
 
@@ -84,8 +100,14 @@ func (s *DefaultService) FeaturedReview(ctx context.Context, reviewerID, reviewI
 	return nil, nil
 }
 
+// GetFeaturedReviews 获取推荐评测
+func (s *DefaultService) GetFeaturedReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
 // GetPendingReviews 获取待审核评测
-func (s *DefaultService) GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*review.ReviewListResponse, error) {
+func (s *DefaultService) GetPendingReviews(ctx context.Context, request review.PendingReviewListRequest) (*review.ReviewListResponse, error) {
 	// 空实现，仅为了满足接口
 	return nil, nil
 }
@@ -97,11 +119,129 @@ func (s *DefaultService) GetUserReviewStats(ctx context.Context, userID string)
 }
 
 // IncrementViewCount 增加查看次数
-func (s *DefaultService) IncrementViewCount(ctx context.Context, reviewID string) error {
+func (s *DefaultService) IncrementViewCount(ctx context.Context, reviewID string, visitor viewcount.Visitor) error {
+	// 空实现，仅为了满足接口
+	return nil
+}
+
+// GetTrendingReviews 获取热门评测
+func (s *DefaultService) GetTrendingReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error) {
+	// 空实现，仅为了满足接口
+	return &review.ReviewListResponse{
+		Total:    0,
+		Page:     1,
+		PageSize: limit,
+		Reviews:  []review.ReviewResponse{},
+	}, nil
+}
+
+// ListDeletedReviews 获取回收站中的评测
+func (s *DefaultService) ListDeletedReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// RestoreReview 从回收站恢复评测
+func (s *DefaultService) RestoreReview(ctx context.Context, reviewID string) error {
+	// 空实现，仅为了满足接口
+	return nil
+}
+
+// PurgeReview 彻底删除评测
+func (s *DefaultService) PurgeReview(ctx context.Context, reviewID string) error {
+	// 空实现，仅为了满足接口
+	return nil
+}
+
+// VoteReview 投票评测是否有帮助
+func (s *DefaultService) VoteReview(ctx context.Context, userID, reviewID string, helpful bool) (*review.VoteResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// RemoveVote 撤销投票
+func (s *DefaultService) RemoveVote(ctx context.Context, userID, reviewID string) (*review.VoteResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// CreateComment 发表评论
+func (s *DefaultService) CreateComment(ctx context.Context, userID, reviewID string, request review.CreateCommentRequest) (*models.ReviewComment, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// ListComments 获取评测的评论
+func (s *DefaultService) ListComments(ctx context.Context, reviewID string, page, pageSize int) (*review.CommentListResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// DeleteComment 删除评论
+func (s *DefaultService) DeleteComment(ctx context.Context, userID, commentID string) error {
+	// 空实现，仅为了满足接口
+	return nil
+}
+
+// HideComment 隐藏评论
+func (s *DefaultService) HideComment(ctx context.Context, moderatorID, commentID, note string) error {
+	// 空实现，仅为了满足接口
+	return nil
+}
+
+// RestoreComment 恢复被隐藏的评论
+func (s *DefaultService) RestoreComment(ctx context.Context, moderatorID, commentID string) error {
 	// 空实现，仅为了满足接口
 	return nil
 }
 
+// UploadReviewImage 上传评测图片
+func (s *DefaultService) UploadReviewImage(ctx context.Context, userID string, content []byte) (*models.ReviewImage, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// CleanupOrphanImages 清理未关联评测的图片
+func (s *DefaultService) CleanupOrphanImages(ctx context.Context, before time.Time) (int, error) {
+	// 空实现，仅为了满足接口
+	return 0, nil
+}
+
+// GetPendingRevisionReviews 获取有待审核修改的评测
+func (s *DefaultService) GetPendingRevisionReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error) {
+	// 空实现，仅为了满足接口
+	return &review.ReviewListResponse{
+		Total:    0,
+		Page:     page,
+		PageSize: pageSize,
+		Reviews:  []review.ReviewResponse{},
+	}, nil
+}
+
+// ListReviewRevisions 获取评测修改历史
+func (s *DefaultService) ListReviewRevisions(ctx context.Context, reviewID string, page, pageSize int) (*review.ReviewRevisionListResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// DiffReviewRevisions 对比评测的两个版本
+func (s *DefaultService) DiffReviewRevisions(ctx context.Context, reviewID string, from, to int) (*review.ReviewRevisionDiffResponse, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// ApproveRevision 通过评测的修改
+func (s *DefaultService) ApproveRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
+// RejectRevision 拒绝评测的修改
+func (s *DefaultService) RejectRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error) {
+	// 空实现，仅为了满足接口
+	return nil, nil
+}
+
 // CreateReview 创建评测
 func (s *ServiceImpl) CreateReview(ctx context.Context, userID string, request review.CreateReviewRequest) (*models.Review, error) {
 	// 将用户ID转换为ObjectID
@@ -110,10 +250,24 @@ func (s *ServiceImpl) CreateReview(ctx context.Context, userID string, request r
 		return nil, errors.NewBadRequestError("无效的用户ID")
 	}
 	
+	// 对比评测关联多个设备，主设备默认为第一个对比设备
+	comparison, err := s.buildComparison(ctx, request.ItemType, models.ReviewContentType(request.ContentType), request.Comparison)
+	if err != nil {
+		return nil, err
+	}
+	
 	// 将外部项目ID转换为ObjectID
-	externalItemID, err := primitive.ObjectIDFromHex(request.ExternalItemID)
+	var externalItemID primitive.ObjectID
+	if comparison != nil {
+		externalItemID, err = primaryDeviceID(request.ExternalItemID, comparison)
+	} else {
+		externalItemID, err = primitive.ObjectIDFromHex(request.ExternalItemID)
+		if err != nil {
+			err = errors.NewBadRequestError("无效的项目ID")
+		}
+	}
 	if err != nil {
-		return nil, errors.NewBadRequestError("无效的项目ID")
+		return nil, err
 	}
 	
 	// 创建评测
@@ -123,7 +277,6 @@ func (s *ServiceImpl) CreateReview(ctx context.Context, userID string, request r
 		ExternalItemID: externalItemID,
 		ItemType:       request.ItemType,
 		UserID:         userObjectID,
-		Content:        request.Content,
 		Pros:           request.Pros,
 		Cons:           request.Cons,
 		Score:          request.Score,
@@ -131,16 +284,32 @@ func (s *ServiceImpl) CreateReview(ctx context.Context, userID string, request r
 		Status:         models.ReviewStatusPending,
 		Type:           models.ReviewType(request.Type),
 		ContentType:    models.ReviewContentType(request.ContentType),
+		Comparison:     comparison,
+		Locale:         request.Locale,
 		ViewCount:      0,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
+	if err := applyContent(&reviewModel, request.Content, request.ContentFormat); err != nil {
+		return nil, err
+	}
+	if len(request.ImageIDs) > 0 {
+		if err := s.attachImages(ctx, &reviewModel, request.ImageIDs); err != nil {
+			return nil, err
+		}
+	}
+	RenderContent(&reviewModel)
+	s.prescreen(ctx, &reviewModel)
 	
 	// 保存到数据库
 	_, err = s.db.Collection(models.ReviewsCollection).InsertOne(ctx, reviewModel)
 	if err != nil {
+		s.releaseImages(ctx, reviewModel.ID)
 		return nil, errors.NewInternalServerError("创建评测失败: " + err.Error())
 	}
+	if reviewModel.Status == models.ReviewStatusApproved {
+		s.refreshRating(ctx, &reviewModel)
+	}
 	
 	return &reviewModel, nil
 }
@@ -155,7 +324,7 @@ func (s *ServiceImpl) GetReview(ctx context.Context, reviewID string) (*models.R
 	
 	// 查询评测
 	var reviewModel models.Review
-	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&reviewModel)
+	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{"_id": id, "deletedAt": nil}).Decode(&reviewModel)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.NewNotFoundError("评测不存在")
@@ -166,20 +335,6 @@ func (s *ServiceImpl) GetReview(ctx context.Context, reviewID string) (*models.R
 	return &reviewModel, nil
 }
 
-// ListReviews 列出评测
-func (s *ServiceImpl) ListReviews(ctx context.Context, request review.ReviewListRequest) (*review.ReviewListResponse, error) {
-	if request.ExternalItemID != "" {
-		result, err := s.getReviewsByItemID(ctx, request.ExternalItemID, request.ItemType, request.Page, request.PageSize, request.SortBy)
-		return result, err
-	} else if request.UserID != "" {
-		result, err := s.getReviewsByUserID(ctx, request.UserID, request.Page, request.PageSize)
-		return result, err
-	}
-	
-	// 默认返回全部评测
-	return s.GetPendingReviews(ctx, request.ItemType, request.Page, request.PageSize)
-}
-
 // UpdateReview 更新评测
 func (s *ServiceImpl) UpdateReview(ctx context.Context, userID, reviewID string, request review.UpdateReviewRequest) (*models.Review, error) {
 	// 将ID转换为ObjectID
@@ -208,47 +363,111 @@ func (s *ServiceImpl) UpdateReview(ctx context.Context, userID, reviewID string,
 		return nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
 	}
 	
-	// 检查评测状态，已审核通过的评测不能修改
-	if currentReview.Status != models.ReviewStatusPending {
-		return nil, errors.NewBadRequestError("已审核的评测不能修改")
+	// 已发布的评测修改后需要重新审核，被拒绝的评测不能修改
+	switch currentReview.Status {
+	case models.ReviewStatusPending:
+	case models.ReviewStatusApproved, models.ReviewStatusFeatured:
+		return s.reviseReview(ctx, &currentReview, request)
+	default:
+		return nil, errors.NewBadRequestError("已拒绝的评测不能修改")
 	}
 	
-	// 更新字段
-	if request.Content != nil && *request.Content != "" {
-		currentReview.Content = *request.Content
+	// 未发布的评测直接修改，同时记录修改历史
+	previous := currentReview
+	if err := s.applyUpdate(ctx, &currentReview, request); err != nil {
+		return nil, err
 	}
 	
-	if request.Score != nil && *request.Score > 0 {
-		currentReview.Score = *request.Score
+	if request.ImageIDs != nil {
+		if err := s.attachImages(ctx, &currentReview, *request.ImageIDs); err != nil {
+			return nil, err
+		}
 	}
 	
-	if request.Usage != nil && *request.Usage != "" {
-		currentReview.Usage = *request.Usage
-	}
+	RenderContent(&currentReview)
+	currentReview.UpdatedAt = time.Now()
+	s.prescreen(ctx, &currentReview)
 	
-	if request.Pros != nil && len(*request.Pros) > 0 {
-		currentReview.Pros = *request.Pros
+	rev := &models.ReviewRevision{
+		Status:     models.ReviewRevisionPending,
+		Snapshot:   currentReview.Snapshot(),
+		Changes:    diffSnapshots(previous.Snapshot(), currentReview.Snapshot()),
+		Moderation: currentReview.Moderation,
 	}
-	
-	if request.Cons != nil && len(*request.Cons) > 0 {
-		currentReview.Cons = *request.Cons
+	if currentReview.Status == models.ReviewStatusApproved {
+		rev.Status = models.ReviewRevisionApproved
+		rev.ReviewerNotes = currentReview.ReviewerNotes
+		rev.ReviewedAt = currentReview.ReviewedAt
 	}
-	
-	currentReview.UpdatedAt = time.Now()
+	if err := s.recordRevision(ctx, &previous, rev); err != nil {
+		return nil, err
+	}
+	s.supersedeRevisions(ctx, reviewObjectID, rev.Revision)
+	currentReview.Revision = rev.Revision
 	
 	// 保存到数据库
-	_, err = s.db.Collection(models.ReviewsCollection).ReplaceOne(ctx, bson.M{
-		"_id":    reviewObjectID,
-		"userId": userObjectID,
+	res, err := s.db.Collection(models.ReviewsCollection).ReplaceOne(ctx, bson.M{
+		"_id":      reviewObjectID,
+		"userId":   userObjectID,
+		"revision": revisionFilter(previous.Revision),
 	}, currentReview)
 	
 	if err != nil {
 		return nil, errors.NewInternalServerError("更新评测失败: " + err.Error())
 	}
+	if res.MatchedCount == 0 {
+		return nil, revisionConflictError()
+	}
+	s.refreshRating(ctx, &currentReview)
 	
 	return &currentReview, nil
 }
 
+// applyUpdate 把修改请求中的内容应用到评测上，图片由调用方单独处理
+func (s *ServiceImpl) applyUpdate(ctx context.Context, r *models.Review, request review.UpdateReviewRequest) error {
+	var content, format string
+	if request.Content != nil {
+		content = *request.Content
+	}
+	if request.ContentFormat != nil {
+		format = *request.ContentFormat
+	}
+	if err := applyContent(r, content, format); err != nil {
+		return err
+	}
+	
+	if request.Score != nil && *request.Score > 0 {
+		r.Score = *request.Score
+	}
+	
+	if request.Usage != nil && *request.Usage != "" {
+		r.Usage = *request.Usage
+	}
+	
+	if request.Pros != nil && len(*request.Pros) > 0 {
+		r.Pros = *request.Pros
+	}
+	
+	if request.Cons != nil && len(*request.Cons) > 0 {
+		r.Cons = *request.Cons
+	}
+	
+	if request.Comparison != nil {
+		comparison, err := s.buildComparison(ctx, r.ItemType, r.ContentType, request.Comparison)
+		if err != nil {
+			return err
+		}
+		// 原主设备不再参与对比时改用第一个对比设备
+		primary, err := primaryDeviceID(r.ExternalItemID.Hex(), comparison)
+		if err != nil {
+			primary = comparison.Items[0].DeviceID
+		}
+		r.Comparison = comparison
+		r.ExternalItemID = primary
+	}
+	return nil
+}
+
 // DeleteReview 删除评测
 func (s *ServiceImpl) DeleteReview(ctx context.Context, userID, reviewID string) error {
 	// 将ID转换为ObjectID
@@ -302,307 +521,37 @@ func (s *ServiceImpl) DeleteReview(ctx context.Context, userID, reviewID string)
 	if err != nil {
 		return errors.NewInternalServerError("删除评测失败: " + err.Error())
 	}
+	s.refreshRating(ctx, &currentReview)
 	
 	return nil
 }
 
-// ApproveReview 批准评测
-func (s *ServiceImpl) ApproveReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error) {
-	// 将ID转换为ObjectID
-	reviewObjectID, err := primitive.ObjectIDFromHex(reviewID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的评测ID")
-	}
-	
-	reviewerObjectID, err := primitive.ObjectIDFromHex(reviewerID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的审核员ID")
-	}
-	
-	// 查找当前评测
-	var currentReview models.Review
-	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{
-		"_id":       reviewObjectID,
-		"status":    models.ReviewStatusPending,
-		"deletedAt": nil,
-	}).Decode(&currentReview)
-	
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.NewNotFoundError("未找到待审核的评测")
-		}
-		return nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
-	}
-	
-	// 更新评测状态
-	now := time.Now()
-	currentReview.Status = models.ReviewStatusApproved
-	currentReview.ReviewerID = &reviewerObjectID
-	currentReview.ReviewedAt = &now
-	currentReview.PublishedAt = &now
-	currentReview.UpdatedAt = now
-	
-	// 保存到数据库
-	_, err = s.db.Collection(models.ReviewsCollection).ReplaceOne(ctx, bson.M{
-		"_id":    reviewObjectID,
-		"status": models.ReviewStatusPending,
-	}, currentReview)
-	
-	if err != nil {
-		return nil, errors.NewInternalServerError("更新评测状态失败: " + err.Error())
-	}
-	
-	return &currentReview, nil
-}
-
-// RejectReview 拒绝评测
-func (s *ServiceImpl) RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error) {
-	// 将ID转换为ObjectID
-	reviewObjectID, err := primitive.ObjectIDFromHex(reviewID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的评测ID")
-	}
-	
-	reviewerObjectID, err := primitive.ObjectIDFromHex(reviewerID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的审核员ID")
-	}
-	
-	// 查找当前评测
-	var currentReview models.Review
-	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{
-		"_id":       reviewObjectID,
-		"status":    models.ReviewStatusPending,
-		"deletedAt": nil,
-	}).Decode(&currentReview)
-	
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.NewNotFoundError("未找到待审核的评测")
-		}
-		return nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
-	}
-	
-	// 更新评测状态
-	now := time.Now()
-	currentReview.Status = models.ReviewStatusRejected
-	currentReview.ReviewerID = &reviewerObjectID
-	currentReview.ReviewerNotes = notes
-	currentReview.ReviewedAt = &now
-	currentReview.UpdatedAt = now
-	
-	// 保存到数据库
-	_, err = s.db.Collection(models.ReviewsCollection).ReplaceOne(ctx, bson.M{
-		"_id":    reviewObjectID,
-		"status": models.ReviewStatusPending,
-	}, currentReview)
-	
-	if err != nil {
-		return nil, errors.NewInternalServerError("更新评测状态失败: " + err.Error())
-	}
-	
-	return &currentReview, nil
-}
-
-// FeaturedReview 设置推荐评测
-func (s *ServiceImpl) FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.Review, error) {
-	// 将ID转换为ObjectID
-	reviewObjectID, err := primitive.ObjectIDFromHex(reviewID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的评测ID")
-	}
-	
-	reviewerObjectID, err := primitive.ObjectIDFromHex(reviewerID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的审核员ID")
-	}
-	
-	// 查找当前评测
-	var currentReview models.Review
-	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{
-		"_id":       reviewObjectID,
-		"status":    models.ReviewStatusApproved,
-		"deletedAt": nil,
-	}).Decode(&currentReview)
-	
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.NewNotFoundError("未找到已批准的评测")
-		}
-		return nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
-	}
-	
-	// 更新评测状态
-	now := time.Now()
-	currentReview.Status = models.ReviewStatusFeatured
-	currentReview.ReviewerID = &reviewerObjectID
-	currentReview.FeaturedRank = &rank
-	currentReview.UpdatedAt = now
-	
-	// 保存到数据库
-	_, err = s.db.Collection(models.ReviewsCollection).ReplaceOne(ctx, bson.M{
-		"_id":    reviewObjectID,
-		"status": models.ReviewStatusApproved,
-	}, currentReview)
-	
-	if err != nil {
-		return nil, errors.NewInternalServerError("更新评测状态失败: " + err.Error())
-	}
-	
-	return &currentReview, nil
-}
-
-// GetPendingReviews 获取待审核的评测
-func (s *ServiceImpl) GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*review.ReviewListResponse, error) {
-	// 设置分页
-	if page <= 0 {
-		page = 1
-	}
-	
-	if pageSize <= 0 || pageSize > 100 {
-		pageSize = 20
-	}
-	
-	skip := (page - 1) * pageSize
-	skipInt64 := int64(skip)
-	limitInt64 := int64(pageSize)
-	
-	// 构建查询
-	filter := bson.M{
-		"status":    models.ReviewStatusPending,
-		"deletedAt": nil,
-	}
-	
-	// 计算总数
-	total, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算待审核评测总数失败: " + err.Error())
-	}
-	
-	// 查询评测列表
-	cursor, err := s.db.Collection(models.ReviewsCollection).Find(ctx, filter, &options.FindOptions{
-		Skip:  &skipInt64,
-		Limit: &limitInt64,
-		Sort:  bson.D{{Key: "createdAt", Value: 1}},
-	})
-	if err != nil {
-		return nil, errors.NewInternalServerError("查询待审核评测列表失败: " + err.Error())
-	}
-	defer cursor.Close(ctx)
-	
-	// 解析评测列表
-	var reviews []models.Review
-	if err = cursor.All(ctx, &reviews); err != nil {
-		return nil, errors.NewInternalServerError("解析待审核评测列表失败: " + err.Error())
-	}
-	
-	// 转换为响应格式
-	response := &review.ReviewListResponse{
-		Total:    int(total),
-		Page:     page,
-		PageSize: pageSize,
-		Reviews:  make([]review.ReviewResponse, len(reviews)),
-	}
-	
-	for i, r := range reviews {
-		response.Reviews[i] = mapReviewToResponse(&r)
-	}
-	
-	return response, nil
-}
-
-// GetUserReviewStats 获取用户评测统计
-func (s *ServiceImpl) GetUserReviewStats(ctx context.Context, userID string) (*review.UserReviewStats, error) {
-	// 实现统计逻辑 - 简单实现
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的用户ID")
-	}
-	
-	// 构建基本过滤器
-	filter := bson.M{
-		"userId":    userObjectID,
-		"deletedAt": nil,
-	}
-	
-	// 计算总数
-	totalCount, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算评测总数失败: " + err.Error())
-	}
-	
-	// 计算已批准的评测
-	approvedFilter := bson.M{
-		"userId":    userObjectID,
-		"status":    models.ReviewStatusApproved,
-		"deletedAt": nil,
-	}
-	approvedCount, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, approvedFilter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算已批准评测总数失败: " + err.Error())
-	}
-	
-	// 计算待审核的评测
-	pendingFilter := bson.M{
-		"userId":    userObjectID,
-		"status":    models.ReviewStatusPending,
-		"deletedAt": nil,
-	}
-	pendingCount, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, pendingFilter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算待审核评测总数失败: " + err.Error())
-	}
-	
-	// 计算已拒绝的评测
-	rejectedFilter := bson.M{
-		"userId":    userObjectID,
-		"status":    models.ReviewStatusRejected,
-		"deletedAt": nil,
-	}
-	rejectedCount, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, rejectedFilter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算已拒绝评测总数失败: " + err.Error())
-	}
-	
-	// 计算推荐的评测
-	featuredFilter := bson.M{
-		"userId":    userObjectID,
-		"status":    models.ReviewStatusFeatured,
-		"deletedAt": nil,
-	}
-	featuredCount, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, featuredFilter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算推荐评测总数失败: " + err.Error())
-	}
-	
-	// 返回统计结果
-	return &review.UserReviewStats{
-		TotalReviews:   int(totalCount),
-		ApprovedCount:  int(approvedCount),
-		PendingCount:   int(pendingCount),
-		RejectedCount:  int(rejectedCount),
-		FeaturedCount:  int(featuredCount),
-		AverageScore:   0, // 暂不计算平均分
-		TotalViewCount: 0, // 暂不计算总浏览量
-	}, nil
-}
-
-// IncrementViewCount 增加评测浏览次数
-func (s *ServiceImpl) IncrementViewCount(ctx context.Context, reviewID string) error {
+// IncrementViewCount 增加评测浏览次数，爬虫不计数，同一访客在去重窗口内只计一次
+// 计数先累加在Redis中，由定时任务批量写回
+func (s *ServiceImpl) IncrementViewCount(ctx context.Context, reviewID string, visitor viewcount.Visitor) error {
 	// 将评测ID转换为ObjectID
 	id, err := primitive.ObjectIDFromHex(reviewID)
 	if err != nil {
 		return errors.NewBadRequestError("无效的评测ID")
 	}
+	if viewcount.IsBot(visitor.UserAgent, s.viewCfg.BotUserAgents) {
+		return nil
+	}
+	if s.views != nil {
+		if _, err := s.views.Record(ctx, id, visitor); err != nil {
+			return errors.NewInternalServerError("记录浏览失败: " + err.Error())
+		}
+		return nil
+	}
 	
-	// 更新浏览次数
+	// 未启用浏览计数时直接更新浏览次数
 	_, err = s.db.Collection(models.ReviewsCollection).UpdateOne(
 		ctx,
 		bson.M{
 			"_id": id,
 			"deletedAt": nil,
 			"status": bson.M{
-				"$in": []string{string(models.ReviewStatusApproved), string(models.ReviewStatusFeatured)},
+				"$in": models.PublishedReviewStatuses,
 			},
 		},
 		bson.M{
@@ -619,197 +568,6 @@ func (s *ServiceImpl) IncrementViewCount(ctx context.Context, reviewID string) e
 	return nil
 }
 
-// 获取用户的评测列表 - 内部方法
-func (s *ServiceImpl) getReviewsByUserID(ctx context.Context, userID string, page, pageSize int) (*review.ReviewListResponse, error) {
-	// 将用户ID转换为ObjectID
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的用户ID")
-	}
-	
-	// 设置分页
-	if page <= 0 {
-		page = 1
-	}
-	
-	if pageSize <= 0 || pageSize > 100 {
-		pageSize = 20
-	}
-	
-	skip := (page - 1) * pageSize
-	skipInt64 := int64(skip)
-	limitInt64 := int64(pageSize)
-	
-	// 构建查询
-	filter := bson.M{
-		"userId":    userObjectID,
-		"deletedAt": nil,
-	}
-	
-	// 计算总数
-	total, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算评测总数失败: " + err.Error())
-	}
-	
-	// 查询评测列表
-	cursor, err := s.db.Collection(models.ReviewsCollection).Find(ctx, filter, &options.FindOptions{
-		Skip:  &skipInt64,
-		Limit: &limitInt64,
-		Sort:  bson.D{{Key: "createdAt", Value: -1}},
-	})
-	if err != nil {
-		return nil, errors.NewInternalServerError("查询评测列表失败: " + err.Error())
-	}
-	defer cursor.Close(ctx)
-	
-	// 解析评测列表
-	var reviews []models.Review
-	if err = cursor.All(ctx, &reviews); err != nil {
-		return nil, errors.NewInternalServerError("解析评测列表失败: " + err.Error())
-	}
-	
-	// 转换为响应格式
-	response := &review.ReviewListResponse{
-		Total:    int(total),
-		Page:     page,
-		PageSize: pageSize,
-		Reviews:  make([]review.ReviewResponse, len(reviews)),
-	}
-	
-	for i, r := range reviews {
-		response.Reviews[i] = mapReviewToResponse(&r)
-	}
-	
-	return response, nil
-}
-
-// 获取项目的评测列表 - 内部方法
-func (s *ServiceImpl) getReviewsByItemID(ctx context.Context, itemID string, itemType string, page, pageSize int, sortBy string) (*review.ReviewListResponse, error) {
-	// 将项目ID转换为ObjectID
-	itemObjectID, err := primitive.ObjectIDFromHex(itemID)
-	if err != nil {
-		return nil, errors.NewBadRequestError("无效的项目ID")
-	}
-	
-	// 设置分页
-	if page <= 0 {
-		page = 1
-	}
-	
-	if pageSize <= 0 || pageSize > 100 {
-		pageSize = 20
-	}
-	
-	skip := (page - 1) * pageSize
-	skipInt64 := int64(skip)
-	limitInt64 := int64(pageSize)
-	
-	// 构建查询
-	filter := bson.M{
-		"externalItemId": itemObjectID,
-		"itemType":       itemType,
-		"status":         models.ReviewStatusApproved,
-		"deletedAt":      nil,
-	}
-	
-	// 计算总数
-	total, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, errors.NewInternalServerError("计算评测总数失败: " + err.Error())
-	}
-	
-	// 确定排序方式
-	sortOpt := bson.D{{Key: "createdAt", Value: -1}} // 默认按创建时间降序
-	
-	if sortBy == "score" {
-		sortOpt = bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}}
-	} else if sortBy == "views" {
-		sortOpt = bson.D{{Key: "viewCount", Value: -1}, {Key: "createdAt", Value: -1}}
-	}
-	
-	// 查询评测列表
-	cursor, err := s.db.Collection(models.ReviewsCollection).Find(ctx, filter, &options.FindOptions{
-		Skip:  &skipInt64,
-		Limit: &limitInt64,
-		Sort:  sortOpt,
-	})
-	if err != nil {
-		return nil, errors.NewInternalServerError("查询评测列表失败: " + err.Error())
-	}
-	defer cursor.Close(ctx)
-	
-	// 解析评测列表
-	var reviews []models.Review
-	if err = cursor.All(ctx, &reviews); err != nil {
-		return nil, errors.NewInternalServerError("解析评测列表失败: " + err.Error())
-	}
-	
-	// 转换为响应格式
-	response := &review.ReviewListResponse{
-		Total:    int(total),
-		Page:     page,
-		PageSize: pageSize,
-		Reviews:  make([]review.ReviewResponse, len(reviews)),
-	}
-	
-	for i, r := range reviews {
-		response.Reviews[i] = mapReviewToResponse(&r)
-	}
-	
-	return response, nil
-}
-
-// 获取推荐评测 - 内部方法
-func (s *ServiceImpl) getFeaturedReviews(ctx context.Context, itemType string, limit int) (*review.ReviewListResponse, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
-	
-	// 构建查询
-	filter := bson.M{
-		"status":      models.ReviewStatusFeatured,
-		"deletedAt":   nil,
-		"featuredRank": bson.M{"$ne": nil},
-	}
-	
-	if itemType != "" {
-		filter["type"] = itemType
-	}
-	
-	limitInt64 := int64(limit)
-	
-	// 查询评测列表
-	cursor, err := s.db.Collection(models.ReviewsCollection).Find(ctx, filter, &options.FindOptions{
-		Limit: &limitInt64,
-		Sort:  bson.D{{Key: "featuredRank", Value: 1}, {Key: "createdAt", Value: -1}},
-	})
-	if err != nil {
-		return nil, errors.NewInternalServerError("查询推荐评测失败: " + err.Error())
-	}
-	defer cursor.Close(ctx)
-	
-	// 解析评测列表
-	var reviews []models.Review
-	if err = cursor.All(ctx, &reviews); err != nil {
-		return nil, errors.NewInternalServerError("解析推荐评测失败: " + err.Error())
-	}
-	
-	// 转换为响应格式
-	response := &review.ReviewListResponse{
-		Total:    len(reviews),
-		Page:     1,
-		PageSize: limit,
-		Reviews:  make([]review.ReviewResponse, len(reviews)),
-	}
-	
-	for i, r := range reviews {
-		response.Reviews[i] = mapReviewToResponse(&r)
-	}
-	
-	return response, nil
-}
-
 // mapReviewToResponse 将Review模型转换为ReviewResponse
 func mapReviewToResponse(r *models.Review) review.ReviewResponse {
 	response := review.ReviewResponse{
@@ -818,14 +576,24 @@ func mapReviewToResponse(r *models.Review) review.ReviewResponse {
 		ItemType:       r.ItemType,
 		UserID:         r.UserID.Hex(),
 		Content:        r.Content,
+		ContentFormat:  string(r.Format()),
+		ContentHTML:    r.ContentHTML,
+		Excerpt:        r.Excerpt,
 		Pros:           r.Pros,
 		Cons:           r.Cons,
 		Score:          r.Score,
 		Usage:          r.Usage,
+		Images:         MapImagesToResponse(r),
 		Status:         string(r.Status),
 		Type:           string(r.Type),
 		ContentType:    string(r.ContentType),
+		Comparison:     MapComparisonToResponse(r),
 		ViewCount:      r.ViewCount,
+		HelpfulCount:   r.HelpfulCount,
+		UnhelpfulCount: r.UnhelpfulCount,
+		HelpfulScore:   r.HelpfulScore,
+		CommentCount:   r.CommentCount,
+		PendingRevision: r.PendingRevision,
 		CreatedAt:      r.CreatedAt,
 		UpdatedAt:      r.UpdatedAt,
 	}
@@ -838,6 +606,7 @@ func mapReviewToResponse(r *models.Review) review.ReviewResponse {
 	response.ReviewedAt = r.ReviewedAt
 	response.PublishedAt = r.PublishedAt
 	response.FeaturedRank = r.FeaturedRank
+	response.DeletedAt = r.DeletedAt
 	
 	return response
 }
\ No newline at end of file
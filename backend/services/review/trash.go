@@ -0,0 +1,90 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/review"
+)
+
+// ListDeletedReviews 获取回收站中的评测，按删除时间倒序
+func (s *ServiceImpl) ListDeletedReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error) {
+	return s.listReviews(ctx, bson.M{"deletedAt": bson.M{"$ne": nil}}, page, pageSize, bson.D{{Key: "deletedAt", Value: -1}}, mapReviewToResponse)
+}
+
+// RestoreReview 从回收站恢复评测
+func (s *ServiceImpl) RestoreReview(ctx context.Context, reviewID string) error {
+	id, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的评测ID")
+	}
+
+	var restored models.Review
+	err = s.db.Collection(models.ReviewsCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deletedAt": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		},
+	).Decode(&restored)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errors.NewNotFoundError("回收站中没有该评测")
+		}
+		return errors.NewInternalServerError("恢复评测失败: " + err.Error())
+	}
+	s.refreshRating(ctx, &restored)
+	return nil
+}
+
+// PurgeReview 彻底删除回收站中的评测
+// 先删除投票、评论、修改历史和图片，最后删除评测文档；中途失败时评测仍在回收站中，可以再次彻底删除
+func (s *ServiceImpl) PurgeReview(ctx context.Context, reviewID string) error {
+	id, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return errors.NewBadRequestError("无效的评测ID")
+	}
+
+	trashed := bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}}
+	count, err := s.db.Collection(models.ReviewsCollection).CountDocuments(ctx, trashed)
+	if err != nil {
+		return errors.NewInternalServerError("获取评测失败: " + err.Error())
+	}
+	if count == 0 {
+		return errors.NewNotFoundError("回收站中没有该评测")
+	}
+
+	// 评测在回收站中可以恢复，投票、评论、图片和修改历史到彻底删除时才清理
+	related := []struct {
+		collection string
+		name       string
+	}{
+		{models.ReviewVotesCollection, "投票"},
+		{models.ReviewCommentsCollection, "评论"},
+		{models.ReviewRevisionsCollection, "修改历史"},
+	}
+	for _, r := range related {
+		if _, err := s.db.Collection(r.collection).DeleteMany(ctx, bson.M{"reviewId": id}); err != nil {
+			return errors.NewInternalServerError("删除评测" + r.name + "失败: " + err.Error())
+		}
+	}
+	if _, err := s.deleteImages(ctx, bson.M{"reviewId": id}); err != nil {
+		return err
+	}
+
+	// 回收站中的评测不计入评分，彻底删除后无需重新计算
+	res, err := s.db.Collection(models.ReviewsCollection).DeleteOne(ctx, trashed)
+	if err != nil {
+		return errors.NewInternalServerError("彻底删除评测失败: " + err.Error())
+	}
+	if res.DeletedCount == 0 {
+		return errors.NewNotFoundError("回收站中没有该评测")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/tests/testutil"
+)
+
+func TestPurgeReviewDeletesReviewLast(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store := &recordingStorage{}
+	svc := &ServiceImpl{db: db, storage: store}
+
+	now := time.Now()
+	trashed := models.Review{ID: primitive.NewObjectID(), Status: models.ReviewStatusApproved, DeletedAt: &now}
+	_, err := db.Collection(models.ReviewsCollection).InsertOne(ctx, trashed)
+	require.NoError(t, err)
+	_, err = db.Collection(models.ReviewImagesCollection).InsertOne(ctx, models.ReviewImage{
+		ID: primitive.NewObjectID(), ReviewID: &trashed.ID, Key: "a.webp", ThumbnailKey: "a_thumb.webp",
+	})
+	require.NoError(t, err)
+	_, err = db.Collection(models.ReviewVotesCollection).InsertOne(ctx, bson.M{"reviewId": trashed.ID})
+	require.NoError(t, err)
+
+	// 删除图片时评测还在回收站中，中途失败可以再次彻底删除
+	reviewExisted := false
+	store.onDelete = func(key string) {
+		count, err := db.Collection(models.ReviewsCollection).CountDocuments(ctx, bson.M{"_id": trashed.ID})
+		require.NoError(t, err)
+		reviewExisted = count == 1
+	}
+
+	require.NoError(t, svc.PurgeReview(ctx, trashed.ID.Hex()))
+	assert.True(t, reviewExisted)
+	assert.Equal(t, []string{"a.webp", "a_thumb.webp"}, store.deleted)
+	for _, collection := range []string{models.ReviewsCollection, models.ReviewImagesCollection, models.ReviewVotesCollection} {
+		count, err := db.Collection(collection).CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Zero(t, count, collection)
+	}
+
+	err = svc.PurgeReview(ctx, trashed.ID.Hex())
+	assert.Equal(t, errors.NotFound, errors.GetErrorCode(err))
+}
@@ -2,7 +2,10 @@ package review
 
 import (
 	"context"
+	"time"
+
 	"project/backend/models"
+	"project/backend/services/viewcount"
 	"project/backend/types/review"
 )
 
@@ -14,14 +17,41 @@ type Service interface {
 	UpdateReview(ctx context.Context, userID, reviewID string, request review.UpdateReviewRequest) (*models.Review, error)
 	DeleteReview(ctx context.Context, userID, reviewID string) error
 	ListReviews(ctx context.Context, request review.ReviewListRequest) (*review.ReviewListResponse, error)
+	GetFeaturedReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error)
+	GetTrendingReviews(ctx context.Context, reviewType string, limit int) (*review.ReviewListResponse, error)
 	
 	// 评测员操作
 	ApproveReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error)
 	RejectReview(ctx context.Context, reviewerID, reviewID string, notes string) (*models.Review, error)
 	FeaturedReview(ctx context.Context, reviewerID, reviewID string, rank int) (*models.Review, error)
-	GetPendingReviews(ctx context.Context, reviewType string, page, pageSize int) (*review.ReviewListResponse, error)
+	GetPendingReviews(ctx context.Context, request review.PendingReviewListRequest) (*review.ReviewListResponse, error)
+
+	// 修改历史，已发布评测的修改需要审核
+	GetPendingRevisionReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error)
+	ListReviewRevisions(ctx context.Context, reviewID string, page, pageSize int) (*review.ReviewRevisionListResponse, error)
+	DiffReviewRevisions(ctx context.Context, reviewID string, from, to int) (*review.ReviewRevisionDiffResponse, error)
+	ApproveRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error)
+	RejectRevision(ctx context.Context, moderatorID, reviewID string, revision int, notes string) (*models.Review, error)
 	
 	// 统计相关
 	GetUserReviewStats(ctx context.Context, userID string) (*review.UserReviewStats, error)
-	IncrementViewCount(ctx context.Context, reviewID string) error
+	IncrementViewCount(ctx context.Context, reviewID string, visitor viewcount.Visitor) error
+
+	// 投票和评论
+	VoteReview(ctx context.Context, userID, reviewID string, helpful bool) (*review.VoteResponse, error)
+	RemoveVote(ctx context.Context, userID, reviewID string) (*review.VoteResponse, error)
+	CreateComment(ctx context.Context, userID, reviewID string, request review.CreateCommentRequest) (*models.ReviewComment, error)
+	ListComments(ctx context.Context, reviewID string, page, pageSize int) (*review.CommentListResponse, error)
+	DeleteComment(ctx context.Context, userID, commentID string) error
+	HideComment(ctx context.Context, moderatorID, commentID, note string) error
+	RestoreComment(ctx context.Context, moderatorID, commentID string) error
+
+	// 评测图片
+	UploadReviewImage(ctx context.Context, userID string, content []byte) (*models.ReviewImage, error)
+	CleanupOrphanImages(ctx context.Context, before time.Time) (int, error)
+
+	// 回收站相关
+	ListDeletedReviews(ctx context.Context, page, pageSize int) (*review.ReviewListResponse, error)
+	RestoreReview(ctx context.Context, reviewID string) error
+	PurgeReview(ctx context.Context, reviewID string) error
 }
\ No newline at end of file
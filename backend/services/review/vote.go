@@ -0,0 +1,156 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/services/rating"
+	"project/backend/types/review"
+)
+
+// VoteReview 投票评测是否有帮助，重复投票时修改原有的票
+func (s *ServiceImpl) VoteReview(ctx context.Context, userID, reviewID string, helpful bool) (*review.VoteResponse, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	target, err := s.publishedReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if target.UserID == userObjectID {
+		return nil, errors.NewBadRequestError("不能给自己的评测投票")
+	}
+
+	now := time.Now()
+	var previous models.ReviewVote
+	for attempt := 0; ; attempt++ {
+		err = s.db.Collection(models.ReviewVotesCollection).FindOneAndUpdate(ctx,
+			bson.M{"reviewId": target.ID, "userId": userObjectID},
+			bson.M{
+				"$set":         bson.M{"helpful": helpful, "updatedAt": now},
+				"$setOnInsert": bson.M{"createdAt": now},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+		).Decode(&previous)
+		// 同一用户并发首次投票时唯一索引冲突，重试一次即可命中已插入的票
+		if mongo.IsDuplicateKeyError(err) && attempt == 0 {
+			continue
+		}
+		break
+	}
+
+	inc := bson.M{}
+	switch {
+	case err == mongo.ErrNoDocuments:
+		inc[voteCounter(helpful)] = 1
+	case err != nil:
+		return nil, errors.NewInternalServerError("投票失败: " + err.Error())
+	case previous.Helpful != helpful:
+		inc[voteCounter(helpful)] = 1
+		inc[voteCounter(!helpful)] = -1
+	}
+
+	return s.applyVoteDelta(ctx, target, inc, &helpful)
+}
+
+// RemoveVote 撤销投票
+func (s *ServiceImpl) RemoveVote(ctx context.Context, userID, reviewID string) (*review.VoteResponse, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	target, err := s.publishedReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous models.ReviewVote
+	err = s.db.Collection(models.ReviewVotesCollection).FindOneAndDelete(ctx,
+		bson.M{"reviewId": target.ID, "userId": userObjectID},
+	).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("尚未对该评测投票")
+		}
+		return nil, errors.NewInternalServerError("撤销投票失败: " + err.Error())
+	}
+
+	return s.applyVoteDelta(ctx, target, bson.M{voteCounter(previous.Helpful): -1}, nil)
+}
+
+// applyVoteDelta 更新评测的票数并重新计算Wilson得分
+func (s *ServiceImpl) applyVoteDelta(ctx context.Context, target *models.Review, inc bson.M, vote *bool) (*review.VoteResponse, error) {
+	collection := s.db.Collection(models.ReviewsCollection)
+	updated := *target
+	if len(inc) > 0 {
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": target.ID},
+			bson.M{"$inc": inc},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err != nil {
+			return nil, errors.NewInternalServerError("更新票数失败: " + err.Error())
+		}
+	}
+
+	score := rating.Wilson(updated.HelpfulCount, updated.UnhelpfulCount)
+	if score != updated.HelpfulScore {
+		// 只在票数未被并发修改时写入，否则由后一次投票写入最新的得分
+		_, err := collection.UpdateOne(ctx,
+			bson.M{
+				"_id":            updated.ID,
+				"helpfulCount":   updated.HelpfulCount,
+				"unhelpfulCount": updated.UnhelpfulCount,
+			},
+			bson.M{"$set": bson.M{"helpfulScore": score}},
+		)
+		if err != nil {
+			return nil, errors.NewInternalServerError("更新评测得分失败: " + err.Error())
+		}
+	}
+
+	return &review.VoteResponse{
+		ReviewID:       updated.ID.Hex(),
+		Helpful:        vote,
+		HelpfulCount:   updated.HelpfulCount,
+		UnhelpfulCount: updated.UnhelpfulCount,
+		HelpfulScore:   score,
+	}, nil
+}
+
+// publishedReview 获取已发布的评测，投票和评论只对已发布的评测开放
+func (s *ServiceImpl) publishedReview(ctx context.Context, reviewID string) (*models.Review, error) {
+	id, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的评测ID")
+	}
+
+	var target models.Review
+	err = s.db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{
+		"_id":       id,
+		"status":    bson.M{"$in": models.PublishedReviewStatuses},
+		"deletedAt": nil,
+	}).Decode(&target)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("评测不存在")
+		}
+		return nil, errors.NewInternalServerError("获取评测失败: " + err.Error())
+	}
+	return &target, nil
+}
+
+func voteCounter(helpful bool) string {
+	if helpful {
+		return "helpfulCount"
+	}
+	return "unhelpfulCount"
+}
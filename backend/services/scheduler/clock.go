@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，测试中可以替换为FakeClock
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock 使用系统时间
+type RealClock struct{}
+
+// Now 返回当前时间
+func (RealClock) Now() time.Time { return time.Now() }
+
+// After 等待d后返回
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// FakeClock 手动推进的时钟，用于测试定时任务
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock 创建从now开始的时钟
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now 返回时钟的当前时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After 时钟推进到now+d后返回
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance 推进时钟并唤醒到期的等待者
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.deadline.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		pending = append(pending, w)
+	}
+	c.waiters = pending
+}
+
+// BlockUntil 阻塞直到有n个等待者，用于确认任务已执行完本轮并进入等待
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LocksCollection 任务租约集合
+const LocksCollection = "scheduler_locks"
+
+// Job 定时任务，每隔Interval执行一次
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// Run 执行任务，now为调度时钟的当前时间
+	Run func(ctx context.Context, now time.Time) error
+}
+
+// Locker 任务租约，多实例部署时保证同一任务同一时间只有一个实例执行
+type Locker interface {
+	// Acquire 获取或续期租约，租约被其他实例持有且未过期时返回false
+	Acquire(ctx context.Context, name, owner string, now time.Time, ttl time.Duration) (bool, error)
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	clock  Clock
+	locker Locker
+	owner  string
+	jobs   []Job
+}
+
+// New 创建调度器，locker为nil时不做选主，适用于单实例部署
+func New(clock Clock, locker Locker) *Scheduler {
+	if clock == nil {
+		clock = RealClock{}
+	}
+	host, _ := os.Hostname()
+	return &Scheduler{
+		clock:  clock,
+		locker: locker,
+		owner:  fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex()),
+	}
+}
+
+// Register 注册任务，需在Start之前调用
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start 启动所有任务并阻塞，ctx取消时等待正在执行的任务结束后返回
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// loop 启动时立即执行一次，之后每隔Interval执行
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		s.RunOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(job.Interval):
+		}
+	}
+}
+
+// RunOnce 获取租约后执行一次任务，任务出错或panic只记录日志
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("定时任务%s异常: %v", job.Name, r)
+		}
+	}()
+
+	now := s.clock.Now()
+	if s.locker != nil {
+		// 租约为两个执行周期，持有者每次执行时续期，实例退出后其他实例最多等待两个周期接管
+		ok, err := s.locker.Acquire(ctx, job.Name, s.owner, now, 2*job.Interval)
+		if err != nil {
+			log.Printf("定时任务%s获取租约失败: %v", job.Name, err)
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+
+	if err := job.Run(ctx, now); err != nil {
+		log.Printf("定时任务%s执行失败: %v", job.Name, err)
+	}
+}
+
+// MongoLocker 基于Mongo文档的租约，_id为任务名
+type MongoLocker struct {
+	db *mongo.Database
+}
+
+// NewMongoLocker 创建Mongo租约
+func NewMongoLocker(db *mongo.Database) *MongoLocker {
+	return &MongoLocker{db: db}
+}
+
+// Acquire 租约由自己持有或已过期时更新持有者，否则插入同名文档会触发主键冲突
+func (l *MongoLocker) Acquire(ctx context.Context, name, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	_, err := l.db.Collection(LocksCollection).UpdateOne(ctx,
+		bson.M{
+			"_id": name,
+			"$or": []bson.M{
+				{"owner": owner},
+				{"expiresAt": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"$set": bson.M{
+			"owner":     owner,
+			"expiresAt": now.Add(ttl),
+			"updatedAt": now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
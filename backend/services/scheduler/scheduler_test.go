@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubLocker 只允许指定的持有者获取租约
+type stubLocker struct {
+	mu     sync.Mutex
+	holder string
+	calls  int
+}
+
+func (l *stubLocker) Acquire(ctx context.Context, name, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	if l.holder == "" {
+		l.holder = owner
+	}
+	return l.holder == owner, nil
+}
+
+func TestSchedulerRunsJobOnEachInterval(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := New(clock, nil)
+
+	var (
+		mu   sync.Mutex
+		runs []time.Time
+	)
+	s.Register(Job{
+		Name:     "test",
+		Interval: time.Minute,
+		Run: func(ctx context.Context, now time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs = append(runs, now)
+			return errors.New("任务出错不影响下一轮")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+
+	// 启动时立即执行一次
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []time.Time{start, start.Add(time.Minute)}, runs)
+}
+
+func TestSchedulerSkipsJobWithoutLease(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	locker := &stubLocker{}
+	leader := New(clock, locker)
+	follower := New(clock, locker)
+
+	runs := map[*Scheduler]int{}
+	for _, s := range []*Scheduler{leader, follower} {
+		s := s
+		job := Job{Name: "test", Interval: time.Minute, Run: func(ctx context.Context, now time.Time) error {
+			runs[s]++
+			return nil
+		}}
+		s.RunOnce(context.Background(), job)
+		s.RunOnce(context.Background(), job)
+	}
+
+	assert.Equal(t, 2, runs[leader])
+	assert.Equal(t, 0, runs[follower])
+	assert.Equal(t, 4, locker.calls)
+}
+
+func TestSchedulerRecoversFromPanic(t *testing.T) {
+	s := New(NewFakeClock(time.Now()), nil)
+	assert.NotPanics(t, func() {
+		s.RunOnce(context.Background(), Job{Name: "panic", Interval: time.Minute, Run: func(ctx context.Context, now time.Time) error {
+			panic("boom")
+		}})
+	})
+}
@@ -339,9 +339,30 @@ func calculateShapeSimilarity(mice []models.MouseDevice) float64 {
 		if baseShape.HandCompatibility == currentShape.HandCompatibility {
 			score += 1.0
 		}
+		// 拇指托、无名指托属于少数鼠标才有的特征，任一方具备时才计分
+		maxScore := 5.0
+		if baseShape.ThumbRest || currentShape.ThumbRest {
+			maxScore++
+			if baseShape.ThumbRest == currentShape.ThumbRest {
+				score += 1.0
+			}
+		}
+		if baseShape.RingFingerRest || currentShape.RingFingerRest {
+			maxScore++
+			if baseShape.RingFingerRest == currentShape.RingFingerRest {
+				score += 1.0
+			}
+		}
+
+		// 材质在旧数据中可能缺失，双方都已知时才计分
+		if baseShape.Material != "" && currentShape.Material != "" {
+			maxScore++
+			if baseShape.Material == currentShape.Material {
+				score += 1.0
+			}
+		}
 
-		// 一个鼠标最多5分(所有属性都匹配)
-		totalMatches += (score / 5.0)
+		totalMatches += (score / maxScore)
 	}
 
 	// 计算总分数 (满分是每个鼠标都与第一个完全匹配)
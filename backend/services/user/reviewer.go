@@ -0,0 +1,299 @@
+package user
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/types/user"
+)
+
+const (
+	// ReviewerMinAccountAge 申请评测员要求的最短注册时长
+	ReviewerMinAccountAge = 7 * 24 * time.Hour
+	// ReviewerReapplyCooldown 申请被拒绝后再次申请的间隔
+	ReviewerReapplyCooldown = 30 * 24 * time.Hour
+)
+
+// Notifier 评测员申请审核结果通知
+type Notifier interface {
+	SendReviewerApprovedEmail(to, username string) error
+	SendReviewerRejectedEmail(to, username, reason string) error
+}
+
+// ApplyForReviewer 申请成为评测员，每个用户同时只能有一个待审核的申请
+func (s *ServiceImpl) ApplyForReviewer(ctx context.Context, userID string, application user.ReviewerApplicationRequest) error {
+	u, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := checkReviewerEligibility(u, now); err != nil {
+		return err
+	}
+
+	// 条件中排除待审核的申请，避免并发提交时覆盖
+	res, err := s.db.Collection(models.UsersCollection).UpdateOne(ctx,
+		bson.M{
+			"_id":                        u.ID,
+			"reviewerApplication.status": bson.M{"$ne": models.ApplicationStatusPending},
+		},
+		bson.M{"$set": bson.M{
+			"reviewerApplication": models.ReviewerApplication{
+				Status:         models.ApplicationStatusPending,
+				AppliedAt:      now,
+				UpdatedAt:      now,
+				Experience:     strings.TrimSpace(application.Experience),
+				ExpertiseAreas: normalizeList(application.ExpertiseAreas),
+				Samples:        normalizeList(application.Samples),
+				Motivation:     strings.TrimSpace(application.Motivation),
+			},
+			"updatedAt": now,
+		}},
+	)
+	if err != nil {
+		return errors.NewInternalServerError("提交评测员申请失败: " + err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return errors.NewAppError(errors.Conflict, "已有待审核的评测员申请")
+	}
+	return nil
+}
+
+// checkReviewerEligibility 检查申请资格：普通用户、邮箱已验证、注册满一定时长，被拒绝后需等待冷却期
+func checkReviewerEligibility(u *models.User, now time.Time) error {
+	if !isPlainUser(u) {
+		return errors.NewBadRequestError("当前角色无需申请评测员")
+	}
+	if !u.Status.EmailVerified && !u.IsVerified {
+		return errors.NewForbiddenError("请先验证邮箱后再申请评测员")
+	}
+
+	registeredAt := u.CreatedAt
+	if registeredAt.IsZero() {
+		registeredAt = u.Stats.CreatedAt
+	}
+	if now.Sub(registeredAt) < ReviewerMinAccountAge {
+		return errors.NewForbiddenError("注册满7天后才能申请评测员")
+	}
+
+	if application := u.ReviewerApplication; application != nil {
+		switch application.Status {
+		case models.ApplicationStatusPending:
+			return errors.NewAppError(errors.Conflict, "已有待审核的评测员申请")
+		case models.ApplicationStatusRejected:
+			if now.Sub(application.UpdatedAt) < ReviewerReapplyCooldown {
+				return errors.NewForbiddenError("申请被拒绝后需等待30天才能再次申请")
+			}
+		}
+	}
+	return nil
+}
+
+// isPlainUser 是否为普通用户，旧数据没有角色时按普通用户处理
+func isPlainUser(u *models.User) bool {
+	return u.Role.Type == "" || u.Role.Type == string(models.RoleUser)
+}
+
+// GetReviewerApplications 按状态分页查询评测员申请，status为空时返回全部，按申请时间先后排序
+func (s *ServiceImpl) GetReviewerApplications(ctx context.Context, status string, page, pageSize int) (*user.ReviewerApplicationListResponse, int64, error) {
+	filter := bson.M{"reviewerApplication": bson.M{"$exists": true}}
+	switch status {
+	case "":
+	case models.ApplicationStatusPending, models.ApplicationStatusApproved, models.ApplicationStatusRejected:
+		filter = bson.M{"reviewerApplication.status": status}
+	default:
+		return nil, 0, errors.NewBadRequestError("无效的申请状态")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collection := s.db.Collection(models.UsersCollection)
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取评测员申请失败: " + err.Error())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "reviewerApplication.appliedAt", Value: 1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetProjection(bson.M{"username": 1, "email": 1, "reviewerApplication": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("获取评测员申请失败: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, 0, errors.NewInternalServerError("解析评测员申请失败: " + err.Error())
+	}
+
+	applications := make([]user.ReviewerApplicationDetails, 0, len(users))
+	for _, u := range users {
+		if u.ReviewerApplication == nil {
+			continue
+		}
+		application := u.ReviewerApplication
+		applications = append(applications, user.ReviewerApplicationDetails{
+			UserID:         u.ID.Hex(),
+			Username:       u.Username,
+			Email:          u.Email,
+			DisplayName:    u.Username,
+			Experience:     application.Experience,
+			ExpertiseAreas: application.ExpertiseAreas,
+			Samples:        application.Samples,
+			Motivation:     application.Motivation,
+			Status:         application.Status,
+			ReviewNotes:    application.ReviewNotes,
+			AppliedAt:      application.AppliedAt,
+			UpdatedAt:      application.UpdatedAt,
+		})
+	}
+
+	return &user.ReviewerApplicationListResponse{
+		Applications: applications,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+	}, total, nil
+}
+
+// ApproveReviewerApplication 批准申请并将普通用户提升为评测员，申请后角色已变更的用户不能批准，避免降低管理员等角色
+// 新角色在用户重新登录后生效
+func (s *ServiceImpl) ApproveReviewerApplication(ctx context.Context, userID string, adminID string) error {
+	applicant, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !isPlainUser(applicant) {
+		return errors.NewBadRequestError("用户当前角色不是普通用户，不能提升为评测员")
+	}
+
+	// 条件中再次限定角色，避免审核期间角色被修改
+	u, err := s.reviewPendingApplication(ctx, userID, adminID,
+		bson.M{"role.type": bson.M{"$in": bson.A{models.RoleUser, "", nil}}},
+		bson.M{
+			"role.type":                       models.RoleReviewer,
+			"reviewerApplication.status":      models.ApplicationStatusApproved,
+			"reviewerApplication.reviewNotes": "",
+		})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		if err := s.notifier.SendReviewerApprovedEmail(u.Email, u.Username); err != nil {
+			log.Printf("发送评测员申请通过通知失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// RejectReviewerApplication 拒绝申请，拒绝原因会发送给申请人
+func (s *ServiceImpl) RejectReviewerApplication(ctx context.Context, userID string, adminID string, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.NewBadRequestError("请填写拒绝原因")
+	}
+
+	u, err := s.reviewPendingApplication(ctx, userID, adminID, nil, bson.M{
+		"reviewerApplication.status":      models.ApplicationStatusRejected,
+		"reviewerApplication.reviewNotes": reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifier != nil {
+		if err := s.notifier.SendReviewerRejectedEmail(u.Email, u.Username, reason); err != nil {
+			log.Printf("发送评测员申请拒绝通知失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// reviewPendingApplication 审核待处理的申请，返回审核后的用户，filter为额外的查询条件
+func (s *ServiceImpl) reviewPendingApplication(ctx context.Context, userID, adminID string, filter, set bson.M) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的用户ID")
+	}
+	reviewerID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("无效的管理员ID")
+	}
+
+	now := time.Now()
+	set["reviewerApplication.reviewedBy"] = reviewerID
+	set["reviewerApplication.updatedAt"] = now
+	set["updatedAt"] = now
+
+	query := bson.M{"_id": objID, "reviewerApplication.status": models.ApplicationStatusPending}
+	for key, value := range filter {
+		query[key] = value
+	}
+
+	var updated models.User
+	err = s.db.Collection(models.UsersCollection).FindOneAndUpdate(ctx,
+		query,
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.NewNotFoundError("没有待审核的评测员申请")
+		}
+		return nil, errors.NewInternalServerError("审核评测员申请失败: " + err.Error())
+	}
+	return &updated, nil
+}
+
+// normalizeList 去除空白和重复项
+func normalizeList(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+// ApplyForReviewer 空实现，数据库不可用时返回错误
+func (s *DefaultService) ApplyForReviewer(ctx context.Context, userID string, application user.ReviewerApplicationRequest) error {
+	return errors.NewInternalServerError("用户服务暂不可用")
+}
+
+// GetReviewerApplications 空实现，数据库不可用时返回错误
+func (s *DefaultService) GetReviewerApplications(ctx context.Context, status string, page, pageSize int) (*user.ReviewerApplicationListResponse, int64, error) {
+	return nil, 0, errors.NewInternalServerError("用户服务暂不可用")
+}
+
+// ApproveReviewerApplication 空实现，数据库不可用时返回错误
+func (s *DefaultService) ApproveReviewerApplication(ctx context.Context, userID string, adminID string) error {
+	return errors.NewInternalServerError("用户服务暂不可用")
+}
+
+// RejectReviewerApplication 空实现，数据库不可用时返回错误
+func (s *DefaultService) RejectReviewerApplication(ctx context.Context, userID string, adminID string, reason string) error {
+	return errors.NewInternalServerError("用户服务暂不可用")
+}
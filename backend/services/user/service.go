@@ -4,6 +4,7 @@ import (
 	"context"
 	"project/backend/internal/errors"
 	"project/backend/models"
+	"project/backend/types/user"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,11 +18,19 @@ type Service interface {
 	UpdateLastLogin(ctx context.Context, userID string) error
 	GetUserProfile(ctx context.Context, userID string) (*models.User, error)
 	UpdateUserProfile(ctx context.Context, userID string, user *models.User) error
+
+	// 评测员申请
+	ApplyForReviewer(ctx context.Context, userID string, application user.ReviewerApplicationRequest) error
+	GetReviewerApplications(ctx context.Context, status string, page, pageSize int) (*user.ReviewerApplicationListResponse, int64, error)
+	ApproveReviewerApplication(ctx context.Context, userID string, adminID string) error
+	RejectReviewerApplication(ctx context.Context, userID string, adminID string, reason string) error
 }
 
 // ServiceImpl 用户服务实现
 type ServiceImpl struct {
 	db *mongo.Database
+	// 评测员申请审核结果通知，可以为nil
+	notifier Notifier
 }
 
 // DefaultService 默认用户服务实现
@@ -29,10 +38,11 @@ type DefaultService struct {
 	db *mongo.Database
 }
 
-// NewService 创建新的用户服务
-func NewService(db *mongo.Database) Service {
+// NewService 创建新的用户服务，notifier为nil时不发送通知
+func NewService(db *mongo.Database, notifier Notifier) Service {
 	return &ServiceImpl{
-		db: db,
+		db:       db,
+		notifier: notifier,
 	}
 }
 
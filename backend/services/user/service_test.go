@@ -1,990 +1,254 @@
 package user
 
 import (
-	"project/backend/internal/errors"
-	"project/backend/types/user"
 	"context"
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
-	"github.com/stretchr/testify/require"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"testing"
 	"time"
-)
 
-// 创建一个模拟服务
-type mockUserService struct {
-	mock.Mock
-}
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
-// 实现Service接口的方法
-func (m *mockUserService) GetUserProfile(ctx context.Context, userID string) (*user.ProfileResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.ProfileResponse), args.Error(1)
-}
+	"project/backend/internal/errors"
+	"project/backend/models"
+	"project/backend/tests/testutil"
+	"project/backend/types/user"
+)
 
-func (m *mockUserService) UpdateUserProfile(ctx context.Context, userID string, profileData user.UpdateProfileRequest) (*user.ProfileResponse, error) {
-	args := m.Called(ctx, userID, profileData)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.ProfileResponse), args.Error(1)
+// recordingNotifier 记录发送的审核结果通知
+type recordingNotifier struct {
+	approved []string
+	rejected map[string]string
 }
 
-func (m *mockUserService) GetUserSettings(ctx context.Context, userID string) (*user.SettingsResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.SettingsResponse), args.Error(1)
+func (n *recordingNotifier) SendReviewerApprovedEmail(to, username string) error {
+	n.approved = append(n.approved, to)
+	return nil
 }
 
-func (m *mockUserService) UpdateUserSettings(ctx context.Context, userID string, settingsData user.UpdateSettingsRequest) (*user.SettingsResponse, error) {
-	args := m.Called(ctx, userID, settingsData)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (n *recordingNotifier) SendReviewerRejectedEmail(to, username, reason string) error {
+	if n.rejected == nil {
+		n.rejected = map[string]string{}
 	}
-	return args.Get(0).(*user.SettingsResponse), args.Error(1)
+	n.rejected[to] = reason
+	return nil
 }
 
-func (m *mockUserService) GetGameSettings(ctx context.Context, userID string) (*user.GameSettingsResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.GameSettingsResponse), args.Error(1)
+// eligibleUser 满足评测员申请条件的普通用户
+func eligibleUser(now time.Time) *models.User {
+	u := models.NewUser("reviewer_candidate", "candidate@example.com", "hashed")
+	u.Status.EmailVerified = true
+	u.CreatedAt = now.Add(-ReviewerMinAccountAge - time.Hour)
+	return u
 }
 
-func (m *mockUserService) UpdateGameSettings(ctx context.Context, userID string, settings user.UpdateGameSettingsRequest) (*user.GameSettingsResponse, error) {
-	args := m.Called(ctx, userID, settings)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.GameSettingsResponse), args.Error(1)
-}
+func TestCheckReviewerEligibility(t *testing.T) {
+	now := time.Now()
 
-func (m *mockUserService) AddSensitivityConfig(ctx context.Context, userID string, config user.AddSensitivityConfigRequest) (*user.GameSettingsResponse, error) {
-	args := m.Called(ctx, userID, config)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.GameSettingsResponse), args.Error(1)
+	tests := []struct {
+		name   string
+		modify func(u *models.User)
+		code   int
+	}{
+		{"普通用户可以申请", func(u *models.User) {}, errors.Success},
+		{"旧数据没有角色按普通用户处理", func(u *models.User) { u.Role.Type = "" }, errors.Success},
+		{"管理员无需申请", func(u *models.User) { u.Role.Type = string(models.RoleAdmin) }, errors.BadRequest},
+		{"评测员无需申请", func(u *models.User) { u.Role.Type = string(models.RoleReviewer) }, errors.BadRequest},
+		{"邮箱未验证", func(u *models.User) { u.Status.EmailVerified = false }, errors.Forbidden},
+		{"旧数据以isVerified标记已验证", func(u *models.User) {
+			u.Status.EmailVerified = false
+			u.IsVerified = true
+		}, errors.Success},
+		{"注册不满7天", func(u *models.User) { u.CreatedAt = now.Add(-time.Hour) }, errors.Forbidden},
+		{"已有待审核的申请", func(u *models.User) {
+			u.ReviewerApplication = &models.ReviewerApplication{Status: models.ApplicationStatusPending}
+		}, errors.Conflict},
+		{"被拒绝后冷却期内", func(u *models.User) {
+			u.ReviewerApplication = &models.ReviewerApplication{Status: models.ApplicationStatusRejected, UpdatedAt: now.Add(-24 * time.Hour)}
+		}, errors.Forbidden},
+		{"被拒绝后冷却期已过", func(u *models.User) {
+			u.ReviewerApplication = &models.ReviewerApplication{Status: models.ApplicationStatusRejected, UpdatedAt: now.Add(-ReviewerReapplyCooldown - time.Hour)}
+		}, errors.Success},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := eligibleUser(now)
+			tt.modify(u)
+			assert.Equal(t, tt.code, errors.GetErrorCode(checkReviewerEligibility(u, now)))
+		})
+	}
+}
+
+// ReviewerApplicationSuite 评测员申请测试套件，使用测试数据库，MongoDB不可用时跳过
+type ReviewerApplicationSuite struct {
+	suite.Suite
+	db       *mongo.Database
+	cleanup  func()
+	svc      Service
+	notifier *recordingNotifier
+	ctx      context.Context
+
+	adminID primitive.ObjectID
+}
+
+func (s *ReviewerApplicationSuite) SetupTest() {
+	s.db, s.cleanup = testutil.SetupTransactionTest(s.T())
+	s.ctx = context.Background()
+	s.notifier = &recordingNotifier{}
+	s.svc = NewService(s.db, s.notifier)
+	s.adminID = primitive.NewObjectID()
+}
+
+func (s *ReviewerApplicationSuite) TearDownTest() {
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+// insertUser 写入一个满足申请条件的用户
+func (s *ReviewerApplicationSuite) insertUser(modify func(u *models.User)) *models.User {
+	u := eligibleUser(time.Now())
+	u.Email = u.ID.Hex() + "@example.com"
+	if modify != nil {
+		modify(u)
+	}
+	_, err := s.db.Collection(models.UsersCollection).InsertOne(s.ctx, u)
+	s.Require().NoError(err)
+	return u
 }
 
-func (m *mockUserService) UpdateSensitivityConfig(ctx context.Context, userID string, game string, config user.UpdateSensitivityConfigRequest) (*user.GameSettingsResponse, error) {
-	args := m.Called(ctx, userID, game, config)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.GameSettingsResponse), args.Error(1)
+// apply 以默认内容提交申请
+func (s *ReviewerApplicationSuite) apply(u *models.User) error {
+	return s.svc.ApplyForReviewer(s.ctx, u.ID.Hex(), user.ReviewerApplicationRequest{
+		Experience:     "  三年外设评测经验  ",
+		ExpertiseAreas: []string{"鼠标", " 键盘 ", "鼠标", ""},
+		Samples:        []string{"https://example.com/review/1"},
+		Motivation:     "分享使用体验",
+	})
 }
 
-func (m *mockUserService) DeleteSensitivityConfig(ctx context.Context, userID string, game string) error {
-	args := m.Called(ctx, userID, game)
-	return args.Error(0)
-}
+func (s *ReviewerApplicationSuite) reload(u *models.User) *models.User {
+	reloaded, err := s.svc.GetUserByID(s.ctx, u.ID.Hex())
+	s.Require().NoError(err)
+	return reloaded
+}
+
+// TestApplyForReviewer 测试提交申请
+func (s *ReviewerApplicationSuite) TestApplyForReviewer() {
+	u := s.insertUser(nil)
+	s.Require().NoError(s.apply(u))
+
+	application := s.reload(u).ReviewerApplication
+	s.Require().NotNil(application)
+	assert.Equal(s.T(), models.ApplicationStatusPending, application.Status)
+	assert.Equal(s.T(), "三年外设评测经验", application.Experience)
+	assert.Equal(s.T(), []string{"鼠标", "键盘"}, application.ExpertiseAreas)
+
+	// 同时只能有一个待审核的申请
+	assert.Equal(s.T(), errors.Conflict, errors.GetErrorCode(s.apply(u)))
+}
 
-func (m *mockUserService) GetPrivacySettings(ctx context.Context, userID string) (*user.PrivacySettingsResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.PrivacySettingsResponse), args.Error(1)
-}
+// TestApplyForReviewerNotEligible 测试不满足条件时不能申请
+func (s *ReviewerApplicationSuite) TestApplyForReviewerNotEligible() {
+	admin := s.insertUser(func(u *models.User) { u.Role.Type = string(models.RoleAdmin) })
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(s.apply(admin)))
 
-func (m *mockUserService) UpdatePrivacySettings(ctx context.Context, userID string, settings user.UpdatePrivacySettingsRequest) (*user.PrivacySettingsResponse, error) {
-	args := m.Called(ctx, userID, settings)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.PrivacySettingsResponse), args.Error(1)
+	unverified := s.insertUser(func(u *models.User) { u.Status.EmailVerified = false })
+	assert.Equal(s.T(), errors.Forbidden, errors.GetErrorCode(s.apply(unverified)))
+	assert.Nil(s.T(), s.reload(unverified).ReviewerApplication)
 }
 
-func (m *mockUserService) GetDevicePreferences(ctx context.Context, userID string) ([]user.DevicePreferenceResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]user.DevicePreferenceResponse), args.Error(1)
-}
+// TestApproveReviewerApplication 测试批准申请后提升为评测员并发送通知
+func (s *ReviewerApplicationSuite) TestApproveReviewerApplication() {
+	u := s.insertUser(nil)
+	s.Require().NoError(s.apply(u))
 
-func (m *mockUserService) AddDevicePreference(ctx context.Context, userID string, pref user.AddDevicePreferenceRequest) (*user.DevicePreferenceResponse, error) {
-	args := m.Called(ctx, userID, pref)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.DevicePreferenceResponse), args.Error(1)
-}
+	s.Require().NoError(s.svc.ApproveReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex()))
 
-func (m *mockUserService) UpdateDevicePreference(ctx context.Context, userID string, devicePrefID string, pref user.UpdateDevicePreferenceRequest) (*user.DevicePreferenceResponse, error) {
-	args := m.Called(ctx, userID, devicePrefID, pref)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.DevicePreferenceResponse), args.Error(1)
-}
+	approved := s.reload(u)
+	assert.Equal(s.T(), string(models.RoleReviewer), approved.Role.Type)
+	assert.Equal(s.T(), models.ApplicationStatusApproved, approved.ReviewerApplication.Status)
+	assert.Equal(s.T(), s.adminID, approved.ReviewerApplication.ReviewedBy)
+	assert.Equal(s.T(), []string{u.Email}, s.notifier.approved)
 
-func (m *mockUserService) DeleteDevicePreference(ctx context.Context, userID string, devicePrefID string) error {
-	args := m.Called(ctx, userID, devicePrefID)
-	return args.Error(0)
+	// 已审核的申请不能再次审核
+	err := s.svc.ApproveReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex())
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(err))
+	err = s.svc.RejectReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex(), "重复审核")
+	assert.Equal(s.T(), errors.NotFound, errors.GetErrorCode(err))
 }
 
-func (m *mockUserService) GetUserPreferences(ctx context.Context, userID string) (*user.PreferencesResponse, error) {
-	args := m.Called(ctx, userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.PreferencesResponse), args.Error(1)
-}
+// TestApproveReviewerApplicationKeepsRole 测试申请后角色已变更的用户不会被降为评测员
+func (s *ReviewerApplicationSuite) TestApproveReviewerApplicationKeepsRole() {
+	u := s.insertUser(func(u *models.User) {
+		u.Role.Type = string(models.RoleAdmin)
+		u.ReviewerApplication = &models.ReviewerApplication{Status: models.ApplicationStatusPending, AppliedAt: time.Now()}
+	})
 
-func (m *mockUserService) UpdateUserPreferences(ctx context.Context, userID string, preferencesData user.UpdatePreferencesRequest) (*user.PreferencesResponse, error) {
-	args := m.Called(ctx, userID, preferencesData)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*user.PreferencesResponse), args.Error(1)
-}
+	err := s.svc.ApproveReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex())
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(err))
 
-func (m *mockUserService) ApplyForReviewer(ctx context.Context, userID string, application user.ReviewerApplicationRequest) error {
-	args := m.Called(ctx, userID, application)
-	return args.Error(0)
+	unchanged := s.reload(u)
+	assert.Equal(s.T(), string(models.RoleAdmin), unchanged.Role.Type)
+	assert.Equal(s.T(), models.ApplicationStatusPending, unchanged.ReviewerApplication.Status)
+	assert.Empty(s.T(), s.notifier.approved)
 }
 
-func (m *mockUserService) GetReviewerApplications(ctx context.Context, status string, page, pageSize int) (*user.ReviewerApplicationListResponse, int64, error) {
-	args := m.Called(ctx, status, page, pageSize)
-	if args.Get(0) == nil {
-		return nil, args.Get(1).(int64), args.Error(2)
-	}
-	return args.Get(0).(*user.ReviewerApplicationListResponse), args.Get(1).(int64), args.Error(2)
-}
+// TestApproveReviewerApplicationWithoutPending 测试没有待审核申请时不能批准
+func (s *ReviewerApplicationSuite) TestApproveReviewerApplicationWithoutPending() {
+	u := s.insertUser(nil)
 
-func (m *mockUserService) ApproveReviewerApplication(ctx context.Context, userID string, adminID string) error {
-	args := m.Called(ctx, userID, adminID)
-	return args.Error(0)
-}
+	err := s.svc.ApproveReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex())
+	assert.Equal(s.T(), errors.NotFound, errors.GetErrorCode(err))
+	assert.Equal(s.T(), string(models.RoleUser), s.reload(u).Role.Type)
 
-func (m *mockUserService) RejectReviewerApplication(ctx context.Context, userID string, adminID string, reason string) error {
-	args := m.Called(ctx, userID, adminID, reason)
-	return args.Error(0)
+	err = s.svc.ApproveReviewerApplication(s.ctx, "invalid-id", s.adminID.Hex())
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(err))
 }
 
-// TestGetUserProfile_Success 测试正常获取用户档案
-func TestGetUserProfile_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建期望的返回值
-	expectedProfile := &user.ProfileResponse{
-		ID:          userID,
-		Username:    "testuser",
-		Email:       "test@example.com",
-		DisplayName: "Test User",
-		Bio:         "This is a test user",
-		Verified:    true,
-		JoinedAt:    time.Now(),
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("GetUserProfile", ctx, userID).Return(expectedProfile, nil)
-	
-	// 调用函数
-	result, err := mockSvc.GetUserProfile(ctx, userID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedProfile, result)
-	mockSvc.AssertExpectations(t)
-}
+// TestRejectReviewerApplication 测试拒绝申请并通知申请人，冷却期内不能再次申请
+func (s *ReviewerApplicationSuite) TestRejectReviewerApplication() {
+	u := s.insertUser(nil)
+	s.Require().NoError(s.apply(u))
 
-// TestGetUserProfile_InvalidID 测试无效ID时的获取用户档案
-func TestGetUserProfile_InvalidID(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	invalidID := "invalid-id"
-	
-	// 设置Mock期望
-	expectedErr := errors.NewBadRequestError("无效的用户ID")
-	mockSvc.On("GetUserProfile", ctx, invalidID).Return(nil, expectedErr)
-	
-	// 调用函数
-	result, err := mockSvc.GetUserProfile(ctx, invalidID)
-	
-	// 验证结果
-	require.Error(t, err)
-	assert.Nil(t, result)
-	assert.Equal(t, expectedErr, err)
-	mockSvc.AssertExpectations(t)
-}
+	err := s.svc.RejectReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex(), "  ")
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(err))
 
-// TestGetUserProfile_UserNotFound 测试用户不存在时的获取用户档案
-func TestGetUserProfile_UserNotFound(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	nonExistentID := primitive.NewObjectID().Hex()
-	
-	// 设置Mock期望
-	expectedErr := errors.NewNotFoundError("用户不存在")
-	mockSvc.On("GetUserProfile", ctx, nonExistentID).Return(nil, expectedErr)
-	
-	// 调用函数
-	result, err := mockSvc.GetUserProfile(ctx, nonExistentID)
-	
-	// 验证结果
-	require.Error(t, err)
-	assert.Nil(t, result)
-	assert.Equal(t, expectedErr, err)
-	mockSvc.AssertExpectations(t)
-}
+	s.Require().NoError(s.svc.RejectReviewerApplication(s.ctx, u.ID.Hex(), s.adminID.Hex(), " 评测样例不足 "))
 
-// TestUpdateUserProfile_Success 测试成功更新用户档案
-func TestUpdateUserProfile_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	displayName := "Updated Name"
-	bio := "Updated bio information"
-	updateRequest := user.UpdateProfileRequest{
-		DisplayName: &displayName,
-		Bio:         &bio,
-	}
-	
-	// 创建期望的返回值
-	expectedProfile := &user.ProfileResponse{
-		ID:          userID,
-		Username:    "testuser",
-		Email:       "test@example.com",
-		DisplayName: displayName,
-		Bio:         bio,
-		Verified:    true,
-		JoinedAt:    time.Now(),
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateUserProfile", ctx, userID, updateRequest).Return(expectedProfile, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateUserProfile(ctx, userID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedProfile, result)
-	assert.Equal(t, displayName, result.DisplayName)
-	assert.Equal(t, bio, result.Bio)
-	mockSvc.AssertExpectations(t)
-}
+	rejected := s.reload(u)
+	assert.Equal(s.T(), string(models.RoleUser), rejected.Role.Type)
+	assert.Equal(s.T(), models.ApplicationStatusRejected, rejected.ReviewerApplication.Status)
+	assert.Equal(s.T(), "评测样例不足", rejected.ReviewerApplication.ReviewNotes)
+	assert.Equal(s.T(), map[string]string{u.Email: "评测样例不足"}, s.notifier.rejected)
 
-// TestUpdateUserSettings_Success 测试成功更新用户设置
-func TestUpdateUserSettings_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	language := "zh-CN"
-	theme := "dark"
-	unit := "mm"
-	updateRequest := user.UpdateSettingsRequest{
-		Language:        &language,
-		Theme:           &theme,
-		MeasurementUnit: &unit,
-		NotificationPref: map[string]bool{
-			"email_notifications": true,
-			"push_notifications":  false,
-		},
-	}
-	
-	// 创建期望的返回值
-	expectedSettings := &user.SettingsResponse{
-		Language:        language,
-		Theme:           theme,
-		MeasurementUnit: unit,
-		NotificationPref: map[string]bool{
-			"email_notifications": true,
-			"push_notifications":  false,
-		},
-		PrivacySettings:  map[string]string{},
-		SecuritySettings: map[string]interface{}{},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateUserSettings", ctx, userID, updateRequest).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateUserSettings(ctx, userID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, language, result.Language)
-	assert.Equal(t, theme, result.Theme)
-	assert.Equal(t, unit, result.MeasurementUnit)
-	assert.Equal(t, true, result.NotificationPref["email_notifications"])
-	assert.Equal(t, false, result.NotificationPref["push_notifications"])
-	mockSvc.AssertExpectations(t)
+	assert.Equal(s.T(), errors.Forbidden, errors.GetErrorCode(s.apply(u)))
 }
 
-// TestApplyForReviewer_Success 测试成功申请评测员
-func TestApplyForReviewer_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建申请请求
-	application := user.ReviewerApplicationRequest{
-		Experience:     "5 years of experience with gaming peripherals and extensive testing background.",
-		ExpertiseAreas: []string{"gaming mice", "gaming keyboards", "mouse sensors"},
-		Samples:        []string{"https://example.com/review1", "https://example.com/review2"},
-		Motivation:     "I want to contribute to the community by providing detailed and objective reviews of gaming peripherals.",
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("ApplyForReviewer", ctx, userID, application).Return(nil)
-	
-	// 调用函数
-	err := mockSvc.ApplyForReviewer(ctx, userID, application)
-	
-	// 验证结果
-	require.NoError(t, err)
-	mockSvc.AssertExpectations(t)
-}
+// TestGetReviewerApplications 测试按状态查询申请
+func (s *ReviewerApplicationSuite) TestGetReviewerApplications() {
+	pending := s.insertUser(nil)
+	s.Require().NoError(s.apply(pending))
+	rejected := s.insertUser(nil)
+	s.Require().NoError(s.apply(rejected))
+	s.Require().NoError(s.svc.RejectReviewerApplication(s.ctx, rejected.ID.Hex(), s.adminID.Hex(), "评测样例不足"))
+	s.insertUser(nil)
 
-// TestGetGameSettings_Success 测试成功获取游戏设置
-func TestGetGameSettings_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建期望的返回值
-	expectedSettings := &user.GameSettingsResponse{
-		PreferredGames:     []string{"CS2", "Valorant", "Apex Legends"},
-		DefaultDPI:         800,
-		PreferredGripStyle: "claw",
-		MouseAcceleration:  false,
-		PollRate:           1000,
-		SensitivityConfigs: []user.SensitivityConfigResponse{
-			{
-				Game:        "CS2",
-				Sensitivity: 1.2,
-				DPI:         800,
-				IsActive:    true,
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			},
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("GetGameSettings", ctx, userID).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.GetGameSettings(ctx, userID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, 800, result.DefaultDPI)
-	assert.Equal(t, "claw", result.PreferredGripStyle)
-	assert.Equal(t, 1000, result.PollRate)
-	assert.Equal(t, 1, len(result.SensitivityConfigs))
-	assert.Equal(t, "CS2", result.SensitivityConfigs[0].Game)
-	mockSvc.AssertExpectations(t)
-}
+	list, total, err := s.svc.GetReviewerApplications(s.ctx, models.ApplicationStatusPending, 1, 10)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(1), total)
+	s.Require().Len(list.Applications, 1)
+	assert.Equal(s.T(), pending.ID.Hex(), list.Applications[0].UserID)
 
-// TestUpdateGameSettings_Success 测试成功更新游戏设置
-func TestUpdateGameSettings_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	dpi := 1600
-	gripStyle := "palm"
-	acceleration := true
-	pollRate := 500
-	preferredGames := []string{"CS2", "Valorant", "Overwatch"}
-	updateRequest := user.UpdateGameSettingsRequest{
-		DefaultDPI:         &dpi,
-		PreferredGripStyle: &gripStyle,
-		MouseAcceleration:  &acceleration,
-		PollRate:           &pollRate,
-		PreferredGames:     &preferredGames,
-	}
-	
-	// 创建期望的返回值
-	expectedSettings := &user.GameSettingsResponse{
-		DefaultDPI:         dpi,
-		PreferredGripStyle: gripStyle,
-		MouseAcceleration:  acceleration,
-		PollRate:           pollRate,
-		PreferredGames:     preferredGames,
-		SensitivityConfigs: []user.SensitivityConfigResponse{},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateGameSettings", ctx, userID, updateRequest).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateGameSettings(ctx, userID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, dpi, result.DefaultDPI)
-	assert.Equal(t, gripStyle, result.PreferredGripStyle)
-	assert.Equal(t, acceleration, result.MouseAcceleration)
-	assert.Equal(t, pollRate, result.PollRate)
-	assert.Equal(t, preferredGames, result.PreferredGames)
-	mockSvc.AssertExpectations(t)
-}
+	_, total, err = s.svc.GetReviewerApplications(s.ctx, "", 1, 10)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), int64(2), total)
 
-// TestAddSensitivityConfig_Success 测试成功添加灵敏度配置
-func TestAddSensitivityConfig_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建添加请求
-	addRequest := user.AddSensitivityConfigRequest{
-		Game:        "Valorant",
-		Sensitivity: 0.5,
-		DPI:         800,
-		IsActive:    true,
-	}
-	
-	// 创建期望的返回值
-	now := time.Now()
-	expectedSettings := &user.GameSettingsResponse{
-		DefaultDPI:         800,
-		PreferredGripStyle: "claw",
-		MouseAcceleration:  false,
-		PollRate:           1000,
-		SensitivityConfigs: []user.SensitivityConfigResponse{
-			{
-				Game:        "Valorant",
-				Sensitivity: 0.5,
-				DPI:         800,
-				IsActive:    true,
-				CreatedAt:   now,
-				UpdatedAt:   now,
-			},
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("AddSensitivityConfig", ctx, userID, addRequest).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.AddSensitivityConfig(ctx, userID, addRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, 1, len(result.SensitivityConfigs))
-	assert.Equal(t, "Valorant", result.SensitivityConfigs[0].Game)
-	assert.Equal(t, 0.5, result.SensitivityConfigs[0].Sensitivity)
-	assert.Equal(t, 800, result.SensitivityConfigs[0].DPI)
-	assert.Equal(t, true, result.SensitivityConfigs[0].IsActive)
-	mockSvc.AssertExpectations(t)
+	_, _, err = s.svc.GetReviewerApplications(s.ctx, "unknown", 1, 10)
+	assert.Equal(s.T(), errors.BadRequest, errors.GetErrorCode(err))
 }
 
-// TestUpdateSensitivityConfig_Success 测试成功更新灵敏度配置
-func TestUpdateSensitivityConfig_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	game := "Valorant"
-	
-	// 创建更新请求
-	sensitivity := 0.7
-	dpi := 1600
-	isActive := false
-	updateRequest := user.UpdateSensitivityConfigRequest{
-		Sensitivity: &sensitivity,
-		DPI:         &dpi,
-		IsActive:    &isActive,
-	}
-	
-	// 创建期望的返回值
-	now := time.Now()
-	expectedSettings := &user.GameSettingsResponse{
-		DefaultDPI:         800,
-		PreferredGripStyle: "claw",
-		MouseAcceleration:  false,
-		PollRate:           1000,
-		SensitivityConfigs: []user.SensitivityConfigResponse{
-			{
-				Game:        game,
-				Sensitivity: sensitivity,
-				DPI:         dpi,
-				IsActive:    isActive,
-				CreatedAt:   now,
-				UpdatedAt:   now,
-			},
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateSensitivityConfig", ctx, userID, game, updateRequest).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateSensitivityConfig(ctx, userID, game, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, 1, len(result.SensitivityConfigs))
-	assert.Equal(t, game, result.SensitivityConfigs[0].Game)
-	assert.Equal(t, sensitivity, result.SensitivityConfigs[0].Sensitivity)
-	assert.Equal(t, dpi, result.SensitivityConfigs[0].DPI)
-	assert.Equal(t, isActive, result.SensitivityConfigs[0].IsActive)
-	mockSvc.AssertExpectations(t)
+func TestReviewerApplicationSuite(t *testing.T) {
+	suite.Run(t, new(ReviewerApplicationSuite))
 }
-
-// TestDeleteSensitivityConfig_Success 测试成功删除灵敏度配置
-func TestDeleteSensitivityConfig_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	game := "Valorant"
-	
-	// 设置Mock期望
-	mockSvc.On("DeleteSensitivityConfig", ctx, userID, game).Return(nil)
-	
-	// 调用函数
-	err := mockSvc.DeleteSensitivityConfig(ctx, userID, game)
-	
-	// 验证结果
-	require.NoError(t, err)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestGetPrivacySettings_Success 测试成功获取隐私设置
-func TestGetPrivacySettings_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建期望的返回值
-	expectedSettings := &user.PrivacySettingsResponse{
-		ProfileVisibility:       "public",
-		DeviceListVisibility:    "friends",
-		ReviewHistoryVisibility: "private",
-		ShowOnlineStatus:        true,
-		ShowActivity:            false,
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("GetPrivacySettings", ctx, userID).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.GetPrivacySettings(ctx, userID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, "public", result.ProfileVisibility)
-	assert.Equal(t, "friends", result.DeviceListVisibility)
-	assert.Equal(t, "private", result.ReviewHistoryVisibility)
-	assert.Equal(t, true, result.ShowOnlineStatus)
-	assert.Equal(t, false, result.ShowActivity)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestUpdatePrivacySettings_Success 测试成功更新隐私设置
-func TestUpdatePrivacySettings_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	profileVisibility := "friends"
-	deviceListVisibility := "private"
-	reviewHistoryVisibility := "public"
-	showOnlineStatus := false
-	showActivity := true
-	updateRequest := user.UpdatePrivacySettingsRequest{
-		ProfileVisibility:       &profileVisibility,
-		DeviceListVisibility:    &deviceListVisibility,
-		ReviewHistoryVisibility: &reviewHistoryVisibility,
-		ShowOnlineStatus:        &showOnlineStatus,
-		ShowActivity:            &showActivity,
-	}
-	
-	// 创建期望的返回值
-	expectedSettings := &user.PrivacySettingsResponse{
-		ProfileVisibility:       profileVisibility,
-		DeviceListVisibility:    deviceListVisibility,
-		ReviewHistoryVisibility: reviewHistoryVisibility,
-		ShowOnlineStatus:        showOnlineStatus,
-		ShowActivity:            showActivity,
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdatePrivacySettings", ctx, userID, updateRequest).Return(expectedSettings, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdatePrivacySettings(ctx, userID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedSettings, result)
-	assert.Equal(t, profileVisibility, result.ProfileVisibility)
-	assert.Equal(t, deviceListVisibility, result.DeviceListVisibility)
-	assert.Equal(t, reviewHistoryVisibility, result.ReviewHistoryVisibility)
-	assert.Equal(t, showOnlineStatus, result.ShowOnlineStatus)
-	assert.Equal(t, showActivity, result.ShowActivity)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestGetDevicePreferences_Success 测试成功获取设备偏好
-func TestGetDevicePreferences_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建期望的返回值
-	deviceID := primitive.NewObjectID().Hex()
-	now := time.Now()
-	expectedPreferences := []user.DevicePreferenceResponse{
-		{
-			ID:          primitive.NewObjectID().Hex(),
-			DeviceID:    deviceID,
-			DeviceType:  "mouse",
-			DeviceName:  "Logitech G Pro X Superlight",
-			DeviceBrand: "Logitech",
-			IsFavorite:  true,
-			IsWishlist:  false,
-			Rating:      5,
-			Notes:       "Best mouse I've ever used",
-			CreatedAt:   now,
-			UpdatedAt:   now,
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("GetDevicePreferences", ctx, userID).Return(expectedPreferences, nil)
-	
-	// 调用函数
-	result, err := mockSvc.GetDevicePreferences(ctx, userID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedPreferences, result)
-	assert.Equal(t, 1, len(result))
-	assert.Equal(t, deviceID, result[0].DeviceID)
-	assert.Equal(t, "mouse", result[0].DeviceType)
-	assert.Equal(t, true, result[0].IsFavorite)
-	assert.Equal(t, 5, result[0].Rating)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestAddDevicePreference_Success 测试成功添加设备偏好
-func TestAddDevicePreference_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	deviceID := primitive.NewObjectID().Hex()
-	
-	// 创建添加请求
-	rating := 5
-	addRequest := user.AddDevicePreferenceRequest{
-		DeviceID:   deviceID,
-		DeviceType: "mouse",
-		IsFavorite: true,
-		IsWishlist: false,
-		Rating:     &rating,
-		Notes:      "Excellent wireless gaming mouse",
-	}
-	
-	// 创建期望的返回值
-	now := time.Now()
-	expectedResponse := &user.DevicePreferenceResponse{
-		ID:          primitive.NewObjectID().Hex(),
-		DeviceID:    deviceID,
-		DeviceType:  "mouse",
-		DeviceName:  "Logitech G Pro X Superlight",
-		DeviceBrand: "Logitech",
-		IsFavorite:  true,
-		IsWishlist:  false,
-		Rating:      rating,
-		Notes:       "Excellent wireless gaming mouse",
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("AddDevicePreference", ctx, userID, addRequest).Return(expectedResponse, nil)
-	
-	// 调用函数
-	result, err := mockSvc.AddDevicePreference(ctx, userID, addRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedResponse, result)
-	assert.Equal(t, deviceID, result.DeviceID)
-	assert.Equal(t, "mouse", result.DeviceType)
-	assert.Equal(t, true, result.IsFavorite)
-	assert.Equal(t, rating, result.Rating)
-	assert.Equal(t, "Excellent wireless gaming mouse", result.Notes)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestUpdateDevicePreference_Success 测试成功更新设备偏好
-func TestUpdateDevicePreference_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	devicePrefID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	isFavorite := false
-	isWishlist := true
-	rating := 4
-	notes := "Updated notes for this device"
-	updateRequest := user.UpdateDevicePreferenceRequest{
-		IsFavorite: &isFavorite,
-		IsWishlist: &isWishlist,
-		Rating:     &rating,
-		Notes:      &notes,
-	}
-	
-	// 创建期望的返回值
-	now := time.Now()
-	deviceID := primitive.NewObjectID().Hex()
-	expectedResponse := &user.DevicePreferenceResponse{
-		ID:          devicePrefID,
-		DeviceID:    deviceID,
-		DeviceType:  "mouse",
-		DeviceName:  "Logitech G Pro X Superlight",
-		DeviceBrand: "Logitech",
-		IsFavorite:  isFavorite,
-		IsWishlist:  isWishlist,
-		Rating:      rating,
-		Notes:       notes,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateDevicePreference", ctx, userID, devicePrefID, updateRequest).Return(expectedResponse, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateDevicePreference(ctx, userID, devicePrefID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedResponse, result)
-	assert.Equal(t, isFavorite, result.IsFavorite)
-	assert.Equal(t, isWishlist, result.IsWishlist)
-	assert.Equal(t, rating, result.Rating)
-	assert.Equal(t, notes, result.Notes)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestDeleteDevicePreference_Success 测试成功删除设备偏好
-func TestDeleteDevicePreference_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	devicePrefID := primitive.NewObjectID().Hex()
-	
-	// 设置Mock期望
-	mockSvc.On("DeleteDevicePreference", ctx, userID, devicePrefID).Return(nil)
-	
-	// 调用函数
-	err := mockSvc.DeleteDevicePreference(ctx, userID, devicePrefID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestGetUserPreferences_Success 测试成功获取用户偏好
-func TestGetUserPreferences_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建期望的返回值
-	expectedPreferences := &user.PreferencesResponse{
-		GameSettings: map[string]interface{}{
-			"preferredGenres": []string{"FPS", "MOBA"},
-			"favoriteGames":   []string{"CS2", "Valorant"},
-		},
-		PeripheralPrefs: map[string]interface{}{
-			"preferredBrands": []string{"Logitech", "Zowie"},
-			"mouseWeight":     "light",
-		},
-		ContentPrefs: map[string]interface{}{
-			"showReviews":   true,
-			"showTutorials": true,
-		},
-		RecommendedForYou: map[string]interface{}{
-			"enableRecommendations": true,
-			"basedOn":               "browser_history",
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("GetUserPreferences", ctx, userID).Return(expectedPreferences, nil)
-	
-	// 调用函数
-	result, err := mockSvc.GetUserPreferences(ctx, userID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedPreferences, result)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestUpdateUserPreferences_Success 测试成功更新用户偏好
-func TestUpdateUserPreferences_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	
-	// 创建更新请求
-	updateRequest := user.UpdatePreferencesRequest{
-		GameSettings: map[string]interface{}{
-			"preferredGenres": []string{"FPS", "RPG", "Strategy"},
-			"favoriteGames":   []string{"CS2", "Valorant", "Baldur's Gate 3"},
-		},
-		PeripheralPrefs: map[string]interface{}{
-			"preferredBrands": []string{"Logitech", "Razer"},
-			"mouseWeight":     "ultralight",
-		},
-	}
-	
-	// 创建期望的返回值
-	expectedPreferences := &user.PreferencesResponse{
-		GameSettings: updateRequest.GameSettings,
-		PeripheralPrefs: updateRequest.PeripheralPrefs,
-		ContentPrefs: map[string]interface{}{
-			"showReviews":   true,
-			"showTutorials": true,
-		},
-		RecommendedForYou: map[string]interface{}{
-			"enableRecommendations": true,
-		},
-	}
-	
-	// 设置Mock期望
-	mockSvc.On("UpdateUserPreferences", ctx, userID, updateRequest).Return(expectedPreferences, nil)
-	
-	// 调用函数
-	result, err := mockSvc.UpdateUserPreferences(ctx, userID, updateRequest)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedPreferences, result)
-	assert.Equal(t, updateRequest.GameSettings, result.GameSettings)
-	assert.Equal(t, updateRequest.PeripheralPrefs, result.PeripheralPrefs)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestGetReviewerApplications_Success 测试成功获取评测员申请列表
-func TestGetReviewerApplications_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	status := "pending"
-	page := 1
-	pageSize := 10
-	
-	// 创建期望的返回值
-	userID1 := primitive.NewObjectID().Hex()
-	userID2 := primitive.NewObjectID().Hex()
-	now := time.Now()
-	
-	expectedResponse := &user.ReviewerApplicationListResponse{
-		Applications: []user.ReviewerApplicationDetails{
-			{
-				UserID:         userID1,
-				Username:       "user1",
-				Email:          "user1@example.com",
-				DisplayName:    "User One",
-				Experience:     "5 years of experience with gaming peripherals",
-				ExpertiseAreas: []string{"mice", "keyboards"},
-				Samples:        []string{"https://example.com/sample1"},
-				Motivation:     "I want to help the community",
-				Status:         "pending",
-				AppliedAt:      now,
-				UpdatedAt:      now,
-			},
-			{
-				UserID:         userID2,
-				Username:       "user2",
-				Email:          "user2@example.com",
-				DisplayName:    "User Two",
-				Experience:     "3 years of experience with gaming peripherals",
-				ExpertiseAreas: []string{"mice", "mousepads"},
-				Samples:        []string{"https://example.com/sample2"},
-				Motivation:     "I want to share my knowledge",
-				Status:         "pending",
-				AppliedAt:      now,
-				UpdatedAt:      now,
-			},
-		},
-		Page:     page,
-		PageSize: pageSize,
-	}
-	
-	expectedTotal := int64(2)
-	
-	// 设置Mock期望
-	mockSvc.On("GetReviewerApplications", ctx, status, page, pageSize).Return(expectedResponse, expectedTotal, nil)
-	
-	// 调用函数
-	result, total, err := mockSvc.GetReviewerApplications(ctx, status, page, pageSize)
-	
-	// 验证结果
-	require.NoError(t, err)
-	assert.Equal(t, expectedResponse, result)
-	assert.Equal(t, expectedTotal, total)
-	assert.Equal(t, 2, len(result.Applications))
-	assert.Equal(t, userID1, result.Applications[0].UserID)
-	assert.Equal(t, userID2, result.Applications[1].UserID)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestApproveReviewerApplication_Success 测试成功批准评测员申请
-func TestApproveReviewerApplication_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	adminID := primitive.NewObjectID().Hex()
-	
-	// 设置Mock期望
-	mockSvc.On("ApproveReviewerApplication", ctx, userID, adminID).Return(nil)
-	
-	// 调用函数
-	err := mockSvc.ApproveReviewerApplication(ctx, userID, adminID)
-	
-	// 验证结果
-	require.NoError(t, err)
-	mockSvc.AssertExpectations(t)
-}
-
-// TestRejectReviewerApplication_Success 测试成功拒绝评测员申请
-func TestRejectReviewerApplication_Success(t *testing.T) {
-	mockSvc := new(mockUserService)
-	ctx := context.Background()
-	userID := primitive.NewObjectID().Hex()
-	adminID := primitive.NewObjectID().Hex()
-	reason := "Insufficient experience in the required areas"
-	
-	// 设置Mock期望
-	mockSvc.On("RejectReviewerApplication", ctx, userID, adminID, reason).Return(nil)
-	
-	// 调用函数
-	err := mockSvc.RejectReviewerApplication(ctx, userID, adminID, reason)
-	
-	// 验证结果
-	require.NoError(t, err)
-	mockSvc.AssertExpectations(t)
-}
\ No newline at end of file
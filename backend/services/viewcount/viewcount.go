@@ -0,0 +1,238 @@
+// Package viewcount 评测浏览计数
+// 同一访客在去重窗口内只计一次，已知爬虫不计数；计数先累加在Redis中，由定时任务批量写回Mongo
+package viewcount
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"project/backend/config"
+	"project/backend/models"
+	"project/backend/services/scheduler"
+)
+
+// 未配置时使用的默认值
+const (
+	DefaultDedupeWindow     = 30 * time.Minute
+	DefaultFlushInterval    = time.Minute
+	DefaultTrendingHalfLife = 48 * time.Hour
+	DefaultTrendingWindow   = 7 * 24 * time.Hour
+)
+
+// FlushJobName 浏览数写回任务名
+const FlushJobName = "review-view-flush"
+
+const (
+	seenKeyPrefix = "views:seen:"
+	// pendingKey 尚未写回的浏览数，评测ID -> 次数
+	pendingKey = "views:pending"
+	// flushingKey 正在写回的批次，写回失败时保留到下一轮
+	flushingKey = "views:flushing"
+	// flushBatchKey 正在写回的批次ID，写入评测后重试同一批次不会重复累加
+	flushBatchKey = "views:flushing:batch"
+)
+
+// botPatterns 常见爬虫和脚本的UA关键字，空UA也视为爬虫
+var botPatterns = []string{
+	"bot", "spider", "crawl", "slurp", "curl", "wget", "python-requests",
+	"go-http-client", "headless", "facebookexternalhit", "preview",
+}
+
+// Visitor 浏览评测的访客，登录用户按用户ID去重，其他访客按IP去重
+type Visitor struct {
+	UserID    string
+	IP        string
+	UserAgent string
+}
+
+// Key 访客的去重标识，Redis中只保存哈希，不保存原始IP
+func (v Visitor) Key() string {
+	id := "ip:" + v.IP
+	if v.UserID != "" {
+		id = "user:" + v.UserID
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+// IsBot 按UA关键字判断是否为爬虫，extra为额外配置的关键字
+func IsBot(userAgent string, extra []string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, patterns := range [][]string{botPatterns, extra} {
+		for _, pattern := range patterns {
+			if pattern != "" && strings.Contains(ua, strings.ToLower(pattern)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithDefaults 填充未配置的项
+func WithDefaults(cfg config.ViewConfig) config.ViewConfig {
+	if cfg.DedupeWindow <= 0 {
+		cfg.DedupeWindow = DefaultDedupeWindow
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.TrendingHalfLife <= 0 {
+		cfg.TrendingHalfLife = DefaultTrendingHalfLife
+	}
+	if cfg.TrendingWindow <= 0 {
+		cfg.TrendingWindow = DefaultTrendingWindow
+	}
+	return cfg
+}
+
+// Counter 评测浏览计数
+type Counter struct {
+	redis *redis.Client
+	db    *mongo.Database
+	cfg   config.ViewConfig
+}
+
+// New 创建浏览计数
+func New(client *redis.Client, db *mongo.Database, cfg config.ViewConfig) *Counter {
+	return &Counter{redis: client, db: db, cfg: WithDefaults(cfg)}
+}
+
+// Record 记录一次浏览，爬虫和去重窗口内的重复浏览不计数，返回是否计数
+func (c *Counter) Record(ctx context.Context, reviewID primitive.ObjectID, visitor Visitor) (bool, error) {
+	if IsBot(visitor.UserAgent, c.cfg.BotUserAgents) {
+		return false, nil
+	}
+
+	seenKey := seenKeyPrefix + reviewID.Hex() + ":" + visitor.Key()
+	first, err := c.redis.SetNX(ctx, seenKey, 1, c.cfg.DedupeWindow).Result()
+	if err != nil || !first {
+		return false, err
+	}
+	if err := c.redis.HIncrBy(ctx, pendingKey, reviewID.Hex(), 1).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Flush 把累加的浏览数批量写回Mongo，同时更新按时间衰减的热度，返回更新的评测数
+// 上一批写回失败时先重试上一批，新的浏览继续累加到下一批
+// 每条更新记录批次ID，部分写入成功后重试时已写入的评测不再匹配，不会重复计数
+func (c *Counter) Flush(ctx context.Context, now time.Time) (int, error) {
+	retrying, err := c.redis.Exists(ctx, flushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if retrying == 0 {
+		pending, err := c.redis.Exists(ctx, pendingKey).Result()
+		if err != nil || pending == 0 {
+			return 0, err
+		}
+		if err := c.redis.Rename(ctx, pendingKey, flushingKey).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	// 重试时沿用已有的批次ID
+	if err := c.redis.SetNX(ctx, flushBatchKey, primitive.NewObjectID().Hex(), 0).Err(); err != nil {
+		return 0, err
+	}
+	batch, err := c.redis.Get(ctx, flushBatchKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	counts, err := c.redis.HGetAll(ctx, flushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	writes := make([]mongo.WriteModel, 0, len(counts))
+	for reviewID, value := range counts {
+		id, err := primitive.ObjectIDFromHex(reviewID)
+		if err != nil {
+			continue
+		}
+		delta, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || delta <= 0 {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"_id":                 id,
+				"deletedAt":           nil,
+				"status":              bson.M{"$in": models.PublishedReviewStatuses},
+				"trending.flushBatch": bson.M{"$ne": batch},
+			}).
+			SetUpdate(viewUpdate(delta, batch, now, c.cfg.TrendingHalfLife)))
+	}
+
+	if len(writes) > 0 {
+		_, err := c.db.Collection(models.ReviewsCollection).BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := c.redis.Del(ctx, flushingKey, flushBatchKey).Err(); err != nil {
+		return 0, err
+	}
+	return len(writes), nil
+}
+
+// FlushJob 定期写回浏览数的定时任务
+func (c *Counter) FlushJob() scheduler.Job {
+	return scheduler.Job{
+		Name:     FlushJobName,
+		Interval: c.cfg.FlushInterval,
+		Run: func(ctx context.Context, now time.Time) error {
+			_, err := c.Flush(ctx, now)
+			return err
+		},
+	}
+}
+
+// TrendingPipeline 热门评测的聚合管道：按当前时间衰减后的热度从高到低排序
+// 只包含trendingWindow内有浏览的评测
+func TrendingPipeline(match bson.M, now time.Time, cfg config.ViewConfig, limit int) mongo.Pipeline {
+	cfg = WithDefaults(cfg)
+	match["trending.updatedAt"] = bson.M{"$gte": now.Add(-cfg.TrendingWindow)}
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"trendingScore": bson.M{"$multiply": bson.A{"$trending.score", decayFactor(now, "$trending.updatedAt", cfg.TrendingHalfLife)}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "trendingScore", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+}
+
+// viewUpdate 增加浏览数，原有热度先衰减到now再加上新的浏览数，并记录写入的批次
+func viewUpdate(delta int64, batch string, now time.Time, halfLife time.Duration) bson.A {
+	lastUpdated := bson.M{"$ifNull": bson.A{"$trending.updatedAt", now}}
+	return bson.A{bson.M{"$set": bson.M{
+		"viewCount": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$viewCount", 0}}, delta}},
+		"trending.score": bson.M{"$add": bson.A{
+			bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$trending.score", 0}}, decayFactor(now, lastUpdated, halfLife)}},
+			delta,
+		}},
+		"trending.updatedAt":  now,
+		"trending.flushBatch": batch,
+	}}}
+}
+
+// decayFactor 从since到now的衰减系数 0.5^(经过时间/半衰期)
+func decayFactor(now time.Time, since interface{}, halfLife time.Duration) bson.M {
+	elapsed := bson.M{"$subtract": bson.A{now, since}} // 毫秒
+	return bson.M{"$pow": bson.A{0.5, bson.M{"$divide": bson.A{elapsed, halfLife.Milliseconds()}}}}
+}
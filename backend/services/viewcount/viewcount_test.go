@@ -0,0 +1,124 @@
+package viewcount
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"project/backend/config"
+	"project/backend/models"
+	"project/backend/tests/testutil"
+)
+
+func TestIsBot(t *testing.T) {
+	assert.True(t, IsBot("", nil))
+	assert.True(t, IsBot("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", nil))
+	assert.True(t, IsBot("curl/8.4.0", nil))
+	assert.True(t, IsBot("Mozilla/5.0 HeadlessChrome/120.0", nil))
+	assert.False(t, IsBot("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0 Safari/537.36", nil))
+	assert.True(t, IsBot("Mozilla/5.0 SiteAuditor", []string{"siteauditor"}))
+}
+
+func TestRecordDedupesVisitors(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+
+	counter := New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), nil, config.ViewConfig{DedupeWindow: time.Minute})
+	ctx := context.Background()
+	reviewID := primitive.NewObjectID()
+	browser := "Mozilla/5.0 (Macintosh) Safari/605.1.15"
+
+	counted, err := counter.Record(ctx, reviewID, Visitor{IP: "1.2.3.4", UserAgent: browser})
+	require.NoError(t, err)
+	assert.True(t, counted)
+
+	// 刷新页面不重复计数
+	counted, err = counter.Record(ctx, reviewID, Visitor{IP: "1.2.3.4", UserAgent: browser})
+	require.NoError(t, err)
+	assert.False(t, counted)
+
+	// 同一IP登录后按用户去重
+	counted, err = counter.Record(ctx, reviewID, Visitor{UserID: "u1", IP: "1.2.3.4", UserAgent: browser})
+	require.NoError(t, err)
+	assert.True(t, counted)
+
+	counted, err = counter.Record(ctx, reviewID, Visitor{IP: "5.6.7.8", UserAgent: "Bingbot/2.0"})
+	require.NoError(t, err)
+	assert.False(t, counted)
+
+	assert.Equal(t, "2", mr.HGet(pendingKey, reviewID.Hex()))
+	for _, key := range mr.Keys() {
+		assert.NotContains(t, key, "1.2.3.4")
+	}
+
+	// 窗口过后再次计数
+	mr.FastForward(2 * time.Minute)
+	counted, err = counter.Record(ctx, reviewID, Visitor{IP: "1.2.3.4", UserAgent: browser})
+	require.NoError(t, err)
+	assert.True(t, counted)
+	assert.Equal(t, "3", mr.HGet(pendingKey, reviewID.Hex()))
+}
+
+func TestFlushWithoutPendingViews(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+
+	counter := New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), nil, config.ViewConfig{})
+	updated, err := counter.Flush(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated)
+}
+
+// TestFlushRetryDoesNotDoubleCount 批次写入评测后删除Redis中的批次失败，重试同一批次不会重复计数
+func TestFlushRetryDoesNotDoubleCount(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+
+	ctx := context.Background()
+	reviewID := primitive.NewObjectID()
+	_, err = db.Collection(models.ReviewsCollection).InsertOne(ctx, bson.M{
+		"_id":       reviewID,
+		"status":    models.ReviewStatusApproved,
+		"deletedAt": nil,
+		"viewCount": 10,
+	})
+	require.NoError(t, err)
+
+	counter := New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), db, config.ViewConfig{})
+	mr.HSet(pendingKey, reviewID.Hex(), "3")
+	updated, err := counter.Flush(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+
+	// 模拟写入成功但删除批次失败：批次仍在Redis中，下一轮重试
+	var review models.Review
+	require.NoError(t, db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{"_id": reviewID}).Decode(&review))
+	mr.HSet(flushingKey, reviewID.Hex(), "3")
+	mr.Set(flushBatchKey, review.Trending.FlushBatch)
+	_, err = counter.Flush(ctx, time.Now())
+	require.NoError(t, err)
+	assert.False(t, mr.Exists(flushingKey))
+	assert.False(t, mr.Exists(flushBatchKey))
+
+	require.NoError(t, db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{"_id": reviewID}).Decode(&review))
+	assert.Equal(t, 13, review.ViewCount)
+
+	// 新的批次照常累加
+	mr.HSet(pendingKey, reviewID.Hex(), "2")
+	_, err = counter.Flush(ctx, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, db.Collection(models.ReviewsCollection).FindOne(ctx, bson.M{"_id": reviewID}).Decode(&review))
+	assert.Equal(t, 15, review.ViewCount)
+}
@@ -7,6 +7,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
 	"time"
 )
 
@@ -174,17 +176,77 @@ func createAdminUser(u TestUser, now time.Time) bson.M {
 	return user
 }
 
-// NewTestDB 创建测试数据库连接
+// testDBPrefix 测试数据库名前缀，后接随机后缀区分各个测试
+const testDBPrefix = "cpc_test_"
+
+// NewTestDB 创建测试数据库连接，地址取环境变量MONGODB_TEST_URI，未设置时连接本机
+// 每次调用都使用独立的数据库名，go test并行运行多个包时各测试互不清空对方的数据
 func NewTestDB() (*mongo.Database, error) {
 	ctx := context.Background()
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
 	// 只创建连接，不做其他操作
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to mongodb: %v", err)
 	}
 
 	// 只返回数据库引用
-	return client.Database("cpc_test"), nil
+	return client.Database(testDBPrefix + primitive.NewObjectID().Hex()), nil
+}
+
+// SetupTestDB 准备不需要事务的测试数据库，单机MongoDB即可，MongoDB不可用时跳过测试
+func SetupTestDB(t *testing.T) (*mongo.Database, func()) {
+	return setupTestDB(t, false)
+}
+
+// SetupTransactionTest 准备需要事务的测试数据库
+// 事务要求MongoDB以副本集方式运行，本地没有可用的副本集时跳过测试。可以启动单节点副本集：
+//
+//	docker run -d -p 27017:27017 mongo:7 --replSet rs0
+//	docker exec <容器> mongosh --eval 'rs.initiate()'
+//
+// 再通过MONGODB_TEST_URI=mongodb://localhost:27017/?directConnection=true指定地址
+func SetupTransactionTest(t *testing.T) (*mongo.Database, func()) {
+	return setupTestDB(t, true)
+}
+
+func setupTestDB(t *testing.T, requireReplicaSet bool) (*mongo.Database, func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	db, err := NewTestDB()
+	if err != nil {
+		t.Skipf("MongoDB不可用: %v", err)
+	}
+	if err := db.Client().Ping(ctx, nil); err != nil {
+		_ = db.Client().Disconnect(context.Background())
+		t.Skipf("MongoDB不可用: %v", err)
+	}
+
+	if requireReplicaSet {
+		var hello struct {
+			SetName string `bson:"setName"`
+		}
+		if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil || hello.SetName == "" {
+			_ = db.Client().Disconnect(context.Background())
+			t.Skip("MongoDB不是副本集，无法使用事务")
+		}
+	}
+
+	if err := db.Drop(ctx); err != nil {
+		t.Fatalf("failed to drop database: %v", err)
+	}
+
+	cleanup := func() {
+		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cleanupCancel()
+		_ = db.Drop(cleanupCtx)
+		_ = db.Client().Disconnect(cleanupCtx)
+	}
+	return db, cleanup
 }
 
 // InitTestData 初始化测试数据
@@ -6,39 +6,51 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// CartItemRequest 用于添加/更新购物车项的请求
+// CartItemRequest 用于添加购物车项的请求
+// 名称和价格由服务端按SKU查询，不接受客户端传入
 type CartItemRequest struct {
-	ProductID   string  `json:"product_id" binding:"required"`
-	ProductType string  `json:"product_type" binding:"required"`
-	Name        string  `json:"name" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required,min=1"`
-	ImageURL    string  `json:"image_url,omitempty"`
+	SKUID    string `json:"sku_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
 }
 
 // UpdateQuantityRequest 用于更新购物车项数量的请求
 type UpdateQuantityRequest struct {
-	ProductID string `json:"product_id" binding:"required"`
-	Quantity  int    `json:"quantity" binding:"required,min=0"`
+	SKUID    string `json:"sku_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"min=0"`
 }
 
-// CartItemResponse 表示购物车项的响应
+// ApplyCouponRequest 购物车使用优惠券的请求
+type ApplyCouponRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// CartItemResponse 表示购物车项的响应，金额单位为分
 type CartItemResponse struct {
-	ProductID   string  `json:"product_id"`
-	ProductType string  `json:"product_type"`
-	Name        string  `json:"name"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	ImageURL    string  `json:"image_url,omitempty"`
+	ProductID   string `json:"product_id"`
+	SKUID       string `json:"sku_id"`
+	ProductType string `json:"product_type"`
+	Name        string `json:"name"`
+	Price       int64  `json:"price"`
+	Currency    string `json:"currency"`
+	Quantity    int    `json:"quantity"`
+	Subtotal    int64  `json:"subtotal"`
+	ImageURL    string `json:"image_url,omitempty"`
+	Available   bool   `json:"available"`
+	Reason      string `json:"reason,omitempty"`
 }
 
 // CartResponse 表示购物车的响应
 type CartResponse struct {
-	ID        string            `json:"id,omitempty"`
-	Items     []CartItemResponse `json:"items"`
-	Total     float64           `json:"total"`
-	ItemCount int               `json:"item_count"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID          string             `json:"id,omitempty"`
+	Items       []CartItemResponse `json:"items"`
+	Total       int64              `json:"total"` // 可购买商品的合计（分）
+	Currency    string             `json:"currency,omitempty"`
+	ItemCount   int                `json:"item_count"`
+	CouponCode  string             `json:"coupon_code,omitempty"`
+	CouponError string             `json:"coupon_error,omitempty"` // 优惠券当前不可用的原因
+	Discount    int64              `json:"discount"`               // 优惠券折扣（分）
+	Payable     int64              `json:"payable"`                // 折后商品金额（分），不含运费和税费
+	UpdatedAt   time.Time          `json:"updated_at"`
 }
 
 // CartDetail 包含购物车完整详情
@@ -46,8 +58,9 @@ type CartDetail struct {
 	ID        primitive.ObjectID `json:"id,omitempty"`
 	UserID    primitive.ObjectID `json:"user_id"`
 	Items     []CartItemResponse `json:"items"`
-	Total     float64           `json:"total"`
-	ItemCount int               `json:"item_count"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
-}
\ No newline at end of file
+	Total     int64              `json:"total"`
+	Currency  string             `json:"currency,omitempty"`
+	ItemCount int                `json:"item_count"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
@@ -9,26 +9,26 @@ import (
 
 // DeviceListFilter 设备列表筛选条件
 type DeviceListFilter struct {
-	Page     int
-	PageSize int
-	Type     string
-	Brand    string
+	Page       int
+	PageSize   int
+	Type       string
+	Brand      string
+	MinRating  float64 // 平均分下限
+	MinReviews int     // 评测数下限
+	SortBy     string
+	SortOrder  string
 }
 
-// CreateReviewRequest 创建评测的请求，与SubmitReviewRequest基本一致
-type CreateReviewRequest SubmitReviewRequest
-
-// UpdateReviewRequest 更新评测的请求，与UpdateDeviceReviewRequest基本一致
-type UpdateReviewRequest UpdateDeviceReviewRequest
-
 // DeviceListRequest 获取设备列表的请求
 type DeviceListRequest struct {
-	Page      int    `form:"page" binding:"omitempty,min=1"`
-	PageSize  int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
-	Type      string `form:"type" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory"`
-	Brand     string `form:"brand" binding:"omitempty"`
-	SortBy    string `form:"sortBy" binding:"omitempty,oneof=name brand createdAt"`
-	SortOrder string `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
+	Page       int     `form:"page" binding:"omitempty,min=1"`
+	PageSize   int     `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	Type       string  `form:"type" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory"`
+	Brand      string  `form:"brand" binding:"omitempty"`
+	MinRating  float64 `form:"minRating" binding:"omitempty,min=1,max=5"`
+	MinReviews int     `form:"minReviews" binding:"omitempty,min=1"`
+	SortBy     string  `form:"sortBy" binding:"omitempty,oneof=name brand createdAt rating reviews"`
+	SortOrder  string  `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
 }
 
 // DeviceListResponse 设备列表的响应
@@ -41,13 +41,14 @@ type DeviceListResponse struct {
 
 // DevicePreview 设备预览信息
 type DevicePreview struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Brand       string    `json:"brand"`
-	Type        string    `json:"type"`
-	ImageURL    string    `json:"imageUrl,omitempty"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Brand       string               `json:"brand"`
+	Type        string               `json:"type"`
+	ImageURL    string               `json:"imageUrl,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Rating      *models.DeviceRating `json:"rating,omitempty"`
+	CreatedAt   time.Time            `json:"createdAt"`
 }
 
 // 鼠标相关类型
@@ -88,6 +89,7 @@ type MouseResponse struct {
 	Shape       models.MouseShape        `json:"shape"`
 	Technical   models.MouseTechnical    `json:"technical"`
 	Recommended models.MouseRecommended  `json:"recommended"`
+	Rating      *models.DeviceRating     `json:"rating,omitempty"`
 	CreatedAt   time.Time                `json:"createdAt"`
 	UpdatedAt   time.Time                `json:"updatedAt"`
 }
@@ -165,72 +167,6 @@ type CreateMousepadRequest struct {
 	Recommended []string `json:"recommended"`
 }
 
-// 评测相关类型
-
-// CreateDeviceReviewRequest 创建设备评测的请求
-type CreateDeviceReviewRequest struct {
-	DeviceID    string   `json:"deviceId" binding:"required"`
-	Content     string   `json:"content" binding:"required,min=50"`
-	Pros        []string `json:"pros" binding:"required,min=1"`
-	Cons        []string `json:"cons" binding:"required,min=1"`
-	Score       float64  `json:"score" binding:"required,min=1,max=5"`
-	Usage       string   `json:"usage" binding:"required"`
-	Type        string   `json:"type" binding:"required,oneof=mouse keyboard monitor mousepad accessory"`
-	ContentType string   `json:"contentType" binding:"required,oneof=single comparison experience gaming buying"`
-}
-
-// UpdateDeviceReviewRequest 更新设备评测的请求
-type UpdateDeviceReviewRequest struct {
-	Content     *string   `json:"content,omitempty" binding:"omitempty,min=50"`
-	Pros        *[]string `json:"pros,omitempty" binding:"omitempty,min=1"`
-	Cons        *[]string `json:"cons,omitempty" binding:"omitempty,min=1"`
-	Score       *float64  `json:"score,omitempty" binding:"omitempty,min=1,max=5"`
-	Usage       *string   `json:"usage,omitempty"`
-	Type        *string   `json:"type,omitempty" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory"`
-	ContentType *string   `json:"contentType,omitempty" binding:"omitempty,oneof=single comparison experience gaming buying"`
-}
-
-// DeviceReviewResponse 设备评测响应
-type DeviceReviewResponse struct {
-	ID            string     `json:"id"`
-	DeviceID      string     `json:"deviceId"`
-	UserID        string     `json:"userId"`
-	Content       string     `json:"content"`
-	Pros          []string   `json:"pros"`
-	Cons          []string   `json:"cons"`
-	Score         float64    `json:"score"`
-	Usage         string     `json:"usage"`
-	Status        string     `json:"status"`
-	Type          string     `json:"type"`
-	ContentType   string     `json:"contentType"`
-	ReviewerID    string     `json:"reviewerId,omitempty"`
-	ReviewerNotes string     `json:"reviewerNotes,omitempty"`
-	ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
-	PublishedAt   *time.Time `json:"publishedAt,omitempty"`
-	FeaturedRank  *int       `json:"featuredRank,omitempty"`
-	ViewCount     int        `json:"viewCount"`
-	CreatedAt     time.Time  `json:"createdAt"`
-	UpdatedAt     time.Time  `json:"updatedAt"`
-}
-
-// DeviceReviewListRequest 获取设备评测列表的请求
-type DeviceReviewListRequest struct {
-	DeviceID  string `form:"deviceId,omitempty"`
-	UserID    string `form:"userId,omitempty"`
-	Page      int    `form:"page" binding:"omitempty,min=1"`
-	PageSize  int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
-	SortBy    string `form:"sortBy" binding:"omitempty,oneof=createdAt score"`
-	SortOrder string `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
-}
-
-// DeviceReviewListResponse 设备评测列表响应
-type DeviceReviewListResponse struct {
-	Total   int                   `json:"total"`
-	Page    int                   `json:"page"`
-	PageSize int                  `json:"pageSize"`
-	Reviews []DeviceReviewResponse `json:"reviews"`
-}
-
 // 用户设备配置相关类型
 
 // CreateUserDeviceRequest 创建用户设备配置的请求
@@ -294,15 +230,3 @@ type UserDeviceListResponse struct {
 	PageSize    int                 `json:"pageSize"`
 	UserDevices []UserDeviceResponse `json:"userDevices"`
 }
-
-// 评测提交相关类型
-type SubmitReviewRequest struct {
-	DeviceID    string   `json:"deviceId" binding:"required"`
-	Content     string   `json:"content" binding:"required,min=50"`
-	Pros        []string `json:"pros" binding:"required,min=1"`
-	Cons        []string `json:"cons" binding:"required,min=1"`
-	Score       float64  `json:"score" binding:"required,min=1,max=5"`
-	Usage       string   `json:"usage" binding:"required"`
-	Type        string   `json:"type" binding:"required,oneof=mouse keyboard monitor mousepad accessory"`
-	ContentType string   `json:"contentType" binding:"required,oneof=single comparison experience gaming buying"`
-}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package device
+
+import (
+	"time"
+
+	"project/backend/models"
+)
+
+// 设备修改历史相关类型
+
+// DeviceRevisionListRequest 修改历史列表请求
+type DeviceRevisionListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// DeviceRevisionListResponse 修改历史列表，按版本号倒序
+type DeviceRevisionListResponse struct {
+	Revisions []DeviceRevisionInfo `json:"revisions"`
+	Total     int                  `json:"total"`
+	Page      int                  `json:"page"`
+	PageSize  int                  `json:"pageSize"`
+}
+
+// DeviceRevisionInfo 单个修改记录
+type DeviceRevisionInfo struct {
+	Revision     int                  `json:"revision"`
+	Source       string               `json:"source"`
+	ActorID      string               `json:"actorId,omitempty"`
+	Changes      []models.FieldChange `json:"changes"`
+	RolledBackTo int                  `json:"rolledBackTo,omitempty"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
+
+// DeviceRevisionDiffRequest 对比两个版本
+type DeviceRevisionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+// DeviceRevisionDiffResponse 两个版本之间的字段差异
+type DeviceRevisionDiffResponse struct {
+	DeviceID string               `json:"deviceId"`
+	From     int                  `json:"from"`
+	To       int                  `json:"to"`
+	Changes  []models.FieldChange `json:"changes"`
+}
@@ -4,7 +4,7 @@ package device
 
 // ComparisonRequest 鼠标比较请求
 type ComparisonRequest struct {
-	IDs []string `form:"ids" binding:"required,min=2,max=3"`
+	IDs []string `form:"ids" binding:"required,min=2,max=4"`
 }
 
 // SimilarityRequest 相似度请求
@@ -54,14 +54,16 @@ type SVGResponse struct {
 
 // SVGRequest SVG请求
 type SVGRequest struct {
-	ID   string `uri:"id" binding:"required"`
-	View string `form:"view" binding:"omitempty,oneof=top side"` 
+	ID        string `uri:"id" binding:"required"`
+	View      string `form:"view" binding:"omitempty,oneof=top side"`
+	Canonical bool   `form:"canonical"` // 返回毫米坐标系下的规范轮廓
 }
 
 // SVGCompareRequest SVG比较请求
 type SVGCompareRequest struct {
-	DeviceIDs []string `json:"deviceIds" binding:"required,min=2,max=3"`
+	DeviceIDs []string `json:"deviceIds" binding:"required,min=2,max=4"`
 	View      string   `json:"view" binding:"required,oneof=top side"`
+	Canonical bool     `json:"canonical"` // 使用毫米坐标系下的规范轮廓
 }
 
 // SVGCompareResponse SVG比较响应
@@ -0,0 +1,72 @@
+package device
+
+import "time"
+
+// SVG批量上传相关类型
+
+// SVGUploadFile 待处理的SVG文件
+type SVGUploadFile struct {
+	Name    string
+	Content []byte
+}
+
+// SVGUploadPreviewResponse 上传预览，供管理员确认匹配结果
+type SVGUploadPreviewResponse struct {
+	BatchID   string                  `json:"batchId"`
+	Status    string                  `json:"status"`
+	ExpiresAt time.Time               `json:"expiresAt"`
+	Items     []SVGUploadItemResponse `json:"items"`
+}
+
+// SVGUploadItemResponse 单个文件的校验和匹配结果
+type SVGUploadItemResponse struct {
+	Index         int                     `json:"index"`
+	FileName      string                  `json:"fileName"`
+	View          string                  `json:"view,omitempty"`
+	Status        string                  `json:"status"`
+	Error         string                  `json:"error,omitempty"`
+	Result        string                  `json:"result,omitempty"`       // 确认后的处理结果：updated、skipped
+	ResultReason  string                  `json:"resultReason,omitempty"` // 跳过或未生成规范版本的原因
+	MatchedDevice *SVGMatchCandidateInfo  `json:"matchedDevice,omitempty"`
+	Candidates    []SVGMatchCandidateInfo `json:"candidates,omitempty"`
+}
+
+// SVGMatchCandidateInfo 候选鼠标
+type SVGMatchCandidateInfo struct {
+	DeviceID string  `json:"deviceId"`
+	Name     string  `json:"name"`
+	Brand    string  `json:"brand"`
+	Score    float64 `json:"score"`
+}
+
+// ConfirmSVGUploadRequest 确认上传请求
+// Items为空时只写入自动匹配成功的文件；指定Items时只处理列出的文件
+type ConfirmSVGUploadRequest struct {
+	Items []SVGUploadConfirmItem `json:"items" binding:"omitempty,dive"`
+}
+
+// SVGUploadConfirmItem 单个文件的确认信息
+type SVGUploadConfirmItem struct {
+	Index    int    `json:"index" binding:"min=0"`
+	DeviceID string `json:"deviceId,omitempty"` // 为空时使用自动匹配结果
+	View     string `json:"view,omitempty" binding:"omitempty,oneof=top side"`
+	Skip     bool   `json:"skip,omitempty"`
+}
+
+// SVGUploadResultResponse 确认上传结果
+// 有文件因读写失败未写入时Status仍为pending，可以再次确认，已写入的文件不会重复写入
+type SVGUploadResultResponse struct {
+	BatchID string                `json:"batchId"`
+	Status  string                `json:"status"`
+	Updated []SVGUploadResultItem `json:"updated"`
+	Skipped []SVGUploadResultItem `json:"skipped"`
+}
+
+// SVGUploadResultItem 单个文件的处理结果
+type SVGUploadResultItem struct {
+	Index    int    `json:"index"`
+	FileName string `json:"fileName"`
+	DeviceID string `json:"deviceId,omitempty"`
+	View     string `json:"view,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -0,0 +1,37 @@
+package device
+
+import "time"
+
+// 设备回收站相关类型
+
+// DeviceTrashListRequest 回收站列表请求
+type DeviceTrashListRequest struct {
+	Type     string `form:"type" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// DeviceTrashListResponse 回收站列表，按删除时间倒序
+type DeviceTrashListResponse struct {
+	Devices  []DeviceTrashItem `json:"devices"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+}
+
+// DeviceTrashItem 回收站中的设备
+type DeviceTrashItem struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Brand     string    `json:"brand"`
+	Type      string    `json:"type"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// DevicePurgeResult 彻底删除设备的结果，包含级联清理的数量
+type DevicePurgeResult struct {
+	DeviceID    string `json:"deviceId"`
+	Reviews     int64  `json:"reviews"`     // 删除的评测
+	Carts       int64  `json:"carts"`       // 移除了该设备的购物车
+	UserDevices int64  `json:"userDevices"` // 移除了该设备的用户设备配置
+}
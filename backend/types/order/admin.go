@@ -0,0 +1,98 @@
+package order
+
+import "time"
+
+// AdminOrderListRequest 管理员订单查询条件
+// 日期格式为2006-01-02，To包含当天
+type AdminOrderListRequest struct {
+	Status      string `form:"status"`
+	UserID      string `form:"userId"`
+	OrderNumber string `form:"orderNumber"` // 按订单号前缀匹配
+	From        string `form:"from"`
+	To          string `form:"to"`
+	Page        int    `form:"page"`
+	PageSize    int    `form:"pageSize"`
+}
+
+// BulkUpdateStatusRequest 批量变更订单状态请求
+type BulkUpdateStatusRequest struct {
+	OrderIDs     []string `json:"orderIds" binding:"required,min=1,max=100"`
+	Status       string   `json:"status" binding:"required"`
+	CancelReason string   `json:"cancelReason,omitempty"`
+}
+
+// BulkUpdateStatusResponse 批量变更结果，单个订单失败不影响其他订单
+type BulkUpdateStatusResponse struct {
+	Updated []string            `json:"updated"`
+	Failed  []BulkUpdateFailure `json:"failed"`
+}
+
+// BulkUpdateFailure 变更失败的订单及原因
+type BulkUpdateFailure struct {
+	OrderID string `json:"orderId"`
+	Error   string `json:"error"`
+}
+
+// UpdateShipmentRequest 填写物流信息请求
+// 已支付的订单填写物流单号后自动标记为已发货
+type UpdateShipmentRequest struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"trackingNumber" binding:"required"`
+}
+
+// AddOrderNoteRequest 添加内部备注请求
+type AddOrderNoteRequest struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+// OrderNoteResponse 内部备注响应
+type OrderNoteResponse struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"authorId"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AdminOrderResponse 管理员查看的订单，包含内部备注
+type AdminOrderResponse struct {
+	OrderResponse
+	AdminNotes []OrderNoteResponse `json:"adminNotes"`
+}
+
+// AdminOrderListResponse 管理员订单列表响应
+type AdminOrderListResponse struct {
+	Orders      []AdminOrderResponse `json:"orders"`
+	TotalCount  int64                `json:"totalCount"`
+	CurrentPage int                  `json:"currentPage"`
+	PageSize    int                  `json:"pageSize"`
+}
+
+// OrderStatsRequest 订单统计请求
+// Interval为day、week或month，默认最近30天按天统计
+type OrderStatsRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Interval string `form:"interval"`
+	Timezone string `form:"timezone"` // IANA时区名，默认UTC
+}
+
+// OrderStatsResponse 订单统计结果，按币种分别汇总，金额单位为分
+// 只统计已支付的订单，营收为订单总价，净营收扣除已退款金额
+type OrderStatsResponse struct {
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	Interval string             `json:"interval"`
+	Totals   []OrderStatsBucket `json:"totals"`
+	Series   []OrderStatsBucket `json:"series"`
+}
+
+// OrderStatsBucket 单个时间段和币种的统计
+type OrderStatsBucket struct {
+	Period            string `json:"period,omitempty"` // 时间段，总计中为空
+	Currency          string `json:"currency"`
+	OrderCount        int64  `json:"orderCount"`
+	Revenue           int64  `json:"revenue"`
+	RefundedAmount    int64  `json:"refundedAmount"`
+	NetRevenue        int64  `json:"netRevenue"`
+	AverageOrderValue int64  `json:"averageOrderValue"`
+}
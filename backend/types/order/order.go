@@ -2,6 +2,8 @@ package order
 
 import (
 	"time"
+
+	"project/backend/models"
 )
 
 // CreateOrderRequest 创建订单请求
@@ -10,13 +12,13 @@ type CreateOrderRequest struct {
 	ShippingInfo  ShippingInfoRequest `json:"shippingInfo"`           // 配送信息
 	PaymentMethod string             `json:"paymentMethod"`           // 支付方式
 	Notes         string             `json:"notes,omitempty"`         // 备注
+	CouponCode    string             `json:"couponCode,omitempty"`    // 优惠券码
 }
 
-// OrderItemRequest 订单商品请求
+// OrderItemRequest 订单商品请求，价格由服务端按SKU计算
 type OrderItemRequest struct {
-	ProductID   string  `json:"productId"`     // 商品ID
-	ProductType string  `json:"productType"`   // 商品类型
-	Quantity    int     `json:"quantity"`      // 数量
+	SKUID    string `json:"skuId"`    // SKU ID
+	Quantity int    `json:"quantity"` // 数量
 }
 
 // ShippingInfoRequest 配送信息请求
@@ -32,6 +34,30 @@ type ShippingInfoRequest struct {
 	ShippingMethod string `json:"shippingMethod"`   // 配送方式
 }
 
+// QuoteOrderRequest 订单试算请求，收货地址只需要国家、省/州和配送方式
+type QuoteOrderRequest struct {
+	Items        []OrderItemRequest  `json:"items"`
+	ShippingInfo ShippingInfoRequest `json:"shippingInfo"`
+	CouponCode   string              `json:"couponCode,omitempty"`
+}
+
+// OrderQuoteResponse 订单试算结果，金额单位为分
+// 含税价时Tax已包含在商品金额中，不再计入Total
+type OrderQuoteResponse struct {
+	Items          []OrderItemResponse `json:"items"`
+	Subtotal       int64               `json:"subtotal"`
+	Discount       int64               `json:"discount"`
+	CouponCode     string              `json:"couponCode,omitempty"`
+	ShippingMethod string              `json:"shippingMethod"`
+	ShippingWeight int                 `json:"shippingWeight"` // 计费重量（克）
+	ShippingFee    int64               `json:"shippingFee"`
+	TaxRate        int                 `json:"taxRate"` // 税率，单位为万分之一
+	TaxInclusive   bool                `json:"taxInclusive"`
+	Tax            int64               `json:"tax"`
+	Total          int64               `json:"total"`
+	Currency       string              `json:"currency"`
+}
+
 // UpdateOrderStatusRequest 更新订单状态请求
 type UpdateOrderStatusRequest struct {
 	Status       string `json:"status"`                      // 订单状态
@@ -47,11 +73,15 @@ type OrderResponse struct {
 	Items         []OrderItemResponse `json:"items"`              // 订单商品
 	ShippingInfo  ShippingInfoResponse `json:"shippingInfo"`      // 配送信息
 	PaymentInfo   PaymentInfoResponse `json:"paymentInfo"`        // 支付信息
-	Subtotal      float64            `json:"subtotal"`            // 商品小计
-	ShippingFee   float64            `json:"shippingFee"`         // 配送费
-	Tax           float64            `json:"tax"`                 // 税费
-	Discount      float64            `json:"discount"`            // 折扣
-	Total         float64            `json:"total"`               // 总价
+	Subtotal      int64              `json:"subtotal"`            // 商品小计（分）
+	ShippingFee   int64              `json:"shippingFee"`         // 配送费（分）
+	Tax           int64              `json:"tax"`                 // 税费（分）
+	TaxInclusive  bool               `json:"taxInclusive"`        // 税费是否已含在商品价格中
+	Discount      int64              `json:"discount"`            // 折扣（分）
+	CouponCode    string             `json:"couponCode,omitempty"` // 使用的优惠券
+	Total         int64              `json:"total"`               // 总价（分）
+	Currency      string             `json:"currency"`            // 币种
+	RefundedAmount int64             `json:"refundedAmount"`      // 已退款金额（分）
 	Notes         string             `json:"notes,omitempty"`     // 备注
 	CreatedAt     time.Time          `json:"createdAt"`
 	UpdatedAt     time.Time          `json:"updatedAt"`
@@ -64,13 +94,18 @@ type OrderResponse struct {
 
 // OrderItemResponse 订单商品响应
 type OrderItemResponse struct {
-	ProductID   string  `json:"productId"`     // 商品ID
-	ProductType string  `json:"productType"`   // 商品类型
-	Name        string  `json:"name"`          // 商品名称
-	Price       float64 `json:"price"`         // 单价
-	Quantity    int     `json:"quantity"`      // 数量
-	Subtotal    float64 `json:"subtotal"`      // 小计
-	ImageURL    string  `json:"imageUrl,omitempty"` // 图片URL
+	ProductID   string                `json:"productId"`          // 设备ID
+	SKUID       string                `json:"skuId"`              // SKU ID
+	SKUCode     string                `json:"skuCode"`            // SKU编码
+	Variant     models.ProductVariant `json:"variant"`            // 规格属性
+	ProductType string                `json:"productType"`        // 商品类型
+	Name        string                `json:"name"`               // 商品名称
+	Price       int64                 `json:"price"`              // 单价（分）
+	Quantity    int                   `json:"quantity"`           // 数量
+	Subtotal    int64                 `json:"subtotal"`           // 小计（分）
+	Discount    int64                 `json:"discount,omitempty"` // 分摊的优惠券折扣（分）
+	RefundedQuantity int              `json:"refundedQuantity"`   // 已退款数量
+	ImageURL    string                `json:"imageUrl,omitempty"` // 图片URL
 }
 
 // ShippingInfoResponse 配送信息响应
@@ -84,6 +119,8 @@ type ShippingInfoResponse struct {
 	ZipCode        string `json:"zipCode"`         // 邮编
 	Country        string `json:"country"`         // 国家
 	ShippingMethod string `json:"shippingMethod"`  // 配送方式
+	Carrier        string `json:"carrier,omitempty"`        // 承运商
+	TrackingNumber string `json:"trackingNumber,omitempty"` // 物流单号
 }
 
 // PaymentInfoResponse 支付信息响应
@@ -93,6 +130,7 @@ type PaymentInfoResponse struct {
 	LastFourDigits  string `json:"lastFourDigits,omitempty"`   // 卡号后四位
 	PaymentStatus   string `json:"paymentStatus"`              // 支付状态
 	PaymentProvider string `json:"paymentProvider,omitempty"`  // 支付提供商
+	Refunds         []PaymentRefundResponse `json:"refunds,omitempty"` // 已完成的退款
 }
 
 // OrderListResponse 订单列表响应
@@ -108,4 +146,9 @@ type PaymentCompleteRequest struct {
 	OrderID       string `json:"orderId"`        // 订单ID
 	TransactionID string `json:"transactionId"`  // 交易ID
 	PaymentStatus string `json:"paymentStatus"`  // 支付状态
-}
\ No newline at end of file
+}
+
+// PaymentIntentRequest 创建支付意图请求
+type PaymentIntentRequest struct {
+	Provider string `json:"provider" binding:"required"` // 支付渠道
+}
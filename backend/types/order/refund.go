@@ -0,0 +1,71 @@
+package order
+
+import "time"
+
+// CreateRefundRequest 申请退款请求，Items为空时退还全部未退款的商品
+type CreateRefundRequest struct {
+	Items  []RefundItemRequest `json:"items,omitempty"`
+	Reason string              `json:"reason" binding:"required"`
+}
+
+// RefundItemRequest 退款商品行
+type RefundItemRequest struct {
+	SKUID    string `json:"skuId"`
+	Quantity int    `json:"quantity"`
+}
+
+// ReviewRefundRequest 管理员审核退款请求
+type ReviewRefundRequest struct {
+	Note    string `json:"note,omitempty"`
+	Restock *bool  `json:"restock,omitempty"` // 批准时是否归还库存，默认归还
+}
+
+// RefundListRequest 退款列表请求
+type RefundListRequest struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
+
+// RefundResponse 退款响应
+type RefundResponse struct {
+	ID               string               `json:"id"`
+	OrderID          string               `json:"orderId"`
+	UserID           string               `json:"userId"`
+	Items            []RefundItemResponse `json:"items"`
+	Amount           int64                `json:"amount"` // 退款金额（分）
+	Currency         string               `json:"currency"`
+	Reason           string               `json:"reason"`
+	Status           string               `json:"status"`
+	Restock          bool                 `json:"restock"`
+	ProviderRefundID string               `json:"providerRefundId,omitempty"`
+	FailureReason    string               `json:"failureReason,omitempty"`
+	ReviewNote       string               `json:"reviewNote,omitempty"`
+	CreatedAt        time.Time            `json:"createdAt"`
+	ReviewedAt       *time.Time           `json:"reviewedAt,omitempty"`
+	RefundedAt       *time.Time           `json:"refundedAt,omitempty"`
+}
+
+// RefundItemResponse 退款商品行响应
+type RefundItemResponse struct {
+	SKUID    string `json:"skuId"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Amount   int64  `json:"amount"`
+}
+
+// RefundListResponse 退款列表响应
+type RefundListResponse struct {
+	Refunds     []RefundResponse `json:"refunds"`
+	TotalCount  int64            `json:"totalCount"`
+	CurrentPage int              `json:"currentPage"`
+	PageSize    int              `json:"pageSize"`
+}
+
+// PaymentRefundResponse 支付信息中的已完成退款
+type PaymentRefundResponse struct {
+	RefundID         string    `json:"refundId"`
+	ProviderRefundID string    `json:"providerRefundId"`
+	Amount           int64     `json:"amount"`
+	RefundedAt       time.Time `json:"refundedAt"`
+}
@@ -0,0 +1,30 @@
+package product
+
+import "project/backend/models"
+
+// CreateSKURequest 创建SKU请求，价格以分为单位
+type CreateSKURequest struct {
+	Code     string                `json:"code" binding:"required"`
+	Variant  models.ProductVariant `json:"variant"`
+	Price    int64                 `json:"price" binding:"required,min=1"`
+	Currency string                `json:"currency" binding:"omitempty,len=3"`
+	Stock    int                   `json:"stock" binding:"min=0"`
+	ImageURL string                `json:"imageUrl,omitempty"`
+	Active   *bool                 `json:"active,omitempty"` // 不传时默认上架
+}
+
+// UpdateSKURequest 更新SKU请求，只更新传入的字段
+type UpdateSKURequest struct {
+	Variant  *models.ProductVariant `json:"variant,omitempty"`
+	Price    *int64                 `json:"price,omitempty" binding:"omitempty,min=1"`
+	Currency *string                `json:"currency,omitempty" binding:"omitempty,len=3"`
+	Stock    *int                   `json:"stock,omitempty" binding:"omitempty,min=0"`
+	ImageURL *string                `json:"imageUrl,omitempty"`
+	Active   *bool                  `json:"active,omitempty"`
+}
+
+// SKUListResponse 设备的SKU列表
+type SKUListResponse struct {
+	DeviceID string              `json:"deviceId"`
+	SKUs     []models.ProductSKU `json:"skus"`
+}
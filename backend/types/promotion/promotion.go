@@ -0,0 +1,47 @@
+package promotion
+
+import (
+	"time"
+
+	"project/backend/models"
+)
+
+// CreateCouponRequest 创建优惠券请求，金额以分为单位
+// 按比例折扣时Value为1-100的百分比
+type CreateCouponRequest struct {
+	Code         string                `json:"code" binding:"required"`
+	Name         string                `json:"name"`
+	Type         models.CouponTypeEnum `json:"type" binding:"required,oneof=percentage fixed"`
+	Value        int64                 `json:"value" binding:"required,min=1"`
+	MaxDiscount  int64                 `json:"maxDiscount" binding:"min=0"`
+	Currency     string                `json:"currency" binding:"omitempty,len=3"`
+	MinSpend     int64                 `json:"minSpend" binding:"min=0"`
+	Brands       []string              `json:"brands,omitempty"`
+	DeviceTypes  []string              `json:"deviceTypes,omitempty"`
+	UsageLimit   int                   `json:"usageLimit" binding:"min=0"`
+	PerUserLimit int                   `json:"perUserLimit" binding:"min=0"`
+	StartsAt     *time.Time            `json:"startsAt,omitempty"`
+	EndsAt       *time.Time            `json:"endsAt,omitempty"`
+	Active       *bool                 `json:"active,omitempty"` // 不传时默认启用
+}
+
+// UpdateCouponRequest 更新优惠券请求，只更新传入的字段
+// 券码和类型创建后不能修改
+type UpdateCouponRequest struct {
+	Name         *string    `json:"name,omitempty"`
+	Value        *int64     `json:"value,omitempty" binding:"omitempty,min=1"`
+	MaxDiscount  *int64     `json:"maxDiscount,omitempty" binding:"omitempty,min=0"`
+	MinSpend     *int64     `json:"minSpend,omitempty" binding:"omitempty,min=0"`
+	Brands       *[]string  `json:"brands,omitempty"`
+	DeviceTypes  *[]string  `json:"deviceTypes,omitempty"`
+	UsageLimit   *int       `json:"usageLimit,omitempty" binding:"omitempty,min=0"`
+	PerUserLimit *int       `json:"perUserLimit,omitempty" binding:"omitempty,min=0"`
+	StartsAt     *time.Time `json:"startsAt,omitempty"`
+	EndsAt       *time.Time `json:"endsAt,omitempty"`
+	Active       *bool      `json:"active,omitempty"`
+}
+
+// CouponListResponse 优惠券列表
+type CouponListResponse struct {
+	Coupons []models.Coupon `json:"coupons"`
+}
@@ -0,0 +1,58 @@
+package review
+
+import "time"
+
+// 旧版 /device-reviews 接口的请求和响应，底层使用统一的评测数据
+
+// CreateDeviceReviewRequest 创建设备评测的请求
+type CreateDeviceReviewRequest struct {
+	DeviceID    string   `json:"deviceId" binding:"required"`
+	Content     string   `json:"content" binding:"required,min=50"`
+	Pros        []string `json:"pros" binding:"required,min=1"`
+	Cons        []string `json:"cons" binding:"required,min=1"`
+	Score       float64  `json:"score" binding:"required,min=1,max=5"`
+	Usage       string   `json:"usage" binding:"required"`
+	Type        string   `json:"type" binding:"required,oneof=mouse keyboard monitor mousepad accessory"`
+	ContentType string   `json:"contentType" binding:"required,oneof=single comparison experience gaming buying"`
+}
+
+// DeviceReviewListRequest 获取设备评测列表的请求
+type DeviceReviewListRequest struct {
+	DeviceID  string `form:"deviceId,omitempty"`
+	UserID    string `form:"userId,omitempty"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	SortBy    string `form:"sortBy" binding:"omitempty,oneof=createdAt score"`
+	SortOrder string `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
+}
+
+// DeviceReviewResponse 设备评测响应
+type DeviceReviewResponse struct {
+	ID            string     `json:"id"`
+	DeviceID      string     `json:"deviceId"`
+	UserID        string     `json:"userId"`
+	Content       string     `json:"content"`
+	Pros          []string   `json:"pros"`
+	Cons          []string   `json:"cons"`
+	Score         float64    `json:"score"`
+	Usage         string     `json:"usage"`
+	Status        string     `json:"status"`
+	Type          string     `json:"type"`
+	ContentType   string     `json:"contentType"`
+	ReviewerID    string     `json:"reviewerId,omitempty"`
+	ReviewerNotes string     `json:"reviewerNotes,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+	PublishedAt   *time.Time `json:"publishedAt,omitempty"`
+	FeaturedRank  *int       `json:"featuredRank,omitempty"`
+	ViewCount     int        `json:"viewCount"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// DeviceReviewListResponse 设备评测列表响应
+type DeviceReviewListResponse struct {
+	Total    int                    `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+	Reviews  []DeviceReviewResponse `json:"reviews"`
+}
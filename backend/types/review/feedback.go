@@ -0,0 +1,50 @@
+package review
+
+import "time"
+
+// VoteRequest 评测是否有帮助的投票
+type VoteRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+// VoteResponse 投票后的评测票数
+type VoteResponse struct {
+	ReviewID       string  `json:"reviewId"`
+	Helpful        *bool   `json:"helpful"` // 当前用户的投票，撤销后为空
+	HelpfulCount   int     `json:"helpfulCount"`
+	UnhelpfulCount int     `json:"unhelpfulCount"`
+	HelpfulScore   float64 `json:"helpfulScore"`
+}
+
+// CreateCommentRequest 发表评论的请求，ParentID为空时为顶层评论
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=2000"`
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// ModerateCommentRequest 隐藏评论的请求
+type ModerateCommentRequest struct {
+	Note string `json:"note,omitempty" binding:"omitempty,max=500"`
+}
+
+// CommentResponse 评论响应，隐藏或删除的评论不返回内容，但保留楼层以免回复脱离上下文
+type CommentResponse struct {
+	ID        string             `json:"id"`
+	ReviewID  string             `json:"reviewId"`
+	UserID    string             `json:"userId"`
+	ParentID  string             `json:"parentId,omitempty"`
+	Depth     int                `json:"depth"`
+	Content   string             `json:"content"`
+	Status    string             `json:"status"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+	Replies   []*CommentResponse `json:"replies"`
+}
+
+// CommentListResponse 按顶层评论分页的评论列表
+type CommentListResponse struct {
+	Total    int                `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"pageSize"`
+	Comments []*CommentResponse `json:"comments"`
+}
@@ -2,26 +2,51 @@ package review
 
 // CreateReviewRequest 创建评测的请求
 type CreateReviewRequest struct {
-	ExternalItemID string   `json:"externalItemId" binding:"required"`
+	ExternalItemID string   `json:"externalItemId" binding:"required_without=Comparison"` // 对比评测可省略，默认为第一个对比设备
 	ItemType       string   `json:"itemType" binding:"required,oneof=device game peripheral software"`
 	Content        string   `json:"content" binding:"required,min=50"`
+	ContentFormat  string   `json:"contentFormat,omitempty" binding:"omitempty,oneof=plain markdown"` // 默认为纯文本
 	Pros           []string `json:"pros" binding:"required,min=1"`
 	Cons           []string `json:"cons" binding:"required,min=1"`
 	Score          float64  `json:"score" binding:"required,min=1,max=5"`
 	Usage          string   `json:"usage" binding:"required"`
 	Type           string   `json:"type" binding:"required,oneof=mouse keyboard monitor mousepad accessory game software"`
 	ContentType    string   `json:"contentType" binding:"required,oneof=single comparison experience gaming buying"`
+	Comparison     *ComparisonRequest `json:"comparison,omitempty"` // 仅对比评测使用
+	ImageIDs       []string           `json:"imageIds,omitempty" binding:"omitempty,max=9"` // 已上传的图片，按展示顺序
+	Locale         string             `json:"-"`                    // 由处理器按请求语言设置，用于预审选择屏蔽词
 }
 
 // UpdateReviewRequest 更新评测的请求
 type UpdateReviewRequest struct {
 	Content     *string   `json:"content,omitempty" binding:"omitempty,min=50"`
+	ContentFormat *string `json:"contentFormat,omitempty" binding:"omitempty,oneof=plain markdown"` // 不传时沿用原格式
 	Pros        *[]string `json:"pros,omitempty" binding:"omitempty,min=1"`
 	Cons        *[]string `json:"cons,omitempty" binding:"omitempty,min=1"`
 	Score       *float64  `json:"score,omitempty" binding:"omitempty,min=1,max=5"`
 	Usage       *string   `json:"usage,omitempty"`
 	Type        *string   `json:"type,omitempty" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory game software"`
 	ContentType *string   `json:"contentType,omitempty" binding:"omitempty,oneof=single comparison experience gaming buying"`
+	Comparison  *ComparisonRequest `json:"comparison,omitempty"`
+	ImageIDs    *[]string          `json:"imageIds,omitempty" binding:"omitempty,max=9"` // 替换全部图片，移除的图片在修改生效后删除
+}
+
+// ComparisonRequest 对比评测的设备、评分和各维度胜出者
+type ComparisonRequest struct {
+	Items   []ComparisonItemRequest   `json:"items" binding:"required,min=2,max=4,dive"`
+	Winners []ComparisonWinnerRequest `json:"winners,omitempty" binding:"omitempty,max=4,dive"`
+}
+
+// ComparisonItemRequest 参与对比的设备
+type ComparisonItemRequest struct {
+	DeviceID string  `json:"deviceId" binding:"required"`
+	Score    float64 `json:"score" binding:"required,min=1,max=5"`
+}
+
+// ComparisonWinnerRequest 某个维度胜出的设备
+type ComparisonWinnerRequest struct {
+	Category string `json:"category" binding:"required,oneof=shape weight sensor build"`
+	DeviceID string `json:"deviceId" binding:"required"`
 }
 
 // ReviewListRequest 获取评测列表的请求
@@ -34,10 +59,18 @@ type ReviewListRequest struct {
 	ContentType    string `form:"contentType,omitempty" binding:"omitempty,oneof=single comparison experience gaming buying"`
 	Page           int    `form:"page" binding:"omitempty,min=1"`
 	PageSize       int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
-	SortBy         string `form:"sortBy" binding:"omitempty,oneof=createdAt score viewCount"`
+	SortBy         string `form:"sortBy" binding:"omitempty,oneof=createdAt score viewCount helpful"`
 	SortOrder      string `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
 }
 
+// PendingReviewListRequest 待审核评测列表的请求，可按预审风险分排序
+type PendingReviewListRequest struct {
+	Type     string `form:"type,omitempty" binding:"omitempty,oneof=mouse keyboard monitor mousepad accessory game software"`
+	SortBy   string `form:"sortBy" binding:"omitempty,oneof=createdAt risk"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
 // ReviewerActionRequest 评测员操作请求
 type ReviewerActionRequest struct {
 	Notes string `json:"notes,omitempty"`
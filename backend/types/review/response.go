@@ -9,21 +9,77 @@ type ReviewResponse struct {
 	ItemType       string     `json:"itemType"`
 	UserID         string     `json:"userId"`
 	Content        string     `json:"content"`
+	ContentFormat  string     `json:"contentFormat"`
+	ContentHTML    string     `json:"contentHtml,omitempty"` // Markdown正文渲染后的HTML，列表中不返回
+	Excerpt        string     `json:"excerpt"`
 	Pros           []string   `json:"pros"`
 	Cons           []string   `json:"cons"`
 	Score          float64    `json:"score"`
 	Usage          string     `json:"usage"`
+	Images         []ReviewImageResponse `json:"images"`
 	Status         string     `json:"status"`
 	Type           string     `json:"type"`
 	ContentType    string     `json:"contentType"`
+	Comparison     *ComparisonResponse `json:"comparison,omitempty"`
+	Moderation     *ModerationResponse `json:"moderation,omitempty"` // 仅待审核列表返回
 	ReviewerID     string     `json:"reviewerId,omitempty"`
 	ReviewerNotes  string     `json:"reviewerNotes,omitempty"`
 	ReviewedAt     *time.Time `json:"reviewedAt,omitempty"`
 	PublishedAt    *time.Time `json:"publishedAt,omitempty"`
 	FeaturedRank   *int       `json:"featuredRank,omitempty"`
 	ViewCount      int        `json:"viewCount"`
+	HelpfulCount   int        `json:"helpfulCount"`
+	UnhelpfulCount int        `json:"unhelpfulCount"`
+	HelpfulScore   float64    `json:"helpfulScore"`
+	CommentCount   int        `json:"commentCount"`
+	PendingRevision int       `json:"pendingRevision,omitempty"` // 已发布评测有待审核的修改时返回
 	CreatedAt      time.Time  `json:"createdAt"`
 	UpdatedAt      time.Time  `json:"updatedAt"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+}
+
+// ReviewImageResponse 评测图片
+type ReviewImageResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// ComparisonResponse 对比评测的内容，附带同一组设备的对比接口地址
+type ComparisonResponse struct {
+	Items      []ComparisonItemResponse   `json:"items"`
+	Winners    []ComparisonWinnerResponse `json:"winners"`
+	DeviceIDs  []string                   `json:"deviceIds"`            // 按对比顺序排列，可直接用于SVG叠加对比
+	CompareURL string                     `json:"compareUrl,omitempty"` // 鼠标参数对比，仅鼠标评测提供
+}
+
+// ComparisonItemResponse 参与对比的设备
+type ComparisonItemResponse struct {
+	DeviceID string  `json:"deviceId"`
+	Score    float64 `json:"score"`
+}
+
+// ComparisonWinnerResponse 某个维度胜出的设备
+type ComparisonWinnerResponse struct {
+	Category string `json:"category"`
+	DeviceID string `json:"deviceId"`
+}
+
+// ModerationResponse 自动预审结果
+type ModerationResponse struct {
+	RiskScore    int                      `json:"riskScore"`
+	Flags        []ModerationFlagResponse `json:"flags"`
+	AutoApproved bool                     `json:"autoApproved"`
+	CheckedAt    time.Time                `json:"checkedAt"`
+}
+
+// ModerationFlagResponse 预审规则命中的一项
+type ModerationFlagResponse struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+	Score  int    `json:"score"`
 }
 
 // ReviewListResponse 评测列表响应
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"time"
+
+	"project/backend/models"
+)
+
+// 评测修改历史相关类型
+
+// ReviewRevisionListRequest 修改历史列表请求
+type ReviewRevisionListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewRevisionDiffRequest 对比两个版本，from默认为最近通过的版本，to默认为待审核的版本
+type ReviewRevisionDiffRequest struct {
+	From int `form:"from" binding:"omitempty,min=1"`
+	To   int `form:"to" binding:"omitempty,min=1"`
+}
+
+// ReviewRevisionResponse 单个修改记录
+type ReviewRevisionResponse struct {
+	ReviewID      string                `json:"reviewId"`
+	UserID        string                `json:"userId"`
+	Revision      int                   `json:"revision"`
+	Status        string                `json:"status"`
+	Snapshot      models.ReviewSnapshot `json:"snapshot"`
+	Changes       []models.FieldChange  `json:"changes"`
+	Moderation    *ModerationResponse   `json:"moderation,omitempty"`
+	ReviewerID    string                `json:"reviewerId,omitempty"`
+	ReviewerNotes string                `json:"reviewerNotes,omitempty"`
+	ReviewedAt    *time.Time            `json:"reviewedAt,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+}
+
+// ReviewRevisionListResponse 修改记录列表
+type ReviewRevisionListResponse struct {
+	Revisions []ReviewRevisionResponse `json:"revisions"`
+	Total     int                      `json:"total"`
+	Page      int                      `json:"page"`
+	PageSize  int                      `json:"pageSize"`
+}
+
+// ReviewRevisionDiffResponse 两个版本之间的字段差异
+type ReviewRevisionDiffResponse struct {
+	ReviewID string               `json:"reviewId"`
+	From     int                  `json:"from"`
+	To       int                  `json:"to"`
+	Changes  []models.FieldChange `json:"changes"`
+}
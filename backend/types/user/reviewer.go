@@ -0,0 +1,47 @@
+package user
+
+import "time"
+
+// ReviewerApplicationRequest 申请成为评测员
+type ReviewerApplicationRequest struct {
+	Experience     string   `json:"experience" binding:"required,min=20,max=2000"`
+	ExpertiseAreas []string `json:"expertiseAreas" binding:"required,min=1,max=10,dive,required,max=50"`
+	Samples        []string `json:"samples" binding:"max=5,dive,url"`
+	Motivation     string   `json:"motivation" binding:"required,min=20,max=2000"`
+}
+
+// RejectReviewerApplicationRequest 拒绝评测员申请
+type RejectReviewerApplicationRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ReviewerApplicationListRequest 评测员申请列表查询参数
+type ReviewerApplicationListRequest struct {
+	Status   string `form:"status"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize"`
+}
+
+// ReviewerApplicationDetails 评测员申请详情
+type ReviewerApplicationDetails struct {
+	UserID         string    `json:"userId"`
+	Username       string    `json:"username"`
+	Email          string    `json:"email"`
+	DisplayName    string    `json:"displayName"`
+	Experience     string    `json:"experience"`
+	ExpertiseAreas []string  `json:"expertiseAreas"`
+	Samples        []string  `json:"samples"`
+	Motivation     string    `json:"motivation"`
+	Status         string    `json:"status"`
+	ReviewNotes    string    `json:"reviewNotes,omitempty"`
+	AppliedAt      time.Time `json:"appliedAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// ReviewerApplicationListResponse 评测员申请列表
+type ReviewerApplicationListResponse struct {
+	Applications []ReviewerApplicationDetails `json:"applications"`
+	Total        int64                        `json:"total"`
+	Page         int                          `json:"page"`
+	PageSize     int                          `json:"pageSize"`
+}